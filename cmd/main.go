@@ -2,6 +2,9 @@ package main
 
 import (
 	"fmt"
+	"os"
+	"path/filepath"
+	"proyecto-grafos-go/configs"
 	"proyecto-grafos-go/internal/domain"
 	"proyecto-grafos-go/internal/handler"
 	"proyecto-grafos-go/internal/repository"
@@ -17,10 +20,20 @@ func main() {
 
 	// Inicialización
 	grafo := domain.NuevoGrafo(false)
-	repo := repository.NuevoRepositorio("data/")
+	config, err := configs.LoadConfig("configs/settings.json")
+	if err != nil {
+		fmt.Printf("ADVERTENCIA: %s; se usa la configuración por defecto\n", err.Error())
+		config = configs.DefaultConfig()
+	}
+	archivos := repository.NuevoRepositorioConSnapshots(config.GetDataPath(), config.GetBackupPath())
+	repo := abrirRepositorio(config, archivos)
+	if sqlite, ok := repo.(*repository.RepositorioSQLite); ok {
+		defer sqlite.Cerrar()
+	}
 
 	// Servicios básicos
 	grafoSvc := service.NuevoServicioGrafo(grafo, repo)
+	grafoSvc.EstablecerConfiguracion(config)
 	cuevaSvc := service.ServicioNuevaCueva(grafo)
 	validacionSvc := service.NuevoServicioValidacion(grafo)
 	conexionSvc := service.NuevoServicioConexion(grafo)
@@ -39,18 +52,20 @@ func main() {
 	grafoHandler := handler.NuevoGraphHandler(grafoSvc)
 	cuevaHandler := handler.NuevoCaveHandler(cuevaSvc)
 
-	// Cargar configuración por defecto de Cueva Acme
+	// Cargar configuración por defecto de Cueva Acme; los ejemplos siempre son archivos,
+	// aunque los grafos del usuario se guarden en SQLite
+	ejemplosSvc := service.NuevoServicioGrafo(grafo, archivos)
 	if rutaConfiguracion, err := utils.ObtenerRutaConfiguracionCuevaAcmePorDefecto(); err == nil {
-		if err := grafoSvc.CargarGrafo(rutaConfiguracion); err != nil {
+		if err := ejemplosSvc.CargarGrafo(rutaConfiguracion); err != nil {
 			fmt.Printf("ERROR: No se pudo cargar la configuración de Cueva Acme: %s\n", err.Error())
 		} else {
 			fmt.Println("✓ Configuración de Cueva Acme cargada exitosamente (10 cuevas interconectadas)")
 		}
 	} else {
 		// Si no existe, intentar cargar datos de ejemplo alternativos
-		if err := grafoSvc.CargarGrafo("caves_directed_example.json"); err != nil {
+		if err := ejemplosSvc.CargarGrafo("caves_directed_example.json"); err != nil {
 			// Si no existe el archivo dirigido, intentar con el simple
-			if err := grafoSvc.CargarGrafo("caves_example.json"); err != nil {
+			if err := ejemplosSvc.CargarGrafo("caves_example.json"); err != nil {
 				fmt.Printf("INFO: No se pudo cargar ningún archivo de configuración: %s\n", err.Error())
 				fmt.Println("NOTA: Puede crear cuevas manualmente desde el menu")
 			} else {
@@ -68,11 +83,32 @@ func main() {
 	fmt.Println("\nIniciando interfaz de usuario...")
 
 	// Mostrar menú principal mejorado con opciones de simulación
-	mostrarMenuPrincipalMejorado(mainMenu, simulationHandler, traversalHandler, grafo)
+	mostrarMenuPrincipalMejorado(mainMenu, simulationHandler, traversalHandler, grafoHandler, grafo)
+}
+
+// abrirRepositorio elige dónde se guardan los grafos según la configuración;
+// si la base SQLite no se puede abrir se sigue trabajando con archivos
+func abrirRepositorio(config *configs.Config, archivos *repository.RepositorioArchivo) repository.GraphRepository {
+	if !config.UsaSQLite() {
+		return archivos
+	}
+
+	ruta := config.GetSQLitePath()
+	if err := os.MkdirAll(filepath.Dir(ruta), 0755); err != nil {
+		fmt.Printf("ADVERTENCIA: no se pudo crear el directorio de %s: %s; se usan archivos\n", ruta, err.Error())
+		return archivos
+	}
+	sqlite, err := repository.NuevoRepositorioSQLite(ruta)
+	if err != nil {
+		fmt.Printf("ADVERTENCIA: %s; se usan archivos\n", err.Error())
+		return archivos
+	}
+	fmt.Printf("✓ Grafos almacenados en la base SQLite %s\n", ruta)
+	return sqlite
 }
 
 // mostrarMenuPrincipalMejorado extiende el menú principal con opciones de simulación
-func mostrarMenuPrincipalMejorado(mainMenu *cli.MainMenu, simulationHandler *handler.SimulationHandler, traversalHandler *handler.TraversalHandler, grafo *domain.Grafo) {
+func mostrarMenuPrincipalMejorado(mainMenu *cli.MainMenu, simulationHandler *handler.SimulationHandler, traversalHandler *handler.TraversalHandler, grafoHandler *handler.GraphHandler, grafo *domain.Grafo) {
 	for {
 		fmt.Println("\n" + strings.Repeat("=", 60))
 		fmt.Println("MENU PRINCIPAL - SISTEMA DE CUEVAS")
@@ -93,7 +129,7 @@ func mostrarMenuPrincipalMejorado(mainMenu *cli.MainMenu, simulationHandler *han
 			mainMenu.Mostrar()
 		case "2":
 			// Nuevo menú de simulación
-			simulationMenu := cli.NuevoSimulationMenu(simulationHandler, traversalHandler, grafo)
+			simulationMenu := cli.NuevoSimulationMenu(simulationHandler, traversalHandler, grafoHandler, grafo)
 			simulationMenu.MostrarMenu()
 		case "3":
 			// Menú de análisis de recorridos
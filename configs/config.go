@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 )
 
 // Config estructura principal de configuración
@@ -29,8 +30,16 @@ type DatabaseConfig struct {
 	BackupDir        string   `json:"backup_dir"`
 	MaxFileSize      int      `json:"max_file_size_mb"`
 	SupportedFormats []string `json:"supported_formats"`
+	Backend          string   `json:"backend"`
+	SQLitePath       string   `json:"sqlite_path"`
 }
 
+// Backends de almacenamiento de grafos admitidos
+const (
+	BackendArchivos = "archivos"
+	BackendSQLite   = "sqlite"
+)
+
 // ServerConfig configuración del servidor
 type ServerConfig struct {
 	Port           int    `json:"port"`
@@ -61,7 +70,9 @@ func DefaultConfig() *Config {
 			DataDir:          "data",
 			BackupDir:        "backups",
 			MaxFileSize:      10,
-			SupportedFormats: []string{"json", "xml", "txt"},
+			SupportedFormats: []string{"json", "xml", "txt", "graphml", "gexf", "csv", "matriz", "dot"},
+			Backend:          BackendArchivos,
+			SQLitePath:       "data/grafos.db",
 		},
 		Server: ServerConfig{
 			Port:           8080,
@@ -81,13 +92,10 @@ func DefaultConfig() *Config {
 
 // LoadConfig carga la configuración desde un archivo
 func LoadConfig(configPath string) (*Config, error) {
-	// Si el archivo no existe, crear uno con configuración por defecto
+	// Si el archivo no existe se usa la configuración por defecto sin escribir nada;
+	// SaveConfig la persiste cuando se pide explícitamente
 	if _, err := os.Stat(configPath); os.IsNotExist(err) {
-		config := DefaultConfig()
-		if err := SaveConfig(config, configPath); err != nil {
-			return nil, fmt.Errorf("error creando archivo de configuración: %w", err)
-		}
-		return config, nil
+		return DefaultConfig(), nil
 	}
 
 	// Leer archivo existente
@@ -151,6 +159,15 @@ func ValidateConfig(config *Config) error {
 	if config.Database.MaxFileSize <= 0 {
 		return fmt.Errorf("tamaño máximo de archivo debe ser mayor a 0")
 	}
+	switch config.Database.Backend {
+	case "", BackendArchivos:
+	case BackendSQLite:
+		if config.Database.SQLitePath == "" {
+			return fmt.Errorf("ruta de la base SQLite no puede estar vacía")
+		}
+	default:
+		return fmt.Errorf("backend debe ser %q o %q", BackendArchivos, BackendSQLite)
+	}
 
 	// Validar Server
 	if config.Server.Port <= 0 || config.Server.Port > 65535 {
@@ -186,6 +203,28 @@ func (c *Config) GetBackupPath() string {
 	return filepath.Clean(c.Database.BackupDir)
 }
 
+// UsaSQLite indica si los grafos se guardan en la base SQLite en lugar de archivos
+func (c *Config) UsaSQLite() bool {
+	return c.Database.Backend == BackendSQLite
+}
+
+// GetSQLitePath retorna la ruta de la base SQLite
+func (c *Config) GetSQLitePath() string {
+	return filepath.Clean(c.Database.SQLitePath)
+}
+
+// IsFormatSupported indica si el formato de archivo (extensión sin punto, o "matriz"
+// para la matriz de adyacencia CSV) está habilitado
+func (c *Config) IsFormatSupported(formato string) bool {
+	formato = strings.TrimPrefix(strings.ToLower(formato), ".")
+	for _, soportado := range c.Database.SupportedFormats {
+		if strings.ToLower(soportado) == formato {
+			return true
+		}
+	}
+	return false
+}
+
 // GetLogPath retorna la ruta completa del archivo de log
 func (c *Config) GetLogPath() string {
 	return filepath.Clean(c.Logging.OutputFile)
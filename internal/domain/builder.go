@@ -0,0 +1,157 @@
+package domain
+
+import "fmt"
+
+// ConstructorGrafo arma un grafo en lote sin el escaneo de duplicados de
+// AgregarArista. El tipo de grafo y las cuevas pueden llegar después de las
+// aristas (por ejemplo, al leer en streaming), así que la validación de
+// extremos y las aristas inversas se resuelven en Construir.
+type ConstructorGrafo struct {
+	esDirigido bool
+	cuevas     map[string]*Cueva
+	aristas    []*Arista
+	indice     map[[2]string]int // Posición en aristas de cada par desde -> hasta
+	repetidas  [][2]string       // Pares repetidos, válidos solo en grafos no dirigidos
+}
+
+// NuevoConstructorGrafo crea un constructor vacío
+func NuevoConstructorGrafo() *ConstructorGrafo {
+	return &ConstructorGrafo{
+		cuevas: make(map[string]*Cueva),
+		indice: make(map[[2]string]int),
+	}
+}
+
+// EstablecerDirigido define el tipo del grafo a construir
+func (c *ConstructorGrafo) EstablecerDirigido(esDirigido bool) {
+	c.esDirigido = esDirigido
+}
+
+// AgregarCueva registra una cueva
+func (c *ConstructorGrafo) AgregarCueva(cueva *Cueva) error {
+	if _, existe := c.cuevas[cueva.ID]; existe {
+		return fmt.Errorf("cueva con ID %s ya existe", cueva.ID)
+	}
+	c.cuevas[cueva.ID] = cueva
+	return nil
+}
+
+// AgregarArista registra una arista en tiempo constante. Un par repetido de
+// aristas no dirigidas actualiza los atributos de la primera (en grafos no
+// dirigidos es la gemela explícita); en grafos dirigidos falla al construir.
+func (c *ConstructorGrafo) AgregarArista(arista *Arista) error {
+	clave := [2]string{arista.Desde, arista.Hasta}
+	if i, existe := c.indice[clave]; existe {
+		existente := c.aristas[i]
+		if existente.EsDirigido || arista.EsDirigido {
+			return fmt.Errorf("arista desde %s hasta %s ya existe", arista.Desde, arista.Hasta)
+		}
+		existente.Distancia = arista.Distancia
+		existente.EsObstruido = arista.EsObstruido
+		existente.Capacidad = arista.Capacidad
+		existente.EsAngosto = arista.EsAngosto
+		c.repetidas = append(c.repetidas, clave)
+		return nil
+	}
+
+	c.indice[clave] = len(c.aristas)
+	c.aristas = append(c.aristas, arista)
+	return nil
+}
+
+// Contiene indica si ya se registró una arista desde -> hasta
+func (c *ConstructorGrafo) Contiene(desde, hasta string) bool {
+	_, existe := c.indice[[2]string{desde, hasta}]
+	return existe
+}
+
+// EsDirigido indica el tipo del grafo a construir
+func (c *ConstructorGrafo) EsDirigido() bool {
+	return c.esDirigido
+}
+
+// IgnorarRepetidas acepta los pares repetidos en grafos dirigidos
+// conservando los atributos del último
+func (c *ConstructorGrafo) IgnorarRepetidas() {
+	c.repetidas = nil
+}
+
+// QuitarAristasSinCuevas descarta las aristas cuyos extremos no existen y las devuelve
+func (c *ConstructorGrafo) QuitarAristasSinCuevas() []*Arista {
+	var quitadas []*Arista
+	conservadas := c.aristas[:0]
+	for _, arista := range c.aristas {
+		_, existeDesde := c.cuevas[arista.Desde]
+		_, existeHasta := c.cuevas[arista.Hasta]
+		if existeDesde && existeHasta {
+			conservadas = append(conservadas, arista)
+		} else {
+			quitadas = append(quitadas, arista)
+		}
+	}
+	if len(quitadas) == 0 {
+		return nil
+	}
+
+	c.aristas = conservadas
+	c.indice = make(map[[2]string]int, len(conservadas))
+	for i, arista := range conservadas {
+		c.indice[[2]string{arista.Desde, arista.Hasta}] = i
+	}
+	return quitadas
+}
+
+// Construir valida las aristas y devuelve el grafo. En grafos no dirigidos
+// cada arista no dirigida queda seguida de su inversa, como con AgregarArista.
+func (c *ConstructorGrafo) Construir() (*Grafo, error) {
+	for _, arista := range c.aristas {
+		if _, existe := c.cuevas[arista.Desde]; !existe {
+			return nil, fmt.Errorf("error agregando arista %s->%s: cueva %s no existe", arista.Desde, arista.Hasta, arista.Desde)
+		}
+		if _, existe := c.cuevas[arista.Hasta]; !existe {
+			return nil, fmt.Errorf("error agregando arista %s->%s: cueva %s no existe", arista.Desde, arista.Hasta, arista.Hasta)
+		}
+	}
+
+	grafo := NuevoGrafo(c.esDirigido)
+	grafo.Cuevas = c.cuevas
+
+	if c.esDirigido {
+		if len(c.repetidas) > 0 {
+			par := c.repetidas[0]
+			return nil, fmt.Errorf("error agregando arista %s->%s: arista desde %s hasta %s ya existe", par[0], par[1], par[0], par[1])
+		}
+		grafo.Aristas = c.aristas
+		return grafo, nil
+	}
+
+	aristas := make([]*Arista, 0, 2*len(c.aristas))
+	emitidas := make([]bool, len(c.aristas))
+	for i, arista := range c.aristas {
+		if emitidas[i] {
+			continue
+		}
+		aristas = append(aristas, arista)
+		emitidas[i] = true
+		if arista.EsDirigido {
+			continue
+		}
+
+		// La inversa explícita ocupa el lugar de la generada automáticamente
+		j, existe := c.indice[[2]string{arista.Hasta, arista.Desde}]
+		switch {
+		case !existe:
+			aristas = append(aristas, arista.Reversa())
+		case emitidas[j]:
+			// Bucle sobre la misma cueva: ya emitida
+		case c.aristas[j].EsDirigido:
+			return nil, fmt.Errorf("error agregando arista %s->%s: arista desde %s hasta %s ya existe",
+				c.aristas[j].Desde, c.aristas[j].Hasta, c.aristas[j].Desde, c.aristas[j].Hasta)
+		default:
+			aristas = append(aristas, c.aristas[j])
+			emitidas[j] = true
+		}
+	}
+	grafo.Aristas = aristas
+	return grafo, nil
+}
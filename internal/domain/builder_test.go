@@ -0,0 +1,79 @@
+package domain
+
+import "testing"
+
+// TestConstructorTipoAlFinal verifica que el tipo puede definirse después de las aristas
+func TestConstructorTipoAlFinal(t *testing.T) {
+	constructor := NuevoConstructorGrafo()
+	constructor.AgregarArista(NuevaArista("A", "B", 3, false))
+	constructor.AgregarArista(&Arista{Desde: "B", Hasta: "A", Distancia: 5})
+	constructor.AgregarArista(NuevaArista("B", "C", 1, true))
+	for _, id := range []string{"A", "B", "C"} {
+		constructor.AgregarCueva(NuevaCueva(id, id))
+	}
+	constructor.EstablecerDirigido(false)
+
+	grafo, err := constructor.Construir()
+	if err != nil {
+		t.Fatalf("Error construyendo: %v", err)
+	}
+
+	// La inversa explícita conserva sus atributos y queda junto a su gemela
+	esperadas := []Arista{
+		{Desde: "A", Hasta: "B", Distancia: 3},
+		{Desde: "B", Hasta: "A", Distancia: 5},
+		{Desde: "B", Hasta: "C", Distancia: 1, EsDirigido: true},
+	}
+	if len(grafo.Aristas) != len(esperadas) {
+		t.Fatalf("Se esperaban %d aristas: %v", len(esperadas), grafo.Aristas)
+	}
+	for i, esperada := range esperadas {
+		if *grafo.Aristas[i] != esperada {
+			t.Errorf("arista %d: se esperaba %v y se obtuvo %v", i, esperada, *grafo.Aristas[i])
+		}
+	}
+}
+
+// TestConstructorParRepetido verifica que un par no dirigido repetido actualiza
+// todos los atributos del túnel
+func TestConstructorParRepetido(t *testing.T) {
+	constructor := NuevoConstructorGrafo()
+	constructor.AgregarCueva(NuevaCueva("A", "A"))
+	constructor.AgregarCueva(NuevaCueva("B", "B"))
+	constructor.AgregarArista(NuevaArista("A", "B", 1, false))
+	constructor.AgregarArista(&Arista{Desde: "A", Hasta: "B", Distancia: 2, EsObstruido: true, Capacidad: 4.5, EsAngosto: true})
+	constructor.EstablecerDirigido(false)
+
+	grafo, err := constructor.Construir()
+	if err != nil {
+		t.Fatalf("Error construyendo: %v", err)
+	}
+	esperada := Arista{Desde: "A", Hasta: "B", Distancia: 2, EsObstruido: true, Capacidad: 4.5, EsAngosto: true}
+	if len(grafo.Aristas) != 2 || *grafo.Aristas[0] != esperada {
+		t.Errorf("se esperaba %v y se obtuvo %v", esperada, grafo.Aristas)
+	}
+}
+
+// TestConstructorErrores verifica extremos inexistentes y duplicados en grafos dirigidos
+func TestConstructorErrores(t *testing.T) {
+	constructor := NuevoConstructorGrafo()
+	constructor.AgregarCueva(NuevaCueva("A", "A"))
+	constructor.AgregarArista(NuevaArista("A", "Z", 1, false))
+	if _, err := constructor.Construir(); err == nil {
+		t.Error("Se esperaba error por cueva inexistente")
+	}
+
+	constructor = NuevoConstructorGrafo()
+	constructor.AgregarCueva(NuevaCueva("A", "A"))
+	constructor.AgregarCueva(NuevaCueva("B", "B"))
+	constructor.AgregarArista(NuevaArista("A", "B", 1, false))
+	constructor.AgregarArista(NuevaArista("A", "B", 2, false))
+	constructor.EstablecerDirigido(true)
+	if _, err := constructor.Construir(); err == nil {
+		t.Error("Se esperaba error por arista duplicada en grafo dirigido")
+	}
+
+	if err := constructor.AgregarCueva(NuevaCueva("A", "otra")); err == nil {
+		t.Error("Se esperaba error por cueva duplicada")
+	}
+}
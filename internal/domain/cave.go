@@ -9,6 +9,8 @@ type Cueva struct {
 	Recursos map[string]int `json:"recursos" xml:"recursos"`
 	X        float64        `json:"x" xml:"x"` // Coordenada x
 	Y        float64        `json:"y" xml:"y"` // Coordenada y
+	// Stock deseado por recurso; nil si la cueva no declara demanda
+	Demanda map[string]int `json:"demanda,omitempty" xml:"demanda,omitempty"`
 }
 
 // Función para crear una nueva cueva
@@ -38,6 +40,34 @@ func (c *Cueva) ObtenerRecurso(recurso string) int {
 	return c.Recursos[recurso]
 }
 
+// EstablecerDemanda fija el stock deseado de un recurso; 0 o menos la elimina
+func (c *Cueva) EstablecerDemanda(recurso string, cantidad int) {
+	if cantidad <= 0 {
+		delete(c.Demanda, recurso)
+		return
+	}
+	if c.Demanda == nil {
+		c.Demanda = make(map[string]int)
+	}
+	c.Demanda[recurso] = cantidad
+}
+
+// ObtenerDemanda devuelve el stock deseado de un recurso, 0 si no lo demanda
+func (c *Cueva) ObtenerDemanda(recurso string) int {
+	if c.Demanda == nil {
+		return 0
+	}
+	return c.Demanda[recurso]
+}
+
+// Deficit devuelve cuánto le falta a la cueva para cubrir su demanda del recurso
+func (c *Cueva) Deficit(recurso string) int {
+	if faltante := c.ObtenerDemanda(recurso) - c.ObtenerRecurso(recurso); faltante > 0 {
+		return faltante
+	}
+	return 0
+}
+
 // Función para formatear los datos de la cueva
 func (c *Cueva) String() string {
 	return fmt.Sprintf("Cueva{ID: %s, Nombre: %s, Recursos: %v}", c.ID, c.Nombre, c.Recursos)
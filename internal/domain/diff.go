@@ -0,0 +1,637 @@
+package domain
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Tipos de operación de un parche
+const (
+	OpCambiarTipoGrafo = "cambiar_tipo_grafo"
+	OpAgregarCueva     = "agregar_cueva"
+	OpEliminarCueva    = "eliminar_cueva"
+	OpRenombrarCueva   = "renombrar_cueva"
+	OpMoverCueva       = "mover_cueva"
+	OpCambiarRecurso   = "cambiar_recurso"
+	OpCambiarDemanda   = "cambiar_demanda"
+	OpAgregarArista    = "agregar_arista"
+	OpEliminarArista   = "eliminar_arista"
+	OpModificarArista  = "modificar_arista"
+)
+
+// VersionFormatoParche versión del formato JSON de los parches
+const VersionFormatoParche = 1
+
+// ParcheGrafo es la lista ordenada de cambios que transforma un grafo en otro
+type ParcheGrafo struct {
+	Version     int               `json:"version"`
+	Operaciones []OperacionParche `json:"operaciones"`
+}
+
+// OperacionParche es un cambio atómico; guarda el estado anterior para
+// detectar conflictos al aplicarlo sobre un grafo distinto del original
+type OperacionParche struct {
+	Op string `json:"op"`
+
+	// Cuevas: estado completo al agregar o eliminar, ID en el resto
+	Cueva       *Cueva  `json:"cueva,omitempty"`
+	ID          string  `json:"id,omitempty"`
+	NombreAntes string  `json:"nombre_antes,omitempty"`
+	Nombre      string  `json:"nombre,omitempty"`
+	XAntes      float64 `json:"x_antes,omitempty"`
+	YAntes      float64 `json:"y_antes,omitempty"`
+	X           float64 `json:"x,omitempty"`
+	Y           float64 `json:"y,omitempty"`
+
+	// Recursos y demanda: nil indica que el recurso no existe
+	Recurso       string `json:"recurso,omitempty"`
+	CantidadAntes *int   `json:"cantidad_antes,omitempty"`
+	Cantidad      *int   `json:"cantidad,omitempty"`
+
+	// Aristas: AmbosSentidos aplica el cambio también a la arista inversa
+	Arista        *Arista `json:"arista,omitempty"`
+	AristaAntes   *Arista `json:"arista_antes,omitempty"`
+	AmbosSentidos bool    `json:"ambos_sentidos,omitempty"`
+
+	// Tipo de grafo
+	EsDirigido *bool `json:"es_dirigido,omitempty"`
+}
+
+// EstaVacio indica si el parche no contiene cambios
+func (p *ParcheGrafo) EstaVacio() bool {
+	return len(p.Operaciones) == 0
+}
+
+// JSON serializa el parche en su formato legible por máquina
+func (p *ParcheGrafo) JSON() ([]byte, error) {
+	return json.MarshalIndent(p, "", "  ")
+}
+
+// ParcheDesdeJSON lee un parche serializado con JSON
+func ParcheDesdeJSON(data []byte) (*ParcheGrafo, error) {
+	var parche ParcheGrafo
+	if err := json.Unmarshal(data, &parche); err != nil {
+		return nil, fmt.Errorf("error parseando el parche: %v", err)
+	}
+	if parche.Version > VersionFormatoParche {
+		return nil, fmt.Errorf("versión de parche no soportada: %d", parche.Version)
+	}
+	return &parche, nil
+}
+
+// CompararGrafos calcula el parche que transforma antes en despues
+func CompararGrafos(antes, despues *Grafo) *ParcheGrafo {
+	antes.mu.RLock()
+	defer antes.mu.RUnlock()
+	if despues != antes {
+		despues.mu.RLock()
+		defer despues.mu.RUnlock()
+	}
+
+	parche := &ParcheGrafo{Version: VersionFormatoParche, Operaciones: []OperacionParche{}}
+
+	if antes.EsDirigido != despues.EsDirigido {
+		esDirigido := despues.EsDirigido
+		parche.Operaciones = append(parche.Operaciones, OperacionParche{Op: OpCambiarTipoGrafo, EsDirigido: &esDirigido})
+	}
+
+	agregadas, eliminadas, modificadas := compararAristas(antes.Aristas, despues.Aristas)
+
+	// Orden de aplicación: quitar aristas, quitar cuevas, cambiar y agregar cuevas, agregar aristas
+	parche.Operaciones = append(parche.Operaciones, eliminadas...)
+
+	for _, id := range idsOrdenados(antes.Cuevas) {
+		if _, existe := despues.Cuevas[id]; !existe {
+			parche.Operaciones = append(parche.Operaciones, OperacionParche{Op: OpEliminarCueva, Cueva: copiarCueva(antes.Cuevas[id])})
+		}
+	}
+
+	for _, id := range idsOrdenados(despues.Cuevas) {
+		nueva := despues.Cuevas[id]
+		anterior, existe := antes.Cuevas[id]
+		if !existe {
+			parche.Operaciones = append(parche.Operaciones, OperacionParche{Op: OpAgregarCueva, Cueva: copiarCueva(nueva)})
+			continue
+		}
+		parche.Operaciones = append(parche.Operaciones, compararCuevas(anterior, nueva)...)
+	}
+
+	parche.Operaciones = append(parche.Operaciones, agregadas...)
+	parche.Operaciones = append(parche.Operaciones, modificadas...)
+	return parche
+}
+
+// cambios de nombre, posición y recursos de una cueva
+func compararCuevas(anterior, nueva *Cueva) []OperacionParche {
+	var operaciones []OperacionParche
+	if anterior.Nombre != nueva.Nombre {
+		operaciones = append(operaciones, OperacionParche{Op: OpRenombrarCueva, ID: nueva.ID, NombreAntes: anterior.Nombre, Nombre: nueva.Nombre})
+	}
+	if anterior.X != nueva.X || anterior.Y != nueva.Y {
+		operaciones = append(operaciones, OperacionParche{
+			Op: OpMoverCueva, ID: nueva.ID, XAntes: anterior.X, YAntes: anterior.Y, X: nueva.X, Y: nueva.Y,
+		})
+	}
+
+	operaciones = append(operaciones, compararCantidades(OpCambiarRecurso, nueva.ID, anterior.Recursos, nueva.Recursos)...)
+	operaciones = append(operaciones, compararCantidades(OpCambiarDemanda, nueva.ID, anterior.Demanda, nueva.Demanda)...)
+	return operaciones
+}
+
+// cambios por recurso entre dos mapas de cantidades (recursos o demanda)
+func compararCantidades(op, id string, anterior, nueva map[string]int) []OperacionParche {
+	var operaciones []OperacionParche
+	recursos := make(map[string]bool)
+	for recurso := range anterior {
+		recursos[recurso] = true
+	}
+	for recurso := range nueva {
+		recursos[recurso] = true
+	}
+	for _, recurso := range clavesOrdenadas(recursos) {
+		cantidadAntes, existiaAntes := anterior[recurso]
+		cantidad, existe := nueva[recurso]
+		if existiaAntes == existe && cantidadAntes == cantidad {
+			continue
+		}
+		operacion := OperacionParche{Op: op, ID: id, Recurso: recurso}
+		if existiaAntes {
+			operacion.CantidadAntes = &cantidadAntes
+		}
+		if existe {
+			operacion.Cantidad = &cantidad
+		}
+		operaciones = append(operaciones, operacion)
+	}
+	return operaciones
+}
+
+// compara las aristas dirección por dirección y fusiona los cambios
+// simétricos (los dos sentidos de un túnel no dirigido) en una sola operación
+func compararAristas(antes, despues []*Arista) (agregadas, eliminadas, modificadas []OperacionParche) {
+	indiceAntes := indexarAristas(antes)
+	indiceDespues := indexarAristas(despues)
+
+	claves := make(map[[2]string]bool)
+	for clave := range indiceAntes {
+		claves[clave] = true
+	}
+	for clave := range indiceDespues {
+		claves[clave] = true
+	}
+	ordenadas := make([][2]string, 0, len(claves))
+	for clave := range claves {
+		ordenadas = append(ordenadas, clave)
+	}
+	sort.Slice(ordenadas, func(i, j int) bool {
+		if ordenadas[i][0] != ordenadas[j][0] {
+			return ordenadas[i][0] < ordenadas[j][0]
+		}
+		return ordenadas[i][1] < ordenadas[j][1]
+	})
+
+	operaciones := make(map[[2]string]*OperacionParche)
+	var orden [][2]string
+	for _, clave := range ordenadas {
+		anterior, nueva := indiceAntes[clave], indiceDespues[clave]
+		var operacion OperacionParche
+		switch {
+		case anterior == nil:
+			operacion = OperacionParche{Op: OpAgregarArista, Arista: copiarArista(nueva)}
+		case nueva == nil:
+			operacion = OperacionParche{Op: OpEliminarArista, Arista: copiarArista(anterior)}
+		case !mismaArista(anterior, nueva):
+			operacion = OperacionParche{Op: OpModificarArista, AristaAntes: copiarArista(anterior), Arista: copiarArista(nueva)}
+		default:
+			continue
+		}
+
+		inversa := [2]string{clave[1], clave[0]}
+		if gemela, ok := operaciones[inversa]; ok && !gemela.AmbosSentidos && sonOperacionesGemelas(gemela, &operacion) {
+			gemela.AmbosSentidos = true
+			continue
+		}
+		operaciones[clave] = &operacion
+		orden = append(orden, clave)
+	}
+
+	for _, clave := range orden {
+		operacion := *operaciones[clave]
+		switch operacion.Op {
+		case OpAgregarArista:
+			agregadas = append(agregadas, operacion)
+		case OpEliminarArista:
+			eliminadas = append(eliminadas, operacion)
+		default:
+			modificadas = append(modificadas, operacion)
+		}
+	}
+	return agregadas, eliminadas, modificadas
+}
+
+// indica si b es el mismo cambio que a en el sentido inverso
+func sonOperacionesGemelas(a, b *OperacionParche) bool {
+	if a.Op != b.Op || a.Arista.Desde == a.Arista.Hasta {
+		return false
+	}
+	if !mismaArista(a.Arista.Reversa(), b.Arista) {
+		return false
+	}
+	if a.AristaAntes != nil {
+		return mismaArista(a.AristaAntes.Reversa(), b.AristaAntes)
+	}
+	return true
+}
+
+// AplicarParche aplica el parche de forma atómica: si alguna operación entra
+// en conflicto con el estado actual el grafo queda sin cambios
+func (g *Grafo) AplicarParche(parche *ParcheGrafo) error {
+	if parche == nil {
+		return fmt.Errorf("parche no puede ser nil")
+	}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	// Trabajar sobre copias y reemplazar al final
+	cuevas := make(map[string]*Cueva, len(g.Cuevas))
+	for id, cueva := range g.Cuevas {
+		cuevas[id] = copiarCueva(cueva)
+	}
+	aristas := make([]*Arista, 0, len(g.Aristas))
+	for _, arista := range g.Aristas {
+		aristas = append(aristas, copiarArista(arista))
+	}
+	esDirigido := g.EsDirigido
+
+	for i := range parche.Operaciones {
+		operacion := &parche.Operaciones[i]
+		var err error
+		switch operacion.Op {
+		case OpCambiarTipoGrafo:
+			if operacion.EsDirigido == nil {
+				err = fmt.Errorf("falta es_dirigido")
+			} else {
+				esDirigido = *operacion.EsDirigido
+			}
+		case OpAgregarCueva, OpEliminarCueva, OpRenombrarCueva, OpMoverCueva, OpCambiarRecurso, OpCambiarDemanda:
+			aristas, err = aplicarOperacionCueva(cuevas, aristas, operacion)
+		case OpAgregarArista, OpEliminarArista, OpModificarArista:
+			aristas, err = aplicarOperacionArista(cuevas, aristas, operacion)
+		default:
+			err = fmt.Errorf("operación desconocida")
+		}
+		if err != nil {
+			return fmt.Errorf("conflicto en la operación %d (%s): %v", i+1, operacion.Op, err)
+		}
+	}
+
+	g.Cuevas = cuevas
+	g.Aristas = aristas
+	g.EsDirigido = esDirigido
+	g.modificado(false)
+	return nil
+}
+
+// aplica una operación sobre cuevas; eliminar una cueva también elimina sus aristas
+func aplicarOperacionCueva(cuevas map[string]*Cueva, aristas []*Arista, operacion *OperacionParche) ([]*Arista, error) {
+	switch operacion.Op {
+	case OpAgregarCueva:
+		if operacion.Cueva == nil {
+			return aristas, fmt.Errorf("falta la cueva")
+		}
+		if _, existe := cuevas[operacion.Cueva.ID]; existe {
+			return aristas, fmt.Errorf("la cueva %s ya existe", operacion.Cueva.ID)
+		}
+		cuevas[operacion.Cueva.ID] = copiarCueva(operacion.Cueva)
+		return aristas, nil
+
+	case OpEliminarCueva:
+		if operacion.Cueva == nil {
+			return aristas, fmt.Errorf("falta la cueva")
+		}
+		actual, existe := cuevas[operacion.Cueva.ID]
+		if !existe {
+			return aristas, fmt.Errorf("la cueva %s no existe", operacion.Cueva.ID)
+		}
+		if !mismaCueva(actual, operacion.Cueva) {
+			return aristas, fmt.Errorf("la cueva %s cambió desde que se generó el parche", actual.ID)
+		}
+		delete(cuevas, actual.ID)
+		restantes := aristas[:0]
+		for _, arista := range aristas {
+			if arista.Desde != actual.ID && arista.Hasta != actual.ID {
+				restantes = append(restantes, arista)
+			}
+		}
+		return restantes, nil
+	}
+
+	cueva, existe := cuevas[operacion.ID]
+	if !existe {
+		return aristas, fmt.Errorf("la cueva %s no existe", operacion.ID)
+	}
+
+	switch operacion.Op {
+	case OpRenombrarCueva:
+		if cueva.Nombre != operacion.NombreAntes {
+			return aristas, fmt.Errorf("se esperaba el nombre '%s' en %s y hay '%s'", operacion.NombreAntes, cueva.ID, cueva.Nombre)
+		}
+		cueva.Nombre = operacion.Nombre
+
+	case OpMoverCueva:
+		if cueva.X != operacion.XAntes || cueva.Y != operacion.YAntes {
+			return aristas, fmt.Errorf("se esperaba %s en (%g, %g) y está en (%g, %g)", cueva.ID, operacion.XAntes, operacion.YAntes, cueva.X, cueva.Y)
+		}
+		cueva.X, cueva.Y = operacion.X, operacion.Y
+
+	case OpCambiarRecurso:
+		cantidad, existe := cueva.Recursos[operacion.Recurso]
+		if existe != (operacion.CantidadAntes != nil) || (existe && cantidad != *operacion.CantidadAntes) {
+			return aristas, fmt.Errorf("el recurso %s de %s cambió desde que se generó el parche", operacion.Recurso, cueva.ID)
+		}
+		if operacion.Cantidad == nil {
+			delete(cueva.Recursos, operacion.Recurso)
+		} else {
+			cueva.AgregarRecurso(operacion.Recurso, *operacion.Cantidad)
+		}
+
+	case OpCambiarDemanda:
+		cantidad, existe := cueva.Demanda[operacion.Recurso]
+		if existe != (operacion.CantidadAntes != nil) || (existe && cantidad != *operacion.CantidadAntes) {
+			return aristas, fmt.Errorf("la demanda de %s en %s cambió desde que se generó el parche", operacion.Recurso, cueva.ID)
+		}
+		if operacion.Cantidad == nil {
+			delete(cueva.Demanda, operacion.Recurso)
+		} else {
+			if cueva.Demanda == nil {
+				cueva.Demanda = make(map[string]int)
+			}
+			cueva.Demanda[operacion.Recurso] = *operacion.Cantidad
+		}
+	}
+	return aristas, nil
+}
+
+// aplica una operación sobre aristas (y su inversa si corresponde)
+func aplicarOperacionArista(cuevas map[string]*Cueva, aristas []*Arista, operacion *OperacionParche) ([]*Arista, error) {
+	if operacion.Arista == nil {
+		return aristas, fmt.Errorf("falta la arista")
+	}
+
+	objetivos := []*Arista{operacion.Arista}
+	anteriores := []*Arista{operacion.AristaAntes}
+	if operacion.AmbosSentidos {
+		objetivos = append(objetivos, operacion.Arista.Reversa())
+		if operacion.AristaAntes != nil {
+			anteriores = append(anteriores, operacion.AristaAntes.Reversa())
+		} else {
+			anteriores = append(anteriores, nil)
+		}
+	}
+
+	for i, objetivo := range objetivos {
+		indice := buscarArista(aristas, objetivo.Desde, objetivo.Hasta)
+		switch operacion.Op {
+		case OpAgregarArista:
+			if indice >= 0 {
+				return aristas, fmt.Errorf("la arista %s -> %s ya existe", objetivo.Desde, objetivo.Hasta)
+			}
+			for _, id := range []string{objetivo.Desde, objetivo.Hasta} {
+				if _, existe := cuevas[id]; !existe {
+					return aristas, fmt.Errorf("la cueva %s no existe", id)
+				}
+			}
+			aristas = append(aristas, copiarArista(objetivo))
+
+		case OpEliminarArista:
+			if indice < 0 {
+				return aristas, fmt.Errorf("la arista %s -> %s no existe", objetivo.Desde, objetivo.Hasta)
+			}
+			if !mismaArista(aristas[indice], objetivo) {
+				return aristas, fmt.Errorf("la arista %s -> %s cambió desde que se generó el parche", objetivo.Desde, objetivo.Hasta)
+			}
+			aristas = append(aristas[:indice], aristas[indice+1:]...)
+
+		case OpModificarArista:
+			if indice < 0 {
+				return aristas, fmt.Errorf("la arista %s -> %s no existe", objetivo.Desde, objetivo.Hasta)
+			}
+			if anteriores[i] == nil || !mismaArista(aristas[indice], anteriores[i]) {
+				return aristas, fmt.Errorf("la arista %s -> %s cambió desde que se generó el parche", objetivo.Desde, objetivo.Hasta)
+			}
+			aristas[indice] = copiarArista(objetivo)
+		}
+	}
+	return aristas, nil
+}
+
+// Reporte genera un resumen legible de los cambios del parche
+func (p *ParcheGrafo) Reporte() string {
+	if p.EstaVacio() {
+		return "Sin cambios\n"
+	}
+
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("%d cambio(s):\n", len(p.Operaciones)))
+	for _, operacion := range p.Operaciones {
+		sb.WriteString(operacion.describir())
+		sb.WriteString("\n")
+	}
+	return sb.String()
+}
+
+// descripción de una operación en una línea
+func (o *OperacionParche) describir() string {
+	switch o.Op {
+	case OpCambiarTipoGrafo:
+		if o.EsDirigido != nil && *o.EsDirigido {
+			return "~ grafo: ahora es dirigido"
+		}
+		return "~ grafo: ahora es no dirigido"
+	case OpAgregarCueva:
+		return fmt.Sprintf("+ cueva %s '%s' en (%g, %g)%s", o.Cueva.ID, o.Cueva.Nombre, o.Cueva.X, o.Cueva.Y, describirRecursos(o.Cueva.Recursos))
+	case OpEliminarCueva:
+		return fmt.Sprintf("- cueva %s '%s'", o.Cueva.ID, o.Cueva.Nombre)
+	case OpRenombrarCueva:
+		return fmt.Sprintf("~ cueva %s: nombre '%s' -> '%s'", o.ID, o.NombreAntes, o.Nombre)
+	case OpMoverCueva:
+		return fmt.Sprintf("~ cueva %s: movida de (%g, %g) a (%g, %g)", o.ID, o.XAntes, o.YAntes, o.X, o.Y)
+	case OpCambiarRecurso:
+		switch {
+		case o.CantidadAntes == nil:
+			return fmt.Sprintf("~ cueva %s: recurso %s agregado (%d)", o.ID, o.Recurso, *o.Cantidad)
+		case o.Cantidad == nil:
+			return fmt.Sprintf("~ cueva %s: recurso %s eliminado (tenía %d)", o.ID, o.Recurso, *o.CantidadAntes)
+		default:
+			return fmt.Sprintf("~ cueva %s: recurso %s %d -> %d", o.ID, o.Recurso, *o.CantidadAntes, *o.Cantidad)
+		}
+	case OpCambiarDemanda:
+		switch {
+		case o.CantidadAntes == nil:
+			return fmt.Sprintf("~ cueva %s: demanda de %s agregada (%d)", o.ID, o.Recurso, *o.Cantidad)
+		case o.Cantidad == nil:
+			return fmt.Sprintf("~ cueva %s: demanda de %s eliminada (era %d)", o.ID, o.Recurso, *o.CantidadAntes)
+		default:
+			return fmt.Sprintf("~ cueva %s: demanda de %s %d -> %d", o.ID, o.Recurso, *o.CantidadAntes, *o.Cantidad)
+		}
+	case OpAgregarArista:
+		return fmt.Sprintf("+ túnel %s (%g)%s", o.describirTunel(), o.Arista.Distancia, describirEstadoArista(o.Arista))
+	case OpEliminarArista:
+		return fmt.Sprintf("- túnel %s", o.describirTunel())
+	case OpModificarArista:
+		var cambios []string
+		if o.AristaAntes.Distancia != o.Arista.Distancia {
+			cambios = append(cambios, fmt.Sprintf("distancia %g -> %g", o.AristaAntes.Distancia, o.Arista.Distancia))
+		}
+		if o.AristaAntes.EsDirigido != o.Arista.EsDirigido {
+			cambios = append(cambios, fmt.Sprintf("dirigido %s -> %s", siNo(o.AristaAntes.EsDirigido), siNo(o.Arista.EsDirigido)))
+		}
+		if o.AristaAntes.EsObstruido != o.Arista.EsObstruido {
+			cambios = append(cambios, fmt.Sprintf("obstruido %s -> %s", siNo(o.AristaAntes.EsObstruido), siNo(o.Arista.EsObstruido)))
+		}
+		if o.AristaAntes.Capacidad != o.Arista.Capacidad {
+			cambios = append(cambios, fmt.Sprintf("capacidad %g -> %g", o.AristaAntes.Capacidad, o.Arista.Capacidad))
+		}
+		if o.AristaAntes.EsAngosto != o.Arista.EsAngosto {
+			cambios = append(cambios, fmt.Sprintf("angosto %s -> %s", siNo(o.AristaAntes.EsAngosto), siNo(o.Arista.EsAngosto)))
+		}
+		return fmt.Sprintf("~ túnel %s: %s", o.describirTunel(), strings.Join(cambios, ", "))
+	}
+	return fmt.Sprintf("? operación desconocida '%s'", o.Op)
+}
+
+// extremos del túnel con la flecha según los sentidos afectados
+func (o *OperacionParche) describirTunel() string {
+	if o.AmbosSentidos {
+		return fmt.Sprintf("%s <-> %s", o.Arista.Desde, o.Arista.Hasta)
+	}
+	return fmt.Sprintf("%s -> %s", o.Arista.Desde, o.Arista.Hasta)
+}
+
+// Funciones auxiliares
+
+func describirRecursos(recursos map[string]int) string {
+	if len(recursos) == 0 {
+		return ""
+	}
+	nombres := make(map[string]bool)
+	for recurso := range recursos {
+		nombres[recurso] = true
+	}
+	var partes []string
+	for _, recurso := range clavesOrdenadas(nombres) {
+		partes = append(partes, fmt.Sprintf("%s=%d", recurso, recursos[recurso]))
+	}
+	return " [" + strings.Join(partes, ", ") + "]"
+}
+
+func describirEstadoArista(arista *Arista) string {
+	var estados []string
+	if arista.EsDirigido {
+		estados = append(estados, "dirigido")
+	}
+	if arista.EsObstruido {
+		estados = append(estados, "obstruido")
+	}
+	if arista.Capacidad != 0 {
+		estados = append(estados, fmt.Sprintf("capacidad %g", arista.Capacidad))
+	}
+	if arista.EsAngosto {
+		estados = append(estados, "angosto")
+	}
+	if len(estados) == 0 {
+		return ""
+	}
+	return " [" + strings.Join(estados, ", ") + "]"
+}
+
+func siNo(valor bool) string {
+	if valor {
+		return "sí"
+	}
+	return "no"
+}
+
+func indexarAristas(aristas []*Arista) map[[2]string]*Arista {
+	indice := make(map[[2]string]*Arista, len(aristas))
+	for _, arista := range aristas {
+		indice[[2]string{arista.Desde, arista.Hasta}] = arista
+	}
+	return indice
+}
+
+func buscarArista(aristas []*Arista, desde, hasta string) int {
+	for i, arista := range aristas {
+		if arista.Desde == desde && arista.Hasta == hasta {
+			return i
+		}
+	}
+	return -1
+}
+
+func mismaArista(a, b *Arista) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
+
+func mismaCueva(a, b *Cueva) bool {
+	if a.ID != b.ID || a.Nombre != b.Nombre || a.X != b.X || a.Y != b.Y {
+		return false
+	}
+	return mismasCantidades(a.Recursos, b.Recursos) && mismasCantidades(a.Demanda, b.Demanda)
+}
+
+func mismasCantidades(a, b map[string]int) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for recurso, cantidad := range a {
+		if otra, existe := b[recurso]; !existe || otra != cantidad {
+			return false
+		}
+	}
+	return true
+}
+
+func copiarCueva(cueva *Cueva) *Cueva {
+	copia := NuevaCueva(cueva.ID, cueva.Nombre)
+	copia.X, copia.Y = cueva.X, cueva.Y
+	for recurso, cantidad := range cueva.Recursos {
+		copia.Recursos[recurso] = cantidad
+	}
+	if cueva.Demanda != nil {
+		copia.Demanda = make(map[string]int, len(cueva.Demanda))
+		for recurso, cantidad := range cueva.Demanda {
+			copia.Demanda[recurso] = cantidad
+		}
+	}
+	return copia
+}
+
+func copiarArista(arista *Arista) *Arista {
+	copia := *arista
+	return &copia
+}
+
+func idsOrdenados(cuevas map[string]*Cueva) []string {
+	ids := make([]string, 0, len(cuevas))
+	for id := range cuevas {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+func clavesOrdenadas(conjunto map[string]bool) []string {
+	claves := make([]string, 0, len(conjunto))
+	for clave := range conjunto {
+		claves = append(claves, clave)
+	}
+	sort.Strings(claves)
+	return claves
+}
@@ -0,0 +1,161 @@
+package domain
+
+import (
+	"fmt"
+	"math/rand"
+	"strings"
+	"testing"
+)
+
+// grafo de prueba con cuevas, recursos y túneles
+func grafoDePrueba(r *rand.Rand, esDirigido bool) *Grafo {
+	grafo := NuevoGrafo(esDirigido)
+	for i := 0; i < 6; i++ {
+		cueva := NuevaCueva(fmt.Sprintf("C%d", i), fmt.Sprintf("Cueva %d", i))
+		cueva.X, cueva.Y = float64(r.Intn(100)), float64(r.Intn(100))
+		if r.Intn(2) == 0 {
+			cueva.AgregarRecurso("agua", r.Intn(10))
+		}
+		if r.Intn(3) == 0 {
+			cueva.EstablecerDemanda("agua", 5+r.Intn(10))
+		}
+		grafo.AgregarCueva(cueva)
+	}
+	for i := 0; i < 8; i++ {
+		desde, hasta := fmt.Sprintf("C%d", r.Intn(6)), fmt.Sprintf("C%d", r.Intn(6))
+		if desde == hasta || grafo.ExisteConexion(hasta, desde) {
+			continue
+		}
+		grafo.AgregarArista(NuevaArista(desde, hasta, float64(1+r.Intn(20)), r.Intn(4) == 0))
+	}
+	return grafo
+}
+
+// copia profunda del grafo
+func clonarGrafo(grafo *Grafo) *Grafo {
+	copia := NuevoGrafo(grafo.EsDirigido)
+	for id, cueva := range grafo.Cuevas {
+		copia.Cuevas[id] = copiarCueva(cueva)
+	}
+	for _, arista := range grafo.Aristas {
+		copia.Aristas = append(copia.Aristas, copiarArista(arista))
+	}
+	return copia
+}
+
+// aplica cambios aleatorios de todos los tipos
+func mutarGrafo(r *rand.Rand, grafo *Grafo) {
+	ids := idsOrdenados(grafo.Cuevas)
+	for i := 0; i < 4; i++ {
+		cueva := grafo.Cuevas[ids[r.Intn(len(ids))]]
+		switch r.Intn(6) {
+		case 0:
+			cueva.X += 1.5
+		case 1:
+			cueva.AgregarRecurso("oro", r.Intn(5))
+		case 2:
+			delete(cueva.Recursos, "agua")
+		case 3:
+			cueva.Nombre += " (renombrada)"
+		case 4:
+			cueva.EstablecerDemanda("agua", 1+r.Intn(20))
+		case 5:
+			cueva.EstablecerDemanda("agua", 0)
+		}
+	}
+
+	if len(grafo.Aristas) > 0 {
+		arista := grafo.Aristas[r.Intn(len(grafo.Aristas))]
+		grafo.EliminarArista(arista)
+		if inversa, ok := grafo.ObtenerConexion(arista.Hasta, arista.Desde); ok && r.Intn(2) == 0 {
+			grafo.EliminarArista(inversa)
+		}
+	}
+	for _, arista := range grafo.Aristas {
+		if r.Intn(3) == 0 {
+			arista.EsObstruido = !arista.EsObstruido
+			arista.Distancia++
+		}
+	}
+
+	grafo.EliminarCueva(ids[r.Intn(len(ids))])
+	nueva := NuevaCueva("N1", "Nueva")
+	nueva.AgregarRecurso("gas", 3)
+	grafo.AgregarCueva(nueva)
+	for id := range grafo.Cuevas {
+		if id != "N1" {
+			grafo.AgregarArista(NuevaArista("N1", id, 7, false))
+			break
+		}
+	}
+	if r.Intn(5) == 0 {
+		grafo.EsDirigido = !grafo.EsDirigido
+	}
+}
+
+// TestCompararYAplicarParche verifica que aplicar el diff reproduce el grafo destino
+func TestCompararYAplicarParche(t *testing.T) {
+	r := rand.New(rand.NewSource(11))
+	for i := 0; i < 200; i++ {
+		antes := grafoDePrueba(r, r.Intn(2) == 0)
+		despues := clonarGrafo(antes)
+		mutarGrafo(r, despues)
+
+		parche := CompararGrafos(antes, despues)
+
+		// Ida y vuelta por JSON
+		data, err := parche.JSON()
+		if err != nil {
+			t.Fatalf("iteración %d: error serializando: %v", i, err)
+		}
+		leido, err := ParcheDesdeJSON(data)
+		if err != nil {
+			t.Fatalf("iteración %d: error leyendo: %v", i, err)
+		}
+
+		if err := antes.AplicarParche(leido); err != nil {
+			t.Fatalf("iteración %d: error aplicando:\n%s\n%v", i, parche.Reporte(), err)
+		}
+		if resto := CompararGrafos(antes, despues); !resto.EstaVacio() {
+			t.Fatalf("iteración %d: quedan diferencias tras aplicar:\n%s", i, resto.Reporte())
+		}
+	}
+}
+
+// TestParcheTunelNoDirigido verifica que los dos sentidos de un túnel se reportan como uno
+func TestParcheTunelNoDirigido(t *testing.T) {
+	antes := NuevoGrafo(false)
+	antes.AgregarCueva(NuevaCueva("A", "A"))
+	antes.AgregarCueva(NuevaCueva("B", "B"))
+	despues := clonarGrafo(antes)
+	despues.AgregarConexion("A", "B", 4)
+
+	parche := CompararGrafos(antes, despues)
+	if len(parche.Operaciones) != 1 || !parche.Operaciones[0].AmbosSentidos {
+		t.Fatalf("Se esperaba una sola operación en ambos sentidos:\n%s", parche.Reporte())
+	}
+	if !strings.Contains(parche.Reporte(), "+ túnel A <-> B (4)") {
+		t.Errorf("Reporte inesperado:\n%s", parche.Reporte())
+	}
+}
+
+// TestAplicarParcheConflicto verifica que un conflicto deja el grafo sin cambios
+func TestAplicarParcheConflicto(t *testing.T) {
+	antes := NuevoGrafo(true)
+	antes.AgregarCueva(NuevaCueva("A", "A"))
+	despues := clonarGrafo(antes)
+	despues.Cuevas["A"].X = 5
+	despues.AgregarCueva(NuevaCueva("B", "B"))
+	parche := CompararGrafos(antes, despues)
+
+	// La cueva se movió en el destino antes de aplicar
+	objetivo := clonarGrafo(antes)
+	objetivo.Cuevas["A"].X = 2
+	err := objetivo.AplicarParche(parche)
+	if err == nil || !strings.Contains(err.Error(), "conflicto") {
+		t.Fatalf("Se esperaba un conflicto, se obtuvo: %v", err)
+	}
+	if _, existe := objetivo.Cuevas["B"]; existe || objetivo.Cuevas["A"].X != 2 {
+		t.Error("El grafo no debe modificarse cuando el parche falla")
+	}
+}
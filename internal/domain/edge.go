@@ -4,20 +4,22 @@ import "fmt"
 
 // Representación de conexión entre cuevas (arista)
 type Arista struct {
-	Desde        string  `json:"desde" xml:"desde"`
-	Hasta        string  `json:"hasta" xml:"hasta"`
-	Distancia    float64 `json:"distancia" xml:"distancia"`
-	EsDirigido   bool    `json:"es_dirigido" xml:"es_dirigido"`
-	EsObstruido  bool    `json:"es_obstruido" xml:"es_obstruido"`
+	Desde       string  `json:"desde" xml:"desde"`
+	Hasta       string  `json:"hasta" xml:"hasta"`
+	Distancia   float64 `json:"distancia" xml:"distancia"`
+	EsDirigido  bool    `json:"es_dirigido" xml:"es_dirigido"`
+	EsObstruido bool    `json:"es_obstruido" xml:"es_obstruido"`
+	Capacidad   float64 `json:"capacidad,omitempty" xml:"capacidad,omitempty"`   // Camiones por hora; 0 si no está definida
+	EsAngosto   bool    `json:"es_angosto,omitempty" xml:"es_angosto,omitempty"` // De un solo carril: un camión a la vez
 }
 
 // Función para crear una nueva arista
 func NuevaArista(desde, hasta string, distancia float64, esDirigido bool) *Arista {
 	return &Arista{
-		Desde:        desde,
-		Hasta:        hasta,
-		Distancia:    distancia,
-		EsDirigido:   esDirigido,
+		Desde:       desde,
+		Hasta:       hasta,
+		Distancia:   distancia,
+		EsDirigido:  esDirigido,
 		EsObstruido: false,
 	}
 }
@@ -25,11 +27,13 @@ func NuevaArista(desde, hasta string, distancia float64, esDirigido bool) *Arist
 // Función para devolver nueva arista inversa
 func (a *Arista) Reversa() *Arista {
 	return &Arista{
-		Desde:        a.Hasta,
-		Hasta:        a.Desde,
-		Distancia:    a.Distancia,
-		EsDirigido:   a.EsDirigido,
-		EsObstruido:  a.EsObstruido,
+		Desde:       a.Hasta,
+		Hasta:       a.Desde,
+		Distancia:   a.Distancia,
+		EsDirigido:  a.EsDirigido,
+		EsObstruido: a.EsObstruido,
+		Capacidad:   a.Capacidad,
+		EsAngosto:   a.EsAngosto,
 	}
 }
 
@@ -43,6 +47,12 @@ func (a *Arista) String() string {
 	if a.EsObstruido {
 		estado = "obstruido"
 	}
+	if a.Capacidad > 0 {
+		estado += fmt.Sprintf(", capacidad: %.2f", a.Capacidad)
+	}
+	if a.EsAngosto {
+		estado += ", angosto"
+	}
 	return fmt.Sprintf("Arista{%s -> %s, distancia: %.2f, %s, %s}",
 		a.Desde, a.Hasta, a.Distancia, direccion, estado)
-}
\ No newline at end of file
+}
@@ -0,0 +1,234 @@
+package domain
+
+import (
+	"fmt"
+	"strings"
+)
+
+// PoliticaConflicto define qué hacer cuando una cueva importada tiene el ID de una existente
+type PoliticaConflicto string
+
+const (
+	// ConflictoOmitir conserva la cueva existente; los túneles importados se conectan a ella
+	ConflictoOmitir PoliticaConflicto = "omitir"
+	// ConflictoSobrescribir reemplaza nombre, coordenadas y recursos, y los atributos de los túneles repetidos
+	ConflictoSobrescribir PoliticaConflicto = "sobrescribir"
+	// ConflictoRenombrar importa la cueva con un ID libre (ID_2, ID_3, ...)
+	ConflictoRenombrar PoliticaConflicto = "renombrar"
+	// ConflictoSumarRecursos conserva la cueva existente sumándole los recursos y la demanda importados
+	ConflictoSumarRecursos PoliticaConflicto = "sumar_recursos"
+)
+
+// CosturaFusion es un túnel nuevo entre una cueva del grafo destino y una del importado
+type CosturaFusion struct {
+	Desde      string  `json:"desde"` // ID en el grafo destino
+	Hasta      string  `json:"hasta"` // ID original en el grafo importado
+	Distancia  float64 `json:"distancia"`
+	EsDirigido bool    `json:"es_dirigido"`
+}
+
+// OpcionesFusion configura cómo se importan las cuevas de otro grafo
+type OpcionesFusion struct {
+	Prefijo  string            `json:"prefijo,omitempty"`  // Se antepone a los IDs importados
+	Remapeo  map[string]string `json:"remapeo,omitempty"`  // ID importado -> ID destino; tiene prioridad sobre el prefijo
+	Politica PoliticaConflicto `json:"politica,omitempty"` // Vacía equivale a ConflictoOmitir
+	Costuras []CosturaFusion   `json:"costuras,omitempty"`
+}
+
+// ConflictoFusion describe una cueva o túnel importado que ya existía
+type ConflictoFusion struct {
+	IDImportado string            `json:"id_importado"`
+	IDDestino   string            `json:"id_destino"`
+	Politica    PoliticaConflicto `json:"politica"`
+	Detalle     string            `json:"detalle"`
+}
+
+// ReporteFusion resume el resultado de una fusión
+type ReporteFusion struct {
+	IDs                 map[string]string `json:"ids"` // ID importado -> ID final
+	CuevasAgregadas     []string          `json:"cuevas_agregadas"`
+	Conflictos          []ConflictoFusion `json:"conflictos,omitempty"`
+	AristasAgregadas    int               `json:"aristas_agregadas"`
+	AristasActualizadas int               `json:"aristas_actualizadas"`
+	AristasOmitidas     int               `json:"aristas_omitidas"`
+	CosturasAgregadas   int               `json:"costuras_agregadas"`
+}
+
+// Fusionar importa las cuevas y túneles de otro grafo. Es atómica: si la
+// política o alguna costura no son válidas el grafo queda sin cambios.
+func (g *Grafo) Fusionar(otro *Grafo, opciones OpcionesFusion) (*ReporteFusion, error) {
+	if otro == nil {
+		return nil, fmt.Errorf("el grafo a fusionar no puede ser nil")
+	}
+	if otro == g {
+		return nil, fmt.Errorf("no se puede fusionar un grafo consigo mismo")
+	}
+
+	politica := opciones.Politica
+	if politica == "" {
+		politica = ConflictoOmitir
+	}
+	switch politica {
+	case ConflictoOmitir, ConflictoSobrescribir, ConflictoRenombrar, ConflictoSumarRecursos:
+	default:
+		return nil, fmt.Errorf("política de conflicto desconocida: %s", politica)
+	}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	otro.mu.RLock()
+	defer otro.mu.RUnlock()
+
+	// Trabajar sobre copias y reemplazar al final
+	cuevas := make(map[string]*Cueva, len(g.Cuevas)+len(otro.Cuevas))
+	for id, cueva := range g.Cuevas {
+		cuevas[id] = copiarCueva(cueva)
+	}
+	aristas := make([]*Arista, 0, len(g.Aristas)+len(otro.Aristas))
+	indice := make(map[[2]string]*Arista, cap(aristas))
+	for _, arista := range g.Aristas {
+		copia := copiarArista(arista)
+		aristas = append(aristas, copia)
+		indice[[2]string{copia.Desde, copia.Hasta}] = copia
+	}
+
+	reporte := &ReporteFusion{IDs: make(map[string]string, len(otro.Cuevas))}
+
+	// Cuevas, en orden de ID para que los renombres sean deterministas
+	for _, id := range idsOrdenados(otro.Cuevas) {
+		importada := copiarCueva(otro.Cuevas[id])
+		destino := opciones.Prefijo + id
+		if remapeado, ok := opciones.Remapeo[id]; ok {
+			destino = remapeado
+		}
+		if strings.TrimSpace(destino) == "" {
+			return nil, fmt.Errorf("la cueva importada %s queda con un ID vacío", id)
+		}
+
+		existente, existe := cuevas[destino]
+		if !existe {
+			importada.ID = destino
+			cuevas[destino] = importada
+			reporte.IDs[id] = destino
+			reporte.CuevasAgregadas = append(reporte.CuevasAgregadas, destino)
+			continue
+		}
+
+		conflicto := ConflictoFusion{IDImportado: id, IDDestino: destino, Politica: politica}
+		switch politica {
+		case ConflictoOmitir:
+			conflicto.Detalle = "se conserva la cueva existente"
+		case ConflictoSobrescribir:
+			importada.ID = destino
+			cuevas[destino] = importada
+			conflicto.Detalle = fmt.Sprintf("'%s' reemplazada por '%s'", existente.Nombre, importada.Nombre)
+		case ConflictoRenombrar:
+			destino = idLibre(cuevas, destino)
+			importada.ID = destino
+			cuevas[destino] = importada
+			reporte.CuevasAgregadas = append(reporte.CuevasAgregadas, destino)
+			conflicto.IDDestino = destino
+			conflicto.Detalle = fmt.Sprintf("importada como %s", destino)
+		case ConflictoSumarRecursos:
+			for recurso, cantidad := range importada.Recursos {
+				existente.Recursos[recurso] += cantidad
+			}
+			for recurso, cantidad := range importada.Demanda {
+				existente.EstablecerDemanda(recurso, existente.ObtenerDemanda(recurso)+cantidad)
+			}
+			conflicto.Detalle = "recursos sumados a la cueva existente" + describirRecursos(importada.Recursos)
+			if len(importada.Demanda) > 0 {
+				conflicto.Detalle += ", demanda" + describirRecursos(importada.Demanda)
+			}
+		}
+		reporte.IDs[id] = destino
+		reporte.Conflictos = append(reporte.Conflictos, conflicto)
+	}
+
+	// Túneles importados; los de un grafo dirigido siguen siendo de un solo sentido
+	for _, arista := range otro.Aristas {
+		importada := copiarArista(arista)
+		importada.Desde, importada.Hasta = reporte.IDs[arista.Desde], reporte.IDs[arista.Hasta]
+		if otro.EsDirigido {
+			importada.EsDirigido = true
+		}
+		if importada.Desde == "" || importada.Hasta == "" {
+			reporte.AristasOmitidas++
+			continue
+		}
+
+		clave := [2]string{importada.Desde, importada.Hasta}
+		if existente, existe := indice[clave]; existe {
+			if politica == ConflictoSobrescribir {
+				existente.Distancia = importada.Distancia
+				existente.EsObstruido = importada.EsObstruido
+				existente.Capacidad = importada.Capacidad
+				existente.EsAngosto = importada.EsAngosto
+				reporte.AristasActualizadas++
+			} else {
+				reporte.AristasOmitidas++
+			}
+			continue
+		}
+		aristas = append(aristas, importada)
+		indice[clave] = importada
+		reporte.AristasAgregadas++
+	}
+
+	// Costuras entre ambas redes
+	for i, costura := range opciones.Costuras {
+		hasta, ok := reporte.IDs[costura.Hasta]
+		if !ok {
+			return nil, fmt.Errorf("costura %d: la cueva %s no existe en el grafo importado", i+1, costura.Hasta)
+		}
+		if _, existe := cuevas[costura.Desde]; !existe {
+			return nil, fmt.Errorf("costura %d: la cueva %s no existe en el grafo destino", i+1, costura.Desde)
+		}
+		if _, existe := indice[[2]string{costura.Desde, hasta}]; existe {
+			return nil, fmt.Errorf("costura %d: el túnel %s -> %s ya existe", i+1, costura.Desde, hasta)
+		}
+
+		arista := NuevaArista(costura.Desde, hasta, costura.Distancia, costura.EsDirigido)
+		nuevas := []*Arista{arista}
+		if !g.EsDirigido && !arista.EsDirigido {
+			if _, existe := indice[[2]string{hasta, costura.Desde}]; !existe {
+				nuevas = append(nuevas, arista.Reversa())
+			}
+		}
+		for _, nueva := range nuevas {
+			aristas = append(aristas, nueva)
+			indice[[2]string{nueva.Desde, nueva.Hasta}] = nueva
+		}
+		reporte.CosturasAgregadas++
+	}
+
+	g.Cuevas = cuevas
+	g.Aristas = aristas
+	g.modificado(false)
+	return reporte, nil
+}
+
+// primer ID de la forma base_N que no está en uso
+func idLibre(cuevas map[string]*Cueva, base string) string {
+	for n := 2; ; n++ {
+		candidato := fmt.Sprintf("%s_%d", base, n)
+		if _, existe := cuevas[candidato]; !existe {
+			return candidato
+		}
+	}
+}
+
+// Reporte describe la fusión en texto legible
+func (r *ReporteFusion) Reporte() string {
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("%d cueva(s) agregada(s), %d conflicto(s)\n", len(r.CuevasAgregadas), len(r.Conflictos)))
+	for _, id := range r.CuevasAgregadas {
+		sb.WriteString(fmt.Sprintf("+ cueva %s\n", id))
+	}
+	for _, conflicto := range r.Conflictos {
+		sb.WriteString(fmt.Sprintf("! cueva %s -> %s (%s): %s\n", conflicto.IDImportado, conflicto.IDDestino, conflicto.Politica, conflicto.Detalle))
+	}
+	sb.WriteString(fmt.Sprintf("Túneles: %d agregado(s), %d actualizado(s), %d omitido(s) por repetidos; %d costura(s)\n",
+		r.AristasAgregadas, r.AristasActualizadas, r.AristasOmitidas, r.CosturasAgregadas))
+	return sb.String()
+}
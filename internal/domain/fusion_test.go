@@ -0,0 +1,147 @@
+package domain
+
+import (
+	"strings"
+	"testing"
+)
+
+// red principal A - B y sección nueva B - C con el ID B repetido
+func redesFusion() (*Grafo, *Grafo) {
+	principal := NuevoGrafo(false)
+	principal.AgregarCueva(NuevaCueva("A", "Entrada"))
+	b := NuevaCueva("B", "Galería")
+	b.AgregarRecurso("agua", 3)
+	principal.AgregarCueva(b)
+	principal.AgregarArista(NuevaArista("A", "B", 5, false))
+
+	seccion := NuevoGrafo(false)
+	bNueva := NuevaCueva("B", "Galería norte")
+	bNueva.AgregarRecurso("agua", 2)
+	bNueva.AgregarRecurso("oro", 1)
+	seccion.AgregarCueva(bNueva)
+	seccion.AgregarCueva(NuevaCueva("C", "Pozo"))
+	seccion.AgregarArista(NuevaArista("B", "C", 7, false))
+	return principal, seccion
+}
+
+// TestFusionarPoliticas verifica cada política ante un ID repetido
+func TestFusionarPoliticas(t *testing.T) {
+	casos := []struct {
+		politica PoliticaConflicto
+		idB      string
+		nombreB  string
+		agua     int
+		cuevas   int
+	}{
+		{ConflictoOmitir, "B", "Galería", 3, 3},
+		{ConflictoSobrescribir, "B", "Galería norte", 2, 3},
+		{ConflictoRenombrar, "B_2", "Galería norte", 2, 4},
+		{ConflictoSumarRecursos, "B", "Galería", 5, 3},
+	}
+
+	for _, caso := range casos {
+		principal, seccion := redesFusion()
+		reporte, err := principal.Fusionar(seccion, OpcionesFusion{Politica: caso.politica})
+		if err != nil {
+			t.Fatalf("%s: error al fusionar: %v", caso.politica, err)
+		}
+		if len(reporte.Conflictos) != 1 || reporte.Conflictos[0].IDDestino != caso.idB {
+			t.Errorf("%s: conflictos inesperados: %+v", caso.politica, reporte.Conflictos)
+		}
+		if reporte.IDs["B"] != caso.idB || principal.NumeroCuevas() != caso.cuevas {
+			t.Errorf("%s: IDs %v, %d cuevas", caso.politica, reporte.IDs, principal.NumeroCuevas())
+		}
+		cueva := principal.Cuevas[caso.idB]
+		if cueva.Nombre != caso.nombreB || cueva.Recursos["agua"] != caso.agua {
+			t.Errorf("%s: cueva %s inesperada: %+v", caso.politica, caso.idB, cueva)
+		}
+		if !principal.ExisteConexion(caso.idB, "C") || !principal.ExisteConexion("C", caso.idB) {
+			t.Errorf("%s: falta el túnel importado %s <-> C", caso.politica, caso.idB)
+		}
+	}
+}
+
+// TestFusionarSumaDemanda verifica que sumar recursos también suma la demanda importada
+func TestFusionarSumaDemanda(t *testing.T) {
+	principal, seccion := redesFusion()
+	principal.Cuevas["B"].EstablecerDemanda("agua", 10)
+	seccion.Cuevas["B"].EstablecerDemanda("agua", 4)
+	seccion.Cuevas["B"].EstablecerDemanda("oro", 2)
+
+	reporte, err := principal.Fusionar(seccion, OpcionesFusion{Politica: ConflictoSumarRecursos})
+	if err != nil {
+		t.Fatalf("Error al fusionar: %v", err)
+	}
+	if b := principal.Cuevas["B"]; b.ObtenerDemanda("agua") != 14 || b.ObtenerDemanda("oro") != 2 {
+		t.Errorf("Demanda inesperada: %v", b.Demanda)
+	}
+	if detalle := reporte.Conflictos[0].Detalle; !strings.Contains(detalle, "demanda [agua=4, oro=2]") {
+		t.Errorf("El detalle no informa la demanda sumada: %s", detalle)
+	}
+}
+
+// TestFusionarPrefijoYCosturas verifica el prefijo, el remapeo y los túneles de unión
+func TestFusionarPrefijoYCosturas(t *testing.T) {
+	principal, seccion := redesFusion()
+	reporte, err := principal.Fusionar(seccion, OpcionesFusion{
+		Prefijo:  "N-",
+		Remapeo:  map[string]string{"C": "POZO"},
+		Costuras: []CosturaFusion{{Desde: "B", Hasta: "B", Distancia: 2}},
+	})
+	if err != nil {
+		t.Fatalf("Error al fusionar: %v", err)
+	}
+	if len(reporte.Conflictos) != 0 || reporte.CosturasAgregadas != 1 || reporte.AristasAgregadas != 2 {
+		t.Errorf("Reporte inesperado: %+v", reporte)
+	}
+	for _, par := range [][2]string{{"N-B", "POZO"}, {"B", "N-B"}, {"N-B", "B"}} {
+		if !principal.ExisteConexion(par[0], par[1]) {
+			t.Errorf("Falta el túnel %s -> %s", par[0], par[1])
+		}
+	}
+	if !strings.Contains(reporte.Reporte(), "+ cueva N-B") {
+		t.Errorf("Reporte sin la cueva agregada:\n%s", reporte.Reporte())
+	}
+
+	// Las cuevas importadas son copias
+	seccion.Cuevas["B"].Nombre = "modificada"
+	if principal.Cuevas["N-B"].Nombre != "Galería norte" {
+		t.Error("La fusión no debe compartir cuevas con el grafo importado")
+	}
+}
+
+// TestFusionarAtomica verifica que una costura inválida no modifica el grafo
+func TestFusionarAtomica(t *testing.T) {
+	principal, seccion := redesFusion()
+	_, err := principal.Fusionar(seccion, OpcionesFusion{
+		Prefijo:  "N-",
+		Costuras: []CosturaFusion{{Desde: "A", Hasta: "Z", Distancia: 1}},
+	})
+	if err == nil || !strings.Contains(err.Error(), "costura 1") {
+		t.Fatalf("Se esperaba un error en la costura: %v", err)
+	}
+	if principal.NumeroCuevas() != 2 || principal.NumeroAristas() != 2 {
+		t.Errorf("El grafo no debe cambiar: %v", principal)
+	}
+
+	if _, err := principal.Fusionar(seccion, OpcionesFusion{Politica: "fusionar"}); err == nil {
+		t.Error("Se esperaba un error por política desconocida")
+	}
+}
+
+// TestFusionarDirigidoEnNoDirigido verifica que los túneles de un grafo dirigido conservan el sentido
+func TestFusionarDirigidoEnNoDirigido(t *testing.T) {
+	principal, _ := redesFusion()
+	dirigido := NuevoGrafo(true)
+	dirigido.AgregarCueva(NuevaCueva("X", "X"))
+	dirigido.AgregarCueva(NuevaCueva("Y", "Y"))
+	dirigido.AgregarArista(&Arista{Desde: "X", Hasta: "Y", Distancia: 1})
+
+	if _, err := principal.Fusionar(dirigido, OpcionesFusion{}); err != nil {
+		t.Fatalf("Error al fusionar: %v", err)
+	}
+	arista, ok := principal.ObtenerConexion("X", "Y")
+	if !ok || !arista.EsDirigido || principal.ExisteConexion("Y", "X") {
+		t.Errorf("El túnel X -> Y debe seguir siendo de un solo sentido: %v", arista)
+	}
+}
@@ -3,6 +3,7 @@ package domain
 import (
 	"fmt"
 	"sync"
+	"sync/atomic"
 )
 
 // Representación de grafo en el sistema
@@ -11,6 +12,8 @@ type Grafo struct {
 	Aristas    []*Arista         `json:"aristas" xml:"aristas"`
 	EsDirigido bool              `json:"es_dirigido" xml:"es_dirigido"`
 	mu         sync.RWMutex      // Operaciones concurrentes
+	version    atomic.Uint64     // Aumenta con cada modificación
+	indice     atomic.Pointer[indiceAdyacencia]
 }
 
 // Función para crear un nuevo grafo
@@ -32,6 +35,7 @@ func (g *Grafo) AgregarCueva(cueva *Cueva) error {
 	}
 
 	g.Cuevas[cueva.ID] = cueva
+	g.modificado(true)
 	return nil
 }
 
@@ -56,17 +60,19 @@ func (g *Grafo) AgregarArista(arista *Arista) error {
 		return fmt.Errorf("cueva %s no existe", arista.Hasta)
 	}
 	// Verificar que la arista aún no exista
-	for _, a := range g.Aristas {
-		if a.Desde == arista.Desde && a.Hasta == arista.Hasta {
-			return fmt.Errorf("arista desde %s hasta %s ya existe", arista.Desde, arista.Hasta)
-		}
+	indice := g.indiceVigente()
+	if _, existe := indice.pares[[2]string{arista.Desde, arista.Hasta}]; existe {
+		return fmt.Errorf("arista desde %s hasta %s ya existe", arista.Desde, arista.Hasta)
 	}
 	g.Aristas = append(g.Aristas, arista)
+	indice.agregar(arista)
 	// Si el grafo no es dirigido, agregar la arista inversa
 	if !g.EsDirigido && !arista.EsDirigido {
 		aristaInversa := arista.Reversa()
 		g.Aristas = append(g.Aristas, aristaInversa)
+		indice.agregar(aristaInversa)
 	}
+	g.modificado(true)
 	return nil
 }
 
@@ -81,15 +87,22 @@ func (g *Grafo) EliminarArista(aristaBorrar *Arista) error {
 	g.mu.Lock()
 	defer g.mu.Unlock()
 
-	for i, arista := range g.Aristas {
-		if arista.Desde == aristaBorrar.Desde && arista.Hasta == aristaBorrar.Hasta {
-			// Eliminar arista del slice
+	indice := g.indiceVigente()
+	arista, existe := indice.pares[[2]string{aristaBorrar.Desde, aristaBorrar.Hasta}]
+	if !existe {
+		return fmt.Errorf("arista no encontrada")
+	}
+
+	// Eliminar arista del slice
+	for i, a := range g.Aristas {
+		if a == arista {
 			g.Aristas = append(g.Aristas[:i], g.Aristas[i+1:]...)
-			return nil
+			break
 		}
 	}
-
-	return fmt.Errorf("arista no encontrada")
+	indice.quitar(arista)
+	g.modificado(true)
+	return nil
 }
 
 // EliminarCueva elimina una cueva del grafo junto con todas sus aristas
@@ -102,16 +115,21 @@ func (g *Grafo) EliminarCueva(id string) error {
 	}
 
 	// Eliminar todas las aristas relacionadas con la cueva
+	indice := g.indiceVigente()
 	nuevasAristas := []*Arista{}
 	for _, arista := range g.Aristas {
 		if arista.Desde != id && arista.Hasta != id {
 			nuevasAristas = append(nuevasAristas, arista)
 		}
 	}
+	for _, arista := range copiarLista(indice.incidentes[id], false) {
+		indice.quitar(arista)
+	}
 	g.Aristas = nuevasAristas
 
 	// Eliminar la cueva
 	delete(g.Cuevas, id)
+	g.modificado(true)
 	return nil
 }
 
@@ -132,8 +150,8 @@ func (g *Grafo) ObtenerVecinos(caveID string) []string {
 	defer g.mu.RUnlock()
 
 	var vecinos []string
-	for _, arista := range g.Aristas {
-		if arista.Desde == caveID && !arista.EsObstruido {
+	for _, arista := range g.indiceVigente().salientes[caveID] {
+		if !arista.EsObstruido {
 			vecinos = append(vecinos, arista.Hasta)
 		}
 	}
@@ -145,13 +163,7 @@ func (g *Grafo) ProximasAristas(caveID string) []*Arista {
 	g.mu.RLock()
 	defer g.mu.RUnlock()
 
-	var proximo []*Arista
-	for _, arista := range g.Aristas {
-		if arista.Hasta == caveID && !arista.EsObstruido {
-			proximo = append(proximo, arista)
-		}
-	}
-	return proximo
+	return copiarLista(g.indiceVigente().entrantes[caveID], true)
 }
 
 // Obtener aristas salientes de una cueva
@@ -159,13 +171,16 @@ func (g *Grafo) AristasSalientes(caveID string) []*Arista {
 	g.mu.RLock()
 	defer g.mu.RUnlock()
 
-	var saliente []*Arista
-	for _, arista := range g.Aristas {
-		if arista.Desde == caveID && !arista.EsObstruido {
-			saliente = append(saliente, arista)
-		}
-	}
-	return saliente
+	return copiarLista(g.indiceVigente().salientes[caveID], true)
+}
+
+// AristasIncidentes devuelve las aristas que salen o llegan a una cueva,
+// incluidas las obstruidas, en el mismo orden que Aristas
+func (g *Grafo) AristasIncidentes(caveID string) []*Arista {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	return copiarLista(g.indiceVigente().incidentes[caveID], false)
 }
 
 // Función para obtener número de cuevas
@@ -201,12 +216,8 @@ func (g *Grafo) ExisteConexion(desde, hasta string) bool {
 	g.mu.RLock()
 	defer g.mu.RUnlock()
 
-	for _, arista := range g.Aristas {
-		if arista.Desde == desde && arista.Hasta == hasta {
-			return true
-		}
-	}
-	return false
+	_, existe := g.indiceVigente().pares[[2]string{desde, hasta}]
+	return existe
 }
 
 // ObtenerConexion obtiene la arista entre dos cuevas
@@ -214,10 +225,29 @@ func (g *Grafo) ObtenerConexion(desde, hasta string) (*Arista, bool) {
 	g.mu.RLock()
 	defer g.mu.RUnlock()
 
-	for _, arista := range g.Aristas {
-		if arista.Desde == desde && arista.Hasta == hasta {
-			return arista, true
+	arista, existe := g.indiceVigente().pares[[2]string{desde, hasta}]
+	return arista, existe
+}
+
+// InvertirArista reemplaza la arista desde -> hasta por una copia en sentido
+// contrario en la misma posición y la devuelve
+func (g *Grafo) InvertirArista(desde, hasta string) (*Arista, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	arista, existe := g.indiceVigente().pares[[2]string{desde, hasta}]
+	if !existe {
+		return nil, fmt.Errorf("conexión desde %s hasta %s no existe", desde, hasta)
+	}
+
+	invertida := arista.Reversa()
+	for i, a := range g.Aristas {
+		if a == arista {
+			g.Aristas[i] = invertida
+			break
 		}
 	}
-	return nil, false
+	// La posición de la arista cambia de lista, así que se reindexa todo
+	g.modificado(false)
+	return invertida, nil
 }
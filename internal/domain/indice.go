@@ -0,0 +1,119 @@
+package domain
+
+// indiceAdyacencia indexa las aristas por ID de cueva. Las listas conservan el
+// orden de Grafo.Aristas, así que recorrerlas equivale a filtrar el slice.
+type indiceAdyacencia struct {
+	version    uint64                // Versión del grafo que refleja
+	salientes  map[string][]*Arista  // Aristas con Desde == ID
+	entrantes  map[string][]*Arista  // Aristas con Hasta == ID
+	incidentes map[string][]*Arista  // Salientes y entrantes intercaladas; los bucles una sola vez
+	pares      map[[2]string]*Arista // Primera arista de cada par desde -> hasta
+}
+
+// construye el índice de un slice de aristas en la versión indicada
+func construirIndice(aristas []*Arista, version uint64) *indiceAdyacencia {
+	indice := &indiceAdyacencia{
+		version:    version,
+		salientes:  make(map[string][]*Arista),
+		entrantes:  make(map[string][]*Arista),
+		incidentes: make(map[string][]*Arista),
+		pares:      make(map[[2]string]*Arista, len(aristas)),
+	}
+	for _, arista := range aristas {
+		indice.agregar(arista)
+	}
+	return indice
+}
+
+// agrega una arista al final de sus listas
+func (i *indiceAdyacencia) agregar(arista *Arista) {
+	i.salientes[arista.Desde] = append(i.salientes[arista.Desde], arista)
+	i.entrantes[arista.Hasta] = append(i.entrantes[arista.Hasta], arista)
+	i.incidentes[arista.Desde] = append(i.incidentes[arista.Desde], arista)
+	if arista.Hasta != arista.Desde {
+		i.incidentes[arista.Hasta] = append(i.incidentes[arista.Hasta], arista)
+	}
+
+	clave := [2]string{arista.Desde, arista.Hasta}
+	if _, existe := i.pares[clave]; !existe {
+		i.pares[clave] = arista
+	}
+}
+
+// quita una arista de sus listas conservando el orden del resto
+func (i *indiceAdyacencia) quitar(arista *Arista) {
+	quitarDeLista(i.salientes, arista.Desde, arista)
+	quitarDeLista(i.entrantes, arista.Hasta, arista)
+	quitarDeLista(i.incidentes, arista.Desde, arista)
+	if arista.Hasta != arista.Desde {
+		quitarDeLista(i.incidentes, arista.Hasta, arista)
+	}
+
+	// Si era la primera del par, la reemplaza la siguiente repetida
+	clave := [2]string{arista.Desde, arista.Hasta}
+	if i.pares[clave] != arista {
+		return
+	}
+	delete(i.pares, clave)
+	for _, otra := range i.salientes[arista.Desde] {
+		if otra.Hasta == arista.Hasta {
+			i.pares[clave] = otra
+			break
+		}
+	}
+}
+
+// quita una arista de la lista de una cueva
+func quitarDeLista(listas map[string][]*Arista, id string, arista *Arista) {
+	lista := listas[id]
+	for j, otra := range lista {
+		if otra == arista {
+			lista = append(lista[:j], lista[j+1:]...)
+			break
+		}
+	}
+	if len(lista) == 0 {
+		delete(listas, id)
+	} else {
+		listas[id] = lista
+	}
+}
+
+// indiceVigente devuelve el índice de las aristas actuales, reconstruyéndolo
+// si el grafo cambió de versión. Requiere mu tomado (lectura o escritura);
+// dos lectores pueden reconstruirlo a la vez sin riesgo.
+func (g *Grafo) indiceVigente() *indiceAdyacencia {
+	version := g.version.Load()
+	if indice := g.indice.Load(); indice != nil && indice.version == version {
+		return indice
+	}
+	indice := construirIndice(g.Aristas, version)
+	g.indice.Store(indice)
+	return indice
+}
+
+// modificado avanza la versión del grafo. Con mantenerIndice el método ya
+// actualizó el índice, que sigue vigente si lo estaba. Requiere mu tomado.
+func (g *Grafo) modificado(mantenerIndice bool) {
+	anterior := g.version.Add(1) - 1
+	if indice := g.indice.Load(); mantenerIndice && indice != nil && indice.version == anterior {
+		indice.version = anterior + 1
+	}
+}
+
+// MarcarModificado avisa que Cuevas o Aristas se cambiaron sin pasar por los
+// métodos del grafo, para que el índice de adyacencia se reconstruya
+func (g *Grafo) MarcarModificado() {
+	g.version.Add(1)
+}
+
+// copia de una lista del índice, filtrando las aristas obstruidas si se pide
+func copiarLista(lista []*Arista, sinObstruidas bool) []*Arista {
+	var copia []*Arista
+	for _, arista := range lista {
+		if !sinObstruidas || !arista.EsObstruido {
+			copia = append(copia, arista)
+		}
+	}
+	return copia
+}
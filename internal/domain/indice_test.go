@@ -0,0 +1,160 @@
+package domain
+
+import (
+	"reflect"
+	"testing"
+)
+
+// red dirigida A -> B -> C y A -> C, con un túnel no dirigido C - D
+func redIndice() *Grafo {
+	g := NuevoGrafo(true)
+	for _, id := range []string{"A", "B", "C", "D"} {
+		g.AgregarCueva(NuevaCueva(id, id))
+	}
+	g.AgregarArista(NuevaArista("A", "B", 1, true))
+	g.AgregarArista(NuevaArista("B", "C", 2, true))
+	g.AgregarArista(NuevaArista("A", "C", 4, true))
+	g.AgregarArista(NuevaArista("C", "D", 1, true))
+	g.AgregarArista(NuevaArista("D", "C", 1, true))
+	return g
+}
+
+// incidentesLineal filtra Aristas como lo hacían las consultas antes del índice
+func incidentesLineal(g *Grafo, id string) []*Arista {
+	var incidentes []*Arista
+	for _, arista := range g.Aristas {
+		if arista.Desde == id || arista.Hasta == id {
+			incidentes = append(incidentes, arista)
+		}
+	}
+	return incidentes
+}
+
+// verificarIndice compara cada consulta indexada con un recorrido lineal
+func verificarIndice(t *testing.T, g *Grafo, contexto string) {
+	t.Helper()
+	for id := range g.Cuevas {
+		if got, want := g.AristasIncidentes(id), incidentesLineal(g, id); !reflect.DeepEqual(got, want) {
+			t.Errorf("%s: incidentes de %s = %v, se esperaba %v", contexto, id, got, want)
+		}
+
+		var vecinos []string
+		for _, arista := range g.Aristas {
+			if arista.Desde == id && !arista.EsObstruido {
+				vecinos = append(vecinos, arista.Hasta)
+			}
+		}
+		if got := g.ObtenerVecinos(id); !reflect.DeepEqual(got, vecinos) {
+			t.Errorf("%s: vecinos de %s = %v, se esperaba %v", contexto, id, got, vecinos)
+		}
+
+		for otro := range g.Cuevas {
+			existe := false
+			for _, arista := range g.Aristas {
+				if arista.Desde == id && arista.Hasta == otro {
+					existe = true
+					break
+				}
+			}
+			if g.ExisteConexion(id, otro) != existe {
+				t.Errorf("%s: ExisteConexion(%s, %s) debería ser %t", contexto, id, otro, existe)
+			}
+		}
+	}
+}
+
+// TestIndiceOperaciones verifica el índice tras cada operación del grafo
+func TestIndiceOperaciones(t *testing.T) {
+	g := redIndice()
+	verificarIndice(t, g, "inicial")
+
+	if err := g.AgregarArista(NuevaArista("A", "B", 9, true)); err == nil {
+		t.Error("Se esperaba un error por arista repetida")
+	}
+
+	arista, _ := g.ObtenerConexion("A", "C")
+	if err := g.EliminarArista(arista); err != nil {
+		t.Fatalf("Error al eliminar arista: %v", err)
+	}
+	verificarIndice(t, g, "eliminar arista")
+
+	invertida, err := g.InvertirArista("B", "C")
+	if err != nil {
+		t.Fatalf("Error al invertir arista: %v", err)
+	}
+	if invertida.Desde != "C" || !g.ExisteConexion("C", "B") || g.ExisteConexion("B", "C") {
+		t.Errorf("Inversión inesperada: %v", invertida)
+	}
+	verificarIndice(t, g, "invertir arista")
+
+	g.Aristas[0].EsObstruido = true
+	verificarIndice(t, g, "obstruir")
+
+	if err := g.EliminarCueva("C"); err != nil {
+		t.Fatalf("Error al eliminar cueva: %v", err)
+	}
+	verificarIndice(t, g, "eliminar cueva")
+	if len(g.AristasIncidentes("C")) != 0 || len(g.AristasIncidentes("D")) != 0 {
+		t.Error("No deben quedar aristas de la cueva eliminada")
+	}
+}
+
+// TestIndiceModificacionDirecta verifica que el índice se reconstruye si Aristas
+// se modifica sin los métodos y se marca el grafo como modificado
+func TestIndiceModificacionDirecta(t *testing.T) {
+	g := redIndice()
+	verificarIndice(t, g, "inicial")
+
+	g.Aristas = append(g.Aristas, NuevaArista("D", "A", 3, true))
+	g.MarcarModificado()
+	verificarIndice(t, g, "append directo")
+
+	// Reemplazo en el lugar: misma longitud y mismo arreglo de fondo
+	g.Aristas[0] = NuevaArista("C", "D", 7, true)
+	g.MarcarModificado()
+	verificarIndice(t, g, "reemplazo en el lugar")
+
+	g.Aristas = []*Arista{NuevaArista("B", "A", 1, true)}
+	g.MarcarModificado()
+	verificarIndice(t, g, "reasignación")
+
+	g.Aristas = nil
+	g.MarcarModificado()
+	verificarIndice(t, g, "vaciado")
+}
+
+// TestIndiceInvertirArista verifica que invertir una arista deja el índice al día
+func TestIndiceInvertirArista(t *testing.T) {
+	g := redIndice()
+	verificarIndice(t, g, "inicial")
+
+	desde, hasta := g.Aristas[0].Desde, g.Aristas[0].Hasta
+	if _, err := g.InvertirArista(desde, hasta); err != nil {
+		t.Fatalf("Error al invertir arista: %v", err)
+	}
+	verificarIndice(t, g, "invertir")
+	if !g.ExisteConexion(hasta, desde) {
+		t.Errorf("Falta la conexión invertida %s -> %s", hasta, desde)
+	}
+}
+
+// TestIndiceRepetidas verifica que el par apunta a la siguiente arista repetida al quitar la primera
+func TestIndiceRepetidas(t *testing.T) {
+	g := NuevoGrafo(true)
+	g.AgregarCueva(NuevaCueva("A", "A"))
+	g.AgregarCueva(NuevaCueva("B", "B"))
+	primera := NuevaArista("A", "B", 1, true)
+	segunda := NuevaArista("A", "B", 2, true)
+	g.Aristas = []*Arista{primera, segunda}
+
+	if arista, _ := g.ObtenerConexion("A", "B"); arista != primera {
+		t.Errorf("Se esperaba la primera arista, se obtuvo %v", arista)
+	}
+	if err := g.EliminarArista(primera); err != nil {
+		t.Fatalf("Error al eliminar arista: %v", err)
+	}
+	if arista, _ := g.ObtenerConexion("A", "B"); arista != segunda {
+		t.Errorf("Se esperaba la segunda arista, se obtuvo %v", arista)
+	}
+	verificarIndice(t, g, "repetidas")
+}
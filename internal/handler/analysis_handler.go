@@ -4,6 +4,8 @@ import (
 	"fmt"
 	"proyecto-grafos-go/internal/domain"
 	"proyecto-grafos-go/internal/service"
+	"proyecto-grafos-go/pkg/algorithms"
+	"strings"
 )
 
 // AnalysisHandler maneja las operaciones de análisis del grafo
@@ -77,6 +79,38 @@ func (ah *AnalysisHandler) CalcularMSTGeneral(grafo *domain.Grafo) (string, erro
 	return output, nil
 }
 
+// CalcularArbolSteiner conecta solo las cuevas terminales indicadas y compara el
+// resultado con el MST general
+func (ah *AnalysisHandler) CalcularArbolSteiner(grafo *domain.Grafo, terminales []string) (string, error) {
+	if grafo == nil {
+		return "", fmt.Errorf("no hay grafo cargado en el sistema")
+	}
+
+	if len(terminales) < 2 {
+		return "Error: Indique al menos 2 cuevas para conectar", nil
+	}
+
+	resultado, err := ah.mstService.ObtenerArbolSteiner(grafo, terminales)
+	if err != nil {
+		return "", err
+	}
+
+	output := ah.mstService.FormatearArbolSteinerParaVisualizacion(resultado)
+
+	// Comparar con el árbol que conecta todas las cuevas
+	mst, err := ah.mstService.ObtenerMSTGeneral(grafo)
+	if err == nil && mst.MST != nil && mst.MST.PesoTotal > 0 {
+		ahorro := mst.MST.PesoTotal - resultado.Arbol.PesoTotal
+		output += "\n=== COMPARACIÓN CON EL MST GENERAL ===\n"
+		output += fmt.Sprintf("Peso del MST (todas las cuevas): %.2f\n", mst.MST.PesoTotal)
+		output += fmt.Sprintf("Peso del árbol de Steiner: %.2f\n", resultado.Arbol.PesoTotal)
+		output += fmt.Sprintf("Ahorro: %.2f (%.2f%%)\n", ahorro, ahorro/mst.MST.PesoTotal*100)
+		output += fmt.Sprintf("Conexiones evitadas: %d\n", mst.MST.NumAristas-resultado.Arbol.NumAristas)
+	}
+
+	return output, nil
+}
+
 // ObtenerEstadisticasRed proporciona información estadística sobre la red de cuevas
 func (ah *AnalysisHandler) ObtenerEstadisticasRed(grafo *domain.Grafo) (string, error) {
 	if grafo == nil {
@@ -136,9 +170,35 @@ func (ah *AnalysisHandler) ObtenerEstadisticasRed(grafo *domain.Grafo) (string,
 		}
 	}
 
+	output += ah.describirMetricasDistancia(grafo)
+
 	return output, nil
 }
 
+// describirMetricasDistancia resume diámetro, radio, centro y periferia de la red
+func (ah *AnalysisHandler) describirMetricasDistancia(grafo *domain.Grafo) string {
+	matriz, err := algorithms.CalcularMatrizDistancias(grafo)
+	if err != nil {
+		return fmt.Sprintf("\n Centro de la red: no disponible (%v)\n", err)
+	}
+	metricas := matriz.Metricas()
+
+	output := "\n Centro de la red:\n"
+	if metricas.Radio == -1 {
+		output += "   • Ninguna cueva alcanza a todas las demás\n"
+	} else {
+		output += fmt.Sprintf("   • Radio: %.2f\n", metricas.Radio)
+		output += fmt.Sprintf("   • Centro (mejor ubicación para un centro de recursos): %s\n", strings.Join(metricas.Centro, ", "))
+	}
+	if metricas.Diametro == -1 {
+		output += "   • Diámetro: infinito (hay cuevas que no se alcanzan entre sí)\n"
+	} else {
+		output += fmt.Sprintf("   • Diámetro: %.2f\n", metricas.Diametro)
+		output += fmt.Sprintf("   • Periferia: %s\n", strings.Join(metricas.Periferia, ", "))
+	}
+	return output
+}
+
 // ValidarConectividad verifica si la red permite crear un MST
 func (ah *AnalysisHandler) ValidarConectividad(grafo *domain.Grafo) (string, error) {
 	if grafo == nil {
@@ -226,6 +286,19 @@ func (ah *AnalysisHandler) ExportarMST(grafo *domain.Grafo) (*domain.Grafo, stri
 	return grafoMST, resumen, nil
 }
 
+// ObtenerMST calcula el árbol de expansión mínimo para exportarlo
+func (ah *AnalysisHandler) ObtenerMST(grafo *domain.Grafo) (*algorithms.MST, error) {
+	if grafo == nil {
+		return nil, fmt.Errorf("no hay grafo cargado en el sistema")
+	}
+
+	resultado, err := ah.mstService.ObtenerMSTGeneral(grafo)
+	if err != nil {
+		return nil, fmt.Errorf("error al calcular MST: %v", err)
+	}
+	return resultado.MST, nil
+}
+
 // CalcularMSTDesdeCueva maneja el requisito 3b: calcular MST desde cueva específica
 func (ah *AnalysisHandler) CalcularMSTDesdeCueva(grafo *domain.Grafo, cuevaOrigen string) (string, error) {
 	if grafo == nil {
@@ -174,6 +174,27 @@ func (ch *CaveHandler) RemoverRecurso(idCueva, recurso string, cantidad int) err
 	return ch.cuevaService.RemoverRecurso(idCueva, recurso, cantidad)
 }
 
+// EstablecerDemanda maneja la definición de la demanda de un recurso en una cueva
+func (ch *CaveHandler) EstablecerDemanda(idCueva, recurso string, cantidad int) error {
+	if ch.cuevaService == nil {
+		return fmt.Errorf("servicio de cueva no inicializado")
+	}
+
+	if idCueva == "" {
+		return fmt.Errorf("ID de cueva no puede estar vacío")
+	}
+
+	if recurso == "" {
+		return fmt.Errorf("nombre de recurso no puede estar vacío")
+	}
+
+	if cantidad < 0 {
+		return fmt.Errorf("la demanda no puede ser negativa")
+	}
+
+	return ch.cuevaService.EstablecerDemanda(idCueva, recurso, cantidad)
+}
+
 // VerificarConexion verifica si dos cuevas están conectadas
 func (ch *CaveHandler) VerificarConexion(desde, hasta string) (bool, error) {
 	if ch.cuevaService == nil {
@@ -271,6 +292,13 @@ func (ch *CaveHandler) GenerarReporteCueva(id string) (string, error) {
 		reporte += "Sin recursos almacenados\n"
 	}
 
+	if len(detalle.Demanda) > 0 {
+		reporte += "Demanda:\n"
+		for recurso, cantidad := range detalle.Demanda {
+			reporte += fmt.Sprintf("  - %s: %d (faltan %d)\n", recurso, cantidad, max(0, cantidad-detalle.Recursos[recurso]))
+		}
+	}
+
 	return reporte, nil
 }
 
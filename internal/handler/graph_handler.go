@@ -3,7 +3,10 @@ package handler
 import (
 	"fmt"
 	"proyecto-grafos-go/internal/domain"
+	"proyecto-grafos-go/internal/repository"
 	"proyecto-grafos-go/internal/service"
+	"proyecto-grafos-go/pkg/algorithms"
+	"strings"
 )
 
 // GraphHandler maneja operaciones relacionadas con el grafo
@@ -151,6 +154,7 @@ func (gh *GraphHandler) LimpiarGrafo() error {
 	// Limpiar cuevas y aristas
 	grafo.Cuevas = make(map[string]*domain.Cueva)
 	grafo.Aristas = []*domain.Arista{}
+	grafo.MarcarModificado()
 
 	return nil
 }
@@ -257,27 +261,78 @@ func (gh *GraphHandler) ExportarGrafo(nombreArchivo, formato string) error {
 		return fmt.Errorf("nombre de archivo no puede estar vacío")
 	}
 
-	formatosValidos := []string{"json", "xml", "txt"}
-	formatoValido := false
-	for _, f := range formatosValidos {
-		if formato == f {
-			formatoValido = true
-			break
-		}
+	// "csv" exporta el par de cuevas y aristas; "matriz", la matriz de adyacencia CSV
+	if !gh.grafoService.FormatoSoportado(formato) {
+		return fmt.Errorf("formato no válido. Formatos soportados: %s", strings.Join(gh.grafoService.FormatosSoportados(), ", "))
 	}
 
-	if !formatoValido {
-		return fmt.Errorf("formato no válido. Formatos soportados: json, xml, txt")
+	extension := formato
+	if formato == "matriz" {
+		extension = "matriz.csv"
 	}
 
 	// Agregar extensión si no la tiene
-	if !gh.tieneExtension(nombreArchivo, formato) {
-		nombreArchivo += "." + formato
+	if !gh.tieneExtension(nombreArchivo, extension) {
+		nombreArchivo += "." + extension
 	}
 
 	return gh.grafoService.GuardarGrafo(nombreArchivo)
 }
 
+// ExportarGrafoDOT exporta el grafo a Graphviz DOT con los resaltados indicados
+func (gh *GraphHandler) ExportarGrafoDOT(nombreArchivo string, resaltados ...repository.ResaltadoDOT) error {
+	if gh.grafoService == nil {
+		return fmt.Errorf("servicio de grafo no inicializado")
+	}
+
+	if nombreArchivo == "" {
+		return fmt.Errorf("nombre de archivo no puede estar vacío")
+	}
+
+	if !gh.tieneExtension(nombreArchivo, "dot") {
+		nombreArchivo += ".dot"
+	}
+
+	return gh.grafoService.ExportarDOT(nombreArchivo, resaltados...)
+}
+
+// ExportarMSTDOT exporta el grafo a DOT resaltando las aristas de un MST
+func (gh *GraphHandler) ExportarMSTDOT(nombreArchivo string, mst *algorithms.MST) error {
+	if mst == nil {
+		return fmt.Errorf("MST no puede ser nulo")
+	}
+
+	resaltado := repository.NuevoResaltadoAristas(fmt.Sprintf("MST (peso %.2f)", mst.PesoTotal), "forestgreen", mst.Aristas)
+	return gh.ExportarGrafoDOT(nombreArchivo, resaltado)
+}
+
+// ExportarRutaDOT exporta el grafo a DOT resaltando la ruta más corta (Dijkstra) entre dos cuevas
+func (gh *GraphHandler) ExportarRutaDOT(nombreArchivo, desde, hasta string) error {
+	grafo, err := gh.ObtenerGrafo()
+	if err != nil {
+		return err
+	}
+
+	ruta, distancia, err := algorithms.DijkstraRuta(grafo, desde, hasta)
+	if err != nil {
+		return err
+	}
+
+	resaltado := repository.NuevoResaltadoRuta(fmt.Sprintf("Ruta %s -> %s (%.2f)", desde, hasta, distancia), "blue", ruta)
+	return gh.ExportarGrafoDOT(nombreArchivo, resaltado)
+}
+
+// ExportarSimulacionDOT exporta el grafo a DOT resaltando la ruta completa de una simulación
+func (gh *GraphHandler) ExportarSimulacionDOT(nombreArchivo string, resultado *service.SimulacionResultado) error {
+	if resultado == nil {
+		return fmt.Errorf("resultado de simulación no puede ser nulo")
+	}
+
+	etiqueta := fmt.Sprintf("Camión %s (%s)", resultado.CamionID, resultado.TipoRecorrido)
+	resaltado := repository.NuevoResaltadoRuta(etiqueta, "darkorange", resultado.RutaCompleta)
+	return gh.ExportarGrafoDOT(nombreArchivo, resaltado)
+}
+
 func (gh *GraphHandler) tieneExtension(nombre, extension string) bool {
 	longitud := len(nombre)
 	longitudExt := len(extension)
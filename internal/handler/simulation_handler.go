@@ -4,7 +4,10 @@ import (
 	"fmt"
 	"proyecto-grafos-go/internal/domain"
 	"proyecto-grafos-go/internal/service"
+	"proyecto-grafos-go/pkg/algorithms"
 	"strconv"
+	"strings"
+	"time"
 )
 
 // SimulationHandler maneja las operaciones de simulación de camiones
@@ -59,6 +62,14 @@ func (sh *SimulationHandler) CargarInsumosEnCamion(camionID string, insumos map[
 	return sh.truckService.CargarInsumos(camionID, insumosInt)
 }
 
+// CargarSegunDemanda carga el camión con lo que les falta a las cuevas del grafo
+func (sh *SimulationHandler) CargarSegunDemanda(grafo *domain.Grafo, camionID string) (map[string]int, error) {
+	if grafo == nil {
+		return nil, fmt.Errorf("el grafo no puede ser nulo")
+	}
+	return sh.truckService.CargarSegunDemanda(grafo, camionID)
+}
+
 // EjecutarSimulacionDFS ejecuta una simulación de entrega usando DFS
 func (sh *SimulationHandler) EjecutarSimulacionDFS(grafo *domain.Grafo, camionID string, cuevaOrigen string) (*service.SimulacionResultado, error) {
 	if grafo == nil {
@@ -87,7 +98,24 @@ func (sh *SimulationHandler) EjecutarSimulacionBFS(grafo *domain.Grafo, camionID
 	return sh.truckService.SimularEntregaBFS(grafo, camionID, cuevaOrigen)
 }
 
-// CompararAlgoritmos compara el rendimiento de DFS vs BFS para la misma simulación
+// EjecutarSimulacionTSP ejecuta una simulación de entrega con un recorrido TSP por caminos mínimos
+func (sh *SimulationHandler) EjecutarSimulacionTSP(grafo *domain.Grafo, camionID string, cuevaOrigen string) (*service.SimulacionResultado, error) {
+	if grafo == nil {
+		return nil, fmt.Errorf("el grafo no puede ser nulo")
+	}
+
+	// Verificar que la cueva origen existe
+	if _, existe := grafo.ObtenerCueva(cuevaOrigen); !existe {
+		return nil, fmt.Errorf("cueva origen '%s' no existe en el grafo", cuevaOrigen)
+	}
+
+	return sh.truckService.SimularEntregaTSP(grafo, camionID, cuevaOrigen)
+}
+
+// algoritmosComparados son los recorridos que se comparan, en orden
+var algoritmosComparados = []service.TipoRecorrido{service.DFS, service.BFS, service.TSP}
+
+// CompararAlgoritmos compara el rendimiento de DFS, BFS y TSP para la misma simulación
 func (sh *SimulationHandler) CompararAlgoritmos(grafo *domain.Grafo, camionID string, cuevaOrigen string) (map[string]*service.SimulacionResultado, error) {
 	if grafo == nil {
 		return nil, fmt.Errorf("el grafo no puede ser nulo")
@@ -107,34 +135,67 @@ func (sh *SimulationHandler) CompararAlgoritmos(grafo *domain.Grafo, camionID st
 		cargaOriginal[recurso] = cantidad
 	}
 
-	// Ejecutar simulación DFS
-	resultadoDFS, err := sh.EjecutarSimulacionDFS(grafo, camionID, cuevaOrigen)
-	if err != nil {
-		return nil, fmt.Errorf("error en simulación DFS: %s", err.Error())
-	}
-	resultados["DFS"] = resultadoDFS
+	// Las entregas cambian el stock de las cuevas; cada algoritmo parte del mismo
+	stockOriginal := copiarStock(grafo)
 
-	// Restaurar carga del camión para la segunda simulación
-	err = sh.truckService.ReiniciarCamion(camionID, cuevaOrigen)
-	if err != nil {
-		return nil, fmt.Errorf("error al reiniciar camión: %s", err.Error())
-	}
+	for i, tipo := range algoritmosComparados {
+		// Restaurar carga del camión y stock de las cuevas antes de cada simulación posterior
+		if i > 0 {
+			restaurarStock(grafo, stockOriginal)
 
-	err = sh.truckService.CargarInsumos(camionID, cargaOriginal)
-	if err != nil {
-		return nil, fmt.Errorf("error al recargar insumos: %s", err.Error())
-	}
+			err = sh.truckService.ReiniciarCamion(camionID, cuevaOrigen)
+			if err != nil {
+				return nil, fmt.Errorf("error al reiniciar camión: %s", err.Error())
+			}
 
-	// Ejecutar simulación BFS
-	resultadoBFS, err := sh.EjecutarSimulacionBFS(grafo, camionID, cuevaOrigen)
-	if err != nil {
-		return nil, fmt.Errorf("error en simulación BFS: %s", err.Error())
+			err = sh.truckService.CargarInsumos(camionID, cargaOriginal)
+			if err != nil {
+				return nil, fmt.Errorf("error al recargar insumos: %s", err.Error())
+			}
+		}
+
+		var resultado *service.SimulacionResultado
+		switch tipo {
+		case service.DFS:
+			resultado, err = sh.EjecutarSimulacionDFS(grafo, camionID, cuevaOrigen)
+		case service.BFS:
+			resultado, err = sh.EjecutarSimulacionBFS(grafo, camionID, cuevaOrigen)
+		case service.TSP:
+			resultado, err = sh.EjecutarSimulacionTSP(grafo, camionID, cuevaOrigen)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("error en simulación %s: %s", tipo, err.Error())
+		}
+		resultados[string(tipo)] = resultado
 	}
-	resultados["BFS"] = resultadoBFS
 
 	return resultados, nil
 }
 
+// copiarStock guarda los recursos de cada cueva del grafo
+func copiarStock(grafo *domain.Grafo) map[string]map[string]int {
+	stock := make(map[string]map[string]int, len(grafo.Cuevas))
+	for id, cueva := range grafo.Cuevas {
+		stock[id] = make(map[string]int, len(cueva.Recursos))
+		for recurso, cantidad := range cueva.Recursos {
+			stock[id][recurso] = cantidad
+		}
+	}
+	return stock
+}
+
+// restaurarStock devuelve a cada cueva los recursos guardados con copiarStock
+func restaurarStock(grafo *domain.Grafo, stock map[string]map[string]int) {
+	for id, recursos := range stock {
+		if cueva, existe := grafo.Cuevas[id]; existe {
+			cueva.Recursos = make(map[string]int, len(recursos))
+			for recurso, cantidad := range recursos {
+				cueva.Recursos[recurso] = cantidad
+			}
+		}
+	}
+}
+
 // ObtenerEstadoCamion obtiene el estado actual de un camión
 func (sh *SimulationHandler) ObtenerEstadoCamion(camionID string) (*service.Camion, error) {
 	return sh.truckService.ObtenerCamion(camionID)
@@ -155,88 +216,215 @@ func (sh *SimulationHandler) EliminarCamion(camionID string) error {
 	return sh.truckService.EliminarCamion(camionID)
 }
 
-// GenerarReporteComparativo genera un reporte comparativo entre DFS y BFS
+// EstablecerAlgoritmoCamino elige el algoritmo para los trayectos de los camiones entre cuevas no adyacentes
+func (sh *SimulationHandler) EstablecerAlgoritmoCamino(algoritmo string) error {
+	return sh.truckService.EstablecerAlgoritmoCamino(algorithms.AlgoritmoCamino(algoritmo))
+}
+
+// ObtenerAlgoritmoCamino devuelve el algoritmo usado para los trayectos de los camiones
+func (sh *SimulationHandler) ObtenerAlgoritmoCamino() string {
+	return string(sh.truckService.ObtenerAlgoritmoCamino())
+}
+
+// EstablecerModoTSP elige cómo se planifican los recorridos TSP de los camiones
+func (sh *SimulationHandler) EstablecerModoTSP(modo string) error {
+	return sh.truckService.EstablecerModoTSP(algorithms.ModoTSP(modo))
+}
+
+// ObtenerModoTSP devuelve el modo usado para los recorridos TSP
+func (sh *SimulationHandler) ObtenerModoTSP() string {
+	return string(sh.truckService.ObtenerModoTSP())
+}
+
+// EstablecerPrioridadEntrega elige cómo se reparte la carga entre las demandas de las cuevas
+func (sh *SimulationHandler) EstablecerPrioridadEntrega(prioridad string) error {
+	return sh.truckService.EstablecerPrioridadEntrega(service.PrioridadEntrega(prioridad))
+}
+
+// ObtenerPrioridadEntrega devuelve la regla usada para repartir la carga
+func (sh *SimulationHandler) ObtenerPrioridadEntrega() string {
+	return string(sh.truckService.ObtenerPrioridadEntrega())
+}
+
+// EstablecerRecursosCriticos define los recursos que se atienden primero
+func (sh *SimulationHandler) EstablecerRecursosCriticos(recursos []string) {
+	sh.truckService.EstablecerRecursosCriticos(recursos)
+}
+
+// ObtenerRecursosCriticos devuelve los recursos críticos en orden de importancia
+func (sh *SimulationHandler) ObtenerRecursosCriticos() []string {
+	return sh.truckService.ObtenerRecursosCriticos()
+}
+
+// EstablecerReproduccion elige cómo se reproducen las simulaciones. La escala
+// solo se usa en el modo escalado; alAvanzar recibe cada evento procesado.
+func (sh *SimulationHandler) EstablecerReproduccion(modo string, escala float64, alAvanzar func(service.EventoSimulacion)) error {
+	return sh.truckService.EstablecerReproduccion(service.Reproduccion{
+		Modo:      service.ModoReproduccion(modo),
+		Escala:    escala,
+		AlAvanzar: alAvanzar,
+	})
+}
+
+// ObtenerModoReproduccion devuelve el modo de reproducción actual
+func (sh *SimulationHandler) ObtenerModoReproduccion() string {
+	return string(sh.truckService.ObtenerReproduccion().Modo)
+}
+
+// EstablecerJornada define en horas cuánto dura la jornada de los camiones
+func (sh *SimulationHandler) EstablecerJornada(horas float64) error {
+	return sh.truckService.EstablecerJornada(time.Duration(horas * float64(time.Hour)))
+}
+
+// ObtenerJornada devuelve la duración de la jornada en horas
+func (sh *SimulationHandler) ObtenerJornada() float64 {
+	return sh.truckService.ObtenerJornada().Hours()
+}
+
+// GenerarReporteComparativo genera un reporte comparativo entre los recorridos simulados (DFS, BFS y TSP)
 func (sh *SimulationHandler) GenerarReporteComparativo(resultados map[string]*service.SimulacionResultado) string {
-	if len(resultados) != 2 {
-		return "Error: Se requieren exactamente 2 resultados para comparar"
+	if len(resultados) < 2 {
+		return "Error: Se requieren al menos 2 resultados para comparar"
 	}
 
-	dfs, existeDFS := resultados["DFS"]
-	bfs, existeBFS := resultados["BFS"]
-
-	if !existeDFS || !existeBFS {
-		return "Error: Se requieren resultados de DFS y BFS para comparar"
+	nombres := make([]string, 0, len(resultados))
+	for _, tipo := range algoritmosComparados {
+		if _, existe := resultados[string(tipo)]; existe {
+			nombres = append(nombres, string(tipo))
+		}
+	}
+	if len(nombres) != len(resultados) {
+		return "Error: Solo se pueden comparar resultados de DFS, BFS y TSP"
 	}
 
-	reporte := "=== REPORTE COMPARATIVO DFS vs BFS ===\n\n"
+	reporte := fmt.Sprintf("=== REPORTE COMPARATIVO %s ===\n\n", strings.Join(nombres, " vs "))
 
 	// Comparación general
 	reporte += "--- COMPARACIÓN GENERAL ---\n"
-	reporte += fmt.Sprintf("DFS - Éxito: %t, Tiempo: %v, Distancia: %.2f km, Cuevas: %d\n",
-		dfs.Exitoso, dfs.TiempoTotal, dfs.DistanciaTotal, len(dfs.RutaCompleta))
-	reporte += fmt.Sprintf("BFS - Éxito: %t, Tiempo: %v, Distancia: %.2f km, Cuevas: %d\n",
-		bfs.Exitoso, bfs.TiempoTotal, bfs.DistanciaTotal, len(bfs.RutaCompleta))
+	for _, nombre := range nombres {
+		r := resultados[nombre]
+		reporte += fmt.Sprintf("%s - Éxito: %t, Tiempo: %v, Distancia: %.2f km, Cuevas: %d\n",
+			nombre, r.Exitoso, r.TiempoTotal, r.DistanciaTotal, len(r.RutaCompleta))
+	}
 
 	// Análisis de eficiencia
 	reporte += "\n--- ANÁLISIS DE EFICIENCIA ---\n"
-	if dfs.TiempoTotal < bfs.TiempoTotal {
-		reporte += "DFS fue más rápido\n"
-	} else if bfs.TiempoTotal < dfs.TiempoTotal {
-		reporte += "BFS fue más rápido\n"
-	} else {
-		reporte += " Ambos algoritmos tardaron lo mismo\n"
-	}
-
-	if dfs.DistanciaTotal < bfs.DistanciaTotal {
-		reporte += "DFS recorrió menor distancia\n"
-	} else if bfs.DistanciaTotal < dfs.DistanciaTotal {
-		reporte += "BFS recorrió menor distancia\n"
-	} else {
-		reporte += "Ambos algoritmos recorrieron la misma distancia\n"
-	}
+	reporte += describirMejores(nombres, "fue más rápido", "Todos los algoritmos tardaron lo mismo", func(a, b string) bool {
+		return resultados[a].TiempoTotal < resultados[b].TiempoTotal
+	})
+	reporte += describirMejores(nombres, "recorrió menor distancia", "Todos los algoritmos recorrieron la misma distancia", func(a, b string) bool {
+		return resultados[a].DistanciaTotal < resultados[b].DistanciaTotal
+	})
 
 	// Comparación de entregas
 	reporte += "\n--- COMPARACIÓN DE ENTREGAS ---\n"
-	entregasDFS := len(dfs.EntregasRealizadas)
-	entregasBFS := len(bfs.EntregasRealizadas)
-
-	reporte += fmt.Sprintf("DFS realizó entregas en %d cuevas\n", entregasDFS)
-	reporte += fmt.Sprintf("BFS realizó entregas en %d cuevas\n", entregasBFS)
-
-	if entregasDFS > entregasBFS {
-		reporte += "DFS realizó más entregas\n"
-	} else if entregasBFS > entregasDFS {
-		reporte += "BFS realizó más entregas\n"
-	} else {
-		reporte += "Ambos algoritmos realizaron la misma cantidad de entregas\n"
+	for _, nombre := range nombres {
+		reporte += fmt.Sprintf("%s realizó entregas en %d cuevas\n", nombre, len(resultados[nombre].EntregasRealizadas))
+	}
+	reporte += describirMejores(nombres, "realizó más entregas", "Todos los algoritmos realizaron la misma cantidad de entregas", func(a, b string) bool {
+		return len(resultados[a].EntregasRealizadas) > len(resultados[b].EntregasRealizadas)
+	})
+
+	// Demanda que queda sin cubrir, si alguna cueva declara demanda
+	faltantes := make(map[string]int, len(nombres))
+	hayDemanda := false
+	for _, nombre := range nombres {
+		for _, faltante := range resultados[nombre].DemandaInsatisfecha {
+			faltantes[nombre] += faltante.Faltante
+		}
+		if _, ok := resultados[nombre].EstadisticasEntrega["demanda_cubierta"]; ok {
+			hayDemanda = true
+		}
+	}
+	if hayDemanda {
+		reporte += "\n--- DEMANDA INSATISFECHA ---\n"
+		for _, nombre := range nombres {
+			reporte += fmt.Sprintf("%s dejó %d unidades sin cubrir\n", nombre, faltantes[nombre])
+		}
+		reporte += describirMejores(nombres, "cubrió más demanda", "Todos los algoritmos cubrieron la misma demanda", func(a, b string) bool {
+			return faltantes[a] < faltantes[b]
+		})
 	}
 
 	// Rutas seguidas
 	reporte += "\n--- RUTAS SEGUIDAS ---\n"
-	reporte += "DFS: " + fmt.Sprintf("%v", dfs.RutaCompleta) + "\n"
-	reporte += "BFS: " + fmt.Sprintf("%v", bfs.RutaCompleta) + "\n"
+	for _, nombre := range nombres {
+		reporte += nombre + ": " + fmt.Sprintf("%v", resultados[nombre].RutaCompleta) + "\n"
+	}
 
-	// Recomendación
+	// Recomendación: el algoritmo exitoso de menor distancia, desempatando por tiempo
 	reporte += "\n--- RECOMENDACIÓN ---\n"
-	if dfs.Exitoso && bfs.Exitoso {
-		if dfs.TiempoTotal < bfs.TiempoTotal && dfs.DistanciaTotal <= bfs.DistanciaTotal {
-			reporte += "RECOMENDACION: Se recomienda usar DFS por mejor rendimiento en tiempo y distancia\n"
-		} else if bfs.TiempoTotal < dfs.TiempoTotal && bfs.DistanciaTotal <= dfs.DistanciaTotal {
-			reporte += "RECOMENDACION: Se recomienda usar BFS por mejor rendimiento en tiempo y distancia\n"
-		} else {
-			reporte += "Ambos algoritmos son viables, elija según sus preferencias específicas\n"
+	recomendado := ""
+	fallidos := make([]string, 0)
+	for _, nombre := range nombres {
+		r := resultados[nombre]
+		if !r.Exitoso {
+			fallidos = append(fallidos, nombre)
+			continue
+		}
+		if recomendado == "" {
+			recomendado = nombre
+			continue
+		}
+		mejor := resultados[recomendado]
+		if r.DistanciaTotal < mejor.DistanciaTotal || (r.DistanciaTotal == mejor.DistanciaTotal && r.TiempoTotal < mejor.TiempoTotal) {
+			recomendado = nombre
 		}
-	} else if dfs.Exitoso {
-		reporte += "RECOMENDACION: Use DFS (BFS falló)\n"
-	} else if bfs.Exitoso {
-		reporte += "RECOMENDACION: Use BFS (DFS falló)\n"
-	} else {
+	}
+	switch {
+	case recomendado == "":
 		reporte += "ERROR: Ningún algoritmo completó exitosamente la simulación\n"
+	case len(fallidos) > 0:
+		reporte += fmt.Sprintf("RECOMENDACION: Use %s (%s falló)\n", recomendado, strings.Join(fallidos, ", "))
+	default:
+		reporte += fmt.Sprintf("RECOMENDACION: Se recomienda usar %s por recorrer la menor distancia\n", recomendado)
 	}
 
 	return reporte
 }
 
+// describirMejores indica qué algoritmos ganan según el criterio mejor, o que empatan todos
+func describirMejores(nombres []string, logro, empate string, mejor func(a, b string) bool) string {
+	ganadores := []string{nombres[0]}
+	for _, nombre := range nombres[1:] {
+		if mejor(nombre, ganadores[0]) {
+			ganadores = []string{nombre}
+		} else if !mejor(ganadores[0], nombre) {
+			ganadores = append(ganadores, nombre)
+		}
+	}
+	if len(ganadores) == len(nombres) {
+		return empate + "\n"
+	}
+	return fmt.Sprintf("%s %s\n", strings.Join(ganadores, " y "), logro)
+}
+
+// EjecutarSimulacionFlota despacha varios camiones a la vez desde el depósito
+func (sh *SimulationHandler) EjecutarSimulacionFlota(grafo *domain.Grafo, deposito string, asignaciones map[string][]string) (*service.ResultadoFlota, error) {
+	if grafo == nil {
+		return nil, fmt.Errorf("el grafo no puede ser nulo")
+	}
+	return sh.truckService.SimularFlota(grafo, deposito, asignaciones)
+}
+
+// PlanificarFlotaCVRP reparte la demanda de las cuevas entre los camiones indicados
+func (sh *SimulationHandler) PlanificarFlotaCVRP(grafo *domain.Grafo, deposito string, camionIDs []string) (*service.PlanFlota, error) {
+	if grafo == nil {
+		return nil, fmt.Errorf("el grafo no puede ser nulo")
+	}
+	return sh.truckService.PlanificarFlotaCVRP(grafo, deposito, camionIDs)
+}
+
+// GenerarReportePlanFlota genera el reporte de un plan de rutas de la flota
+func (sh *SimulationHandler) GenerarReportePlanFlota(plan *service.PlanFlota) string {
+	return sh.truckService.GenerarReportePlanFlota(plan)
+}
+
+// GenerarReporteFlota genera el reporte de una simulación de flota
+func (sh *SimulationHandler) GenerarReporteFlota(resultado *service.ResultadoFlota) string {
+	return sh.truckService.GenerarReporteFlota(resultado)
+}
+
 // GenerarReporteSimulacion genera un reporte detallado de una simulación
 func (sh *SimulationHandler) GenerarReporteSimulacion(resultado *service.SimulacionResultado) string {
 	return sh.truckService.GenerarReporteSimulacion(resultado)
@@ -4,6 +4,8 @@ import (
 	"fmt"
 	"proyecto-grafos-go/internal/domain"
 	"proyecto-grafos-go/internal/service"
+	"proyecto-grafos-go/pkg/algorithms"
+	"strings"
 )
 
 // TraversalHandler maneja las operaciones de recorrido de grafos
@@ -109,6 +111,78 @@ func (th *TraversalHandler) ObtenerCuevasAccesibles(grafo *domain.Grafo, cuevaOr
 	return th.traversalService.ObtenerCuevasAccesibles(grafo, cuevaOrigen)
 }
 
+// BuscarCamino busca el camino más corto entre dos cuevas con el algoritmo indicado
+func (th *TraversalHandler) BuscarCamino(grafo *domain.Grafo, desde, hasta string, algoritmo string) (*algorithms.ResultadoCamino, error) {
+	if err := th.validarParametros(grafo, desde); err != nil {
+		return nil, err
+	}
+	if _, existe := grafo.ObtenerCueva(hasta); !existe {
+		return nil, fmt.Errorf("cueva destino '%s' no existe en el grafo", hasta)
+	}
+
+	return th.traversalService.BuscarCamino(grafo, desde, hasta, algorithms.AlgoritmoCamino(algoritmo))
+}
+
+// GenerarReporteCamino genera un reporte de un camino más corto
+func (th *TraversalHandler) GenerarReporteCamino(resultado *algorithms.ResultadoCamino) string {
+	if resultado == nil {
+		return "Error: Resultado de camino nulo"
+	}
+
+	reporte := fmt.Sprintf("=== CAMINO MÁS CORTO (%s) ===\n", resultado.Algoritmo)
+	reporte += fmt.Sprintf("Ruta: %s\n", strings.Join(resultado.Ruta, " -> "))
+	reporte += fmt.Sprintf("Costo: %.2f\n", resultado.Costo)
+	reporte += fmt.Sprintf("Nodos expandidos: %d\n", resultado.NodosExpandidos)
+	return reporte
+}
+
+// BuscarRutasAlternativas busca las k rutas más cortas sin ciclos entre dos cuevas
+func (th *TraversalHandler) BuscarRutasAlternativas(grafo *domain.Grafo, desde, hasta string, k int) ([]*algorithms.ResultadoCamino, error) {
+	if err := th.validarParametros(grafo, desde); err != nil {
+		return nil, err
+	}
+	if _, existe := grafo.ObtenerCueva(hasta); !existe {
+		return nil, fmt.Errorf("cueva destino '%s' no existe en el grafo", hasta)
+	}
+	if k <= 0 {
+		return nil, fmt.Errorf("la cantidad de rutas debe ser mayor que cero")
+	}
+
+	return th.traversalService.KCaminosMasCortos(grafo, desde, hasta, k)
+}
+
+// GenerarReporteRutasAlternativas genera un reporte de las rutas alternativas y los túneles que comparten
+func (th *TraversalHandler) GenerarReporteRutasAlternativas(grafo *domain.Grafo, caminos []*algorithms.ResultadoCamino) string {
+	if len(caminos) == 0 {
+		return "Error: No hay rutas para reportar"
+	}
+
+	primero := caminos[0]
+	reporte := fmt.Sprintf("=== RUTAS ALTERNATIVAS %s -> %s ===\n", primero.Ruta[0], primero.Ruta[len(primero.Ruta)-1])
+	for i, camino := range caminos {
+		reporte += fmt.Sprintf("%d. %s (%.2f km", i+1, strings.Join(camino.Ruta, " -> "), camino.Costo)
+		if i > 0 {
+			reporte += fmt.Sprintf(", +%.2f", camino.Costo-primero.Costo)
+		}
+		reporte += ")\n"
+	}
+
+	reporte += "\n--- TÚNELES COMPARTIDOS ---\n"
+	compartidos := algorithms.SegmentosCompartidos(caminos, grafo != nil && grafo.EsDirigido)
+	if len(compartidos) == 0 {
+		reporte += "Las rutas no comparten túneles\n"
+	}
+	for _, segmento := range compartidos {
+		rutas := make([]string, len(segmento.Rutas))
+		for i, ruta := range segmento.Rutas {
+			rutas[i] = fmt.Sprintf("%d", ruta)
+		}
+		reporte += fmt.Sprintf("%s - %s: rutas %s\n", segmento.Desde, segmento.Hasta, strings.Join(rutas, ", "))
+	}
+
+	return reporte
+}
+
 // GenerarReporteRecorrido genera un reporte detallado de un recorrido
 func (th *TraversalHandler) GenerarReporteRecorrido(resultado *service.RecorridoResultado) string {
 	if resultado == nil {
@@ -0,0 +1,559 @@
+package repository
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"proyecto-grafos-go/internal/domain"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Sufijos de los archivos CSV. Un grafo "red.csv" se guarda como el par
+// "red.cuevas.csv" y "red.aristas.csv"; "red.matriz.csv" es una matriz de adyacencia.
+const (
+	sufijoCuevasCSV  = ".cuevas.csv"
+	sufijoAristasCSV = ".aristas.csv"
+	sufijoMatrizCSV  = ".matriz.csv"
+)
+
+// Columnas fijas de los CSV de cuevas y aristas; en el de cuevas, cualquier
+// otra columna es un recurso y su celda la cantidad, o la demanda del recurso
+// si la columna empieza con prefijoDemandaCSV
+var (
+	columnasCuevasCSV  = []string{"id", "nombre", "x", "y"}
+	columnasAristasCSV = []string{"desde", "hasta", "distancia", "dirigido", "obstruido", "capacidad", "angosto"}
+)
+
+// Prefijo de las columnas de demanda del CSV de cuevas ("demanda:agua")
+const prefijoDemandaCSV = "demanda:"
+
+// Marca de una celda de la matriz de adyacencia con el túnel obstruido
+const marcaObstruidoCSV = "*"
+
+// ArchivosCSV devuelve los nombres del par de archivos CSV de un grafo. Acepta
+// el nombre base ("red.csv") o cualquiera de los dos archivos del par.
+func ArchivosCSV(archivo string) (cuevas, aristas string) {
+	base := archivo
+	for _, sufijo := range []string{sufijoCuevasCSV, sufijoAristasCSV, ".csv"} {
+		if strings.HasSuffix(strings.ToLower(base), sufijo) {
+			base = base[:len(base)-len(sufijo)]
+			break
+		}
+	}
+	return base + sufijoCuevasCSV, base + sufijoAristasCSV
+}
+
+// indica si el archivo es una matriz de adyacencia CSV
+func esMatrizCSV(archivo string) bool {
+	return strings.HasSuffix(strings.ToLower(archivo), sufijoMatrizCSV)
+}
+
+// indica si el archivo se guarda como par de CSV de cuevas y aristas
+func esParCSV(archivo string) bool {
+	return strings.EqualFold(filepath.Ext(archivo), ".csv") && !esMatrizCSV(archivo)
+}
+
+// CargarCSV carga un grafo desde el par de CSV de cuevas y aristas
+func (ra *RepositorioArchivo) CargarCSV(archivo string) (*domain.Grafo, error) {
+	return exigirSinErrores(ra.cargarCSVValidado(archivo))
+}
+
+// carga el par de CSV validando cada fila; las ubicaciones indican el archivo y la línea
+func (ra *RepositorioArchivo) cargarCSVValidado(archivo string) (*domain.Grafo, []ProblemaValidacion, error) {
+	archivoCuevas, archivoAristas := ArchivosCSV(archivo)
+	carga := nuevaCargaValidada("")
+
+	// Cuevas
+	filas, err := ra.leerCSV(archivoCuevas)
+	if err != nil {
+		return nil, nil, err
+	}
+	columnas, err := columnasEncabezadoCSV(archivoCuevas, filas, columnasCuevasCSV, "id")
+	if err != nil {
+		return nil, nil, err
+	}
+	for _, fila := range filas[1:] {
+		ubicacion := fmt.Sprintf("%s:línea %d", archivoCuevas, fila.linea)
+		if cueva, ok := cuevaDesdeFilaCSV(carga, fila, columnas, ubicacion); ok {
+			carga.cueva(cueva, ubicacion)
+		}
+	}
+
+	// Aristas; el grafo es dirigido si todas sus aristas lo son
+	filas, err = ra.leerCSV(archivoAristas)
+	if err != nil {
+		return nil, nil, err
+	}
+	columnas, err = columnasEncabezadoCSV(archivoAristas, filas, columnasAristasCSV, "desde", "hasta", "distancia")
+	if err != nil {
+		return nil, nil, err
+	}
+	for _, nombre := range columnas.desconocidas() {
+		carga.problema(fmt.Sprintf("%s:línea %d", archivoAristas, filas[0].linea), nombre, SeveridadAdvertencia, "columna desconocida '%s'", nombre)
+	}
+	aristas, dirigidas := 0, 0
+	for _, fila := range filas[1:] {
+		ubicacion := fmt.Sprintf("%s:línea %d", archivoAristas, fila.linea)
+		arista, ok := aristaDesdeFilaCSV(carga, fila, columnas, ubicacion)
+		if !ok {
+			continue
+		}
+		aristas++
+		if arista.EsDirigido {
+			dirigidas++
+		}
+		carga.arista(arista, ubicacion)
+	}
+	carga.constructor.EstablecerDirigido(aristas > 0 && dirigidas == aristas)
+
+	return carga.terminar()
+}
+
+// convierte una fila del CSV de cuevas; las columnas no fijas son recursos o demandas
+func cuevaDesdeFilaCSV(carga *cargaValidada, fila filaCSV, columnas columnasCSV, ubicacion string) (*domain.Cueva, bool) {
+	id := columnas.valor(fila, "id")
+	cueva := domain.NuevaCueva(id, id)
+	if nombre := columnas.valor(fila, "nombre"); nombre != "" {
+		cueva.Nombre = nombre
+	}
+
+	valido := true
+	for _, campo := range []string{"x", "y"} {
+		texto := columnas.valor(fila, campo)
+		if texto == "" {
+			continue
+		}
+		valor, err := parsearDecimalCSV(texto)
+		if err != nil {
+			carga.problema(ubicacion, campo, SeveridadError, "coordenada inválida '%s'", texto)
+			valido = false
+			continue
+		}
+		if campo == "x" {
+			cueva.X = valor
+		} else {
+			cueva.Y = valor
+		}
+	}
+
+	for _, columna := range columnas.desconocidas() {
+		texto := columnas.valor(fila, columna)
+		if texto == "" {
+			continue
+		}
+		cantidad, err := strconv.Atoi(texto)
+		if recurso, esDemanda := strings.CutPrefix(columna, prefijoDemandaCSV); esDemanda {
+			if err != nil {
+				carga.problema(ubicacion, columna, SeveridadError, "demanda inválida '%s' del recurso '%s'", texto, recurso)
+				valido = false
+				continue
+			}
+			if cueva.Demanda == nil {
+				cueva.Demanda = make(map[string]int)
+			}
+			cueva.Demanda[recurso] = cantidad
+			continue
+		}
+		if err != nil {
+			carga.problema(ubicacion, columna, SeveridadError, "cantidad inválida '%s' del recurso '%s'", texto, columna)
+			valido = false
+			continue
+		}
+		cueva.Recursos[columna] = cantidad
+	}
+	return cueva, valido
+}
+
+// convierte una fila del CSV de aristas
+func aristaDesdeFilaCSV(carga *cargaValidada, fila filaCSV, columnas columnasCSV, ubicacion string) (*domain.Arista, bool) {
+	arista := &domain.Arista{
+		Desde: columnas.valor(fila, "desde"),
+		Hasta: columnas.valor(fila, "hasta"),
+	}
+
+	texto := columnas.valor(fila, "distancia")
+	distancia, err := parsearDecimalCSV(texto)
+	if err != nil {
+		carga.problema(ubicacion, "distancia", SeveridadError, "distancia inválida '%s'", texto)
+		return nil, false
+	}
+	arista.Distancia = distancia
+
+	for _, campo := range []string{"dirigido", "obstruido", "angosto"} {
+		texto := columnas.valor(fila, campo)
+		valor, err := parsearBoolCSV(texto)
+		if err != nil {
+			carga.problema(ubicacion, campo, SeveridadError, "valor booleano inválido '%s'", texto)
+			return nil, false
+		}
+		switch campo {
+		case "dirigido":
+			arista.EsDirigido = valor
+		case "obstruido":
+			arista.EsObstruido = valor
+		default:
+			arista.EsAngosto = valor
+		}
+	}
+
+	if texto := columnas.valor(fila, "capacidad"); texto != "" {
+		capacidad, err := parsearDecimalCSV(texto)
+		if err != nil {
+			carga.problema(ubicacion, "capacidad", SeveridadError, "capacidad inválida '%s'", texto)
+			return nil, false
+		}
+		arista.Capacidad = capacidad
+	}
+	return arista, true
+}
+
+// GuardarCSV guarda el grafo como el par de CSV de cuevas y aristas
+func (ra *RepositorioArchivo) GuardarCSV(grafo *domain.Grafo, archivo string) error {
+	archivoCuevas, archivoAristas := ArchivosCSV(archivo)
+	dataGrafo := extraerDatosGrafo(grafo)
+
+	// Una columna por cada recurso presente en alguna cueva y otra por cada recurso demandado
+	conjuntoRecursos := make(map[string]bool)
+	conjuntoDemandas := make(map[string]bool)
+	for _, cueva := range dataGrafo.Cuevas {
+		for recurso := range cueva.Recursos {
+			conjuntoRecursos[recurso] = true
+		}
+		for recurso := range cueva.Demanda {
+			conjuntoDemandas[recurso] = true
+		}
+	}
+	recursos := clavesOrdenadasCSV(conjuntoRecursos)
+	demandas := clavesOrdenadasCSV(conjuntoDemandas)
+
+	encabezado := append(append([]string{}, columnasCuevasCSV...), recursos...)
+	for _, recurso := range demandas {
+		encabezado = append(encabezado, prefijoDemandaCSV+recurso)
+	}
+	filasCuevas := [][]string{encabezado}
+	for _, cueva := range dataGrafo.Cuevas {
+		fila := []string{cueva.ID, cueva.Nombre, formatearFloatTXT(cueva.X), formatearFloatTXT(cueva.Y)}
+		fila = append(fila, celdasCantidadesCSV(cueva.Recursos, recursos)...)
+		fila = append(fila, celdasCantidadesCSV(cueva.Demanda, demandas)...)
+		filasCuevas = append(filasCuevas, fila)
+	}
+
+	// En un grafo dirigido todas las aristas se marcan como dirigidas
+	filasAristas := [][]string{columnasAristasCSV}
+	for _, arista := range dataGrafo.Aristas {
+		filasAristas = append(filasAristas, []string{
+			arista.Desde, arista.Hasta, formatearFloatTXT(arista.Distancia),
+			strconv.FormatBool(dataGrafo.EsDirigido || arista.EsDirigido),
+			strconv.FormatBool(arista.EsObstruido),
+			formatearCapacidadCSV(arista.Capacidad),
+			strconv.FormatBool(arista.EsAngosto),
+		})
+	}
+
+	if err := ra.escribirCSV(archivoCuevas, filasCuevas); err != nil {
+		return err
+	}
+	return ra.escribirCSV(archivoAristas, filasAristas)
+}
+
+// CargarMatrizCSV carga un grafo desde una matriz de adyacencia CSV
+func (ra *RepositorioArchivo) CargarMatrizCSV(archivo string) (*domain.Grafo, error) {
+	return exigirSinErrores(ra.cargarMatrizCSVValidado(archivo))
+}
+
+// Celda con túnel de la matriz de adyacencia
+type celdaMatrizCSV struct {
+	arista    *domain.Arista
+	ubicacion string
+}
+
+// carga una matriz de adyacencia. La primera fila y la primera columna tienen
+// los IDs de las cuevas; cada celda es la distancia del túnel fila -> columna,
+// con "*" al final si está obstruido. Las celdas vacías o "-" no tienen túnel;
+// "0" es un túnel de distancia 0 salvo en la diagonal, donde se suele usar como
+// relleno. La matriz simétrica es un grafo no dirigido; si no, uno dirigido.
+func (ra *RepositorioArchivo) cargarMatrizCSVValidado(archivo string) (*domain.Grafo, []ProblemaValidacion, error) {
+	filas, err := ra.leerCSV(archivo)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(filas) == 0 {
+		return nil, nil, fmt.Errorf("%s: la matriz de adyacencia está vacía", archivo)
+	}
+	carga := nuevaCargaValidada(archivo)
+
+	encabezado := filas[0]
+	ids := encabezado.campos[1:]
+	for _, id := range ids {
+		carga.cueva(domain.NuevaCueva(id, id), fmt.Sprintf("línea %d", encabezado.linea))
+	}
+
+	var celdas []celdaMatrizCSV
+	indice := make(map[[2]string]*domain.Arista)
+	for _, fila := range filas[1:] {
+		ubicacion := fmt.Sprintf("línea %d", fila.linea)
+		desde := fila.campos[0]
+		if !carga.cuevas[desde] {
+			carga.problema(ubicacion, "", SeveridadError, "la fila '%s' no está en el encabezado", desde)
+			continue
+		}
+		if len(fila.campos) != len(encabezado.campos) {
+			carga.problema(ubicacion, "", SeveridadError, "se esperaban %d columnas y hay %d", len(encabezado.campos), len(fila.campos))
+			continue
+		}
+
+		for i, texto := range fila.campos[1:] {
+			if texto == "" || texto == "-" || (texto == "0" && desde == ids[i]) {
+				continue
+			}
+			arista := &domain.Arista{Desde: desde, Hasta: ids[i]}
+			if strings.HasSuffix(texto, marcaObstruidoCSV) {
+				arista.EsObstruido = true
+				texto = strings.TrimSpace(strings.TrimSuffix(texto, marcaObstruidoCSV))
+			}
+			distancia, err := parsearDecimalCSV(texto)
+			if err != nil {
+				carga.problema(ubicacion, ids[i], SeveridadError, "distancia inválida '%s'", fila.campos[i+1])
+				continue
+			}
+			arista.Distancia = distancia
+			celdas = append(celdas, celdaMatrizCSV{arista: arista, ubicacion: ubicacion})
+			indice[[2]string{arista.Desde, arista.Hasta}] = arista
+		}
+	}
+
+	esDirigido := false
+	for _, celda := range celdas {
+		inversa, ok := indice[[2]string{celda.arista.Hasta, celda.arista.Desde}]
+		if !ok || inversa.Distancia != celda.arista.Distancia || inversa.EsObstruido != celda.arista.EsObstruido {
+			esDirigido = true
+			break
+		}
+	}
+	carga.constructor.EstablecerDirigido(esDirigido)
+
+	// En la matriz simétrica cada túnel se agrega una vez y se genera su inversa
+	agregadas := make(map[[2]string]bool)
+	for _, celda := range celdas {
+		arista := celda.arista
+		if !esDirigido && agregadas[[2]string{arista.Hasta, arista.Desde}] {
+			continue
+		}
+		arista.EsDirigido = esDirigido
+		agregadas[[2]string{arista.Desde, arista.Hasta}] = true
+		carga.arista(arista, celda.ubicacion)
+	}
+
+	return carga.terminar()
+}
+
+// GuardarMatrizCSV guarda el grafo como matriz de adyacencia CSV. El formato
+// solo conserva los IDs de las cuevas y las distancias de los túneles; las
+// celdas sin túnel quedan vacías para distinguirlas de los túneles de distancia 0.
+func (ra *RepositorioArchivo) GuardarMatrizCSV(grafo *domain.Grafo, archivo string) error {
+	dataGrafo := extraerDatosGrafo(grafo)
+
+	ids := make([]string, len(dataGrafo.Cuevas))
+	posiciones := make(map[string]int, len(dataGrafo.Cuevas))
+	for i, cueva := range dataGrafo.Cuevas {
+		ids[i] = cueva.ID
+		posiciones[cueva.ID] = i + 1
+	}
+
+	filas := make([][]string, 0, len(ids)+1)
+	filas = append(filas, append([]string{"id"}, ids...))
+	for _, id := range ids {
+		fila := make([]string, len(ids)+1)
+		fila[0] = id
+		filas = append(filas, fila)
+	}
+
+	// Las aristas del grafo ya incluyen la inversa de cada túnel no dirigido
+	for _, arista := range grafo.Aristas {
+		desde, okDesde := posiciones[arista.Desde]
+		hasta, okHasta := posiciones[arista.Hasta]
+		if !okDesde || !okHasta {
+			continue
+		}
+		celda := formatearFloatTXT(arista.Distancia)
+		if arista.EsObstruido {
+			celda += marcaObstruidoCSV
+		}
+		filas[desde][hasta] = celda
+	}
+
+	return ra.escribirCSV(archivo, filas)
+}
+
+// Fila de un CSV con su número de línea
+type filaCSV struct {
+	linea  int
+	campos []string
+}
+
+// lee un CSV completo. Admite la marca BOM y el separador ";" que usan las
+// hojas de cálculo en configuración regional española.
+func (ra *RepositorioArchivo) leerCSV(archivo string) ([]filaCSV, error) {
+	data, err := os.ReadFile(filepath.Join(ra.dataDir, archivo))
+	if err != nil {
+		return nil, fmt.Errorf("error reading CSV file: %v", err)
+	}
+	data = bytes.TrimPrefix(data, []byte("\ufeff"))
+
+	lector := csv.NewReader(bytes.NewReader(data))
+	lector.Comma = detectarSeparadorCSV(data)
+	lector.FieldsPerRecord = -1
+	lector.TrimLeadingSpace = true
+
+	var filas []filaCSV
+	for {
+		registro, err := lector.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("error parsing CSV: %s: %v", archivo, err)
+		}
+		linea, _ := lector.FieldPos(0)
+		for i := range registro {
+			registro[i] = strings.TrimSpace(registro[i])
+		}
+		filas = append(filas, filaCSV{linea: linea, campos: registro})
+	}
+	return filas, nil
+}
+
+// escribe las filas en un CSV separado por comas
+func (ra *RepositorioArchivo) escribirCSV(archivo string, filas [][]string) error {
+	file, err := os.Create(filepath.Join(ra.dataDir, archivo))
+	if err != nil {
+		return fmt.Errorf("error creating CSV file: %v", err)
+	}
+	defer file.Close()
+
+	escritor := csv.NewWriter(file)
+	if err := escritor.WriteAll(filas); err != nil {
+		return fmt.Errorf("error writing to CSV file: %v", err)
+	}
+	return nil
+}
+
+// usa ";" si la primera línea lo contiene y no tiene comas
+func detectarSeparadorCSV(data []byte) rune {
+	primera := data
+	if fin := bytes.IndexByte(data, '\n'); fin >= 0 {
+		primera = data[:fin]
+	}
+	if bytes.IndexByte(primera, ';') >= 0 && bytes.IndexByte(primera, ',') < 0 {
+		return ';'
+	}
+	return ','
+}
+
+// Posición de cada columna según el encabezado
+type columnasCSV struct {
+	posiciones map[string]int
+	nombres    []string
+	fijas      map[string]bool
+}
+
+// lee el encabezado de un CSV y exige las columnas obligatorias
+func columnasEncabezadoCSV(archivo string, filas []filaCSV, fijas []string, obligatorias ...string) (columnasCSV, error) {
+	if len(filas) == 0 {
+		return columnasCSV{}, fmt.Errorf("%s: falta la fila de encabezado", archivo)
+	}
+
+	columnas := columnasCSV{
+		posiciones: make(map[string]int),
+		fijas:      make(map[string]bool),
+	}
+	for _, nombre := range fijas {
+		columnas.fijas[nombre] = true
+	}
+	for i, nombre := range filas[0].campos {
+		clave := strings.ToLower(nombre)
+		if _, repetida := columnas.posiciones[clave]; repetida {
+			return columnasCSV{}, fmt.Errorf("%s:línea %d: columna '%s' repetida", archivo, filas[0].linea, nombre)
+		}
+		columnas.posiciones[clave] = i
+		columnas.nombres = append(columnas.nombres, nombre)
+	}
+	for _, nombre := range obligatorias {
+		if _, ok := columnas.posiciones[nombre]; !ok {
+			return columnasCSV{}, fmt.Errorf("%s:línea %d: falta la columna '%s'", archivo, filas[0].linea, nombre)
+		}
+	}
+	return columnas, nil
+}
+
+// valor de una columna en la fila; vacío si la fila o el encabezado no la tienen
+func (c columnasCSV) valor(fila filaCSV, nombre string) string {
+	i, ok := c.posiciones[strings.ToLower(nombre)]
+	if !ok || i >= len(fila.campos) {
+		return ""
+	}
+	return fila.campos[i]
+}
+
+// columnas que no son fijas, en el orden del encabezado
+func (c columnasCSV) desconocidas() []string {
+	var nombres []string
+	for _, nombre := range c.nombres {
+		if !c.fijas[strings.ToLower(nombre)] {
+			nombres = append(nombres, nombre)
+		}
+	}
+	return nombres
+}
+
+// parsea un número aceptando coma decimal
+func parsearDecimalCSV(texto string) (float64, error) {
+	return strconv.ParseFloat(strings.Replace(texto, ",", ".", 1), 64)
+}
+
+// parsea un booleano; vacío equivale a false
+func parsearBoolCSV(texto string) (bool, error) {
+	switch strings.ToLower(texto) {
+	case "", "no":
+		return false, nil
+	case "si", "sí":
+		return true, nil
+	}
+	return strconv.ParseBool(texto)
+}
+
+// la capacidad no definida se deja como celda vacía
+func formatearCapacidadCSV(capacidad float64) string {
+	if capacidad == 0 {
+		return ""
+	}
+	return formatearFloatTXT(capacidad)
+}
+
+// devuelve las claves de un conjunto en orden alfabético
+func clavesOrdenadasCSV(conjunto map[string]bool) []string {
+	claves := make([]string, 0, len(conjunto))
+	for clave := range conjunto {
+		claves = append(claves, clave)
+	}
+	sort.Strings(claves)
+	return claves
+}
+
+// una celda por recurso con su cantidad, o vacía si no está
+func celdasCantidadesCSV(cantidades map[string]int, recursos []string) []string {
+	celdas := make([]string, 0, len(recursos))
+	for _, recurso := range recursos {
+		if cantidad, ok := cantidades[recurso]; ok {
+			celdas = append(celdas, strconv.Itoa(cantidad))
+		} else {
+			celdas = append(celdas, "")
+		}
+	}
+	return celdas
+}
@@ -0,0 +1,173 @@
+package repository
+
+import (
+	"proyecto-grafos-go/internal/domain"
+	"reflect"
+	"testing"
+)
+
+// grafo de prueba con recursos, un túnel con capacidad, uno obstruido y uno de un solo sentido
+func grafoPruebaCSV(esDirigido bool) *domain.Grafo {
+	grafo := domain.NuevoGrafo(esDirigido)
+	for _, datos := range []struct {
+		id, nombre string
+		x, y       float64
+	}{{"A", "Cueva, A", 1.5, -2}, {"B", "Cueva \"B\"", 0, 0}, {"C", "Cueva C", 10, 20}} {
+		cueva := domain.NuevaCueva(datos.id, datos.nombre)
+		cueva.X, cueva.Y = datos.x, datos.y
+		grafo.AgregarCueva(cueva)
+	}
+	grafo.Cuevas["A"].AgregarRecurso("agua", 5)
+	grafo.Cuevas["C"].AgregarRecurso("oro", 2)
+	grafo.Cuevas["A"].EstablecerDemanda("agua", 10)
+	grafo.Cuevas["B"].EstablecerDemanda("comida", 4)
+
+	conCapacidad := domain.NuevaArista("A", "B", 4.25, false)
+	conCapacidad.Capacidad = 12.5
+	grafo.AgregarArista(conCapacidad)
+	obstruida := domain.NuevaArista("B", "C", 7, esDirigido)
+	obstruida.EsObstruido = true
+	grafo.AgregarArista(obstruida)
+	grafo.AgregarArista(domain.NuevaArista("C", "A", 3, true))
+	return grafo
+}
+
+// TestIdaYVueltaCSV verifica el par de CSV y la matriz de adyacencia
+func TestIdaYVueltaCSV(t *testing.T) {
+	dir := t.TempDir()
+	repo := NuevoRepositorio(dir)
+
+	for _, esDirigido := range []bool{false, true} {
+		grafo := grafoPruebaCSV(esDirigido)
+		if esDirigido {
+			// En un grafo dirigido todas las aristas se leen como dirigidas
+			for _, arista := range grafo.Aristas {
+				arista.EsDirigido = true
+			}
+		}
+
+		if err := repo.Guardar("red.csv", grafo); err != nil {
+			t.Fatalf("Error guardando CSV: %v", err)
+		}
+		if !repo.Existe("red.csv") || !repo.Existe("red.cuevas.csv") || !repo.Existe("red.aristas.csv") {
+			t.Fatal("Deben existir el par de archivos CSV")
+		}
+		cargado, err := repo.Cargar("red.aristas.csv")
+		if err != nil {
+			t.Fatalf("Error cargando CSV: %v", err)
+		}
+		if err := compararGrafos(grafo, cargado); err != nil {
+			t.Errorf("dirigido=%t: %v", esDirigido, err)
+		}
+
+		if err := repo.Guardar("red.matriz.csv", grafo); err != nil {
+			t.Fatalf("Error guardando la matriz: %v", err)
+		}
+		matriz, err := repo.Cargar("red.matriz.csv")
+		if err != nil {
+			t.Fatalf("Error cargando la matriz: %v", err)
+		}
+		if matriz.NumeroCuevas() != 3 || !matriz.EsDirigido {
+			t.Errorf("La matriz asimétrica debe cargarse como grafo dirigido: %v", matriz)
+		}
+		for _, arista := range grafo.Aristas {
+			obtenida, ok := matriz.ObtenerConexion(arista.Desde, arista.Hasta)
+			if !ok || obtenida.Distancia != arista.Distancia || obtenida.EsObstruido != arista.EsObstruido {
+				t.Errorf("dirigido=%t: arista %v no se conservó en la matriz: %v", esDirigido, arista, obtenida)
+			}
+		}
+	}
+
+	archivos, err := repo.Listar()
+	if err != nil {
+		t.Fatalf("Error listando: %v", err)
+	}
+	if !reflect.DeepEqual(archivos, []string{"red.csv", "red.matriz.csv"}) {
+		t.Errorf("Listado inesperado: %v", archivos)
+	}
+
+	if err := repo.Eliminar("red.csv"); err != nil || repo.Existe("red.cuevas.csv") || repo.Existe("red.aristas.csv") {
+		t.Errorf("Eliminar debe borrar el par de CSV: %v", err)
+	}
+}
+
+// TestCargarCSVHojaDeCalculo verifica separador ";", coma decimal, BOM y la validación por línea
+func TestCargarCSVHojaDeCalculo(t *testing.T) {
+	dir := t.TempDir()
+	repo := NuevoRepositorio(dir)
+	escribirArchivoPrueba(t, dir, "campo.cuevas.csv", "\ufeffID;Nombre;X;Y;Agua\nA;Entrada;1,5;2;10\nB;Galería;0;0;\nC;Pozo;norte;0;3\n")
+	escribirArchivoPrueba(t, dir, "campo.aristas.csv", "desde;hasta;distancia;obstruido;nota\nA;B;2,5;sí;ok\nB;Z;1;no;\n")
+
+	grafo, problemas, err := repo.CargarConValidacion("campo.csv", ValidacionTolerante)
+	if err != nil {
+		t.Fatalf("El modo tolerante debe cargar: %v", err)
+	}
+	if grafo.EsDirigido || grafo.NumeroCuevas() != 2 || grafo.NumeroAristas() != 2 {
+		t.Errorf("Se esperaba un grafo no dirigido con 2 cuevas y 2 aristas: %v", grafo)
+	}
+	if cueva := grafo.Cuevas["A"]; cueva.X != 1.5 || cueva.Recursos["Agua"] != 10 {
+		t.Errorf("Cueva A mal leída: %+v", cueva)
+	}
+	if arista, ok := grafo.ObtenerConexion("B", "A"); !ok || arista.Distancia != 2.5 || !arista.EsObstruido {
+		t.Errorf("Arista B->A mal leída: %v", arista)
+	}
+
+	esperados := [][]string{
+		{"campo.cuevas.csv:línea 4 (x)", "error", "norte"},
+		{"campo.aristas.csv:línea 1 (nota)", "advertencia"},
+		{"campo.aristas.csv:línea 3", "error", "'Z'"},
+	}
+	for _, esperado := range esperados {
+		if !contieneProblema(problemas, esperado...) {
+			t.Errorf("Falta el problema %v en %v", esperado, problemas)
+		}
+	}
+	if len(problemas) != len(esperados) {
+		t.Errorf("Se esperaban %d problemas: %v", len(esperados), problemas)
+	}
+}
+
+// TestCargarMatrizCSV verifica la lectura de una matriz simétrica
+func TestCargarMatrizCSV(t *testing.T) {
+	dir := t.TempDir()
+	repo := NuevoRepositorio(dir)
+	escribirArchivoPrueba(t, dir, "red.matriz.csv", ",A,B,C\nA,0,3,\nB,3,0,2*\nC,,2*,0\n")
+
+	grafo, err := repo.CargarMatrizCSV("red.matriz.csv")
+	if err != nil {
+		t.Fatalf("Error cargando la matriz: %v", err)
+	}
+	if grafo.EsDirigido || grafo.NumeroCuevas() != 3 || grafo.NumeroAristas() != 4 {
+		t.Errorf("Se esperaba un grafo no dirigido con 3 cuevas y 4 aristas: %v", grafo)
+	}
+	if arista, ok := grafo.ObtenerConexion("C", "B"); !ok || arista.Distancia != 2 || !arista.EsObstruido {
+		t.Errorf("Arista C->B mal leída: %v", arista)
+	}
+
+	// Fuera de la diagonal "0" es un túnel de distancia 0 y sobrevive a la ida y vuelta
+	cero := domain.NuevoGrafo(false)
+	cero.AgregarCueva(domain.NuevaCueva("A", "A"))
+	cero.AgregarCueva(domain.NuevaCueva("B", "B"))
+	cero.AgregarCueva(domain.NuevaCueva("C", "C"))
+	cero.AgregarConexion("A", "B", 0)
+	if err := repo.GuardarMatrizCSV(cero, "cero.matriz.csv"); err != nil {
+		t.Fatalf("Error guardando la matriz: %v", err)
+	}
+	cargado, err := repo.CargarMatrizCSV("cero.matriz.csv")
+	if err != nil {
+		t.Fatalf("Error cargando la matriz: %v", err)
+	}
+	if arista, ok := cargado.ObtenerConexion("B", "A"); !ok || arista.Distancia != 0 || cargado.NumeroAristas() != 2 {
+		t.Errorf("El túnel de distancia 0 debe conservarse: %v", cargado.Aristas)
+	}
+
+	escribirArchivoPrueba(t, dir, "mala.matriz.csv", "id,A,B\nA,,x\nQ,1,\n")
+	_, problemas, err := repo.CargarConValidacion("mala.matriz.csv", ValidacionTolerante)
+	if err != nil {
+		t.Fatalf("El modo tolerante debe cargar: %v", err)
+	}
+	if !contieneProblema(problemas, "mala.matriz.csv:línea 2 (B)", "distancia inválida") ||
+		!contieneProblema(problemas, "línea 3", "'Q'") {
+		t.Errorf("Problemas inesperados: %v", problemas)
+	}
+}
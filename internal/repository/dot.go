@@ -0,0 +1,161 @@
+package repository
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"proyecto-grafos-go/internal/domain"
+	"strings"
+)
+
+// Colores usados para los resaltados sin color explícito
+var coloresResaltadoDOT = []string{"forestgreen", "blue", "darkorange", "purple", "deeppink"}
+
+// ResaltadoDOT es un conjunto de aristas que se dibuja en negrita sobre el grafo
+type ResaltadoDOT struct {
+	Etiqueta string      // Nombre mostrado en la leyenda
+	Color    string      // Color Graphviz; vacío para usar la paleta por defecto
+	Aristas  [][2]string // Pares desde -> hasta
+}
+
+// NuevoResaltadoRuta crea un resaltado con los tramos consecutivos de una ruta
+func NuevoResaltadoRuta(etiqueta, color string, ruta []string) ResaltadoDOT {
+	resaltado := ResaltadoDOT{Etiqueta: etiqueta, Color: color}
+	for i := 0; i+1 < len(ruta); i++ {
+		resaltado.Aristas = append(resaltado.Aristas, [2]string{ruta[i], ruta[i+1]})
+	}
+	return resaltado
+}
+
+// NuevoResaltadoAristas crea un resaltado a partir de aristas (por ejemplo, las de un MST)
+func NuevoResaltadoAristas(etiqueta, color string, aristas []*domain.Arista) ResaltadoDOT {
+	resaltado := ResaltadoDOT{Etiqueta: etiqueta, Color: color}
+	for _, arista := range aristas {
+		resaltado.Aristas = append(resaltado.Aristas, [2]string{arista.Desde, arista.Hasta})
+	}
+	return resaltado
+}
+
+// GuardarDOT exporta el grafo en formato Graphviz DOT. Las cuevas se fijan en
+// sus coordenadas (usar neato), las aristas obstruidas se dibujan discontinuas
+// en rojo y cada resaltado se superpone en negrita con su color.
+func (ra *RepositorioArchivo) GuardarDOT(grafo *domain.Grafo, archivo string, resaltados ...ResaltadoDOT) error {
+	dirArchivo := filepath.Join(ra.dataDir, archivo)
+
+	contenido := generarDOT(extraerDatosGrafo(grafo), resaltados)
+
+	if err := os.WriteFile(dirArchivo, []byte(contenido), 0644); err != nil {
+		return fmt.Errorf("error al escribir el archivo DOT: %v", err)
+	}
+
+	return nil
+}
+
+// genera el texto DOT del grafo con sus resaltados
+func generarDOT(dataGrafo *DataGrafo, resaltados []ResaltadoDOT) string {
+	var sb strings.Builder
+
+	sb.WriteString("digraph cuevas {\n")
+	sb.WriteString("  layout=neato;\n")
+	sb.WriteString("  overlap=false;\n")
+	sb.WriteString("  node [shape=ellipse, style=filled, fillcolor=lightyellow, fontsize=10];\n")
+	sb.WriteString("  edge [fontsize=8];\n")
+
+	// Leyenda con los resaltados
+	colores := make([]string, len(resaltados))
+	if len(resaltados) > 0 {
+		var leyenda []string
+		for i, resaltado := range resaltados {
+			colores[i] = resaltado.Color
+			if colores[i] == "" {
+				colores[i] = coloresResaltadoDOT[i%len(coloresResaltadoDOT)]
+			}
+			leyenda = append(leyenda, fmt.Sprintf("%s: %s", resaltado.Etiqueta, colores[i]))
+		}
+		sb.WriteString(fmt.Sprintf("  label=%s;\n", citarDOT(strings.Join(leyenda, "\n"))))
+		sb.WriteString("  labelloc=b;\n")
+	}
+
+	// Cuevas en sus coordenadas
+	sb.WriteString("\n")
+	for _, cueva := range dataGrafo.Cuevas {
+		etiqueta := cueva.ID
+		if cueva.Nombre != "" && cueva.Nombre != cueva.ID {
+			etiqueta = fmt.Sprintf("%s\n%s", cueva.Nombre, cueva.ID)
+		}
+		sb.WriteString(fmt.Sprintf("  %s [label=%s, pos=\"%s,%s!\"];\n",
+			citarDOT(cueva.ID), citarDOT(etiqueta), formatearFloatTXT(cueva.X), formatearFloatTXT(cueva.Y)))
+	}
+
+	// Resaltado (el último gana) de cada par de cuevas
+	resaltadoDe := make(map[[2]string]int)
+	for i, resaltado := range resaltados {
+		for _, par := range resaltado.Aristas {
+			resaltadoDe[par] = i
+		}
+	}
+	buscarResaltado := func(arista *domain.Arista, noDirigida bool) (int, bool) {
+		i, ok := resaltadoDe[[2]string{arista.Desde, arista.Hasta}]
+		if !ok && noDirigida {
+			i, ok = resaltadoDe[[2]string{arista.Hasta, arista.Desde}]
+		}
+		return i, ok
+	}
+
+	// Aristas del grafo
+	sb.WriteString("\n")
+	dibujadas := make(map[[2]string]bool)
+	for _, arista := range dataGrafo.Aristas {
+		noDirigida := !dataGrafo.EsDirigido && !arista.EsDirigido
+		atributos := []string{fmt.Sprintf("label=%s", citarDOT(formatearFloatTXT(arista.Distancia)))}
+		if noDirigida {
+			atributos = append(atributos, "dir=none")
+		}
+
+		estilos := []string{}
+		color := ""
+		if arista.EsObstruido {
+			estilos = append(estilos, "dashed")
+			color = "red"
+		}
+		if i, ok := buscarResaltado(arista, noDirigida); ok {
+			estilos = append(estilos, "bold")
+			color = colores[i]
+			atributos = append(atributos, "penwidth=3")
+		}
+		if len(estilos) > 0 {
+			atributos = append(atributos, fmt.Sprintf("style=%s", citarDOT(strings.Join(estilos, ","))))
+		}
+		if color != "" {
+			atributos = append(atributos, fmt.Sprintf("color=%s", citarDOT(color)))
+		}
+
+		sb.WriteString(fmt.Sprintf("  %s -> %s [%s];\n", citarDOT(arista.Desde), citarDOT(arista.Hasta), strings.Join(atributos, ", ")))
+
+		dibujadas[[2]string{arista.Desde, arista.Hasta}] = true
+		if noDirigida {
+			dibujadas[[2]string{arista.Hasta, arista.Desde}] = true
+		}
+	}
+
+	// Tramos resaltados sin túnel directo (por ejemplo, saltos de un recorrido)
+	for i, resaltado := range resaltados {
+		for _, par := range resaltado.Aristas {
+			if dibujadas[par] {
+				continue
+			}
+			dibujadas[par] = true
+			sb.WriteString(fmt.Sprintf("  %s -> %s [style=\"bold,dotted\", color=%s, penwidth=3];\n",
+				citarDOT(par[0]), citarDOT(par[1]), citarDOT(colores[i])))
+		}
+	}
+
+	sb.WriteString("}\n")
+	return sb.String()
+}
+
+// cita un identificador o etiqueta DOT
+func citarDOT(texto string) string {
+	reemplazos := strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`, "\r", "")
+	return `"` + reemplazos.Replace(texto) + `"`
+}
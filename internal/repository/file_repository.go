@@ -8,13 +8,23 @@ import (
 	"os"
 	"path/filepath"
 	"proyecto-grafos-go/internal/domain"
+	"sort"
 	"strconv"
 	"strings"
+	"sync"
+	"time"
 )
 
+// VersionFormatoTXT es la versión de la gramática TXT que escribe GuardarTXT.
+// Los archivos sin clave "version" se interpretan como versión 1.
+const VersionFormatoTXT = 1
+
 // Carga y guardado de grafos desde archivos
 type RepositorioArchivo struct {
-	dataDir string
+	dataDir     string
+	backupDir   string           // Directorio de snapshots; vacío los desactiva
+	muSnapshots sync.Mutex       // Serializa la numeración de snapshots
+	reloj       func() time.Time // Reemplazable en pruebas
 }
 
 // Función para crear un nuevo repositorio de archivos
@@ -24,6 +34,14 @@ func NuevoRepositorio(dataDir string) *RepositorioArchivo {
 	}
 }
 
+// Función para crear un repositorio de archivos que guarda un snapshot en backupDir en cada guardado
+func NuevoRepositorioConSnapshots(dataDir, backupDir string) *RepositorioArchivo {
+	return &RepositorioArchivo{
+		dataDir:   dataDir,
+		backupDir: backupDir,
+	}
+}
+
 // Estructura para datos de serialización
 type DataGrafo struct {
 	XMLNombre  xml.Name         `xml:"grafo" json:"-"`
@@ -32,47 +50,79 @@ type DataGrafo struct {
 	EsDirigido bool             `xml:"es_dirigido" json:"es_dirigido"`
 }
 
+// Representación XML del grafo; encoding/xml no puede serializar el mapa de recursos
+type dataGrafoXML struct {
+	XMLNombre  xml.Name         `xml:"grafo"`
+	Cuevas     []*cuevaXML      `xml:"cuevas>cueva"`
+	Aristas    []*domain.Arista `xml:"aristas>arista"`
+	EsDirigido bool             `xml:"es_dirigido"`
+}
+
+// Representación XML de una cueva
+type cuevaXML struct {
+	ID           string                   `xml:"id"`
+	Nombre       string                   `xml:"nombre"`
+	Recursos     []recursoXML             `xml:"recursos>recurso"`
+	Demanda      []recursoXML             `xml:"demanda>recurso"`
+	X            float64                  `xml:"x"`
+	Y            float64                  `xml:"y"`
+	Desconocidos []elementoDesconocidoXML `xml:",any"` // Solo lectura, para advertir
+}
+
+// Representación XML de un recurso de una cueva
+type recursoXML struct {
+	Nombre   string `xml:"nombre,attr"`
+	Cantidad int    `xml:"cantidad,attr"`
+}
+
 // Función para cargar un grafo desde un archivo JSON
 func (ra *RepositorioArchivo) CargarJSON(archivo string) (*domain.Grafo, error) {
+	return exigirSinErrores(ra.cargarJSONValidado(archivo))
+}
+
+// carga un JSON en streaming validando sus elementos
+func (ra *RepositorioArchivo) cargarJSONValidado(archivo string) (*domain.Grafo, []ProblemaValidacion, error) {
 	dirArchivo := filepath.Join(ra.dataDir, archivo)
 
-	data, err := os.ReadFile(dirArchivo)
+	file, err := os.Open(dirArchivo)
 	if err != nil {
-		return nil, fmt.Errorf("error reading JSON file: %v", err)
-	}
-
-	var dataGrafo DataGrafo
-	if err := json.Unmarshal(data, &dataGrafo); err != nil {
-		return nil, fmt.Errorf("error parsing JSON: %v", err)
+		return nil, nil, fmt.Errorf("error reading JSON file: %v", err)
 	}
+	defer file.Close()
 
-	return ra.construirGrafo(&dataGrafo)
+	return decodificarJSONStreaming(file, archivo)
 }
 
 // Función para cargar un grafo desde un archivo XML
 func (ra *RepositorioArchivo) CargarXML(archivo string) (*domain.Grafo, error) {
+	return exigirSinErrores(ra.cargarXMLValidado(archivo))
+}
+
+// carga un XML en streaming validando sus elementos
+func (ra *RepositorioArchivo) cargarXMLValidado(archivo string) (*domain.Grafo, []ProblemaValidacion, error) {
 	dirArchivo := filepath.Join(ra.dataDir, archivo)
 
-	data, err := os.ReadFile(dirArchivo)
+	file, err := os.Open(dirArchivo)
 	if err != nil {
-		return nil, fmt.Errorf("error reading XML file: %v", err)
-	}
-
-	var dataGrafo DataGrafo
-	if err := xml.Unmarshal(data, &dataGrafo); err != nil {
-		return nil, fmt.Errorf("error parsing XML: %v", err)
+		return nil, nil, fmt.Errorf("error reading XML file: %v", err)
 	}
+	defer file.Close()
 
-	return ra.construirGrafo(&dataGrafo)
+	return decodificarXMLStreaming(file, archivo)
 }
 
 // Función para cargar un grafo desde un archivo de texto
 func (ra *RepositorioArchivo) CargarTXT(archivo string) (*domain.Grafo, error) {
+	return exigirSinErrores(ra.cargarTXTValidado(archivo))
+}
+
+// carga un TXT validando cada línea; las líneas mal formadas se reportan con su número
+func (ra *RepositorioArchivo) cargarTXTValidado(archivo string) (*domain.Grafo, []ProblemaValidacion, error) {
 	dirArchivo := filepath.Join(ra.dataDir, archivo)
 
 	file, err := os.Open(dirArchivo)
 	if err != nil {
-		return nil, fmt.Errorf("error opening TXT file: %v", err)
+		return nil, nil, fmt.Errorf("error opening TXT file: %v", err)
 	}
 	defer file.Close()
 
@@ -80,10 +130,24 @@ func (ra *RepositorioArchivo) CargarTXT(archivo string) (*domain.Grafo, error) {
 	dataGrafo.Cuevas = make([]*domain.Cueva, 0)
 	dataGrafo.Aristas = make([]*domain.Arista, 0)
 
+	var ubicaciones ubicacionesDataGrafo
+	var problemas []ProblemaValidacion
+	problemaLinea := func(numLinea int, campo, severidad, formato string, args ...interface{}) {
+		problemas = append(problemas, ProblemaValidacion{
+			Archivo:   archivo,
+			Ubicacion: fmt.Sprintf("línea %d", numLinea),
+			Campo:     campo,
+			Severidad: severidad,
+			Mensaje:   fmt.Sprintf(formato, args...),
+		})
+	}
+
 	scanner := bufio.NewScanner(file)
 	section := ""
+	numLinea := 0
 
 	for scanner.Scan() {
+		numLinea++
 		line := strings.TrimSpace(scanner.Text())
 
 		// Ignorar líneas vacías y comentarios
@@ -94,30 +158,53 @@ func (ra *RepositorioArchivo) CargarTXT(archivo string) (*domain.Grafo, error) {
 		// Identificar secciones
 		if strings.HasPrefix(line, "[") && strings.HasSuffix(line, "]") {
 			section = strings.Trim(line, "[]")
+			if section != "grafo" && section != "cuevas" && section != "aristas" {
+				problemaLinea(numLinea, "", SeveridadAdvertencia, "sección desconocida [%s]; se ignora su contenido", section)
+			}
+			continue
+		}
+
+		// Encabezados del formato anterior (DIRIGIDO=, CUEVAS, CONEXIONES); DIRIGIDO=
+		// solo abre el archivo, después puede ser el comienzo de un ID
+		if seccionLegada, ok := seccionesLegadasTXT[line]; ok {
+			section = seccionLegada
 			continue
 		}
+		if section == "" && strings.HasPrefix(line, "DIRIGIDO=") {
+			line = strings.ToLower(line)
+			section = "grafo"
+		}
 
 		switch section {
 		case "grafo":
+			// Una configuración inválida (por ejemplo, una versión no soportada) impide interpretar el resto
 			if err := ra.parseConfigGrafo(line, &dataGrafo); err != nil {
-				return nil, fmt.Errorf("error analizando la configuración del grafo: %v", err)
+				return nil, problemas, fmt.Errorf("%s:línea %d: error analizando la configuración del grafo: %v", archivo, numLinea, err)
+			}
+			if clave := strings.TrimSpace(strings.SplitN(line, "=", 2)[0]); clave != "dirigido" && clave != "version" {
+				problemaLinea(numLinea, clave, SeveridadAdvertencia, "clave de configuración desconocida '%s'", clave)
 			}
 		case "cuevas":
 			if err := ra.parseLineaCueva(line, &dataGrafo); err != nil {
-				return nil, fmt.Errorf("error analizando la línea de la cueva: %v", err)
+				problemaLinea(numLinea, "", SeveridadError, "error analizando la línea de la cueva: %v", err)
+				continue
 			}
+			ubicaciones.Cuevas = append(ubicaciones.Cuevas, fmt.Sprintf("línea %d", numLinea))
 		case "aristas":
 			if err := ra.parseLineaArista(line, &dataGrafo); err != nil {
-				return nil, fmt.Errorf("error analizando la línea de la arista: %v", err)
+				problemaLinea(numLinea, "", SeveridadError, "error analizando la línea de la arista: %v", err)
+				continue
 			}
+			ubicaciones.Aristas = append(ubicaciones.Aristas, fmt.Sprintf("línea %d", numLinea))
 		}
 	}
 
 	if err := scanner.Err(); err != nil {
-		return nil, fmt.Errorf("error al leer el archivo TXT: %v", err)
+		return nil, problemas, fmt.Errorf("error al leer el archivo TXT: %v", err)
 	}
 
-	return ra.construirGrafo(&dataGrafo)
+	grafo, problemasDatos, err := validarDataGrafo(archivo, &dataGrafo, &ubicaciones)
+	return grafo, append(problemas, problemasDatos...), err
 }
 
 // parsea la configuración del grafo
@@ -137,6 +224,14 @@ func (ra *RepositorioArchivo) parseConfigGrafo(linea string, dataGrafo *DataGraf
 			return fmt.Errorf("valor de dirección inválido: %s", valor)
 		}
 		dataGrafo.EsDirigido = dirigido
+	case "version":
+		version, err := strconv.Atoi(valor)
+		if err != nil {
+			return fmt.Errorf("versión de formato inválida: %s", valor)
+		}
+		if version < 1 || version > VersionFormatoTXT {
+			return fmt.Errorf("versión de formato TXT no soportada: %d (máxima %d)", version, VersionFormatoTXT)
+		}
 	}
 
 	return nil
@@ -144,21 +239,21 @@ func (ra *RepositorioArchivo) parseConfigGrafo(linea string, dataGrafo *DataGraf
 
 // parsea una línea de cueva del archivo TXT
 func (ra *RepositorioArchivo) parseLineaCueva(linea string, dataGrafo *DataGrafo) error {
-	// Formato: ID,Name,X,Y,recurso1:cantidad1,recurso2:cantidad2
-	partes := strings.Split(linea, ",")
+	// Formato: ID,Name,X,Y,recurso1:cantidad1[:demanda1],recurso2:cantidad2[:demanda2]
+	partes := dividirCamposTXT(linea, ',')
 	if len(partes) < 4 {
 		return fmt.Errorf("formato inválido de cueva: %s", linea)
 	}
 
-	id := strings.TrimSpace(partes[0])
-	nombre := strings.TrimSpace(partes[1])
+	id := desescaparCampoTXT(partes[0])
+	nombre := desescaparCampoTXT(partes[1])
 
-	x, err := strconv.ParseFloat(strings.TrimSpace(partes[2]), 64)
+	x, err := strconv.ParseFloat(partes[2], 64)
 	if err != nil {
 		return fmt.Errorf("coordinada X inválida: %s", partes[2])
 	}
 
-	y, err := strconv.ParseFloat(strings.TrimSpace(partes[3]), 64)
+	y, err := strconv.ParseFloat(partes[3], 64)
 	if err != nil {
 		return fmt.Errorf("coordinada Y inválida: %s", partes[3])
 	}
@@ -169,23 +264,36 @@ func (ra *RepositorioArchivo) parseLineaCueva(linea string, dataGrafo *DataGrafo
 
 	// Parsear recursos si existen
 	for i := 4; i < len(partes); i++ {
-		parteRecurso := strings.TrimSpace(partes[i])
+		parteRecurso := partes[i]
 		if parteRecurso == "" {
 			continue
 		}
 
-		partesRecurso := strings.Split(parteRecurso, ":")
-		if len(partesRecurso) != 2 {
+		partesRecurso := dividirCamposTXT(parteRecurso, ':')
+		if len(partesRecurso) != 2 && len(partesRecurso) != 3 {
 			return fmt.Errorf("formato de recurso inválido: %s", parteRecurso)
 		}
 
-		recurso := strings.TrimSpace(partesRecurso[0])
-		cantidad, err := strconv.Atoi(strings.TrimSpace(partesRecurso[1]))
-		if err != nil {
-			return fmt.Errorf("cantidad de recurso inválido: %s", partesRecurso[1])
+		// La cantidad puede quedar vacía si la cueva solo demanda el recurso
+		recurso := desescaparCampoTXT(partesRecurso[0])
+		if partesRecurso[1] != "" || len(partesRecurso) == 2 {
+			cantidad, err := strconv.Atoi(partesRecurso[1])
+			if err != nil {
+				return fmt.Errorf("cantidad de recurso inválido: %s", partesRecurso[1])
+			}
+			cueva.AgregarRecurso(recurso, cantidad)
 		}
 
-		cueva.AgregarRecurso(recurso, cantidad)
+		if len(partesRecurso) == 3 {
+			demanda, err := strconv.Atoi(partesRecurso[2])
+			if err != nil {
+				return fmt.Errorf("demanda de recurso inválida: %s", partesRecurso[2])
+			}
+			if cueva.Demanda == nil {
+				cueva.Demanda = make(map[string]int)
+			}
+			cueva.Demanda[recurso] = demanda
+		}
 	}
 
 	dataGrafo.Cuevas = append(dataGrafo.Cuevas, cueva)
@@ -194,23 +302,23 @@ func (ra *RepositorioArchivo) parseLineaCueva(linea string, dataGrafo *DataGrafo
 
 // parsea una línea de arista del archivo TXT
 func (ra *RepositorioArchivo) parseLineaArista(linea string, dataGrafo *DataGrafo) error {
-	// Formato: From,To,Distance,IsDirected
-	partes := strings.Split(linea, ",")
+	// Formato: From,To,Distance[,IsDirected[,IsObstructed[,Capacity[,IsNarrow]]]]
+	partes := dividirCamposTXT(linea, ',')
 	if len(partes) < 3 {
 		return fmt.Errorf("formado de arista inválido: %s", linea)
 	}
 
-	desde := strings.TrimSpace(partes[0])
-	hasta := strings.TrimSpace(partes[1])
+	desde := desescaparCampoTXT(partes[0])
+	hasta := desescaparCampoTXT(partes[1])
 
-	distancia, err := strconv.ParseFloat(strings.TrimSpace(partes[2]), 64)
+	distancia, err := strconv.ParseFloat(partes[2], 64)
 	if err != nil {
 		return fmt.Errorf("distancia inválida: %s", partes[2])
 	}
 
 	esDirigido := dataGrafo.EsDirigido
 	if len(partes) > 3 {
-		dirigido, err := strconv.ParseBool(strings.TrimSpace(partes[3]))
+		dirigido, err := strconv.ParseBool(partes[3])
 		if err != nil {
 			return fmt.Errorf("valor de dirección inválido: %s", partes[3])
 		}
@@ -218,37 +326,46 @@ func (ra *RepositorioArchivo) parseLineaArista(linea string, dataGrafo *DataGraf
 	}
 
 	arista := domain.NuevaArista(desde, hasta, distancia, esDirigido)
-	dataGrafo.Aristas = append(dataGrafo.Aristas, arista)
 
-	return nil
-}
-
-// Función para construir el grafo
-func (ra *RepositorioArchivo) construirGrafo(dataGrafo *DataGrafo) (*domain.Grafo, error) {
-	grafo := domain.NuevoGrafo(dataGrafo.EsDirigido)
+	if len(partes) > 4 {
+		obstruido, err := strconv.ParseBool(partes[4])
+		if err != nil {
+			return fmt.Errorf("valor de obstrucción inválido: %s", partes[4])
+		}
+		arista.EsObstruido = obstruido
+	}
 
-	// Agregar cuevas
-	for _, cueva := range dataGrafo.Cuevas {
-		if err := grafo.AgregarCueva(cueva); err != nil {
-			return nil, fmt.Errorf("error agregando cueva %s: %v", cueva.ID, err)
+	if len(partes) > 5 {
+		capacidad, err := strconv.ParseFloat(partes[5], 64)
+		if err != nil {
+			return fmt.Errorf("capacidad inválida: %s", partes[5])
 		}
+		arista.Capacidad = capacidad
 	}
 
-	// Agregar aristas
-	for _, arista := range dataGrafo.Aristas {
-		if err := grafo.AgregarArista(arista); err != nil {
-			return nil, fmt.Errorf("error agregando arista %s->%s: %v", arista.Desde, arista.Hasta, err)
+	if len(partes) > 6 {
+		angosto, err := strconv.ParseBool(partes[6])
+		if err != nil {
+			return fmt.Errorf("valor de túnel angosto inválido: %s", partes[6])
 		}
+		arista.EsAngosto = angosto
 	}
 
-	return grafo, nil
+	dataGrafo.Aristas = append(dataGrafo.Aristas, arista)
+
+	return nil
+}
+
+// Función para construir el grafo
+func construirGrafo(dataGrafo *DataGrafo) (*domain.Grafo, error) {
+	return exigirSinErrores(validarDataGrafo("", dataGrafo, nil))
 }
 
 // guardar el grafo en un archivo JSON
 func (ra *RepositorioArchivo) GuardarJSON(grafo *domain.Grafo, archivo string) error {
 	dirArchivo := filepath.Join(ra.dataDir, archivo)
 
-	dataGrafo := ra.extraerDatosGrafo(grafo)
+	dataGrafo := extraerDatosGrafo(grafo)
 
 	data, err := json.MarshalIndent(dataGrafo, "", "  ")
 	if err != nil {
@@ -266,9 +383,9 @@ func (ra *RepositorioArchivo) GuardarJSON(grafo *domain.Grafo, archivo string) e
 func (ra *RepositorioArchivo) GuardarXML(grafo *domain.Grafo, archivo string) error {
 	dirArchivo := filepath.Join(ra.dataDir, archivo)
 
-	dataGrafo := ra.extraerDatosGrafo(grafo)
+	dataXML := haciaDataGrafoXML(extraerDatosGrafo(grafo))
 
-	data, err := xml.MarshalIndent(dataGrafo, "", "  ")
+	data, err := xml.MarshalIndent(dataXML, "", "  ")
 	if err != nil {
 		return fmt.Errorf("error ordenando el XML: %v", err)
 	}
@@ -283,7 +400,7 @@ func (ra *RepositorioArchivo) GuardarXML(grafo *domain.Grafo, archivo string) er
 	return nil
 }
 
-// GuardarTXT guarda un grafo en formato TXT
+// GuardarTXT guarda un grafo en formato TXT con la misma gramática que lee CargarTXT
 func (ra *RepositorioArchivo) GuardarTXT(grafo *domain.Grafo, archivo string) error {
 	dirArchivo := filepath.Join(ra.dataDir, archivo)
 
@@ -293,65 +410,389 @@ func (ra *RepositorioArchivo) GuardarTXT(grafo *domain.Grafo, archivo string) er
 	}
 	defer file.Close()
 
-	writer := bufio.NewWriter(file)
-	defer writer.Flush()
+	dataGrafo := extraerDatosGrafo(grafo)
+
+	var sb strings.Builder
 
 	// Escribir encabezado del grafo
-	_, err = writer.WriteString(fmt.Sprintf("DIRIGIDO=%t\n", grafo.EsDirigido))
-	if err != nil {
-		return fmt.Errorf("error writing to TXT file: %v", err)
-	}
+	sb.WriteString("# Red de cuevas\n")
+	sb.WriteString("[grafo]\n")
+	sb.WriteString(fmt.Sprintf("version=%d\n", VersionFormatoTXT))
+	sb.WriteString(fmt.Sprintf("dirigido=%t\n", dataGrafo.EsDirigido))
 
 	// Escribir cuevas
-	_, err = writer.WriteString("CUEVAS\n")
-	if err != nil {
-		return fmt.Errorf("error writing to TXT file: %v", err)
-	}
-
-	for _, cueva := range grafo.Cuevas {
-		linea := fmt.Sprintf("%s,%s,%.2f,%.2f\n", cueva.ID, cueva.Nombre, cueva.X, cueva.Y)
-		_, err = writer.WriteString(linea)
-		if err != nil {
-			return fmt.Errorf("error writing cave to TXT file: %v", err)
+	sb.WriteString("\n[cuevas]\n")
+	sb.WriteString("# id,nombre,x,y,recurso:cantidad[:demanda],...\n")
+	for _, cueva := range dataGrafo.Cuevas {
+		campos := []string{
+			escaparCampoTXT(cueva.ID),
+			escaparCampoTXT(cueva.Nombre),
+			formatearFloatTXT(cueva.X),
+			formatearFloatTXT(cueva.Y),
 		}
+		for _, recurso := range recursosYDemandaOrdenados(cueva) {
+			campo := escaparCampoTXT(recurso) + ":"
+			if cantidad, ok := cueva.Recursos[recurso]; ok {
+				campo += strconv.Itoa(cantidad)
+			}
+			if demanda, ok := cueva.Demanda[recurso]; ok {
+				campo += ":" + strconv.Itoa(demanda)
+			}
+			campos = append(campos, campo)
+		}
+		sb.WriteString(strings.Join(campos, ","))
+		sb.WriteString("\n")
 	}
 
 	// Escribir aristas
-	_, err = writer.WriteString("CONEXIONES\n")
-	if err != nil {
-		return fmt.Errorf("error writing to TXT file: %v", err)
+	sb.WriteString("\n[aristas]\n")
+	sb.WriteString("# desde,hasta,distancia,dirigido,obstruido[,capacidad[,angosto]]\n")
+	for _, arista := range dataGrafo.Aristas {
+		sb.WriteString(fmt.Sprintf("%s,%s,%s,%t,%t",
+			escaparCampoTXT(arista.Desde), escaparCampoTXT(arista.Hasta),
+			formatearFloatTXT(arista.Distancia), arista.EsDirigido, arista.EsObstruido))
+		if arista.Capacidad != 0 || arista.EsAngosto {
+			sb.WriteString("," + formatearFloatTXT(arista.Capacidad))
+		}
+		if arista.EsAngosto {
+			sb.WriteString(",true")
+		}
+		sb.WriteString("\n")
 	}
 
-	for _, arista := range grafo.Aristas {
-		linea := fmt.Sprintf("%s,%s,%.2f,%t,%t\n",
-			arista.Desde, arista.Hasta, arista.Distancia,
-			arista.EsDirigido, arista.EsObstruido)
-		_, err = writer.WriteString(linea)
-		if err != nil {
-			return fmt.Errorf("error writing edge to TXT file: %v", err)
-		}
+	if _, err := file.WriteString(sb.String()); err != nil {
+		return fmt.Errorf("error writing to TXT file: %v", err)
 	}
 
 	return nil
 }
 
-// extraerDatosGrafo extrae los datos del grafo para serialización
-func (ra *RepositorioArchivo) extraerDatosGrafo(grafo *domain.Grafo) *DataGrafo {
+// extraerDatosGrafo extrae los datos del grafo para serialización.
+// Las cuevas se ordenan por ID y cada arista no dirigida se emite una sola vez
+// cuando su inversa es idéntica.
+func extraerDatosGrafo(grafo *domain.Grafo) *DataGrafo {
 	dataGrafo := &DataGrafo{
 		EsDirigido: grafo.EsDirigido,
+		Cuevas:     make([]*domain.Cueva, 0, len(grafo.Cuevas)),
+		Aristas:    make([]*domain.Arista, 0, len(grafo.Aristas)),
 	}
 
 	// Extraer cuevas
 	for _, cueva := range grafo.Cuevas {
 		dataGrafo.Cuevas = append(dataGrafo.Cuevas, cueva)
 	}
+	sort.Slice(dataGrafo.Cuevas, func(i, j int) bool {
+		return dataGrafo.Cuevas[i].ID < dataGrafo.Cuevas[j].ID
+	})
 
 	// Extraer aristas
-	dataGrafo.Aristas = grafo.Aristas
+	indice := make(map[[2]string]*domain.Arista, len(grafo.Aristas))
+	for _, arista := range grafo.Aristas {
+		indice[[2]string{arista.Desde, arista.Hasta}] = arista
+	}
+	omitidas := make(map[*domain.Arista]bool)
+	for _, arista := range grafo.Aristas {
+		if omitidas[arista] {
+			continue
+		}
+		dataGrafo.Aristas = append(dataGrafo.Aristas, arista)
+
+		if grafo.EsDirigido || arista.EsDirigido {
+			continue
+		}
+		inversa, ok := indice[[2]string{arista.Hasta, arista.Desde}]
+		if ok && inversa != arista && !inversa.EsDirigido &&
+			inversa.Distancia == arista.Distancia && inversa.EsObstruido == arista.EsObstruido &&
+			inversa.Capacidad == arista.Capacidad && inversa.EsAngosto == arista.EsAngosto {
+			omitidas[inversa] = true
+		}
+	}
 
 	return dataGrafo
 }
 
+// convierte los datos del grafo a su representación XML
+func haciaDataGrafoXML(dataGrafo *DataGrafo) *dataGrafoXML {
+	dataXML := &dataGrafoXML{
+		Aristas:    dataGrafo.Aristas,
+		EsDirigido: dataGrafo.EsDirigido,
+	}
+
+	for _, cueva := range dataGrafo.Cuevas {
+		cXML := &cuevaXML{ID: cueva.ID, Nombre: cueva.Nombre, X: cueva.X, Y: cueva.Y}
+		for _, recurso := range recursosYDemandaOrdenados(cueva) {
+			if cantidad, ok := cueva.Recursos[recurso]; ok {
+				cXML.Recursos = append(cXML.Recursos, recursoXML{Nombre: recurso, Cantidad: cantidad})
+			}
+			if demanda, ok := cueva.Demanda[recurso]; ok {
+				cXML.Demanda = append(cXML.Demanda, recursoXML{Nombre: recurso, Cantidad: demanda})
+			}
+		}
+		dataXML.Cuevas = append(dataXML.Cuevas, cXML)
+	}
+
+	return dataXML
+}
+
+// convierte la representación XML de una cueva
+func desdeCuevaXML(cXML *cuevaXML) *domain.Cueva {
+	cueva := domain.NuevaCueva(cXML.ID, cXML.Nombre)
+	cueva.X = cXML.X
+	cueva.Y = cXML.Y
+	for _, recurso := range cXML.Recursos {
+		cueva.AgregarRecurso(recurso.Nombre, recurso.Cantidad)
+	}
+	for _, demanda := range cXML.Demanda {
+		if cueva.Demanda == nil {
+			cueva.Demanda = make(map[string]int)
+		}
+		cueva.Demanda[demanda.Nombre] = demanda.Cantidad
+	}
+	return cueva
+}
+
+// devuelve los nombres de recursos de una cueva en orden alfabético
+func recursosOrdenados(cueva *domain.Cueva) []string {
+	nombres := make([]string, 0, len(cueva.Recursos))
+	for nombre := range cueva.Recursos {
+		nombres = append(nombres, nombre)
+	}
+	sort.Strings(nombres)
+	return nombres
+}
+
+// devuelve los recursos que demanda una cueva en orden alfabético
+func demandaOrdenada(cueva *domain.Cueva) []string {
+	nombres := make([]string, 0, len(cueva.Demanda))
+	for nombre := range cueva.Demanda {
+		nombres = append(nombres, nombre)
+	}
+	sort.Strings(nombres)
+	return nombres
+}
+
+// devuelve los recursos con stock o demanda de una cueva en orden alfabético
+func recursosYDemandaOrdenados(cueva *domain.Cueva) []string {
+	nombres := recursosOrdenados(cueva)
+	for nombre := range cueva.Demanda {
+		if _, ok := cueva.Recursos[nombre]; !ok {
+			nombres = append(nombres, nombre)
+		}
+	}
+	sort.Strings(nombres)
+	return nombres
+}
+
+// Encabezados del formato TXT anterior y su sección equivalente
+var seccionesLegadasTXT = map[string]string{
+	"CUEVAS":     "cuevas",
+	"CONEXIONES": "aristas",
+}
+
+// formatea un número sin pérdida de precisión
+func formatearFloatTXT(valor float64) string {
+	return strconv.FormatFloat(valor, 'g', -1, 64)
+}
+
+// escapa los caracteres reservados de la gramática TXT; los espacios en los
+// extremos también se escapan porque el lector recorta cada campo
+func escaparCampoTXT(campo string) string {
+	runas := []rune(campo)
+	var sb strings.Builder
+	for i, r := range runas {
+		extremo := i == 0 || i == len(runas)-1
+		switch {
+		case r == '\\' || r == ',' || r == ':' || r == '#' || r == '[':
+			sb.WriteRune('\\')
+			sb.WriteRune(r)
+		case r == '\n':
+			sb.WriteString("\\n")
+		case r == '\r':
+			sb.WriteString("\\r")
+		case r == '\t':
+			sb.WriteString("\\t")
+		case r == ' ' && extremo:
+			sb.WriteString("\\s")
+		default:
+			sb.WriteRune(r)
+		}
+	}
+	return sb.String()
+}
+
+// revierte escaparCampoTXT
+func desescaparCampoTXT(campo string) string {
+	if !strings.Contains(campo, "\\") {
+		return campo
+	}
+	var sb strings.Builder
+	escapado := false
+	for _, r := range campo {
+		if escapado {
+			switch r {
+			case 'n':
+				sb.WriteRune('\n')
+			case 'r':
+				sb.WriteRune('\r')
+			case 't':
+				sb.WriteRune('\t')
+			case 's':
+				sb.WriteRune(' ')
+			default:
+				sb.WriteRune(r)
+			}
+			escapado = false
+			continue
+		}
+		if r == '\\' {
+			escapado = true
+			continue
+		}
+		sb.WriteRune(r)
+	}
+	return sb.String()
+}
+
+// divide una línea por el separador ignorando los separadores escapados;
+// los campos conservan sus secuencias de escape y se recortan los espacios
+func dividirCamposTXT(linea string, separador rune) []string {
+	var campos []string
+	var sb strings.Builder
+	escapado := false
+	for _, r := range linea {
+		switch {
+		case escapado:
+			sb.WriteRune(r)
+			escapado = false
+		case r == '\\':
+			sb.WriteRune(r)
+			escapado = true
+		case r == separador:
+			campos = append(campos, strings.TrimSpace(sb.String()))
+			sb.Reset()
+		default:
+			sb.WriteRune(r)
+		}
+	}
+	return append(campos, strings.TrimSpace(sb.String()))
+}
+
+// FormatoArchivo devuelve el formato con que se lee o escribe el archivo según su
+// extensión: "matriz" para la matriz de adyacencia CSV y "json" por defecto
+func FormatoArchivo(archivo string) string {
+	if esMatrizCSV(archivo) {
+		return "matriz"
+	}
+	switch extension := strings.ToLower(filepath.Ext(archivo)); extension {
+	case ".xml", ".txt", ".graphml", ".gexf", ".dot", ".csv":
+		return strings.TrimPrefix(extension, ".")
+	default:
+		return "json"
+	}
+}
+
+// Guardar guarda el grafo eligiendo el formato según la extensión (JSON por defecto)
+func (ra *RepositorioArchivo) Guardar(archivo string, grafo *domain.Grafo) error {
+	return ra.GuardarConMensaje(archivo, grafo, "")
+}
+
+// escribe el archivo con el formato correspondiente a su extensión
+func (ra *RepositorioArchivo) guardarSegunExtension(archivo string, grafo *domain.Grafo) error {
+	switch strings.ToLower(filepath.Ext(archivo)) {
+	case ".xml":
+		return ra.GuardarXML(grafo, archivo)
+	case ".txt":
+		return ra.GuardarTXT(grafo, archivo)
+	case ".graphml":
+		return ra.GuardarGraphML(grafo, archivo)
+	case ".gexf":
+		return ra.GuardarGEXF(grafo, archivo)
+	case ".dot":
+		return ra.GuardarDOT(grafo, archivo)
+	case ".csv":
+		if esMatrizCSV(archivo) {
+			return ra.GuardarMatrizCSV(grafo, archivo)
+		}
+		return ra.GuardarCSV(grafo, archivo)
+	default:
+		return ra.GuardarJSON(grafo, archivo)
+	}
+}
+
+// Cargar carga un grafo eligiendo el formato según la extensión (JSON por defecto)
+func (ra *RepositorioArchivo) Cargar(archivo string) (*domain.Grafo, error) {
+	return exigirSinErrores(ra.cargarValidado(archivo))
+}
+
+// CargarConValidacion carga el archivo y devuelve todos los problemas de validación.
+// En modo tolerante se omiten los elementos inválidos; en modo estricto cualquier
+// problema impide la carga.
+func (ra *RepositorioArchivo) CargarConValidacion(archivo string, modo ModoValidacion) (*domain.Grafo, []ProblemaValidacion, error) {
+	grafo, problemas, err := ra.cargarValidado(archivo)
+	if err != nil {
+		return nil, problemas, err
+	}
+	return aplicarModoValidacion(grafo, problemas, modo)
+}
+
+// carga según la extensión del archivo acumulando los problemas de validación
+func (ra *RepositorioArchivo) cargarValidado(archivo string) (*domain.Grafo, []ProblemaValidacion, error) {
+	switch strings.ToLower(filepath.Ext(archivo)) {
+	case ".xml":
+		return ra.cargarXMLValidado(archivo)
+	case ".txt":
+		return ra.cargarTXTValidado(archivo)
+	case ".graphml":
+		return ra.cargarGraphMLValidado(archivo)
+	case ".gexf":
+		return ra.cargarGEXFValidado(archivo)
+	case ".dot":
+		return nil, nil, fmt.Errorf("el formato DOT solo admite exportación")
+	case ".csv":
+		if esMatrizCSV(archivo) {
+			return ra.cargarMatrizCSVValidado(archivo)
+		}
+		return ra.cargarCSVValidado(archivo)
+	default:
+		return ra.cargarJSONValidado(archivo)
+	}
+}
+
+// Listar devuelve los archivos de grafos del directorio de datos
+func (ra *RepositorioArchivo) Listar() ([]string, error) {
+	return ra.ListarArchivos()
+}
+
+// Eliminar borra un archivo de grafo del directorio de datos
+func (ra *RepositorioArchivo) Eliminar(archivo string) error {
+	if esParCSV(archivo) {
+		archivoCuevas, archivoAristas := ArchivosCSV(archivo)
+		if err := os.Remove(filepath.Join(ra.dataDir, archivoCuevas)); err != nil {
+			return fmt.Errorf("error eliminando el archivo %s: %v", archivoCuevas, err)
+		}
+		archivo = archivoAristas
+	}
+	if err := os.Remove(filepath.Join(ra.dataDir, archivo)); err != nil {
+		return fmt.Errorf("error eliminando el archivo %s: %v", archivo, err)
+	}
+	return nil
+}
+
+// Existe verifica si el archivo existe en el directorio de datos
+func (ra *RepositorioArchivo) Existe(archivo string) bool {
+	if esParCSV(archivo) {
+		archivoCuevas, archivoAristas := ArchivosCSV(archivo)
+		return ra.existeArchivo(archivoCuevas) && ra.existeArchivo(archivoAristas)
+	}
+	return ra.existeArchivo(archivo)
+}
+
+// indica si existe un archivo regular en el directorio de datos
+func (ra *RepositorioArchivo) existeArchivo(archivo string) bool {
+	info, err := os.Stat(filepath.Join(ra.dataDir, archivo))
+	return err == nil && !info.IsDir()
+}
+
 // listar los archivos disponibles en el directorio de datos
 func (ra *RepositorioArchivo) ListarArchivos() ([]string, error) {
 	archivos, err := os.ReadDir(ra.dataDir)
@@ -363,11 +804,31 @@ func (ra *RepositorioArchivo) ListarArchivos() ([]string, error) {
 	for _, archivo := range archivos {
 		if !archivo.IsDir() {
 			ext := filepath.Ext(archivo.Name())
-			if ext == ".json" || ext == ".xml" || ext == ".txt" {
+			if ext == ".json" || ext == ".xml" || ext == ".txt" || ext == ".graphml" || ext == ".gexf" {
 				nArchivos = append(nArchivos, archivo.Name())
+			} else if ext == ".csv" {
+				nArchivos = append(nArchivos, ra.nombreListadoCSV(archivo.Name())...)
 			}
 		}
 	}
 
 	return nArchivos, nil
 }
+
+// los pares CSV completos se listan una vez con su nombre base ("red.csv")
+func (ra *RepositorioArchivo) nombreListadoCSV(nombre string) []string {
+	if esMatrizCSV(nombre) {
+		return []string{nombre}
+	}
+	archivoCuevas, archivoAristas := ArchivosCSV(nombre)
+	if !ra.Existe(nombre) {
+		return []string{nombre}
+	}
+	switch nombre {
+	case archivoCuevas:
+		return []string{strings.TrimSuffix(nombre, sufijoCuevasCSV) + ".csv"}
+	case archivoAristas:
+		return nil
+	}
+	return []string{nombre}
+}
@@ -0,0 +1,438 @@
+package repository
+
+import (
+	"fmt"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"proyecto-grafos-go/internal/domain"
+	"strings"
+	"testing"
+	"time"
+)
+
+// Alfabeto con los caracteres reservados de la gramática TXT
+const alfabetoPrueba = "abcXYZ019 ,:#[]\\=\n\t-_ñá"
+
+// genera una cadena aleatoria con caracteres problemáticos
+func cadenaAleatoria(r *rand.Rand, max int) string {
+	runas := []rune(alfabetoPrueba)
+	n := r.Intn(max + 1)
+	resultado := make([]rune, n)
+	for i := range resultado {
+		resultado[i] = runas[r.Intn(len(runas))]
+	}
+	return string(resultado)
+}
+
+// genera un grafo aleatorio con recursos, coordenadas y aristas variadas
+func grafoAleatorio(r *rand.Rand) *domain.Grafo {
+	grafo := domain.NuevoGrafo(r.Intn(2) == 0)
+
+	numCuevas := 1 + r.Intn(8)
+	ids := make([]string, 0, numCuevas)
+	for i := 0; i < numCuevas; i++ {
+		id := fmt.Sprintf("%d%s", i, cadenaAleatoria(r, 4))
+		cueva := domain.NuevaCueva(id, cadenaAleatoria(r, 10))
+		cueva.X = r.NormFloat64() * 1000
+		cueva.Y = r.Float64() / 3
+		for j := r.Intn(4); j > 0; j-- {
+			cueva.AgregarRecurso(cadenaAleatoria(r, 6), r.Intn(1000)-100)
+		}
+		grafo.AgregarCueva(cueva)
+		ids = append(ids, id)
+	}
+
+	for i := r.Intn(numCuevas * 2); i > 0; i-- {
+		arista := domain.NuevaArista(ids[r.Intn(numCuevas)], ids[r.Intn(numCuevas)], r.Float64()*100, r.Intn(3) == 0)
+		arista.EsObstruido = r.Intn(4) == 0
+		if arista.Desde == arista.Hasta || grafo.ExisteConexion(arista.Desde, arista.Hasta) {
+			continue
+		}
+		// AgregarArista no comprueba si la inversa automática ya existe
+		if !grafo.EsDirigido && grafo.ExisteConexion(arista.Hasta, arista.Desde) {
+			continue
+		}
+		grafo.AgregarArista(arista)
+	}
+
+	// Inversas con atributos distintos a la arista original
+	if !grafo.EsDirigido && len(grafo.Aristas) > 0 {
+		arista := grafo.Aristas[r.Intn(len(grafo.Aristas))]
+		if inversa, ok := grafo.ObtenerConexion(arista.Hasta, arista.Desde); ok {
+			inversa.EsObstruido = !inversa.EsObstruido
+		}
+	}
+
+	return grafo
+}
+
+// asigna capacidades, incluidas las fraccionarias, a dos de cada tres aristas
+// y marca como angosta una de cada cuatro
+func asignarCapacidades(r *rand.Rand, grafo *domain.Grafo) {
+	for _, arista := range grafo.Aristas {
+		if r.Intn(3) > 0 {
+			arista.Capacidad = float64(1+r.Intn(500)) / 4
+		}
+		arista.EsAngosto = r.Intn(4) == 0
+	}
+}
+
+// asigna demanda a la mitad de los recursos con stock y a veces a uno sin stock
+func asignarDemandas(r *rand.Rand, grafo *domain.Grafo) {
+	for _, cueva := range grafo.Cuevas {
+		for recurso := range cueva.Recursos {
+			if r.Intn(2) == 0 {
+				cueva.EstablecerDemanda(recurso, 1+r.Intn(500))
+			}
+		}
+		if r.Intn(2) == 0 {
+			cueva.EstablecerDemanda(cadenaAleatoria(r, 6), 1+r.Intn(500))
+		}
+	}
+}
+
+// compara dos grafos sin depender del orden de cuevas y aristas
+func compararGrafos(esperado, obtenido *domain.Grafo) error {
+	if esperado.EsDirigido != obtenido.EsDirigido {
+		return fmt.Errorf("dirigido: esperado %t, obtenido %t", esperado.EsDirigido, obtenido.EsDirigido)
+	}
+	if len(esperado.Cuevas) != len(obtenido.Cuevas) {
+		return fmt.Errorf("cuevas: esperadas %d, obtenidas %d", len(esperado.Cuevas), len(obtenido.Cuevas))
+	}
+	for id, c := range esperado.Cuevas {
+		o, ok := obtenido.Cuevas[id]
+		if !ok {
+			return fmt.Errorf("falta la cueva %q", id)
+		}
+		if c.Nombre != o.Nombre || c.X != o.X || c.Y != o.Y {
+			return fmt.Errorf("cueva %q: esperada %+v, obtenida %+v", id, c, o)
+		}
+		if len(c.Recursos) != len(o.Recursos) {
+			return fmt.Errorf("cueva %q: recursos esperados %v, obtenidos %v", id, c.Recursos, o.Recursos)
+		}
+		for recurso, cantidad := range c.Recursos {
+			if valor, ok := o.Recursos[recurso]; !ok || valor != cantidad {
+				return fmt.Errorf("cueva %q: recursos esperados %v, obtenidos %v", id, c.Recursos, o.Recursos)
+			}
+		}
+		if len(c.Demanda) != len(o.Demanda) {
+			return fmt.Errorf("cueva %q: demanda esperada %v, obtenida %v", id, c.Demanda, o.Demanda)
+		}
+		for recurso, cantidad := range c.Demanda {
+			if valor, ok := o.Demanda[recurso]; !ok || valor != cantidad {
+				return fmt.Errorf("cueva %q: demanda esperada %v, obtenida %v", id, c.Demanda, o.Demanda)
+			}
+		}
+	}
+	if len(esperado.Aristas) != len(obtenido.Aristas) {
+		return fmt.Errorf("aristas: esperadas %d, obtenidas %d", len(esperado.Aristas), len(obtenido.Aristas))
+	}
+	for _, a := range esperado.Aristas {
+		o, ok := obtenido.ObtenerConexion(a.Desde, a.Hasta)
+		if !ok {
+			return fmt.Errorf("falta la arista %q->%q", a.Desde, a.Hasta)
+		}
+		if *a != *o {
+			return fmt.Errorf("arista esperada %v, obtenida %v", a, o)
+		}
+	}
+	return nil
+}
+
+// TestIdaYVueltaFormatos verifica que guardar y cargar no pierde información en ningún formato
+func TestIdaYVueltaFormatos(t *testing.T) {
+	repo := NuevoRepositorio(t.TempDir())
+	r := rand.New(rand.NewSource(42))
+
+	formatos := []struct {
+		nombre  string
+		guardar func(*domain.Grafo, string) error
+		cargar  func(string) (*domain.Grafo, error)
+	}{
+		{"json", repo.GuardarJSON, repo.CargarJSON},
+		{"xml", repo.GuardarXML, repo.CargarXML},
+		{"txt", repo.GuardarTXT, repo.CargarTXT},
+		{"graphml", repo.GuardarGraphML, repo.CargarGraphML},
+		{"gexf", repo.GuardarGEXF, repo.CargarGEXF},
+	}
+
+	for i := 0; i < 200; i++ {
+		grafo := grafoAleatorio(r)
+		for _, formato := range formatos {
+			archivo := "grafo." + formato.nombre
+			if err := formato.guardar(grafo, archivo); err != nil {
+				t.Fatalf("iteración %d, %s: error al guardar: %v", i, formato.nombre, err)
+			}
+			cargado, err := formato.cargar(archivo)
+			if err != nil {
+				t.Fatalf("iteración %d, %s: error al cargar: %v", i, formato.nombre, err)
+			}
+			if err := compararGrafos(grafo, cargado); err != nil {
+				t.Fatalf("iteración %d, %s: %v", i, formato.nombre, err)
+			}
+		}
+	}
+}
+
+// TestIdaYVueltaCapacidad verifica que la capacidad de los túneles y la marca de
+// angosto se conserven
+func TestIdaYVueltaCapacidad(t *testing.T) {
+	repo := NuevoRepositorio(t.TempDir())
+	r := rand.New(rand.NewSource(16))
+
+	for i := 0; i < 50; i++ {
+		grafo := grafoAleatorio(r)
+		asignarCapacidades(r, grafo)
+		for _, archivo := range []string{"grafo.json", "grafo.xml", "grafo.txt", "grafo.graphml", "grafo.gexf"} {
+			if err := repo.Guardar(archivo, grafo); err != nil {
+				t.Fatalf("iteración %d, %s: error al guardar: %v", i, archivo, err)
+			}
+			cargado, err := repo.Cargar(archivo)
+			if err != nil {
+				t.Fatalf("iteración %d, %s: error al cargar: %v", i, archivo, err)
+			}
+			if err := compararGrafos(grafo, cargado); err != nil {
+				t.Fatalf("iteración %d, %s: %v", i, archivo, err)
+			}
+		}
+	}
+}
+
+// TestIdaYVueltaDemanda verifica que la demanda de las cuevas sobreviva al guardado,
+// incluida la de recursos de los que la cueva no tiene stock
+func TestIdaYVueltaDemanda(t *testing.T) {
+	repo := NuevoRepositorio(t.TempDir())
+	r := rand.New(rand.NewSource(21))
+
+	for i := 0; i < 50; i++ {
+		grafo := grafoAleatorio(r)
+		asignarDemandas(r, grafo)
+		for _, archivo := range []string{"grafo.json", "grafo.xml", "grafo.txt", "grafo.graphml", "grafo.gexf"} {
+			if err := repo.Guardar(archivo, grafo); err != nil {
+				t.Fatalf("iteración %d, %s: error al guardar: %v", i, archivo, err)
+			}
+			cargado, err := repo.Cargar(archivo)
+			if err != nil {
+				t.Fatalf("iteración %d, %s: error al cargar: %v", i, archivo, err)
+			}
+			if err := compararGrafos(grafo, cargado); err != nil {
+				t.Fatalf("iteración %d, %s: %v", i, archivo, err)
+			}
+		}
+	}
+}
+
+// TestCargarTXTVersionNoSoportada verifica que se rechacen versiones futuras del formato
+func TestCargarTXTVersionNoSoportada(t *testing.T) {
+	dir := t.TempDir()
+	repo := NuevoRepositorio(dir)
+	contenido := fmt.Sprintf("[grafo]\nversion=%d\ndirigido=false\n", VersionFormatoTXT+1)
+	if err := os.WriteFile(filepath.Join(dir, "futuro.txt"), []byte(contenido), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := repo.CargarTXT("futuro.txt"); err == nil {
+		t.Error("Se esperaba error para una versión de formato no soportada")
+	}
+}
+
+// TestCargarTXTFormatoAnterior verifica la lectura de archivos escritos por el GuardarTXT anterior
+func TestCargarTXTFormatoAnterior(t *testing.T) {
+	dir := t.TempDir()
+	repo := NuevoRepositorio(dir)
+	contenido := "DIRIGIDO=true\nCUEVAS\nA,Cueva A,1.00,2.00\nB,Cueva B,3.00,4.00\nCONEXIONES\nA,B,5.00,true,true\n"
+	if err := os.WriteFile(filepath.Join(dir, "anterior.txt"), []byte(contenido), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	grafo, err := repo.CargarTXT("anterior.txt")
+	if err != nil {
+		t.Fatalf("Error inesperado: %v", err)
+	}
+	if !grafo.EsDirigido || len(grafo.Cuevas) != 2 || len(grafo.Aristas) != 1 {
+		t.Fatalf("Grafo inesperado: %v", grafo)
+	}
+	if !grafo.Aristas[0].EsObstruido {
+		t.Error("La arista debería estar obstruida")
+	}
+}
+
+// TestCargarTXTIDConEncabezadoAnterior verifica que un ID que empieza como el
+// encabezado DIRIGIDO= del formato anterior se lea como cueva
+func TestCargarTXTIDConEncabezadoAnterior(t *testing.T) {
+	dir := t.TempDir()
+	repo := NuevoRepositorio(dir)
+	grafo := domain.NuevoGrafo(true)
+	grafo.AgregarCueva(domain.NuevaCueva("DIRIGIDO=false", "Cueva rara"))
+	grafo.AgregarCueva(domain.NuevaCueva("B", "Cueva B"))
+	grafo.AgregarConexion("DIRIGIDO=false", "B", 2)
+
+	if err := repo.GuardarTXT(grafo, "encabezado.txt"); err != nil {
+		t.Fatalf("Error al guardar: %v", err)
+	}
+	cargado, err := repo.CargarTXT("encabezado.txt")
+	if err != nil {
+		t.Fatalf("Error al cargar: %v", err)
+	}
+	if err := compararGrafos(grafo, cargado); err != nil {
+		t.Error(err)
+	}
+}
+
+// TestCargarGraphMLExterno verifica la lectura de un GraphML escrito por otra herramienta
+func TestCargarGraphMLExterno(t *testing.T) {
+	dir := t.TempDir()
+	repo := NuevoRepositorio(dir)
+	contenido := `<?xml version="1.0" encoding="UTF-8"?>
+<graphml xmlns="http://graphml.graphdrawing.org/xmlns">
+  <key id="d0" for="node" attr.name="label" attr.type="string"/>
+  <key id="d1" for="node" attr.name="recurso:agua" attr.type="int"><default>1</default></key>
+  <key id="d2" for="edge" attr.name="weight" attr.type="double"/>
+  <graph id="G" edgedefault="directed">
+    <node id="A"><data key="d0">Entrada</data><data key="d1">7</data></node>
+    <node id="B"/>
+    <edge source="A" target="B"><data key="d2">4.5</data></edge>
+    <edge source="B" target="A" directed="false"/>
+  </graph>
+</graphml>`
+	if err := os.WriteFile(filepath.Join(dir, "externo.graphml"), []byte(contenido), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	grafo, err := repo.CargarGraphML("externo.graphml")
+	if err != nil {
+		t.Fatalf("Error inesperado: %v", err)
+	}
+	if !grafo.EsDirigido {
+		t.Error("El grafo debería ser dirigido")
+	}
+	if grafo.Cuevas["A"].Nombre != "Entrada" || grafo.Cuevas["A"].Recursos["agua"] != 7 {
+		t.Errorf("Cueva A inesperada: %+v", grafo.Cuevas["A"])
+	}
+	if grafo.Cuevas["B"].Recursos["agua"] != 1 {
+		t.Errorf("Cueva B debería tener el recurso por defecto: %+v", grafo.Cuevas["B"])
+	}
+	arista, ok := grafo.ObtenerConexion("A", "B")
+	if !ok || arista.Distancia != 4.5 || !arista.EsDirigido {
+		t.Errorf("Arista A->B inesperada: %v", arista)
+	}
+	if inversa, ok := grafo.ObtenerConexion("B", "A"); !ok || inversa.EsDirigido || inversa.Distancia != 1 {
+		t.Errorf("Arista B->A inesperada: %v", inversa)
+	}
+}
+
+// TestCargarGEXFExterno verifica la lectura de un GEXF exportado por Gephi
+func TestCargarGEXFExterno(t *testing.T) {
+	dir := t.TempDir()
+	repo := NuevoRepositorio(dir)
+	contenido := `<?xml version="1.0" encoding="UTF-8"?>
+<gexf xmlns="http://www.gexf.net/1.2draft" xmlns:viz="http://www.gexf.net/1.2draft/viz" version="1.2">
+  <graph defaultedgetype="undirected" mode="static">
+    <attributes class="node">
+      <attribute id="0" title="recurso:oro" type="integer"/>
+    </attributes>
+    <nodes>
+      <node id="A" label="Entrada">
+        <attvalues><attvalue for="0" value="3"/></attvalues>
+        <viz:position x="1.5" y="-2" z="0"/>
+      </node>
+      <node id="B" label="Salida"/>
+    </nodes>
+    <edges>
+      <edge id="0" source="A" target="B" weight="12"/>
+    </edges>
+  </graph>
+</gexf>`
+	if err := os.WriteFile(filepath.Join(dir, "gephi.gexf"), []byte(contenido), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	grafo, err := repo.CargarGEXF("gephi.gexf")
+	if err != nil {
+		t.Fatalf("Error inesperado: %v", err)
+	}
+	cueva := grafo.Cuevas["A"]
+	if cueva.Nombre != "Entrada" || cueva.X != 1.5 || cueva.Y != -2 || cueva.Recursos["oro"] != 3 {
+		t.Errorf("Cueva A inesperada: %+v", cueva)
+	}
+	if len(grafo.Aristas) != 2 {
+		t.Fatalf("Se esperaban 2 aristas (no dirigida), obtenidas %d", len(grafo.Aristas))
+	}
+	if arista, ok := grafo.ObtenerConexion("B", "A"); !ok || arista.Distancia != 12 {
+		t.Errorf("Arista B->A inesperada: %v", arista)
+	}
+}
+
+// TestGenerarDOT verifica posiciones, obstrucciones y resaltados en la exportación DOT
+func TestGenerarDOT(t *testing.T) {
+	grafo := domain.NuevoGrafo(false)
+	for i, id := range []string{"A", "B", "C"} {
+		cueva := domain.NuevaCueva(id, "Cueva "+id)
+		cueva.X = float64(i * 10)
+		cueva.Y = 2.5
+		grafo.AgregarCueva(cueva)
+	}
+	grafo.AgregarConexion("A", "B", 4)
+	grafo.AgregarConexion("B", "C", 6)
+	for _, arista := range grafo.Aristas {
+		if arista.Desde == "C" || arista.Hasta == "C" {
+			arista.EsObstruido = true
+		}
+	}
+
+	dot := generarDOT(extraerDatosGrafo(grafo), []ResaltadoDOT{
+		NuevoResaltadoRuta("Ruta", "blue", []string{"B", "A", "C"}),
+	})
+
+	esperados := []string{
+		`"A" [label="Cueva A\nA", pos="0,2.5!"];`,
+		`"A" -> "B" [label="4", dir=none, penwidth=3, style="bold", color="blue"];`,
+		`"B" -> "C" [label="6", dir=none, style="dashed", color="red"];`,
+		`"A" -> "C" [style="bold,dotted", color="blue", penwidth=3];`,
+	}
+	for _, esperado := range esperados {
+		if !strings.Contains(dot, esperado) {
+			t.Errorf("Falta %q en:\n%s", esperado, dot)
+		}
+	}
+	if strings.Count(dot, "->") != 3 {
+		t.Errorf("Se esperaban 3 aristas dibujadas:\n%s", dot)
+	}
+}
+
+// TestCargarRedGrande verifica que la carga en streaming escala a redes de 100k cuevas
+func TestCargarRedGrande(t *testing.T) {
+	if testing.Short() {
+		t.Skip("red grande omitida en modo corto")
+	}
+
+	const cuevas = 100000
+	constructor := domain.NuevoConstructorGrafo()
+	for i := 0; i < cuevas; i++ {
+		constructor.AgregarCueva(domain.NuevaCueva(fmt.Sprintf("C%d", i), ""))
+		if i > 0 {
+			constructor.AgregarArista(domain.NuevaArista(fmt.Sprintf("C%d", i-1), fmt.Sprintf("C%d", i), 1, false))
+		}
+	}
+	grafo, err := constructor.Construir()
+	if err != nil {
+		t.Fatalf("Error construyendo la red: %v", err)
+	}
+
+	repo := NuevoRepositorio(t.TempDir())
+	for _, archivo := range []string{"red.json", "red.xml"} {
+		if err := repo.Guardar(archivo, grafo); err != nil {
+			t.Fatalf("%s: error al guardar: %v", archivo, err)
+		}
+		inicio := time.Now()
+		cargado, err := repo.Cargar(archivo)
+		if err != nil {
+			t.Fatalf("%s: error al cargar: %v", archivo, err)
+		}
+		if cargado.NumeroCuevas() != cuevas || cargado.NumeroAristas() != 2*(cuevas-1) {
+			t.Fatalf("%s: se cargaron %d cuevas y %d aristas", archivo, cargado.NumeroCuevas(), cargado.NumeroAristas())
+		}
+		t.Logf("%s: cargado en %v", archivo, time.Since(inicio))
+	}
+}
@@ -0,0 +1,328 @@
+package repository
+
+import (
+	"encoding/xml"
+	"fmt"
+	"os"
+	"path/filepath"
+	"proyecto-grafos-go/internal/domain"
+	"strconv"
+	"strings"
+)
+
+// Documento GEXF 1.3 (formato nativo de Gephi)
+type documentoGEXF struct {
+	XMLName  xml.Name  `xml:"gexf"`
+	Xmlns    string    `xml:"xmlns,attr,omitempty"`
+	XmlnsViz string    `xml:"xmlns:viz,attr,omitempty"`
+	Version  string    `xml:"version,attr,omitempty"`
+	Grafo    grafoGEXF `xml:"graph"`
+}
+
+// Grafo GEXF con sus declaraciones de atributos
+type grafoGEXF struct {
+	TipoAristaPorDefecto string          `xml:"defaultedgetype,attr"`
+	Modo                 string          `xml:"mode,attr,omitempty"`
+	Atributos            []atributosGEXF `xml:"attributes"`
+	Nodos                []nodoGEXF      `xml:"nodes>node"`
+	Aristas              []aristaGEXF    `xml:"edges>edge"`
+}
+
+// Declaración de atributos para nodos o aristas
+type atributosGEXF struct {
+	Clase     string         `xml:"class,attr"`
+	Atributos []atributoGEXF `xml:"attribute"`
+}
+
+// Atributo declarado
+type atributoGEXF struct {
+	ID      string `xml:"id,attr"`
+	Titulo  string `xml:"title,attr"`
+	Tipo    string `xml:"type,attr"`
+	Defecto string `xml:"default,omitempty"`
+}
+
+// Nodo GEXF
+type nodoGEXF struct {
+	ID       string        `xml:"id,attr"`
+	Etiqueta *string       `xml:"label,attr,omitempty"`
+	Valores  []valorGEXF   `xml:"attvalues>attvalue"`
+	Posicion *posicionGEXF `xml:"position"`
+}
+
+// Arista GEXF; Tipo es opcional y sobreescribe defaultedgetype
+type aristaGEXF struct {
+	ID      string      `xml:"id,attr"`
+	Desde   string      `xml:"source,attr"`
+	Hasta   string      `xml:"target,attr"`
+	Tipo    string      `xml:"type,attr,omitempty"`
+	Peso    string      `xml:"weight,attr,omitempty"`
+	Valores []valorGEXF `xml:"attvalues>attvalue"`
+}
+
+// Valor de un atributo
+type valorGEXF struct {
+	Para  string `xml:"for,attr"`
+	Valor string `xml:"value,attr"`
+}
+
+// Posición de visualización (espacio de nombres viz)
+type posicionGEXF struct {
+	X float64 `xml:"x,attr"`
+	Y float64 `xml:"y,attr"`
+	Z float64 `xml:"z,attr"`
+}
+
+// MarshalXML escribe la posición con el prefijo viz declarado en la raíz;
+// al leer se acepta cualquier espacio de nombres
+func (p posicionGEXF) MarshalXML(e *xml.Encoder, inicio xml.StartElement) error {
+	inicio.Name = xml.Name{Local: "viz:position"}
+	inicio.Attr = []xml.Attr{
+		{Name: xml.Name{Local: "x"}, Value: formatearFloatTXT(p.X)},
+		{Name: xml.Name{Local: "y"}, Value: formatearFloatTXT(p.Y)},
+		{Name: xml.Name{Local: "z"}, Value: formatearFloatTXT(p.Z)},
+	}
+	if err := e.EncodeToken(inicio); err != nil {
+		return err
+	}
+	return e.EncodeToken(inicio.End())
+}
+
+// CargarGEXF carga un grafo desde un archivo GEXF
+func (ra *RepositorioArchivo) CargarGEXF(archivo string) (*domain.Grafo, error) {
+	return exigirSinErrores(ra.cargarGEXFValidado(archivo))
+}
+
+// carga un GEXF validando sus elementos
+func (ra *RepositorioArchivo) cargarGEXFValidado(archivo string) (*domain.Grafo, []ProblemaValidacion, error) {
+	dirArchivo := filepath.Join(ra.dataDir, archivo)
+
+	data, err := os.ReadFile(dirArchivo)
+	if err != nil {
+		return nil, nil, fmt.Errorf("error reading GEXF file: %v", err)
+	}
+
+	var documento documentoGEXF
+	if err := xml.Unmarshal(data, &documento); err != nil {
+		return nil, nil, fmt.Errorf("error parsing GEXF: %v", err)
+	}
+
+	dataGrafo, err := desdeDocumentoGEXF(&documento)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return validarDataGrafo(archivo, dataGrafo, ubicacionesPorElemento(dataGrafo, "node", "edge"))
+}
+
+// GuardarGEXF guarda el grafo en un archivo GEXF
+func (ra *RepositorioArchivo) GuardarGEXF(grafo *domain.Grafo, archivo string) error {
+	dirArchivo := filepath.Join(ra.dataDir, archivo)
+
+	documento := haciaDocumentoGEXF(extraerDatosGrafo(grafo))
+
+	data, err := xml.MarshalIndent(documento, "", "  ")
+	if err != nil {
+		return fmt.Errorf("error ordenando el GEXF: %v", err)
+	}
+
+	if err := os.WriteFile(dirArchivo, []byte(xml.Header+string(data)), 0644); err != nil {
+		return fmt.Errorf("error al escribir el archivo GEXF: %v", err)
+	}
+
+	return nil
+}
+
+// convierte los datos del grafo a un documento GEXF
+func haciaDocumentoGEXF(dataGrafo *DataGrafo) *documentoGEXF {
+	tipoPorDefecto := "undirected"
+	if dataGrafo.EsDirigido {
+		tipoPorDefecto = "directed"
+	}
+
+	atributosNodo := atributosGEXF{Clase: "node"}
+	atributosArista := atributosGEXF{
+		Clase: "edge",
+		Atributos: []atributoGEXF{
+			{ID: "es_dirigido", Titulo: "es_dirigido", Tipo: "boolean"},
+			{ID: "es_obstruido", Titulo: "es_obstruido", Tipo: "boolean"},
+			{ID: "capacidad", Titulo: "capacidad", Tipo: "double"},
+			{ID: "es_angosto", Titulo: "es_angosto", Tipo: "boolean"},
+		},
+	}
+
+	// Un atributo por cada recurso y cada demanda distintos
+	idsRecurso := make(map[string]string)
+	idsDemanda := make(map[string]string)
+	nodos := make([]nodoGEXF, 0, len(dataGrafo.Cuevas))
+	for _, cueva := range dataGrafo.Cuevas {
+		nodo := nodoGEXF{
+			ID:       cueva.ID,
+			Etiqueta: &cueva.Nombre,
+			Posicion: &posicionGEXF{X: cueva.X, Y: cueva.Y},
+		}
+		for _, recurso := range recursosOrdenados(cueva) {
+			id, ok := idsRecurso[recurso]
+			if !ok {
+				id = fmt.Sprintf("r%d", len(idsRecurso))
+				idsRecurso[recurso] = id
+				atributosNodo.Atributos = append(atributosNodo.Atributos, atributoGEXF{
+					ID: id, Titulo: prefijoAtributoRecurso + recurso, Tipo: "integer",
+				})
+			}
+			nodo.Valores = append(nodo.Valores, valorGEXF{Para: id, Valor: strconv.Itoa(cueva.Recursos[recurso])})
+		}
+		for _, recurso := range demandaOrdenada(cueva) {
+			id, ok := idsDemanda[recurso]
+			if !ok {
+				id = fmt.Sprintf("d%d", len(idsDemanda))
+				idsDemanda[recurso] = id
+				atributosNodo.Atributos = append(atributosNodo.Atributos, atributoGEXF{
+					ID: id, Titulo: prefijoAtributoDemanda + recurso, Tipo: "integer",
+				})
+			}
+			nodo.Valores = append(nodo.Valores, valorGEXF{Para: id, Valor: strconv.Itoa(cueva.Demanda[recurso])})
+		}
+		nodos = append(nodos, nodo)
+	}
+
+	aristas := make([]aristaGEXF, 0, len(dataGrafo.Aristas))
+	for i, arista := range dataGrafo.Aristas {
+		tipo := "undirected"
+		if dataGrafo.EsDirigido || arista.EsDirigido {
+			tipo = "directed"
+		}
+		aristaGX := aristaGEXF{
+			ID:    strconv.Itoa(i),
+			Desde: arista.Desde,
+			Hasta: arista.Hasta,
+			Tipo:  tipo,
+			Peso:  formatearFloatTXT(arista.Distancia),
+			Valores: []valorGEXF{
+				{Para: "es_dirigido", Valor: strconv.FormatBool(arista.EsDirigido)},
+				{Para: "es_obstruido", Valor: strconv.FormatBool(arista.EsObstruido)},
+			},
+		}
+		if arista.Capacidad != 0 {
+			aristaGX.Valores = append(aristaGX.Valores, valorGEXF{Para: "capacidad", Valor: formatearFloatTXT(arista.Capacidad)})
+		}
+		if arista.EsAngosto {
+			aristaGX.Valores = append(aristaGX.Valores, valorGEXF{Para: "es_angosto", Valor: "true"})
+		}
+		aristas = append(aristas, aristaGX)
+	}
+
+	return &documentoGEXF{
+		Xmlns:    "http://gexf.net/1.3",
+		XmlnsViz: "http://gexf.net/1.3/viz",
+		Version:  "1.3",
+		Grafo: grafoGEXF{
+			TipoAristaPorDefecto: tipoPorDefecto,
+			Modo:                 "static",
+			Atributos:            []atributosGEXF{atributosNodo, atributosArista},
+			Nodos:                nodos,
+			Aristas:              aristas,
+		},
+	}
+}
+
+// convierte un documento GEXF a los datos del grafo
+func desdeDocumentoGEXF(documento *documentoGEXF) (*DataGrafo, error) {
+	grafoGX := documento.Grafo
+
+	// Títulos de atributos por clase e ID
+	titulos := map[string]map[string]atributoGEXF{"node": {}, "edge": {}}
+	for _, declaracion := range grafoGX.Atributos {
+		if _, ok := titulos[declaracion.Clase]; !ok {
+			continue
+		}
+		for _, atributo := range declaracion.Atributos {
+			titulos[declaracion.Clase][atributo.ID] = atributo
+		}
+	}
+
+	dataGrafo := &DataGrafo{
+		EsDirigido: grafoGX.TipoAristaPorDefecto == "directed",
+		Cuevas:     make([]*domain.Cueva, 0, len(grafoGX.Nodos)),
+		Aristas:    make([]*domain.Arista, 0, len(grafoGX.Aristas)),
+	}
+
+	for _, nodo := range grafoGX.Nodos {
+		nombre := nodo.ID
+		if nodo.Etiqueta != nil {
+			nombre = *nodo.Etiqueta
+		}
+		cueva := domain.NuevaCueva(nodo.ID, nombre)
+		if nodo.Posicion != nil {
+			cueva.X = nodo.Posicion.X
+			cueva.Y = nodo.Posicion.Y
+		}
+
+		for titulo, valor := range valoresGEXF(titulos["node"], nodo.Valores) {
+			var err error
+			if recurso, esRecurso := strings.CutPrefix(titulo, prefijoAtributoRecurso); esRecurso {
+				var cantidad int
+				cantidad, err = strconv.Atoi(strings.TrimSpace(valor))
+				cueva.AgregarRecurso(recurso, cantidad)
+			} else if recurso, esDemanda := strings.CutPrefix(titulo, prefijoAtributoDemanda); esDemanda {
+				err = aplicarDemanda(cueva, recurso, valor)
+			}
+			if err != nil {
+				return nil, fmt.Errorf("valor inválido para %s en el nodo %s: %s", titulo, nodo.ID, valor)
+			}
+		}
+
+		dataGrafo.Cuevas = append(dataGrafo.Cuevas, cueva)
+	}
+
+	for _, aristaGX := range grafoGX.Aristas {
+		esDirigido := dataGrafo.EsDirigido
+		switch aristaGX.Tipo {
+		case "directed":
+			esDirigido = true
+		case "undirected":
+			esDirigido = false
+		}
+
+		arista := domain.NuevaArista(aristaGX.Desde, aristaGX.Hasta, 1, esDirigido)
+		valores := valoresGEXF(titulos["edge"], aristaGX.Valores)
+		if aristaGX.Peso != "" {
+			valores["weight"] = aristaGX.Peso
+		}
+
+		if err := aplicarAtributosArista(arista, valores); err != nil {
+			return nil, err
+		}
+
+		dataGrafo.Aristas = append(dataGrafo.Aristas, arista)
+	}
+
+	return dataGrafo, nil
+}
+
+// resuelve los valores de un elemento a pares título -> valor,
+// incluyendo los valores por defecto de los atributos declarados
+func valoresGEXF(atributos map[string]atributoGEXF, valoresElemento []valorGEXF) map[string]string {
+	valores := make(map[string]string)
+	for _, atributo := range atributos {
+		if atributo.Defecto != "" {
+			valores[tituloAtributoGEXF(atributo)] = atributo.Defecto
+		}
+	}
+	for _, valor := range valoresElemento {
+		titulo := valor.Para
+		if atributo, ok := atributos[valor.Para]; ok {
+			titulo = tituloAtributoGEXF(atributo)
+		}
+		valores[titulo] = valor.Valor
+	}
+	return valores
+}
+
+// título del atributo; si falta se usa su ID
+func tituloAtributoGEXF(atributo atributoGEXF) string {
+	if atributo.Titulo != "" {
+		return atributo.Titulo
+	}
+	return atributo.ID
+}
@@ -0,0 +1,333 @@
+package repository
+
+import (
+	"encoding/xml"
+	"fmt"
+	"os"
+	"path/filepath"
+	"proyecto-grafos-go/internal/domain"
+	"strconv"
+	"strings"
+)
+
+// Prefijos de los atributos de nodo que representan el stock y la demanda
+// de recursos de una cueva
+const (
+	prefijoAtributoRecurso = "recurso:"
+	prefijoAtributoDemanda = "demanda:"
+)
+
+// Documento GraphML (http://graphml.graphdrawing.org)
+type documentoGraphML struct {
+	XMLName xml.Name       `xml:"graphml"`
+	Xmlns   string         `xml:"xmlns,attr,omitempty"`
+	Claves  []claveGraphML `xml:"key"`
+	Grafos  []grafoGraphML `xml:"graph"`
+}
+
+// Declaración de un atributo de nodo o arista
+type claveGraphML struct {
+	ID      string `xml:"id,attr"`
+	Para    string `xml:"for,attr"`
+	Nombre  string `xml:"attr.name,attr"`
+	Tipo    string `xml:"attr.type,attr"`
+	Defecto string `xml:"default,omitempty"`
+}
+
+// Grafo dentro del documento GraphML
+type grafoGraphML struct {
+	ID               string          `xml:"id,attr,omitempty"`
+	AristaPorDefecto string          `xml:"edgedefault,attr"`
+	Nodos            []nodoGraphML   `xml:"node"`
+	Aristas          []aristaGraphML `xml:"edge"`
+}
+
+// Nodo GraphML
+type nodoGraphML struct {
+	ID    string        `xml:"id,attr"`
+	Datos []datoGraphML `xml:"data"`
+}
+
+// Arista GraphML; Dirigida es opcional y sobreescribe edgedefault
+type aristaGraphML struct {
+	Desde    string        `xml:"source,attr"`
+	Hasta    string        `xml:"target,attr"`
+	Dirigida string        `xml:"directed,attr,omitempty"`
+	Datos    []datoGraphML `xml:"data"`
+}
+
+// Valor de un atributo
+type datoGraphML struct {
+	Clave string `xml:"key,attr"`
+	Valor string `xml:",chardata"`
+}
+
+// CargarGraphML carga un grafo desde un archivo GraphML
+func (ra *RepositorioArchivo) CargarGraphML(archivo string) (*domain.Grafo, error) {
+	return exigirSinErrores(ra.cargarGraphMLValidado(archivo))
+}
+
+// carga un GraphML validando sus elementos
+func (ra *RepositorioArchivo) cargarGraphMLValidado(archivo string) (*domain.Grafo, []ProblemaValidacion, error) {
+	dirArchivo := filepath.Join(ra.dataDir, archivo)
+
+	data, err := os.ReadFile(dirArchivo)
+	if err != nil {
+		return nil, nil, fmt.Errorf("error reading GraphML file: %v", err)
+	}
+
+	var documento documentoGraphML
+	if err := xml.Unmarshal(data, &documento); err != nil {
+		return nil, nil, fmt.Errorf("error parsing GraphML: %v", err)
+	}
+
+	dataGrafo, err := desdeDocumentoGraphML(&documento)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return validarDataGrafo(archivo, dataGrafo, ubicacionesPorElemento(dataGrafo, "node", "edge"))
+}
+
+// GuardarGraphML guarda el grafo en un archivo GraphML
+func (ra *RepositorioArchivo) GuardarGraphML(grafo *domain.Grafo, archivo string) error {
+	dirArchivo := filepath.Join(ra.dataDir, archivo)
+
+	documento := haciaDocumentoGraphML(extraerDatosGrafo(grafo))
+
+	data, err := xml.MarshalIndent(documento, "", "  ")
+	if err != nil {
+		return fmt.Errorf("error ordenando el GraphML: %v", err)
+	}
+
+	if err := os.WriteFile(dirArchivo, []byte(xml.Header+string(data)), 0644); err != nil {
+		return fmt.Errorf("error al escribir el archivo GraphML: %v", err)
+	}
+
+	return nil
+}
+
+// convierte los datos del grafo a un documento GraphML
+func haciaDocumentoGraphML(dataGrafo *DataGrafo) *documentoGraphML {
+	documento := &documentoGraphML{
+		Xmlns: "http://graphml.graphdrawing.org/xmlns",
+		Claves: []claveGraphML{
+			{ID: "nombre", Para: "node", Nombre: "nombre", Tipo: "string"},
+			{ID: "x", Para: "node", Nombre: "x", Tipo: "double"},
+			{ID: "y", Para: "node", Nombre: "y", Tipo: "double"},
+			{ID: "distancia", Para: "edge", Nombre: "distancia", Tipo: "double"},
+			{ID: "es_dirigido", Para: "edge", Nombre: "es_dirigido", Tipo: "boolean"},
+			{ID: "es_obstruido", Para: "edge", Nombre: "es_obstruido", Tipo: "boolean"},
+			{ID: "capacidad", Para: "edge", Nombre: "capacidad", Tipo: "double"},
+			{ID: "es_angosto", Para: "edge", Nombre: "es_angosto", Tipo: "boolean"},
+		},
+	}
+
+	aristaPorDefecto := "undirected"
+	if dataGrafo.EsDirigido {
+		aristaPorDefecto = "directed"
+	}
+	grafoML := grafoGraphML{ID: "G", AristaPorDefecto: aristaPorDefecto}
+
+	// Una clave por cada recurso y cada demanda distintos
+	clavesRecurso := make(map[string]string)
+	clavesDemanda := make(map[string]string)
+	for _, cueva := range dataGrafo.Cuevas {
+		nodo := nodoGraphML{
+			ID: cueva.ID,
+			Datos: []datoGraphML{
+				{Clave: "nombre", Valor: cueva.Nombre},
+				{Clave: "x", Valor: formatearFloatTXT(cueva.X)},
+				{Clave: "y", Valor: formatearFloatTXT(cueva.Y)},
+			},
+		}
+		for _, recurso := range recursosOrdenados(cueva) {
+			clave, ok := clavesRecurso[recurso]
+			if !ok {
+				clave = fmt.Sprintf("r%d", len(clavesRecurso))
+				clavesRecurso[recurso] = clave
+				documento.Claves = append(documento.Claves, claveGraphML{
+					ID: clave, Para: "node", Nombre: prefijoAtributoRecurso + recurso, Tipo: "int",
+				})
+			}
+			nodo.Datos = append(nodo.Datos, datoGraphML{Clave: clave, Valor: strconv.Itoa(cueva.Recursos[recurso])})
+		}
+		for _, recurso := range demandaOrdenada(cueva) {
+			clave, ok := clavesDemanda[recurso]
+			if !ok {
+				clave = fmt.Sprintf("d%d", len(clavesDemanda))
+				clavesDemanda[recurso] = clave
+				documento.Claves = append(documento.Claves, claveGraphML{
+					ID: clave, Para: "node", Nombre: prefijoAtributoDemanda + recurso, Tipo: "int",
+				})
+			}
+			nodo.Datos = append(nodo.Datos, datoGraphML{Clave: clave, Valor: strconv.Itoa(cueva.Demanda[recurso])})
+		}
+		grafoML.Nodos = append(grafoML.Nodos, nodo)
+	}
+
+	for _, arista := range dataGrafo.Aristas {
+		aristaML := aristaGraphML{
+			Desde:    arista.Desde,
+			Hasta:    arista.Hasta,
+			Dirigida: strconv.FormatBool(dataGrafo.EsDirigido || arista.EsDirigido),
+			Datos: []datoGraphML{
+				{Clave: "distancia", Valor: formatearFloatTXT(arista.Distancia)},
+				{Clave: "es_dirigido", Valor: strconv.FormatBool(arista.EsDirigido)},
+				{Clave: "es_obstruido", Valor: strconv.FormatBool(arista.EsObstruido)},
+			},
+		}
+		if arista.Capacidad != 0 {
+			aristaML.Datos = append(aristaML.Datos, datoGraphML{Clave: "capacidad", Valor: formatearFloatTXT(arista.Capacidad)})
+		}
+		if arista.EsAngosto {
+			aristaML.Datos = append(aristaML.Datos, datoGraphML{Clave: "es_angosto", Valor: "true"})
+		}
+		grafoML.Aristas = append(grafoML.Aristas, aristaML)
+	}
+
+	documento.Grafos = []grafoGraphML{grafoML}
+	return documento
+}
+
+// convierte un documento GraphML a los datos del grafo; acepta archivos de
+// otras herramientas usando "label" como nombre y "weight" como distancia
+func desdeDocumentoGraphML(documento *documentoGraphML) (*DataGrafo, error) {
+	if len(documento.Grafos) == 0 {
+		return nil, fmt.Errorf("el documento GraphML no contiene ningún grafo")
+	}
+	grafoML := documento.Grafos[0]
+
+	claves := make(map[string]claveGraphML)
+	for _, clave := range documento.Claves {
+		claves[clave.ID] = clave
+	}
+
+	dataGrafo := &DataGrafo{
+		EsDirigido: grafoML.AristaPorDefecto == "directed",
+		Cuevas:     make([]*domain.Cueva, 0, len(grafoML.Nodos)),
+		Aristas:    make([]*domain.Arista, 0, len(grafoML.Aristas)),
+	}
+
+	for _, nodo := range grafoML.Nodos {
+		cueva := domain.NuevaCueva(nodo.ID, nodo.ID)
+		valores := valoresGraphML(claves, "node", nodo.Datos)
+
+		for nombre, valor := range valores {
+			var err error
+			switch {
+			case nombre == "nombre":
+				cueva.Nombre = valor
+			case nombre == "label":
+				if _, existe := valores["nombre"]; !existe {
+					cueva.Nombre = valor
+				}
+			case nombre == "x":
+				cueva.X, err = strconv.ParseFloat(strings.TrimSpace(valor), 64)
+			case nombre == "y":
+				cueva.Y, err = strconv.ParseFloat(strings.TrimSpace(valor), 64)
+			case strings.HasPrefix(nombre, prefijoAtributoRecurso):
+				var cantidad int
+				cantidad, err = strconv.Atoi(strings.TrimSpace(valor))
+				cueva.AgregarRecurso(strings.TrimPrefix(nombre, prefijoAtributoRecurso), cantidad)
+			case strings.HasPrefix(nombre, prefijoAtributoDemanda):
+				err = aplicarDemanda(cueva, strings.TrimPrefix(nombre, prefijoAtributoDemanda), valor)
+			}
+			if err != nil {
+				return nil, fmt.Errorf("valor inválido para %s en el nodo %s: %s", nombre, nodo.ID, valor)
+			}
+		}
+
+		dataGrafo.Cuevas = append(dataGrafo.Cuevas, cueva)
+	}
+
+	for _, aristaML := range grafoML.Aristas {
+		esDirigido := dataGrafo.EsDirigido
+		if aristaML.Dirigida != "" {
+			dirigida, err := strconv.ParseBool(aristaML.Dirigida)
+			if err != nil {
+				return nil, fmt.Errorf("valor de dirección inválido en la arista %s->%s: %s", aristaML.Desde, aristaML.Hasta, aristaML.Dirigida)
+			}
+			esDirigido = dirigida
+		}
+
+		arista := domain.NuevaArista(aristaML.Desde, aristaML.Hasta, 1, esDirigido)
+		valores := valoresGraphML(claves, "edge", aristaML.Datos)
+
+		if err := aplicarAtributosArista(arista, valores); err != nil {
+			return nil, err
+		}
+
+		dataGrafo.Aristas = append(dataGrafo.Aristas, arista)
+	}
+
+	return dataGrafo, nil
+}
+
+// resuelve los datos de un elemento a pares nombre de atributo -> valor,
+// incluyendo los valores por defecto de las claves declaradas
+func valoresGraphML(claves map[string]claveGraphML, para string, datos []datoGraphML) map[string]string {
+	valores := make(map[string]string)
+	for _, clave := range claves {
+		if (clave.Para == para || clave.Para == "all") && clave.Defecto != "" {
+			valores[nombreClaveGraphML(clave)] = clave.Defecto
+		}
+	}
+	for _, dato := range datos {
+		nombre := dato.Clave
+		if clave, ok := claves[dato.Clave]; ok {
+			nombre = nombreClaveGraphML(clave)
+		}
+		valores[nombre] = dato.Valor
+	}
+	return valores
+}
+
+// nombre del atributo declarado por una clave; si falta se usa su ID
+func nombreClaveGraphML(clave claveGraphML) string {
+	if clave.Nombre != "" {
+		return clave.Nombre
+	}
+	return clave.ID
+}
+
+// fija la demanda leída de un atributo de nodo de GraphML o GEXF; se guarda
+// tal cual, como en los demás formatos, para que la validación la revise
+func aplicarDemanda(cueva *domain.Cueva, recurso, valor string) error {
+	cantidad, err := strconv.Atoi(strings.TrimSpace(valor))
+	if err != nil {
+		return err
+	}
+	if cueva.Demanda == nil {
+		cueva.Demanda = make(map[string]int)
+	}
+	cueva.Demanda[recurso] = cantidad
+	return nil
+}
+
+// aplica los atributos leídos de GraphML o GEXF a una arista
+func aplicarAtributosArista(arista *domain.Arista, valores map[string]string) error {
+	for nombre, valor := range valores {
+		valor = strings.TrimSpace(valor)
+		var err error
+		switch nombre {
+		case "distancia", "weight":
+			if _, existe := valores["distancia"]; nombre == "weight" && existe {
+				continue
+			}
+			arista.Distancia, err = strconv.ParseFloat(valor, 64)
+		case "es_dirigido":
+			arista.EsDirigido, err = strconv.ParseBool(valor)
+		case "es_obstruido":
+			arista.EsObstruido, err = strconv.ParseBool(valor)
+		case "capacidad":
+			arista.Capacidad, err = strconv.ParseFloat(valor, 64)
+		case "es_angosto":
+			arista.EsAngosto, err = strconv.ParseBool(valor)
+		}
+		if err != nil {
+			return fmt.Errorf("valor inválido para %s en la arista %s->%s: %s", nombre, arista.Desde, arista.Hasta, valor)
+		}
+	}
+	return nil
+}
@@ -3,24 +3,39 @@ package repository
 import (
 	"fmt"
 	"proyecto-grafos-go/internal/domain"
+	"sort"
 	"sync"
+	"time"
 )
 
 // MemoryRepository implementa un repositorio en memoria para testing
 type MemoryRepository struct {
-	grafos map[string]*domain.Grafo
-	mutex  sync.RWMutex
+	grafos    map[string]*domain.Grafo
+	snapshots map[string][]snapshotMemoria
+	mutex     sync.RWMutex
+}
+
+// Snapshot en memoria junto con la copia del grafo
+type snapshotMemoria struct {
+	Snapshot
+	grafo *domain.Grafo
 }
 
 // NuevoMemoryRepository crea una nueva instancia del repositorio en memoria
 func NuevoMemoryRepository() *MemoryRepository {
 	return &MemoryRepository{
-		grafos: make(map[string]*domain.Grafo),
+		grafos:    make(map[string]*domain.Grafo),
+		snapshots: make(map[string][]snapshotMemoria),
 	}
 }
 
 // Guardar almacena un grafo en memoria
 func (mr *MemoryRepository) Guardar(nombre string, grafo *domain.Grafo) error {
+	return mr.GuardarConMensaje(nombre, grafo, "")
+}
+
+// GuardarConMensaje almacena un grafo en memoria y registra un snapshot numerado
+func (mr *MemoryRepository) GuardarConMensaje(nombre string, grafo *domain.Grafo, mensaje string) error {
 	if grafo == nil {
 		return fmt.Errorf("grafo no puede ser nil")
 	}
@@ -35,10 +50,102 @@ func (mr *MemoryRepository) Guardar(nombre string, grafo *domain.Grafo) error {
 	// Crear una copia del grafo para evitar mutaciones
 	grafoCopia := mr.copiarGrafo(grafo)
 	mr.grafos[nombre] = grafoCopia
+	mr.registrarSnapshot(nombre, grafoCopia, mensaje)
 
 	return nil
 }
 
+// registrarSnapshot agrega una versión numerada; requiere el mutex tomado
+func (mr *MemoryRepository) registrarSnapshot(nombre string, grafo *domain.Grafo, mensaje string) {
+	historial := mr.snapshots[nombre]
+	numero := 1
+	if len(historial) > 0 {
+		numero = historial[len(historial)-1].Numero + 1
+	}
+	mr.snapshots[nombre] = append(historial, snapshotMemoria{
+		Snapshot: Snapshot{Numero: numero, Nombre: nombre, Fecha: time.Now(), Mensaje: mensaje},
+		grafo:    mr.copiarGrafo(grafo),
+	})
+}
+
+// ListarSnapshots devuelve los snapshots de un grafo ordenados por número
+func (mr *MemoryRepository) ListarSnapshots(nombre string) ([]Snapshot, error) {
+	mr.mutex.RLock()
+	defer mr.mutex.RUnlock()
+
+	snapshots := make([]Snapshot, 0, len(mr.snapshots[nombre]))
+	for _, snapshot := range mr.snapshots[nombre] {
+		snapshots = append(snapshots, snapshot.Snapshot)
+	}
+	return snapshots, nil
+}
+
+// buscarSnapshot localiza un snapshot por número; requiere el mutex tomado
+func (mr *MemoryRepository) buscarSnapshot(nombre string, numero int) (*snapshotMemoria, error) {
+	for i := range mr.snapshots[nombre] {
+		if mr.snapshots[nombre][i].Numero == numero {
+			return &mr.snapshots[nombre][i], nil
+		}
+	}
+	return nil, fmt.Errorf("snapshot %d de '%s' no encontrado", numero, nombre)
+}
+
+// CargarSnapshot recupera la versión indicada sin modificar el grafo actual
+func (mr *MemoryRepository) CargarSnapshot(nombre string, numero int) (*domain.Grafo, error) {
+	mr.mutex.RLock()
+	defer mr.mutex.RUnlock()
+
+	snapshot, err := mr.buscarSnapshot(nombre, numero)
+	if err != nil {
+		return nil, err
+	}
+	return mr.copiarGrafo(snapshot.grafo), nil
+}
+
+// RestaurarSnapshot reemplaza el grafo actual por la versión indicada y
+// registra la restauración como un snapshot nuevo
+func (mr *MemoryRepository) RestaurarSnapshot(nombre string, numero int) error {
+	mr.mutex.Lock()
+	defer mr.mutex.Unlock()
+
+	snapshot, err := mr.buscarSnapshot(nombre, numero)
+	if err != nil {
+		return err
+	}
+	grafo := mr.copiarGrafo(snapshot.grafo)
+	mr.grafos[nombre] = grafo
+	mr.registrarSnapshot(nombre, grafo, fmt.Sprintf("restaurado desde la versión %d", numero))
+	return nil
+}
+
+// PodarSnapshots elimina los snapshots que superan la cantidad o antigüedad máxima
+// (0 desactiva el criterio) y devuelve cuántos se eliminaron
+func (mr *MemoryRepository) PodarSnapshots(nombre string, maxCantidad int, maxAntiguedad time.Duration) (int, error) {
+	mr.mutex.Lock()
+	defer mr.mutex.Unlock()
+
+	historial := mr.snapshots[nombre]
+	snapshots := make([]Snapshot, len(historial))
+	for i, snapshot := range historial {
+		snapshots[i] = snapshot.Snapshot
+	}
+
+	podar := make(map[int]bool)
+	for _, snapshot := range seleccionarSnapshotsAPodar(snapshots, maxCantidad, maxAntiguedad, time.Now()) {
+		podar[snapshot.Numero] = true
+	}
+
+	conservados := make([]snapshotMemoria, 0, len(historial)-len(podar))
+	for _, snapshot := range historial {
+		if !podar[snapshot.Numero] {
+			conservados = append(conservados, snapshot)
+		}
+	}
+	mr.snapshots[nombre] = conservados
+
+	return len(podar), nil
+}
+
 // Cargar recupera un grafo desde memoria
 func (mr *MemoryRepository) Cargar(nombre string) (*domain.Grafo, error) {
 	if nombre == "" {
@@ -57,8 +164,8 @@ func (mr *MemoryRepository) Cargar(nombre string) (*domain.Grafo, error) {
 	return mr.copiarGrafo(grafo), nil
 }
 
-// Listar retorna todos los nombres de grafos almacenados
-func (mr *MemoryRepository) Listar() []string {
+// Listar retorna todos los nombres de grafos almacenados en orden alfabético
+func (mr *MemoryRepository) Listar() ([]string, error) {
 	mr.mutex.RLock()
 	defer mr.mutex.RUnlock()
 
@@ -66,8 +173,9 @@ func (mr *MemoryRepository) Listar() []string {
 	for nombre := range mr.grafos {
 		nombres = append(nombres, nombre)
 	}
+	sort.Strings(nombres)
 
-	return nombres
+	return nombres, nil
 }
 
 // Eliminar remueve un grafo de memoria
@@ -102,6 +210,7 @@ func (mr *MemoryRepository) Limpiar() {
 	defer mr.mutex.Unlock()
 
 	mr.grafos = make(map[string]*domain.Grafo)
+	mr.snapshots = make(map[string][]snapshotMemoria)
 }
 
 // Contar retorna el número de grafos almacenados
@@ -131,10 +240,13 @@ func (mr *MemoryRepository) copiarGrafo(original *domain.Grafo) *domain.Grafo {
 			Recursos: make(map[string]int),
 		}
 
-		// Copiar recursos
+		// Copiar recursos y demanda
 		for recurso, cantidad := range cueva.Recursos {
 			nuevaCueva.Recursos[recurso] = cantidad
 		}
+		for recurso, cantidad := range cueva.Demanda {
+			nuevaCueva.EstablecerDemanda(recurso, cantidad)
+		}
 
 		copia.Cuevas[id] = nuevaCueva
 	}
@@ -147,6 +259,8 @@ func (mr *MemoryRepository) copiarGrafo(original *domain.Grafo) *domain.Grafo {
 			Distancia:   arista.Distancia,
 			EsDirigido:  arista.EsDirigido,
 			EsObstruido: arista.EsObstruido,
+			Capacidad:   arista.Capacidad,
+			EsAngosto:   arista.EsAngosto,
 		}
 
 		copia.Aristas = append(copia.Aristas, nuevaArista)
@@ -0,0 +1,41 @@
+package repository
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"proyecto-grafos-go/internal/domain"
+)
+
+// RepositorioParches lo implementan los repositorios que persisten parches de grafos
+type RepositorioParches interface {
+	GuardarParche(archivo string, parche *domain.ParcheGrafo) error
+	CargarParche(archivo string) (*domain.ParcheGrafo, error)
+}
+
+// Verificación en compilación de la implementación
+var _ RepositorioParches = (*RepositorioArchivo)(nil)
+
+// GuardarParche guarda un parche en formato JSON
+func (ra *RepositorioArchivo) GuardarParche(archivo string, parche *domain.ParcheGrafo) error {
+	data, err := parche.JSON()
+	if err != nil {
+		return fmt.Errorf("error serializando el parche: %v", err)
+	}
+
+	if err := os.WriteFile(filepath.Join(ra.dataDir, archivo), data, 0644); err != nil {
+		return fmt.Errorf("error al escribir el parche: %v", err)
+	}
+
+	return nil
+}
+
+// CargarParche carga un parche guardado en formato JSON
+func (ra *RepositorioArchivo) CargarParche(archivo string) (*domain.ParcheGrafo, error) {
+	data, err := os.ReadFile(filepath.Join(ra.dataDir, archivo))
+	if err != nil {
+		return nil, fmt.Errorf("error leyendo el parche: %v", err)
+	}
+
+	return domain.ParcheDesdeJSON(data)
+}
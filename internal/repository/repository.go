@@ -0,0 +1,33 @@
+package repository
+
+import "proyecto-grafos-go/internal/domain"
+
+// GraphRepository es la interfaz común para persistir grafos identificados por nombre
+type GraphRepository interface {
+	Guardar(nombre string, grafo *domain.Grafo) error
+	Cargar(nombre string) (*domain.Grafo, error)
+	Listar() ([]string, error)
+	Eliminar(nombre string) error
+	Existe(nombre string) bool
+}
+
+// ExportadorDOT lo implementan los repositorios capaces de exportar a Graphviz
+type ExportadorDOT interface {
+	GuardarDOT(grafo *domain.Grafo, archivo string, resaltados ...ResaltadoDOT) error
+}
+
+// RepositorioConResumenes lo implementan los repositorios que pueden describir
+// los grafos guardados sin cargarlos
+type RepositorioConResumenes interface {
+	ListarResumenes() ([]ResumenGrafo, error)
+}
+
+// Verificación en compilación de las implementaciones
+var (
+	_ GraphRepository = (*RepositorioArchivo)(nil)
+	_ GraphRepository = (*MemoryRepository)(nil)
+	_ GraphRepository = (*RepositorioSQLite)(nil)
+	_ ExportadorDOT   = (*RepositorioArchivo)(nil)
+
+	_ RepositorioConResumenes = (*RepositorioSQLite)(nil)
+)
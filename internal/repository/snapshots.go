@@ -0,0 +1,305 @@
+package repository
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"proyecto-grafos-go/internal/domain"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Sufijo de los archivos de metadatos de cada snapshot
+const sufijoMetaSnapshot = ".meta.json"
+
+// Snapshot describe una versión inmutable y numerada de un grafo guardado
+type Snapshot struct {
+	Numero  int       `json:"numero"`
+	Nombre  string    `json:"nombre"`
+	Fecha   time.Time `json:"fecha"`
+	Mensaje string    `json:"mensaje,omitempty"`
+	Tamano  int64     `json:"tamano,omitempty"`
+}
+
+// RepositorioConSnapshots lo implementan los repositorios que guardan historial de versiones
+type RepositorioConSnapshots interface {
+	GuardarConMensaje(nombre string, grafo *domain.Grafo, mensaje string) error
+	ListarSnapshots(nombre string) ([]Snapshot, error)
+	CargarSnapshot(nombre string, numero int) (*domain.Grafo, error)
+	RestaurarSnapshot(nombre string, numero int) error
+	PodarSnapshots(nombre string, maxCantidad int, maxAntiguedad time.Duration) (int, error)
+}
+
+// Verificación en compilación de las implementaciones
+var (
+	_ RepositorioConSnapshots = (*RepositorioArchivo)(nil)
+	_ RepositorioConSnapshots = (*MemoryRepository)(nil)
+)
+
+// seleccionarSnapshotsAPodar devuelve los snapshots que exceden la cantidad máxima
+// o la antigüedad máxima (0 desactiva cada criterio); el más reciente nunca se poda
+func seleccionarSnapshotsAPodar(snapshots []Snapshot, maxCantidad int, maxAntiguedad time.Duration, ahora time.Time) []Snapshot {
+	var podar []Snapshot
+	for i, snapshot := range snapshots {
+		posicionDesdeFinal := len(snapshots) - 1 - i
+		if posicionDesdeFinal == 0 {
+			continue
+		}
+		excedeCantidad := maxCantidad > 0 && posicionDesdeFinal >= maxCantidad
+		excedeAntiguedad := maxAntiguedad > 0 && ahora.Sub(snapshot.Fecha) > maxAntiguedad
+		if excedeCantidad || excedeAntiguedad {
+			podar = append(podar, snapshot)
+		}
+	}
+	return podar
+}
+
+// GuardarConMensaje guarda el grafo y, si hay directorio de backups, registra un snapshot
+func (ra *RepositorioArchivo) GuardarConMensaje(archivo string, grafo *domain.Grafo, mensaje string) error {
+	if err := ra.guardarSegunExtension(archivo, grafo); err != nil {
+		return err
+	}
+
+	// DOT es solo exportación: no se versiona
+	if ra.backupDir == "" || strings.EqualFold(filepath.Ext(archivo), ".dot") {
+		return nil
+	}
+
+	_, err := ra.registrarSnapshot(archivo, mensaje)
+	return err
+}
+
+// directorio donde se guardan los snapshots de un archivo
+func (ra *RepositorioArchivo) dirSnapshots(archivo string) (string, error) {
+	if ra.backupDir == "" {
+		return "", fmt.Errorf("los snapshots no están habilitados (falta el directorio de backups)")
+	}
+	return filepath.Join(ra.backupDir, claveSnapshot(archivo)), nil
+}
+
+// nombre con el que se versiona un archivo: el par CSV comparte historial bajo
+// su nombre base ("red.csv") aunque se guarde con el nombre de uno de sus archivos
+func claveSnapshot(archivo string) string {
+	if esParCSV(archivo) {
+		archivoCuevas, _ := ArchivosCSV(archivo)
+		return strings.TrimSuffix(archivoCuevas, sufijoCuevasCSV) + ".csv"
+	}
+	return archivo
+}
+
+// archivos que forman un grafo guardado: dos para el par CSV, uno para el resto
+func archivosDelGrafo(archivo string) []string {
+	if esParCSV(archivo) {
+		archivoCuevas, archivoAristas := ArchivosCSV(archivo)
+		return []string{archivoCuevas, archivoAristas}
+	}
+	return []string{archivo}
+}
+
+// nombre del archivo de contenido de un snapshot; para el par CSV es el nombre
+// base de los dos archivos del snapshot ("000001.cuevas.csv" y "000001.aristas.csv")
+func nombreContenidoSnapshot(archivo string, numero int) string {
+	extension := filepath.Ext(archivo)
+	if esMatrizCSV(archivo) {
+		extension = sufijoMatrizCSV
+	}
+	return fmt.Sprintf("%06d%s", numero, extension)
+}
+
+// copia el archivo recién guardado como un nuevo snapshot de solo lectura
+func (ra *RepositorioArchivo) registrarSnapshot(archivo, mensaje string) (*Snapshot, error) {
+	ra.muSnapshots.Lock()
+	defer ra.muSnapshots.Unlock()
+	return ra.registrarSnapshotBloqueado(archivo, mensaje)
+}
+
+// registrarSnapshot sin tomar muSnapshots; el llamador ya lo tiene
+func (ra *RepositorioArchivo) registrarSnapshotBloqueado(archivo, mensaje string) (*Snapshot, error) {
+	dir, err := ra.dirSnapshots(archivo)
+	if err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("error creando el directorio de snapshots: %v", err)
+	}
+
+	origenes := archivosDelGrafo(archivo)
+	contenidos := make([][]byte, len(origenes))
+	var tamano int64
+	for i, origen := range origenes {
+		contenido, err := os.ReadFile(filepath.Join(ra.dataDir, origen))
+		if err != nil {
+			return nil, fmt.Errorf("error leyendo el archivo guardado: %v", err)
+		}
+		contenidos[i] = contenido
+		tamano += int64(len(contenido))
+	}
+
+	existentes, err := ra.ListarSnapshots(archivo)
+	if err != nil {
+		return nil, err
+	}
+	numero := 1
+	if len(existentes) > 0 {
+		numero = existentes[len(existentes)-1].Numero + 1
+	}
+
+	snapshot := &Snapshot{
+		Numero:  numero,
+		Nombre:  claveSnapshot(archivo),
+		Fecha:   ra.ahora(),
+		Mensaje: mensaje,
+		Tamano:  tamano,
+	}
+	meta, err := json.MarshalIndent(snapshot, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("error serializando el snapshot: %v", err)
+	}
+
+	for i, destino := range archivosDelGrafo(nombreContenidoSnapshot(archivo, numero)) {
+		if err := escribirInmutable(filepath.Join(dir, destino), contenidos[i]); err != nil {
+			return nil, err
+		}
+	}
+	if err := escribirInmutable(filepath.Join(dir, fmt.Sprintf("%06d%s", numero, sufijoMetaSnapshot)), meta); err != nil {
+		return nil, err
+	}
+
+	return snapshot, nil
+}
+
+// escribe un archivo nuevo de solo lectura; falla si ya existe
+func escribirInmutable(ruta string, contenido []byte) error {
+	archivo, err := os.OpenFile(ruta, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0444)
+	if err != nil {
+		return fmt.Errorf("error creando el snapshot %s: %v", ruta, err)
+	}
+	if _, err := archivo.Write(contenido); err != nil {
+		archivo.Close()
+		return fmt.Errorf("error escribiendo el snapshot %s: %v", ruta, err)
+	}
+	return archivo.Close()
+}
+
+// ListarSnapshots devuelve los snapshots de un archivo ordenados por número
+func (ra *RepositorioArchivo) ListarSnapshots(archivo string) ([]Snapshot, error) {
+	dir, err := ra.dirSnapshots(archivo)
+	if err != nil {
+		return nil, err
+	}
+
+	entradas, err := os.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return []Snapshot{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error leyendo el directorio de snapshots: %v", err)
+	}
+
+	snapshots := make([]Snapshot, 0)
+	for _, entrada := range entradas {
+		if entrada.IsDir() || !strings.HasSuffix(entrada.Name(), sufijoMetaSnapshot) {
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join(dir, entrada.Name()))
+		if err != nil {
+			return nil, fmt.Errorf("error leyendo el snapshot %s: %v", entrada.Name(), err)
+		}
+		var snapshot Snapshot
+		if err := json.Unmarshal(data, &snapshot); err != nil {
+			return nil, fmt.Errorf("error parseando el snapshot %s: %v", entrada.Name(), err)
+		}
+		snapshots = append(snapshots, snapshot)
+	}
+
+	sort.Slice(snapshots, func(i, j int) bool {
+		return snapshots[i].Numero < snapshots[j].Numero
+	})
+	return snapshots, nil
+}
+
+// CargarSnapshot carga la versión indicada sin modificar el archivo actual
+func (ra *RepositorioArchivo) CargarSnapshot(archivo string, numero int) (*domain.Grafo, error) {
+	dir, err := ra.dirSnapshots(archivo)
+	if err != nil {
+		return nil, err
+	}
+
+	nombre := nombreContenidoSnapshot(archivo, numero)
+	for _, contenido := range archivosDelGrafo(nombre) {
+		if _, err := os.Stat(filepath.Join(dir, contenido)); err != nil {
+			return nil, fmt.Errorf("snapshot %d de '%s' no encontrado", numero, archivo)
+		}
+	}
+
+	return NuevoRepositorio(dir).Cargar(nombre)
+}
+
+// RestaurarSnapshot reemplaza el archivo actual por la versión indicada y
+// registra la restauración como un snapshot nuevo
+func (ra *RepositorioArchivo) RestaurarSnapshot(archivo string, numero int) error {
+	// Un guardado concurrente no debe intercalarse entre la escritura y el nuevo snapshot
+	ra.muSnapshots.Lock()
+	defer ra.muSnapshots.Unlock()
+
+	dir, err := ra.dirSnapshots(archivo)
+	if err != nil {
+		return err
+	}
+
+	// Se leen todos los archivos antes de escribir para no dejar un par CSV a medias
+	origenes := archivosDelGrafo(nombreContenidoSnapshot(archivo, numero))
+	contenidos := make([][]byte, len(origenes))
+	for i, origen := range origenes {
+		contenido, err := os.ReadFile(filepath.Join(dir, origen))
+		if err != nil {
+			return fmt.Errorf("snapshot %d de '%s' no encontrado", numero, archivo)
+		}
+		contenidos[i] = contenido
+	}
+
+	for i, destino := range archivosDelGrafo(archivo) {
+		if err := os.WriteFile(filepath.Join(ra.dataDir, destino), contenidos[i], 0644); err != nil {
+			return fmt.Errorf("error restaurando el archivo %s: %v", destino, err)
+		}
+	}
+
+	_, err = ra.registrarSnapshotBloqueado(archivo, fmt.Sprintf("restaurado desde la versión %d", numero))
+	return err
+}
+
+// PodarSnapshots elimina los snapshots que superan la cantidad o antigüedad máxima
+// (0 desactiva el criterio) y devuelve cuántos se eliminaron
+func (ra *RepositorioArchivo) PodarSnapshots(archivo string, maxCantidad int, maxAntiguedad time.Duration) (int, error) {
+	ra.muSnapshots.Lock()
+	defer ra.muSnapshots.Unlock()
+
+	snapshots, err := ra.ListarSnapshots(archivo)
+	if err != nil {
+		return 0, err
+	}
+	dir, _ := ra.dirSnapshots(archivo)
+
+	eliminados := 0
+	for _, snapshot := range seleccionarSnapshotsAPodar(snapshots, maxCantidad, maxAntiguedad, ra.ahora()) {
+		nombres := append(archivosDelGrafo(nombreContenidoSnapshot(archivo, snapshot.Numero)), fmt.Sprintf("%06d%s", snapshot.Numero, sufijoMetaSnapshot))
+		for _, nombre := range nombres {
+			if err := os.Remove(filepath.Join(dir, nombre)); err != nil && !os.IsNotExist(err) {
+				return eliminados, fmt.Errorf("error eliminando el snapshot %d: %v", snapshot.Numero, err)
+			}
+		}
+		eliminados++
+	}
+
+	return eliminados, nil
+}
+
+// hora actual; reemplazable en pruebas
+func (ra *RepositorioArchivo) ahora() time.Time {
+	if ra.reloj != nil {
+		return ra.reloj()
+	}
+	return time.Now()
+}
@@ -0,0 +1,212 @@
+package repository
+
+import (
+	"math/rand"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// repositorio de archivos con snapshots y un reloj controlable
+func nuevoRepositorioConReloj(t *testing.T, ahora *time.Time) *RepositorioArchivo {
+	raiz := t.TempDir()
+	repo := NuevoRepositorioConSnapshots(filepath.Join(raiz, "data"), filepath.Join(raiz, "backups"))
+	if err := os.MkdirAll(filepath.Join(raiz, "data"), 0755); err != nil {
+		t.Fatalf("Error creando el directorio de datos: %v", err)
+	}
+	repo.reloj = func() time.Time { return *ahora }
+	return repo
+}
+
+// TestSnapshotsNumeradosYRestauracion verifica numeración, mensajes, inmutabilidad y restauración
+func TestSnapshotsNumeradosYRestauracion(t *testing.T) {
+	ahora := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	repo := nuevoRepositorioConReloj(t, &ahora)
+
+	r := rand.New(rand.NewSource(3))
+	primero, segundo := grafoAleatorio(r), grafoAleatorio(r)
+	if err := repo.GuardarConMensaje("red.json", primero, "versión inicial"); err != nil {
+		t.Fatalf("Error guardando: %v", err)
+	}
+	ahora = ahora.Add(time.Hour)
+	if err := repo.Guardar("red.json", segundo); err != nil {
+		t.Fatalf("Error guardando: %v", err)
+	}
+
+	snapshots, err := repo.ListarSnapshots("red.json")
+	if err != nil || len(snapshots) != 2 {
+		t.Fatalf("Se esperaban 2 snapshots: %v (%v)", snapshots, err)
+	}
+	if snapshots[0].Numero != 1 || snapshots[0].Mensaje != "versión inicial" || snapshots[1].Numero != 2 {
+		t.Errorf("Snapshots inesperados: %+v", snapshots)
+	}
+	if !snapshots[1].Fecha.Equal(ahora) {
+		t.Errorf("Fecha inesperada: %v", snapshots[1].Fecha)
+	}
+
+	info, err := os.Stat(filepath.Join(repo.backupDir, "red.json", nombreContenidoSnapshot("red.json", 1)))
+	if err != nil || info.Mode().Perm()&0222 != 0 {
+		t.Errorf("El snapshot debe existir y ser de solo lectura: %v (%v)", info, err)
+	}
+
+	cargado, err := repo.CargarSnapshot("red.json", 1)
+	if err != nil {
+		t.Fatalf("Error cargando el snapshot: %v", err)
+	}
+	if err := compararGrafos(primero, cargado); err != nil {
+		t.Fatalf("snapshot 1: %v", err)
+	}
+
+	if err := repo.RestaurarSnapshot("red.json", 1); err != nil {
+		t.Fatalf("Error restaurando: %v", err)
+	}
+	actual, err := repo.Cargar("red.json")
+	if err != nil {
+		t.Fatalf("Error cargando el archivo restaurado: %v", err)
+	}
+	if err := compararGrafos(primero, actual); err != nil {
+		t.Fatalf("archivo restaurado: %v", err)
+	}
+
+	snapshots, _ = repo.ListarSnapshots("red.json")
+	if len(snapshots) != 3 || snapshots[2].Mensaje != "restaurado desde la versión 1" {
+		t.Errorf("La restauración debe registrar un snapshot nuevo: %+v", snapshots)
+	}
+
+	if _, err := repo.CargarSnapshot("red.json", 99); err == nil {
+		t.Error("Se esperaba error al cargar un snapshot inexistente")
+	}
+}
+
+// TestSnapshotsParCSV verifica que el par CSV se versiona y restaura con sus dos archivos
+func TestSnapshotsParCSV(t *testing.T) {
+	ahora := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	repo := nuevoRepositorioConReloj(t, &ahora)
+
+	primero, segundo := grafoPruebaCSV(false), grafoPruebaCSV(true)
+	if err := repo.GuardarConMensaje("red.csv", primero, "versión inicial"); err != nil {
+		t.Fatalf("Error guardando: %v", err)
+	}
+	// Guardar con el nombre de uno de los archivos comparte el historial del par
+	if err := repo.Guardar("red.cuevas.csv", segundo); err != nil {
+		t.Fatalf("Error guardando: %v", err)
+	}
+
+	snapshots, err := repo.ListarSnapshots("red.csv")
+	if err != nil || len(snapshots) != 2 || snapshots[0].Nombre != "red.csv" {
+		t.Fatalf("Se esperaban 2 snapshots del par: %+v (%v)", snapshots, err)
+	}
+	for _, nombre := range []string{"000001.cuevas.csv", "000001.aristas.csv"} {
+		if _, err := os.Stat(filepath.Join(repo.backupDir, "red.csv", nombre)); err != nil {
+			t.Errorf("Falta %s en el snapshot: %v", nombre, err)
+		}
+	}
+
+	cargado, err := repo.CargarSnapshot("red.csv", 1)
+	if err != nil {
+		t.Fatalf("Error cargando el snapshot: %v", err)
+	}
+	if err := compararGrafos(primero, cargado); err != nil {
+		t.Fatalf("snapshot 1: %v", err)
+	}
+
+	if err := repo.RestaurarSnapshot("red.csv", 1); err != nil {
+		t.Fatalf("Error restaurando: %v", err)
+	}
+	actual, err := repo.Cargar("red.csv")
+	if err != nil {
+		t.Fatalf("Error cargando el par restaurado: %v", err)
+	}
+	if err := compararGrafos(primero, actual); err != nil {
+		t.Fatalf("par restaurado: %v", err)
+	}
+
+	if eliminados, err := repo.PodarSnapshots("red.csv", 1, 0); err != nil || eliminados != 2 {
+		t.Fatalf("Se esperaban 2 eliminados: %d (%v)", eliminados, err)
+	}
+	if _, err := os.Stat(filepath.Join(repo.backupDir, "red.csv", "000001.aristas.csv")); !os.IsNotExist(err) {
+		t.Errorf("La poda debe borrar los dos archivos del snapshot: %v", err)
+	}
+}
+
+// TestSnapshotsMatrizCSV verifica que la matriz de adyacencia se versiona como matriz
+func TestSnapshotsMatrizCSV(t *testing.T) {
+	ahora := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	repo := nuevoRepositorioConReloj(t, &ahora)
+
+	if err := repo.Guardar("red.matriz.csv", grafoPruebaCSV(false)); err != nil {
+		t.Fatalf("Error guardando: %v", err)
+	}
+	if _, err := repo.CargarSnapshot("red.matriz.csv", 1); err != nil {
+		t.Fatalf("Error cargando el snapshot de la matriz: %v", err)
+	}
+}
+
+// TestPodarSnapshots verifica la poda por cantidad y por antigüedad
+func TestPodarSnapshots(t *testing.T) {
+	ahora := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	repo := nuevoRepositorioConReloj(t, &ahora)
+
+	r := rand.New(rand.NewSource(5))
+	for i := 0; i < 5; i++ {
+		if err := repo.Guardar("red.txt", grafoAleatorio(r)); err != nil {
+			t.Fatalf("Error guardando: %v", err)
+		}
+		ahora = ahora.Add(24 * time.Hour)
+	}
+
+	eliminados, err := repo.PodarSnapshots("red.txt", 3, 0)
+	if err != nil || eliminados != 2 {
+		t.Fatalf("Se esperaban 2 eliminados por cantidad: %d (%v)", eliminados, err)
+	}
+	snapshots, _ := repo.ListarSnapshots("red.txt")
+	if len(snapshots) != 3 || snapshots[0].Numero != 3 {
+		t.Fatalf("Snapshots inesperados tras podar por cantidad: %+v", snapshots)
+	}
+
+	// Todos superan la antigüedad, pero el más reciente se conserva
+	ahora = ahora.Add(30 * 24 * time.Hour)
+	eliminados, err = repo.PodarSnapshots("red.txt", 0, 7*24*time.Hour)
+	if err != nil || eliminados != 2 {
+		t.Fatalf("Se esperaban 2 eliminados por antigüedad: %d (%v)", eliminados, err)
+	}
+
+	// La numeración continúa tras la poda
+	if err := repo.Guardar("red.txt", grafoAleatorio(r)); err != nil {
+		t.Fatalf("Error guardando: %v", err)
+	}
+	snapshots, _ = repo.ListarSnapshots("red.txt")
+	if len(snapshots) != 2 || snapshots[0].Numero != 5 || snapshots[1].Numero != 6 {
+		t.Errorf("Snapshots inesperados tras la poda por antigüedad: %+v", snapshots)
+	}
+}
+
+// TestSnapshotsMemoria verifica el historial del repositorio en memoria
+func TestSnapshotsMemoria(t *testing.T) {
+	repo := NuevoMemoryRepository()
+	r := rand.New(rand.NewSource(9))
+	primero := grafoAleatorio(r)
+
+	repo.GuardarConMensaje("red", primero, "inicial")
+	repo.Guardar("red", grafoAleatorio(r))
+	if err := repo.RestaurarSnapshot("red", 1); err != nil {
+		t.Fatalf("Error restaurando: %v", err)
+	}
+
+	actual, err := repo.Cargar("red")
+	if err != nil {
+		t.Fatalf("Error cargando: %v", err)
+	}
+	if err := compararGrafos(primero, actual); err != nil {
+		t.Fatalf("grafo restaurado: %v", err)
+	}
+
+	snapshots, _ := repo.ListarSnapshots("red")
+	if len(snapshots) != 3 {
+		t.Fatalf("Se esperaban 3 snapshots: %+v", snapshots)
+	}
+	if eliminados, _ := repo.PodarSnapshots("red", 1, 0); eliminados != 2 {
+		t.Errorf("Se esperaban 2 eliminados: %d", eliminados)
+	}
+}
@@ -0,0 +1,376 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"proyecto-grafos-go/internal/domain"
+	"time"
+
+	_ "modernc.org/sqlite" // Driver SQLite en Go puro (sin cgo)
+)
+
+// Esquema de la base de datos: un grafo con nombre por fila en grafos,
+// y sus cuevas, recursos, demandas y aristas en tablas hijas
+const esquemaSQLite = `
+CREATE TABLE IF NOT EXISTS grafos (
+	id          INTEGER PRIMARY KEY AUTOINCREMENT,
+	nombre      TEXT    NOT NULL UNIQUE,
+	es_dirigido INTEGER NOT NULL,
+	guardado_en TEXT    NOT NULL
+);
+CREATE TABLE IF NOT EXISTS cuevas (
+	grafo_id INTEGER NOT NULL REFERENCES grafos(id) ON DELETE CASCADE,
+	id       TEXT    NOT NULL,
+	nombre   TEXT    NOT NULL,
+	x        REAL    NOT NULL,
+	y        REAL    NOT NULL,
+	PRIMARY KEY (grafo_id, id)
+);
+CREATE TABLE IF NOT EXISTS recursos (
+	grafo_id INTEGER NOT NULL,
+	cueva_id TEXT    NOT NULL,
+	nombre   TEXT    NOT NULL,
+	cantidad INTEGER NOT NULL,
+	PRIMARY KEY (grafo_id, cueva_id, nombre),
+	FOREIGN KEY (grafo_id, cueva_id) REFERENCES cuevas(grafo_id, id) ON DELETE CASCADE
+);
+CREATE TABLE IF NOT EXISTS demandas (
+	grafo_id INTEGER NOT NULL,
+	cueva_id TEXT    NOT NULL,
+	recurso  TEXT    NOT NULL,
+	cantidad INTEGER NOT NULL,
+	PRIMARY KEY (grafo_id, cueva_id, recurso),
+	FOREIGN KEY (grafo_id, cueva_id) REFERENCES cuevas(grafo_id, id) ON DELETE CASCADE
+);
+CREATE TABLE IF NOT EXISTS aristas (
+	grafo_id     INTEGER NOT NULL REFERENCES grafos(id) ON DELETE CASCADE,
+	orden        INTEGER NOT NULL,
+	desde        TEXT    NOT NULL,
+	hasta        TEXT    NOT NULL,
+	distancia    REAL    NOT NULL,
+	es_dirigido  INTEGER NOT NULL,
+	es_obstruido INTEGER NOT NULL,
+	capacidad    REAL    NOT NULL DEFAULT 0,
+	es_angosto   INTEGER NOT NULL DEFAULT 0,
+	PRIMARY KEY (grafo_id, orden)
+);
+CREATE INDEX IF NOT EXISTS idx_aristas_desde ON aristas(grafo_id, desde);
+CREATE INDEX IF NOT EXISTS idx_aristas_hasta ON aristas(grafo_id, hasta);
+`
+
+// RepositorioSQLite persiste grafos con nombre en un archivo SQLite embebido
+type RepositorioSQLite struct {
+	db *sql.DB
+}
+
+// ResumenGrafo describe un grafo almacenado sin cargarlo completo
+type ResumenGrafo struct {
+	Nombre     string    `json:"nombre"`
+	EsDirigido bool      `json:"es_dirigido"`
+	NumCuevas  int       `json:"num_cuevas"`
+	NumAristas int       `json:"num_aristas"` // Cada túnel no dirigido cuenta una vez
+	GuardadoEn time.Time `json:"guardado_en"`
+}
+
+// NuevoRepositorioSQLite abre (o crea) la base de datos en la ruta indicada
+func NuevoRepositorioSQLite(ruta string) (*RepositorioSQLite, error) {
+	db, err := sql.Open("sqlite", "file:"+ruta+"?_pragma=foreign_keys(1)&_pragma=busy_timeout(5000)")
+	if err != nil {
+		return nil, fmt.Errorf("error abriendo la base de datos SQLite: %v", err)
+	}
+
+	if _, err := db.Exec(esquemaSQLite); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("error creando el esquema SQLite: %v", err)
+	}
+
+	// Las bases creadas antes de guardar la capacidad o los túneles angostos no tienen esas columnas
+	if err := agregarColumnaSQLite(db, "aristas", "capacidad", "REAL NOT NULL DEFAULT 0"); err != nil {
+		db.Close()
+		return nil, err
+	}
+	if err := agregarColumnaSQLite(db, "aristas", "es_angosto", "INTEGER NOT NULL DEFAULT 0"); err != nil {
+		db.Close()
+		return nil, err
+	}
+
+	return &RepositorioSQLite{db: db}, nil
+}
+
+// agregarColumnaSQLite agrega la columna a la tabla si todavía no existe
+func agregarColumnaSQLite(db *sql.DB, tabla, columna, definicion string) error {
+	var existe bool
+	err := db.QueryRow(`SELECT EXISTS (SELECT 1 FROM pragma_table_info(?) WHERE name = ?)`, tabla, columna).Scan(&existe)
+	if err != nil {
+		return fmt.Errorf("error consultando las columnas de %s: %v", tabla, err)
+	}
+	if existe {
+		return nil
+	}
+	if _, err := db.Exec(fmt.Sprintf(`ALTER TABLE %s ADD COLUMN %s %s`, tabla, columna, definicion)); err != nil {
+		return fmt.Errorf("error agregando la columna %s a %s: %v", columna, tabla, err)
+	}
+	return nil
+}
+
+// Cerrar libera la conexión con la base de datos
+func (rs *RepositorioSQLite) Cerrar() error {
+	return rs.db.Close()
+}
+
+// Guardar almacena el grafo con el nombre indicado, reemplazando la versión anterior
+func (rs *RepositorioSQLite) Guardar(nombre string, grafo *domain.Grafo) error {
+	if grafo == nil {
+		return fmt.Errorf("grafo no puede ser nil")
+	}
+	if nombre == "" {
+		return fmt.Errorf("nombre no puede estar vacío")
+	}
+
+	tx, err := rs.db.Begin()
+	if err != nil {
+		return fmt.Errorf("error iniciando transacción: %v", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(`DELETE FROM grafos WHERE nombre = ?`, nombre); err != nil {
+		return fmt.Errorf("error reemplazando el grafo '%s': %v", nombre, err)
+	}
+
+	dataGrafo := extraerDatosGrafo(grafo)
+
+	resultado, err := tx.Exec(`INSERT INTO grafos (nombre, es_dirigido, guardado_en) VALUES (?, ?, ?)`,
+		nombre, dataGrafo.EsDirigido, time.Now().UTC().Format(time.RFC3339Nano))
+	if err != nil {
+		return fmt.Errorf("error guardando el grafo '%s': %v", nombre, err)
+	}
+	grafoID, err := resultado.LastInsertId()
+	if err != nil {
+		return fmt.Errorf("error obteniendo el ID del grafo: %v", err)
+	}
+
+	insertarCueva, err := tx.Prepare(`INSERT INTO cuevas (grafo_id, id, nombre, x, y) VALUES (?, ?, ?, ?, ?)`)
+	if err != nil {
+		return err
+	}
+	defer insertarCueva.Close()
+
+	insertarRecurso, err := tx.Prepare(`INSERT INTO recursos (grafo_id, cueva_id, nombre, cantidad) VALUES (?, ?, ?, ?)`)
+	if err != nil {
+		return err
+	}
+	defer insertarRecurso.Close()
+
+	insertarDemanda, err := tx.Prepare(`INSERT INTO demandas (grafo_id, cueva_id, recurso, cantidad) VALUES (?, ?, ?, ?)`)
+	if err != nil {
+		return err
+	}
+	defer insertarDemanda.Close()
+
+	insertarArista, err := tx.Prepare(`INSERT INTO aristas (grafo_id, orden, desde, hasta, distancia, es_dirigido, es_obstruido, capacidad, es_angosto) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)`)
+	if err != nil {
+		return err
+	}
+	defer insertarArista.Close()
+
+	for _, cueva := range dataGrafo.Cuevas {
+		if _, err := insertarCueva.Exec(grafoID, cueva.ID, cueva.Nombre, cueva.X, cueva.Y); err != nil {
+			return fmt.Errorf("error guardando la cueva %s: %v", cueva.ID, err)
+		}
+		for recurso, cantidad := range cueva.Recursos {
+			if _, err := insertarRecurso.Exec(grafoID, cueva.ID, recurso, cantidad); err != nil {
+				return fmt.Errorf("error guardando el recurso %s de la cueva %s: %v", recurso, cueva.ID, err)
+			}
+		}
+		for recurso, cantidad := range cueva.Demanda {
+			if _, err := insertarDemanda.Exec(grafoID, cueva.ID, recurso, cantidad); err != nil {
+				return fmt.Errorf("error guardando la demanda de %s de la cueva %s: %v", recurso, cueva.ID, err)
+			}
+		}
+	}
+
+	for i, arista := range dataGrafo.Aristas {
+		if _, err := insertarArista.Exec(grafoID, i, arista.Desde, arista.Hasta, arista.Distancia,
+			arista.EsDirigido, arista.EsObstruido, arista.Capacidad, arista.EsAngosto); err != nil {
+			return fmt.Errorf("error guardando la arista %s->%s: %v", arista.Desde, arista.Hasta, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("error confirmando la transacción: %v", err)
+	}
+	return nil
+}
+
+// Cargar recupera el grafo con el nombre indicado
+func (rs *RepositorioSQLite) Cargar(nombre string) (*domain.Grafo, error) {
+	if nombre == "" {
+		return nil, fmt.Errorf("nombre no puede estar vacío")
+	}
+
+	var grafoID int64
+	dataGrafo := &DataGrafo{}
+	err := rs.db.QueryRow(`SELECT id, es_dirigido FROM grafos WHERE nombre = ?`, nombre).Scan(&grafoID, &dataGrafo.EsDirigido)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("grafo '%s' no encontrado", nombre)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error consultando el grafo '%s': %v", nombre, err)
+	}
+
+	// Cuevas
+	filas, err := rs.db.Query(`SELECT id, nombre, x, y FROM cuevas WHERE grafo_id = ? ORDER BY id`, grafoID)
+	if err != nil {
+		return nil, fmt.Errorf("error consultando cuevas: %v", err)
+	}
+	cuevas := make(map[string]*domain.Cueva)
+	for filas.Next() {
+		var id, nombreCueva string
+		var x, y float64
+		if err := filas.Scan(&id, &nombreCueva, &x, &y); err != nil {
+			filas.Close()
+			return nil, fmt.Errorf("error leyendo cueva: %v", err)
+		}
+		cueva := domain.NuevaCueva(id, nombreCueva)
+		cueva.X = x
+		cueva.Y = y
+		cuevas[id] = cueva
+		dataGrafo.Cuevas = append(dataGrafo.Cuevas, cueva)
+	}
+	filas.Close()
+	if err := filas.Err(); err != nil {
+		return nil, fmt.Errorf("error leyendo cuevas: %v", err)
+	}
+
+	// Recursos
+	filas, err = rs.db.Query(`SELECT cueva_id, nombre, cantidad FROM recursos WHERE grafo_id = ?`, grafoID)
+	if err != nil {
+		return nil, fmt.Errorf("error consultando recursos: %v", err)
+	}
+	for filas.Next() {
+		var cuevaID, recurso string
+		var cantidad int
+		if err := filas.Scan(&cuevaID, &recurso, &cantidad); err != nil {
+			filas.Close()
+			return nil, fmt.Errorf("error leyendo recurso: %v", err)
+		}
+		if cueva, ok := cuevas[cuevaID]; ok {
+			cueva.AgregarRecurso(recurso, cantidad)
+		}
+	}
+	filas.Close()
+	if err := filas.Err(); err != nil {
+		return nil, fmt.Errorf("error leyendo recursos: %v", err)
+	}
+
+	// Demandas
+	filas, err = rs.db.Query(`SELECT cueva_id, recurso, cantidad FROM demandas WHERE grafo_id = ?`, grafoID)
+	if err != nil {
+		return nil, fmt.Errorf("error consultando demandas: %v", err)
+	}
+	for filas.Next() {
+		var cuevaID, recurso string
+		var cantidad int
+		if err := filas.Scan(&cuevaID, &recurso, &cantidad); err != nil {
+			filas.Close()
+			return nil, fmt.Errorf("error leyendo demanda: %v", err)
+		}
+		if cueva, ok := cuevas[cuevaID]; ok {
+			if cueva.Demanda == nil {
+				cueva.Demanda = make(map[string]int)
+			}
+			cueva.Demanda[recurso] = cantidad
+		}
+	}
+	filas.Close()
+	if err := filas.Err(); err != nil {
+		return nil, fmt.Errorf("error leyendo demandas: %v", err)
+	}
+
+	// Aristas
+	filas, err = rs.db.Query(`SELECT desde, hasta, distancia, es_dirigido, es_obstruido, capacidad, es_angosto FROM aristas WHERE grafo_id = ? ORDER BY orden`, grafoID)
+	if err != nil {
+		return nil, fmt.Errorf("error consultando aristas: %v", err)
+	}
+	for filas.Next() {
+		arista := &domain.Arista{}
+		if err := filas.Scan(&arista.Desde, &arista.Hasta, &arista.Distancia, &arista.EsDirigido, &arista.EsObstruido, &arista.Capacidad, &arista.EsAngosto); err != nil {
+			filas.Close()
+			return nil, fmt.Errorf("error leyendo arista: %v", err)
+		}
+		dataGrafo.Aristas = append(dataGrafo.Aristas, arista)
+	}
+	filas.Close()
+	if err := filas.Err(); err != nil {
+		return nil, fmt.Errorf("error leyendo aristas: %v", err)
+	}
+
+	return construirGrafo(dataGrafo)
+}
+
+// Listar devuelve los nombres de los grafos almacenados en orden alfabético
+func (rs *RepositorioSQLite) Listar() ([]string, error) {
+	filas, err := rs.db.Query(`SELECT nombre FROM grafos ORDER BY nombre`)
+	if err != nil {
+		return nil, fmt.Errorf("error listando grafos: %v", err)
+	}
+	defer filas.Close()
+
+	nombres := make([]string, 0)
+	for filas.Next() {
+		var nombre string
+		if err := filas.Scan(&nombre); err != nil {
+			return nil, fmt.Errorf("error leyendo nombre de grafo: %v", err)
+		}
+		nombres = append(nombres, nombre)
+	}
+	return nombres, filas.Err()
+}
+
+// Eliminar borra el grafo y todas sus cuevas, recursos y aristas
+func (rs *RepositorioSQLite) Eliminar(nombre string) error {
+	if nombre == "" {
+		return fmt.Errorf("nombre no puede estar vacío")
+	}
+
+	resultado, err := rs.db.Exec(`DELETE FROM grafos WHERE nombre = ?`, nombre)
+	if err != nil {
+		return fmt.Errorf("error eliminando el grafo '%s': %v", nombre, err)
+	}
+	if filas, _ := resultado.RowsAffected(); filas == 0 {
+		return fmt.Errorf("grafo '%s' no encontrado", nombre)
+	}
+	return nil
+}
+
+// Existe verifica si hay un grafo almacenado con ese nombre
+func (rs *RepositorioSQLite) Existe(nombre string) bool {
+	var existe bool
+	err := rs.db.QueryRow(`SELECT EXISTS (SELECT 1 FROM grafos WHERE nombre = ?)`, nombre).Scan(&existe)
+	return err == nil && existe
+}
+
+// ListarResumenes devuelve nombre, tamaño y fecha de guardado de todos los grafos
+func (rs *RepositorioSQLite) ListarResumenes() ([]ResumenGrafo, error) {
+	filas, err := rs.db.Query(`
+		SELECT g.nombre, g.es_dirigido, g.guardado_en,
+		       (SELECT COUNT(*) FROM cuevas c WHERE c.grafo_id = g.id),
+		       (SELECT COUNT(*) FROM aristas a WHERE a.grafo_id = g.id)
+		FROM grafos g ORDER BY g.nombre`)
+	if err != nil {
+		return nil, fmt.Errorf("error listando resúmenes: %v", err)
+	}
+	defer filas.Close()
+
+	resumenes := make([]ResumenGrafo, 0)
+	for filas.Next() {
+		var resumen ResumenGrafo
+		var guardadoEn string
+		if err := filas.Scan(&resumen.Nombre, &resumen.EsDirigido, &guardadoEn, &resumen.NumCuevas, &resumen.NumAristas); err != nil {
+			return nil, fmt.Errorf("error leyendo resumen: %v", err)
+		}
+		resumen.GuardadoEn, _ = time.Parse(time.RFC3339Nano, guardadoEn)
+		resumenes = append(resumenes, resumen)
+	}
+	return resumenes, filas.Err()
+}
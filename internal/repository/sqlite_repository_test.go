@@ -0,0 +1,117 @@
+package repository
+
+import (
+	"database/sql"
+	"math/rand"
+	"path/filepath"
+	"proyecto-grafos-go/internal/domain"
+	"testing"
+)
+
+// TestRepositorioSQLiteIdaYVuelta verifica que los grafos se recuperan sin pérdidas
+func TestRepositorioSQLiteIdaYVuelta(t *testing.T) {
+	repo, err := NuevoRepositorioSQLite(filepath.Join(t.TempDir(), "grafos.db"))
+	if err != nil {
+		t.Fatalf("Error abriendo la base de datos: %v", err)
+	}
+	defer repo.Cerrar()
+
+	r := rand.New(rand.NewSource(7))
+	for i := 0; i < 50; i++ {
+		grafo := grafoAleatorio(r)
+		asignarCapacidades(r, grafo)
+		asignarDemandas(r, grafo)
+		if err := repo.Guardar("red", grafo); err != nil {
+			t.Fatalf("iteración %d: error al guardar: %v", i, err)
+		}
+		cargado, err := repo.Cargar("red")
+		if err != nil {
+			t.Fatalf("iteración %d: error al cargar: %v", i, err)
+		}
+		if err := compararGrafos(grafo, cargado); err != nil {
+			t.Fatalf("iteración %d: %v", i, err)
+		}
+	}
+}
+
+// TestRepositorioSQLiteGrafosConNombre verifica listar, existir y eliminar
+func TestRepositorioSQLiteGrafosConNombre(t *testing.T) {
+	repo, err := NuevoRepositorioSQLite(filepath.Join(t.TempDir(), "grafos.db"))
+	if err != nil {
+		t.Fatalf("Error abriendo la base de datos: %v", err)
+	}
+	defer repo.Cerrar()
+
+	r := rand.New(rand.NewSource(1))
+	for _, nombre := range []string{"lunes", "martes", "miercoles"} {
+		if err := repo.Guardar(nombre, grafoAleatorio(r)); err != nil {
+			t.Fatalf("Error guardando %s: %v", nombre, err)
+		}
+	}
+
+	nombres, err := repo.Listar()
+	if err != nil || len(nombres) != 3 || nombres[0] != "lunes" {
+		t.Fatalf("Listado inesperado: %v (%v)", nombres, err)
+	}
+
+	if err := repo.Eliminar("martes"); err != nil {
+		t.Fatalf("Error eliminando: %v", err)
+	}
+	if repo.Existe("martes") || !repo.Existe("lunes") {
+		t.Error("Existe no refleja la eliminación")
+	}
+	if err := repo.Eliminar("martes"); err == nil {
+		t.Error("Se esperaba error al eliminar un grafo inexistente")
+	}
+	if _, err := repo.Cargar("martes"); err == nil {
+		t.Error("Se esperaba error al cargar un grafo eliminado")
+	}
+
+	resumenes, err := repo.ListarResumenes()
+	if err != nil || len(resumenes) != 2 {
+		t.Fatalf("Resúmenes inesperados: %v (%v)", resumenes, err)
+	}
+	if resumenes[0].GuardadoEn.IsZero() {
+		t.Error("La fecha de guardado debería registrarse")
+	}
+}
+
+// TestRepositorioSQLiteMigraCapacidad verifica que una base anterior a la
+// columna de capacidad se actualiza al abrirla
+func TestRepositorioSQLiteMigraCapacidad(t *testing.T) {
+	ruta := filepath.Join(t.TempDir(), "grafos.db")
+	db, err := sql.Open("sqlite", "file:"+ruta)
+	if err != nil {
+		t.Fatalf("Error abriendo la base de datos: %v", err)
+	}
+	_, err = db.Exec(`CREATE TABLE aristas (
+		grafo_id INTEGER NOT NULL, orden INTEGER NOT NULL, desde TEXT NOT NULL, hasta TEXT NOT NULL,
+		distancia REAL NOT NULL, es_dirigido INTEGER NOT NULL, es_obstruido INTEGER NOT NULL,
+		PRIMARY KEY (grafo_id, orden))`)
+	db.Close()
+	if err != nil {
+		t.Fatalf("Error creando el esquema anterior: %v", err)
+	}
+
+	repo, err := NuevoRepositorioSQLite(ruta)
+	if err != nil {
+		t.Fatalf("Error abriendo la base anterior: %v", err)
+	}
+	defer repo.Cerrar()
+
+	grafo := domain.NuevoGrafo(false)
+	grafo.AgregarCueva(domain.NuevaCueva("A", "A"))
+	grafo.AgregarCueva(domain.NuevaCueva("B", "B"))
+	grafo.AgregarConexion("A", "B", 5)
+	grafo.Aristas[0].Capacidad = 2.5
+	if err := repo.Guardar("red", grafo); err != nil {
+		t.Fatalf("Error al guardar: %v", err)
+	}
+	cargado, err := repo.Cargar("red")
+	if err != nil {
+		t.Fatalf("Error al cargar: %v", err)
+	}
+	if err := compararGrafos(grafo, cargado); err != nil {
+		t.Fatal(err)
+	}
+}
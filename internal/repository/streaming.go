@@ -0,0 +1,238 @@
+package repository
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"proyecto-grafos-go/internal/domain"
+	"sort"
+	"strings"
+)
+
+// Tamaño del búfer de lectura para los decodificadores en streaming
+const tamanoBufferStreaming = 64 * 1024
+
+// decodificarJSONStreaming lee un grafo JSON elemento a elemento, sin cargar
+// el archivo completo en memoria, validando cada elemento con su ruta JSON
+func decodificarJSONStreaming(r io.Reader, archivo string) (*domain.Grafo, []ProblemaValidacion, error) {
+	decoder := json.NewDecoder(bufio.NewReaderSize(r, tamanoBufferStreaming))
+	carga := nuevaCargaValidada(archivo)
+
+	if err := esperarDelimitadorJSON(decoder, '{'); err != nil {
+		return nil, nil, err
+	}
+
+	for decoder.More() {
+		token, err := decoder.Token()
+		if err != nil {
+			return nil, carga.problemas, fmt.Errorf("error parsing JSON: %v", err)
+		}
+		clave, _ := token.(string)
+
+		// Las claves se comparan sin distinguir mayúsculas, como json.Unmarshal
+		switch {
+		case strings.EqualFold(clave, "cuevas"):
+			err = decodificarArregloJSON(decoder, func(i int, elemento json.RawMessage) {
+				ubicacion := fmt.Sprintf("$.cuevas[%d]", i)
+				var cueva domain.Cueva
+				if decodificarElementoJSON(carga, elemento, &cueva, ubicacion, camposCueva) {
+					carga.cueva(&cueva, ubicacion)
+				}
+			})
+		case strings.EqualFold(clave, "aristas"):
+			err = decodificarArregloJSON(decoder, func(i int, elemento json.RawMessage) {
+				ubicacion := fmt.Sprintf("$.aristas[%d]", i)
+				var arista domain.Arista
+				if decodificarElementoJSON(carga, elemento, &arista, ubicacion, camposArista) {
+					carga.arista(&arista, ubicacion)
+				}
+			})
+		case strings.EqualFold(clave, "es_dirigido"):
+			var esDirigido bool
+			if err = decoder.Decode(&esDirigido); err != nil {
+				err = fmt.Errorf("error parsing JSON: $.%s: %v", clave, err)
+			}
+			carga.constructor.EstablecerDirigido(esDirigido)
+		default:
+			carga.camposDesconocidos("$", []string{clave}, camposGrafo)
+			var ignorado json.RawMessage
+			if err = decoder.Decode(&ignorado); err != nil {
+				err = fmt.Errorf("error parsing JSON: %v", err)
+			}
+		}
+		if err != nil {
+			return nil, carga.problemas, err
+		}
+	}
+
+	if err := esperarDelimitadorJSON(decoder, '}'); err != nil {
+		return nil, carga.problemas, err
+	}
+
+	return carga.terminar()
+}
+
+// decodifica un elemento de un arreglo; los valores de tipo incorrecto se
+// reportan como error del elemento y los campos desconocidos como advertencia
+func decodificarElementoJSON(carga *cargaValidada, elemento json.RawMessage, destino interface{}, ubicacion string, conocidos map[string]bool) bool {
+	// Camino rápido: un elemento válido se decodifica una sola vez
+	estricto := json.NewDecoder(bytes.NewReader(elemento))
+	estricto.DisallowUnknownFields()
+	if estricto.Decode(destino) == nil {
+		return true
+	}
+
+	var campos map[string]json.RawMessage
+	if err := json.Unmarshal(elemento, &campos); err != nil {
+		carga.problema(ubicacion, "", SeveridadError, "se esperaba un objeto: %v", err)
+		return false
+	}
+	nombres := make([]string, 0, len(campos))
+	for nombre := range campos {
+		nombres = append(nombres, nombre)
+	}
+	sort.Strings(nombres)
+	carga.camposDesconocidos(ubicacion, nombres, conocidos)
+
+	if err := json.Unmarshal(elemento, destino); err != nil {
+		if errTipo, ok := err.(*json.UnmarshalTypeError); ok {
+			carga.problema(ubicacion, errTipo.Field, SeveridadError, "valor de tipo %s inválido, se esperaba %s", errTipo.Value, errTipo.Type)
+		} else {
+			carga.problema(ubicacion, "", SeveridadError, "%v", err)
+		}
+		return false
+	}
+	return true
+}
+
+// recorre un arreglo JSON llamando a procesar por cada elemento; acepta null
+func decodificarArregloJSON(decoder *json.Decoder, procesar func(i int, elemento json.RawMessage)) error {
+	token, err := decoder.Token()
+	if err != nil {
+		return fmt.Errorf("error parsing JSON: %v", err)
+	}
+	if token == nil {
+		return nil
+	}
+	if delimitador, ok := token.(json.Delim); !ok || delimitador != '[' {
+		return fmt.Errorf("error parsing JSON: se esperaba un arreglo y se encontró %v", token)
+	}
+
+	for i := 0; decoder.More(); i++ {
+		var elemento json.RawMessage
+		if err := decoder.Decode(&elemento); err != nil {
+			return fmt.Errorf("error parsing JSON: %v", err)
+		}
+		procesar(i, elemento)
+	}
+
+	if _, err := decoder.Token(); err != nil {
+		return fmt.Errorf("error parsing JSON: %v", err)
+	}
+	return nil
+}
+
+// consume el siguiente token y verifica que sea el delimitador indicado
+func esperarDelimitadorJSON(decoder *json.Decoder, esperado json.Delim) error {
+	token, err := decoder.Token()
+	if err != nil {
+		return fmt.Errorf("error parsing JSON: %v", err)
+	}
+	if delimitador, ok := token.(json.Delim); !ok || delimitador != esperado {
+		return fmt.Errorf("error parsing JSON: se esperaba '%v' y se encontró %v", esperado, token)
+	}
+	return nil
+}
+
+// Elemento XML no contemplado por el esquema
+type elementoDesconocidoXML struct {
+	XMLName xml.Name
+}
+
+// Arista leída desde XML con los elementos desconocidos que contenga
+type aristaLecturaXML struct {
+	domain.Arista
+	Desconocidos []elementoDesconocidoXML `xml:",any"`
+}
+
+// decodificarXMLStreaming lee un grafo XML elemento a elemento validando
+// cada cueva y arista con su número de línea
+func decodificarXMLStreaming(r io.Reader, archivo string) (*domain.Grafo, []ProblemaValidacion, error) {
+	decoder := xml.NewDecoder(bufio.NewReaderSize(r, tamanoBufferStreaming))
+	carga := nuevaCargaValidada(archivo)
+	raiz := true
+
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, carga.problemas, fmt.Errorf("error parsing XML: %v", err)
+		}
+
+		inicio, ok := token.(xml.StartElement)
+		if !ok {
+			continue
+		}
+		// El nombre de la raíz no se valida, igual que con xml.Unmarshal
+		if raiz {
+			raiz = false
+			continue
+		}
+
+		linea, _ := decoder.InputPos()
+		ubicacion := fmt.Sprintf("línea %d", linea)
+
+		switch inicio.Name.Local {
+		case "cuevas", "aristas":
+			// Contenedores: sus hijos se procesan en las siguientes iteraciones
+		case "cueva":
+			var cXML cuevaXML
+			if err := decoder.DecodeElement(&cXML, &inicio); err != nil {
+				carga.problema(ubicacion, "", SeveridadError, "cueva inválida: %v", err)
+				continue
+			}
+			carga.camposDesconocidos(ubicacion, nombresElementosXML(cXML.Desconocidos), camposCueva)
+			carga.cueva(desdeCuevaXML(&cXML), ubicacion)
+		case "arista":
+			var aXML aristaLecturaXML
+			if err := decoder.DecodeElement(&aXML, &inicio); err != nil {
+				carga.problema(ubicacion, "", SeveridadError, "arista inválida: %v", err)
+				continue
+			}
+			carga.camposDesconocidos(ubicacion, nombresElementosXML(aXML.Desconocidos), camposArista)
+			arista := aXML.Arista
+			carga.arista(&arista, ubicacion)
+		case "es_dirigido":
+			var esDirigido bool
+			if err := decoder.DecodeElement(&esDirigido, &inicio); err != nil {
+				return nil, carga.problemas, fmt.Errorf("error parsing XML: %s: %v", ubicacion, err)
+			}
+			carga.constructor.EstablecerDirigido(esDirigido)
+		default:
+			carga.camposDesconocidos(ubicacion, []string{inicio.Name.Local}, camposGrafo)
+			if err := decoder.Skip(); err != nil {
+				return nil, carga.problemas, fmt.Errorf("error parsing XML: %v", err)
+			}
+		}
+	}
+
+	if raiz {
+		return nil, nil, fmt.Errorf("error parsing XML: EOF")
+	}
+
+	return carga.terminar()
+}
+
+// nombres locales de los elementos desconocidos
+func nombresElementosXML(elementos []elementoDesconocidoXML) []string {
+	nombres := make([]string, 0, len(elementos))
+	for _, elemento := range elementos {
+		nombres = append(nombres, elemento.XMLName.Local)
+	}
+	return nombres
+}
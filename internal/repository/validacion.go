@@ -0,0 +1,287 @@
+package repository
+
+import (
+	"fmt"
+	"proyecto-grafos-go/internal/domain"
+	"proyecto-grafos-go/pkg/utils"
+	"strings"
+)
+
+// ModoValidacion define qué hacer con los problemas detectados al cargar
+type ModoValidacion int
+
+const (
+	// ValidacionTolerante carga el grafo descartando los elementos inválidos y devuelve los problemas
+	ValidacionTolerante ModoValidacion = iota
+	// ValidacionEstricta rechaza la carga ante cualquier problema
+	ValidacionEstricta
+)
+
+// RepositorioValidado lo implementan los repositorios que pueden cargar reportando problemas de validación
+type RepositorioValidado interface {
+	CargarConValidacion(nombre string, modo ModoValidacion) (*domain.Grafo, []ProblemaValidacion, error)
+}
+
+// Verificación en compilación de la implementación
+var _ RepositorioValidado = (*RepositorioArchivo)(nil)
+
+// Severidad de un problema de validación
+const (
+	SeveridadError       = "error"       // El elemento no puede cargarse
+	SeveridadAdvertencia = "advertencia" // El elemento se carga tal cual
+)
+
+// ProblemaValidacion describe un problema y su ubicación en el archivo
+type ProblemaValidacion struct {
+	Archivo   string `json:"archivo,omitempty"`
+	Ubicacion string `json:"ubicacion"` // "línea 12" o ruta JSON como "$.aristas[3]"
+	Campo     string `json:"campo,omitempty"`
+	Severidad string `json:"severidad"`
+	Mensaje   string `json:"mensaje"`
+}
+
+// String formatea el problema como archivo:ubicación: mensaje
+func (p ProblemaValidacion) String() string {
+	ubicacion := p.Ubicacion
+	if p.Campo != "" {
+		if strings.HasPrefix(ubicacion, "$") {
+			ubicacion += "." + p.Campo
+		} else {
+			ubicacion += fmt.Sprintf(" (%s)", p.Campo)
+		}
+	}
+	if p.Archivo != "" {
+		ubicacion = p.Archivo + ":" + ubicacion
+	}
+	return fmt.Sprintf("%s: %s: %s", ubicacion, p.Severidad, p.Mensaje)
+}
+
+// ErrorValidacion agrupa los problemas que impidieron cargar un archivo
+type ErrorValidacion struct {
+	Problemas []ProblemaValidacion
+}
+
+// Error lista todos los problemas
+func (e *ErrorValidacion) Error() string {
+	lineas := make([]string, 0, len(e.Problemas)+1)
+	lineas = append(lineas, fmt.Sprintf("el archivo tiene %d problema(s) de validación:", len(e.Problemas)))
+	for _, problema := range e.Problemas {
+		lineas = append(lineas, "  "+problema.String())
+	}
+	return strings.Join(lineas, "\n")
+}
+
+// Campos conocidos de cada elemento, para advertir de los desconocidos
+var (
+	camposGrafo  = map[string]bool{"cuevas": true, "aristas": true, "es_dirigido": true}
+	camposCueva  = map[string]bool{"id": true, "nombre": true, "recursos": true, "demanda": true, "x": true, "y": true}
+	camposArista = map[string]bool{"desde": true, "hasta": true, "distancia": true, "es_dirigido": true, "es_obstruido": true, "capacidad": true, "es_angosto": true}
+)
+
+// Arista cuyo extremo no se conocía al leerla
+type aristaPendiente struct {
+	arista    *domain.Arista
+	ubicacion string
+}
+
+// cargaValidada alimenta un constructor en lote validando cada elemento;
+// la usan todos los formatos para reportar problemas con su ubicación
+type cargaValidada struct {
+	archivo     string
+	constructor *domain.ConstructorGrafo
+	problemas   []ProblemaValidacion
+	cuevas      map[string]bool
+	pendientes  []aristaPendiente
+	repetidas   []aristaPendiente
+}
+
+// crea una carga validada para el archivo indicado
+func nuevaCargaValidada(archivo string) *cargaValidada {
+	return &cargaValidada{
+		archivo:     archivo,
+		constructor: domain.NuevoConstructorGrafo(),
+		cuevas:      make(map[string]bool),
+	}
+}
+
+// registra un problema
+func (c *cargaValidada) problema(ubicacion, campo, severidad, formato string, args ...interface{}) {
+	c.problemas = append(c.problemas, ProblemaValidacion{
+		Archivo:   c.archivo,
+		Ubicacion: ubicacion,
+		Campo:     campo,
+		Severidad: severidad,
+		Mensaje:   fmt.Sprintf(formato, args...),
+	})
+}
+
+// advierte de los campos que no pertenecen al esquema
+func (c *cargaValidada) camposDesconocidos(ubicacion string, campos []string, conocidos map[string]bool) {
+	for _, campo := range campos {
+		if !conocidos[strings.ToLower(campo)] {
+			c.problema(ubicacion, campo, SeveridadAdvertencia, "campo desconocido '%s'", campo)
+		}
+	}
+}
+
+// valida y agrega una cueva
+func (c *cargaValidada) cueva(cueva *domain.Cueva, ubicacion string) {
+	if strings.TrimSpace(cueva.ID) == "" {
+		c.problema(ubicacion, "id", SeveridadError, "la cueva no tiene ID")
+		return
+	}
+	if err := c.constructor.AgregarCueva(cueva); err != nil {
+		c.problema(ubicacion, "id", SeveridadError, "%v", err)
+		return
+	}
+	c.cuevas[cueva.ID] = true
+
+	if !utils.ValidarID(cueva.ID) {
+		c.problema(ubicacion, "id", SeveridadAdvertencia, "ID de cueva '%s' con formato inválido", cueva.ID)
+	}
+	if !utils.ValidarCoordenadas(cueva.X, cueva.Y) {
+		c.problema(ubicacion, "x", SeveridadAdvertencia, "coordenadas (%g, %g) fuera de rango", cueva.X, cueva.Y)
+	}
+	for _, recurso := range recursosOrdenados(cueva) {
+		if cueva.Recursos[recurso] < 0 {
+			c.problema(ubicacion, "recursos", SeveridadAdvertencia, "cantidad negativa del recurso '%s': %d", recurso, cueva.Recursos[recurso])
+		}
+	}
+	for _, recurso := range recursosYDemandaOrdenados(cueva) {
+		if demanda, ok := cueva.Demanda[recurso]; ok && demanda <= 0 {
+			c.problema(ubicacion, "demanda", SeveridadAdvertencia, "demanda no positiva del recurso '%s': %d", recurso, demanda)
+		}
+	}
+}
+
+// valida y agrega una arista
+func (c *cargaValidada) arista(arista *domain.Arista, ubicacion string) {
+	repetida := c.constructor.Contiene(arista.Desde, arista.Hasta)
+	if err := c.constructor.AgregarArista(arista); err != nil {
+		c.problema(ubicacion, "", SeveridadError, "%v", err)
+		return
+	}
+	if repetida {
+		c.repetidas = append(c.repetidas, aristaPendiente{arista: arista, ubicacion: ubicacion})
+	}
+
+	if !c.cuevas[arista.Desde] || !c.cuevas[arista.Hasta] {
+		c.pendientes = append(c.pendientes, aristaPendiente{arista: arista, ubicacion: ubicacion})
+	}
+	if !utils.ValidarDistancia(arista.Distancia) {
+		c.problema(ubicacion, "distancia", SeveridadAdvertencia, "distancia %g fuera de rango (0, 10000]", arista.Distancia)
+	}
+	if arista.Capacidad < 0 {
+		c.problema(ubicacion, "capacidad", SeveridadAdvertencia, "capacidad negativa %g", arista.Capacidad)
+	}
+}
+
+// resuelve las validaciones pendientes y construye el grafo omitiendo los
+// elementos con errores; devuelve todos los problemas encontrados
+func (c *cargaValidada) terminar() (*domain.Grafo, []ProblemaValidacion, error) {
+	for _, pendiente := range c.pendientes {
+		for _, id := range []string{pendiente.arista.Desde, pendiente.arista.Hasta} {
+			if !c.cuevas[id] {
+				c.problema(pendiente.ubicacion, "", SeveridadError, "la arista %s -> %s referencia la cueva inexistente '%s'",
+					pendiente.arista.Desde, pendiente.arista.Hasta, id)
+				break
+			}
+		}
+	}
+	c.constructor.QuitarAristasSinCuevas()
+
+	if c.constructor.EsDirigido() {
+		for _, repetida := range c.repetidas {
+			c.problema(repetida.ubicacion, "", SeveridadError, "arista %s -> %s repetida; se conserva la última",
+				repetida.arista.Desde, repetida.arista.Hasta)
+		}
+	}
+	c.constructor.IgnorarRepetidas()
+
+	grafo, err := c.constructor.Construir()
+	if err != nil {
+		return nil, c.problemas, err
+	}
+	return grafo, c.problemas, nil
+}
+
+// aplica el modo de validación al resultado de una carga
+func aplicarModoValidacion(grafo *domain.Grafo, problemas []ProblemaValidacion, modo ModoValidacion) (*domain.Grafo, []ProblemaValidacion, error) {
+	if modo == ValidacionEstricta && len(problemas) > 0 {
+		return nil, problemas, &ErrorValidacion{Problemas: problemas}
+	}
+	return grafo, problemas, nil
+}
+
+// solo los problemas que impiden cargar un elemento
+func erroresDeValidacion(problemas []ProblemaValidacion) []ProblemaValidacion {
+	var errores []ProblemaValidacion
+	for _, problema := range problemas {
+		if problema.Severidad == SeveridadError {
+			errores = append(errores, problema)
+		}
+	}
+	return errores
+}
+
+// ValidarDataGrafo valida los datos de un grafo y devuelve todos los problemas;
+// las ubicaciones son rutas JSON ($.cuevas[i], $.aristas[i])
+func ValidarDataGrafo(archivo string, dataGrafo *DataGrafo) []ProblemaValidacion {
+	_, problemas, _ := validarDataGrafo(archivo, dataGrafo, nil)
+	return problemas
+}
+
+// exige que la carga no tenga errores; las advertencias se ignoran
+func exigirSinErrores(grafo *domain.Grafo, problemas []ProblemaValidacion, err error) (*domain.Grafo, error) {
+	if err != nil {
+		return nil, err
+	}
+	if errores := erroresDeValidacion(problemas); len(errores) > 0 {
+		return nil, &ErrorValidacion{Problemas: errores}
+	}
+	return grafo, nil
+}
+
+// ubicaciones 1-based de los elementos de un documento XML (node[1], edge[3])
+func ubicacionesPorElemento(dataGrafo *DataGrafo, elementoCueva, elementoArista string) *ubicacionesDataGrafo {
+	ubicaciones := &ubicacionesDataGrafo{
+		Cuevas:  make([]string, len(dataGrafo.Cuevas)),
+		Aristas: make([]string, len(dataGrafo.Aristas)),
+	}
+	for i := range dataGrafo.Cuevas {
+		ubicaciones.Cuevas[i] = fmt.Sprintf("%s[%d]", elementoCueva, i+1)
+	}
+	for i := range dataGrafo.Aristas {
+		ubicaciones.Aristas[i] = fmt.Sprintf("%s[%d]", elementoArista, i+1)
+	}
+	return ubicaciones
+}
+
+// Ubicación de cada elemento de un DataGrafo en el archivo de origen
+type ubicacionesDataGrafo struct {
+	Cuevas  []string
+	Aristas []string
+}
+
+// valida y construye un DataGrafo; sin ubicaciones usa rutas JSON
+func validarDataGrafo(archivo string, dataGrafo *DataGrafo, ubicaciones *ubicacionesDataGrafo) (*domain.Grafo, []ProblemaValidacion, error) {
+	carga := nuevaCargaValidada(archivo)
+	carga.constructor.EstablecerDirigido(dataGrafo.EsDirigido)
+
+	for i, cueva := range dataGrafo.Cuevas {
+		ubicacion := fmt.Sprintf("$.cuevas[%d]", i)
+		if ubicaciones != nil && i < len(ubicaciones.Cuevas) {
+			ubicacion = ubicaciones.Cuevas[i]
+		}
+		carga.cueva(cueva, ubicacion)
+	}
+	for i, arista := range dataGrafo.Aristas {
+		ubicacion := fmt.Sprintf("$.aristas[%d]", i)
+		if ubicaciones != nil && i < len(ubicaciones.Aristas) {
+			ubicacion = ubicaciones.Aristas[i]
+		}
+		carga.arista(arista, ubicacion)
+	}
+
+	return carga.terminar()
+}
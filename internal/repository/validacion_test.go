@@ -0,0 +1,163 @@
+package repository
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// escribe un archivo de prueba en el directorio del repositorio
+func escribirArchivoPrueba(t *testing.T, dir, nombre, contenido string) {
+	t.Helper()
+	if err := os.WriteFile(filepath.Join(dir, nombre), []byte(contenido), 0644); err != nil {
+		t.Fatalf("Error escribiendo %s: %v", nombre, err)
+	}
+}
+
+// indica si algún problema contiene todos los fragmentos indicados
+func contieneProblema(problemas []ProblemaValidacion, fragmentos ...string) bool {
+	for _, problema := range problemas {
+		texto := problema.String()
+		encontrado := true
+		for _, fragmento := range fragmentos {
+			if !strings.Contains(texto, fragmento) {
+				encontrado = false
+				break
+			}
+		}
+		if encontrado {
+			return true
+		}
+	}
+	return false
+}
+
+// TestValidacionTXT verifica números de línea y los modos tolerante y estricto
+func TestValidacionTXT(t *testing.T) {
+	dir := t.TempDir()
+	repo := NuevoRepositorio(dir)
+	escribirArchivoPrueba(t, dir, "red.txt", `[grafo]
+version=1
+dirigido=false
+color=rojo
+
+[cuevas]
+A,Cueva A,0,0
+B,Cueva B,1,1
+C,sin coordenadas
+
+[aristas]
+A,B,-3,false,false
+A,Z,2,false,false
+
+[extras]
+cualquier cosa
+`)
+
+	_, err := repo.Cargar("red.txt")
+	var errValidacion *ErrorValidacion
+	if !errors.As(err, &errValidacion) || len(errValidacion.Problemas) != 2 {
+		t.Fatalf("Se esperaban 2 errores de validación: %v", err)
+	}
+	if !strings.Contains(err.Error(), "red.txt:línea 9") || !strings.Contains(err.Error(), "red.txt:línea 13") {
+		t.Errorf("El error debe indicar las líneas: %v", err)
+	}
+
+	grafo, problemas, err := repo.CargarConValidacion("red.txt", ValidacionTolerante)
+	if err != nil {
+		t.Fatalf("El modo tolerante debe cargar: %v", err)
+	}
+	if grafo.NumeroCuevas() != 2 || grafo.NumeroAristas() != 2 {
+		t.Errorf("Se esperaban 2 cuevas y 2 aristas: %v", grafo)
+	}
+	esperados := [][]string{
+		{"línea 4 (color)", "advertencia"},
+		{"línea 9", "error", "cueva"},
+		{"línea 12 (distancia)", "advertencia", "-3"},
+		{"línea 13", "error", "'Z'"},
+		{"línea 15", "sección desconocida"},
+	}
+	for _, esperado := range esperados {
+		if !contieneProblema(problemas, esperado...) {
+			t.Errorf("Falta el problema %v en %v", esperado, problemas)
+		}
+	}
+
+	if _, _, err := repo.CargarConValidacion("red.txt", ValidacionEstricta); err == nil {
+		t.Error("El modo estricto debe rechazar la carga")
+	}
+}
+
+// TestValidacionJSON verifica rutas JSON, campos desconocidos y tipos inválidos
+func TestValidacionJSON(t *testing.T) {
+	dir := t.TempDir()
+	repo := NuevoRepositorio(dir)
+	escribirArchivoPrueba(t, dir, "red.json", `{
+  "cuevas": [
+    {"id": "A", "nombre": "Cueva A", "x": 0, "y": 0, "color": "rojo"},
+    {"id": "B", "nombre": "Cueva B", "x": 5000, "y": 0}
+  ],
+  "aristas": [
+    {"desde": "A", "hasta": "B", "distancia": 4},
+    {"desde": "A", "hasta": "B", "distancia": "lejos", "es_dirigido": true},
+    {"desde": "B", "hasta": "Q", "distancia": 1, "es_dirigido": true}
+  ],
+  "es_dirigido": false,
+  "version": 2
+}`)
+
+	grafo, problemas, err := repo.CargarConValidacion("red.json", ValidacionTolerante)
+	if err != nil {
+		t.Fatalf("El modo tolerante debe cargar: %v", err)
+	}
+	if grafo.NumeroCuevas() != 2 || grafo.NumeroAristas() != 2 {
+		t.Errorf("Se esperaban 2 cuevas y 2 aristas: %v", grafo)
+	}
+
+	esperados := [][]string{
+		{"red.json:$.cuevas[0].color", "advertencia", "campo desconocido"},
+		{"$.cuevas[1].x", "fuera de rango"},
+		{"$.aristas[1].distancia", "error"},
+		{"$.aristas[2]", "error", "'Q'"},
+		{"$.version", "advertencia"},
+	}
+	for _, esperado := range esperados {
+		if !contieneProblema(problemas, esperado...) {
+			t.Errorf("Falta el problema %v en %v", esperado, problemas)
+		}
+	}
+	if len(problemas) != len(esperados) {
+		t.Errorf("Se esperaban %d problemas: %v", len(esperados), problemas)
+	}
+
+	if _, err := repo.Cargar("red.json"); err == nil {
+		t.Error("Cargar debe fallar ante errores de validación")
+	}
+}
+
+// TestValidacionXML verifica que los problemas XML indican la línea
+func TestValidacionXML(t *testing.T) {
+	dir := t.TempDir()
+	repo := NuevoRepositorio(dir)
+	escribirArchivoPrueba(t, dir, "red.xml", `<?xml version="1.0" encoding="UTF-8"?>
+<grafo>
+  <cuevas>
+    <cueva><id>A</id><nombre>A</nombre><x>0</x><y>0</y></cueva>
+  </cuevas>
+  <aristas>
+    <arista><desde>A</desde><hasta>B</hasta><distancia>2</distancia><peso>9</peso></arista>
+  </aristas>
+  <es_dirigido>true</es_dirigido>
+</grafo>`)
+
+	_, problemas, err := repo.CargarConValidacion("red.xml", ValidacionTolerante)
+	if err != nil {
+		t.Fatalf("El modo tolerante debe cargar: %v", err)
+	}
+	if !contieneProblema(problemas, "línea 7 (peso)", "campo desconocido") ||
+		!contieneProblema(problemas, "línea 7", "error", "'B'") {
+		t.Errorf("Problemas inesperados: %v", problemas)
+	}
+}
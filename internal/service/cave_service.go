@@ -96,6 +96,7 @@ type DetalleCueva struct {
 	X             float64        `json:"x"`
 	Y             float64        `json:"y"`
 	Recursos      map[string]int `json:"recursos"`
+	Demanda       map[string]int `json:"demanda"`
 	NumConexiones int            `json:"num_conexiones"`
 	Vecinos       []string       `json:"vecinos"`
 }
@@ -144,13 +145,17 @@ func (sc *ServicioCueva) ObtenerDetalleCueva(id string) (*DetalleCueva, error) {
 		X:        cueva.X,
 		Y:        cueva.Y,
 		Recursos: make(map[string]int),
+		Demanda:  make(map[string]int),
 		Vecinos:  sc.ObtenerVecinos(id),
 	}
 
-	// Copiar recursos
+	// Copiar recursos y demanda
 	for recurso, cantidad := range cueva.Recursos {
 		detalle.Recursos[recurso] = cantidad
 	}
+	for recurso, cantidad := range cueva.Demanda {
+		detalle.Demanda[recurso] = cantidad
+	}
 
 	detalle.NumConexiones = len(detalle.Vecinos)
 
@@ -172,6 +177,17 @@ func (sc *ServicioCueva) AgregarRecurso(idCueva, recurso string, cantidad int) e
 	return nil
 }
 
+// EstablecerDemanda fija el stock deseado de un recurso; 0 elimina la demanda
+func (sc *ServicioCueva) EstablecerDemanda(idCueva, recurso string, cantidad int) error {
+	cueva, existe := sc.grafo.ObtenerCueva(idCueva)
+	if !existe {
+		return fmt.Errorf("cueva no encontrada")
+	}
+
+	cueva.EstablecerDemanda(recurso, cantidad)
+	return nil
+}
+
 // RemoverRecurso remueve recursos de una cueva
 func (sc *ServicioCueva) RemoverRecurso(idCueva, recurso string, cantidad int) error {
 	cueva, existe := sc.grafo.ObtenerCueva(idCueva)
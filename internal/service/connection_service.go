@@ -79,6 +79,8 @@ func (sc *ServicioConexion) convertirANoDirigido() error {
 		if !aristasExistentes[claveInversa] {
 			aristaInversa := domain.NuevaArista(arista.Hasta, arista.Desde, arista.Distancia, false)
 			aristaInversa.EsObstruido = arista.EsObstruido
+			aristaInversa.Capacidad = arista.Capacidad
+			aristaInversa.EsAngosto = arista.EsAngosto
 			nuevasAristas = append(nuevasAristas, aristaInversa)
 		}
 	}
@@ -89,6 +91,7 @@ func (sc *ServicioConexion) convertirANoDirigido() error {
 	}
 
 	sc.grafo.Aristas = nuevasAristas
+	sc.grafo.MarcarModificado()
 	return nil
 }
 
@@ -98,6 +101,7 @@ func (sc *ServicioConexion) convertirADirigido() error {
 	for _, arista := range sc.grafo.Aristas {
 		arista.EsDirigido = true
 	}
+	sc.grafo.MarcarModificado()
 	return nil
 }
 
@@ -129,6 +133,49 @@ func (sc *ServicioConexion) ObstruirConexion(solicitud *ObstruirConexion) error
 		return fmt.Errorf("conexión desde %s hasta %s no existe", solicitud.DesdeCuevaID, solicitud.HastaCuevaID)
 	}
 
+	sc.grafo.MarcarModificado()
+	return nil
+}
+
+// EstablecerCapacidad define la capacidad (camiones por hora) de una conexión;
+// 0 la deja sin definir. En grafos no dirigidos también se aplica a la inversa.
+func (sc *ServicioConexion) EstablecerCapacidad(desde, hasta string, capacidad float64) error {
+	if capacidad < 0 {
+		return fmt.Errorf("la capacidad no puede ser negativa")
+	}
+
+	aristasModificadas := 0
+	for _, arista := range sc.grafo.Aristas {
+		if (arista.Desde == desde && arista.Hasta == hasta) ||
+			(!sc.grafo.EsDirigido && arista.Desde == hasta && arista.Hasta == desde) {
+			arista.Capacidad = capacidad
+			aristasModificadas++
+		}
+	}
+
+	if aristasModificadas == 0 {
+		return fmt.Errorf("conexión desde %s hasta %s no existe", desde, hasta)
+	}
+	sc.grafo.MarcarModificado()
+	return nil
+}
+
+// EstablecerAngosto marca una conexión como de un solo carril: los camiones la
+// cruzan de a uno. En grafos no dirigidos también se aplica a la inversa.
+func (sc *ServicioConexion) EstablecerAngosto(desde, hasta string, angosto bool) error {
+	aristasModificadas := 0
+	for _, arista := range sc.grafo.Aristas {
+		if (arista.Desde == desde && arista.Hasta == hasta) ||
+			(!sc.grafo.EsDirigido && arista.Desde == hasta && arista.Hasta == desde) {
+			arista.EsAngosto = angosto
+			aristasModificadas++
+		}
+	}
+
+	if aristasModificadas == 0 {
+		return fmt.Errorf("conexión desde %s hasta %s no existe", desde, hasta)
+	}
+	sc.grafo.MarcarModificado()
 	return nil
 }
 
@@ -239,23 +286,9 @@ func (sc *ServicioConexion) InvertirDireccionConexion(solicitud *CambiarSentidoR
 		return fmt.Errorf("cueva %s no existe", solicitud.HastaCuevaID)
 	}
 
-	// Encontrar la arista
-	var aristaEncontrada *domain.Arista
-	for _, arista := range sc.grafo.Aristas {
-		if arista.Desde == solicitud.DesdeCuevaID && arista.Hasta == solicitud.HastaCuevaID {
-			aristaEncontrada = arista
-			break
-		}
-	}
-
-	if aristaEncontrada == nil {
-		return fmt.Errorf("conexión desde %s hasta %s no existe", solicitud.DesdeCuevaID, solicitud.HastaCuevaID)
-	}
-
 	// Cambiar el sentido de la arista
-	aristaEncontrada.Desde, aristaEncontrada.Hasta = aristaEncontrada.Hasta, aristaEncontrada.Desde
-
-	return nil
+	_, err := sc.grafo.InvertirArista(solicitud.DesdeCuevaID, solicitud.HastaCuevaID)
+	return err
 }
 
 // Listar todas las conexiones en el grafo
@@ -322,6 +355,7 @@ func (sc *ServicioConexion) EliminarConexion(desdeCuevaID, hastaCuevaID string)
 	}
 
 	sc.grafo.Aristas = aristasActualizadas
+	sc.grafo.MarcarModificado()
 	return nil
 }
 
@@ -368,18 +402,8 @@ func (sc *ServicioConexion) CambiarSentidoRuta(solicitud *CambiarSentidoRuta) er
 	}
 
 	// Buscar la arista original
-	var aristaEncontrada *domain.Arista
-	var indiceArista int = -1
-
-	for i, arista := range sc.grafo.Aristas {
-		if arista.Desde == solicitud.DesdeCuevaID && arista.Hasta == solicitud.HastaCuevaID {
-			aristaEncontrada = arista
-			indiceArista = i
-			break
-		}
-	}
-
-	if aristaEncontrada == nil {
+	aristaEncontrada, existe := sc.grafo.ObtenerConexion(solicitud.DesdeCuevaID, solicitud.HastaCuevaID)
+	if !existe {
 		return fmt.Errorf("conexión desde %s hasta %s no existe", solicitud.DesdeCuevaID, solicitud.HastaCuevaID)
 	}
 
@@ -388,19 +412,9 @@ func (sc *ServicioConexion) CambiarSentidoRuta(solicitud *CambiarSentidoRuta) er
 		return fmt.Errorf("no se puede cambiar el sentido de una conexión no dirigida")
 	}
 
-	// Crear la nueva arista con dirección invertida
-	nuevaArista := domain.NuevaArista(
-		aristaEncontrada.Hasta,
-		aristaEncontrada.Desde,
-		aristaEncontrada.Distancia,
-		aristaEncontrada.EsDirigido,
-	)
-	nuevaArista.EsObstruido = aristaEncontrada.EsObstruido
-
-	// Reemplazar la arista original con la nueva arista invertida
-	sc.grafo.Aristas[indiceArista] = nuevaArista
-
-	return nil
+	// Reemplazar la arista original con una invertida
+	_, err := sc.grafo.InvertirArista(solicitud.DesdeCuevaID, solicitud.HastaCuevaID)
+	return err
 }
 
 // Cambiar el sentido de múltiples rutas en una sola operación
@@ -423,27 +437,22 @@ func (sc *ServicioConexion) InvertirRutasDesdeCueva(cuevaID string) error {
 		return fmt.Errorf("cueva %s no existe", cuevaID)
 	}
 
-	rutasInvertidas := 0
-
 	// Buscar todas las aristas que salen de la cueva especificada
-	for i, arista := range sc.grafo.Aristas {
+	var invertir []*domain.Arista
+	for _, arista := range sc.grafo.AristasIncidentes(cuevaID) {
 		if arista.Desde == cuevaID && arista.EsDirigido {
-			// Crear nueva arista invertida
-			nuevaArista := domain.NuevaArista(
-				arista.Hasta,
-				arista.Desde,
-				arista.Distancia,
-				arista.EsDirigido,
-			)
-			nuevaArista.EsObstruido = arista.EsObstruido
-
-			// Reemplazar la arista original
-			sc.grafo.Aristas[i] = nuevaArista
-			rutasInvertidas++
+			invertir = append(invertir, arista)
 		}
 	}
 
-	if rutasInvertidas == 0 {
+	// Reemplazar cada arista original por una invertida
+	for _, arista := range invertir {
+		if _, err := sc.grafo.InvertirArista(arista.Desde, arista.Hasta); err != nil {
+			return err
+		}
+	}
+
+	if len(invertir) == 0 {
 		return fmt.Errorf("la cueva %s no tiene rutas dirigidas salientes para invertir", cuevaID)
 	}
 
@@ -457,27 +466,22 @@ func (sc *ServicioConexion) InvertirRutasHaciaCueva(cuevaID string) error {
 		return fmt.Errorf("cueva %s no existe", cuevaID)
 	}
 
-	rutasInvertidas := 0
-
 	// Buscar todas las aristas que llegan a la cueva especificada
-	for i, arista := range sc.grafo.Aristas {
+	var invertir []*domain.Arista
+	for _, arista := range sc.grafo.AristasIncidentes(cuevaID) {
 		if arista.Hasta == cuevaID && arista.EsDirigido {
-			// Crear nueva arista invertida
-			nuevaArista := domain.NuevaArista(
-				arista.Hasta,
-				arista.Desde,
-				arista.Distancia,
-				arista.EsDirigido,
-			)
-			nuevaArista.EsObstruido = arista.EsObstruido
-
-			// Reemplazar la arista original
-			sc.grafo.Aristas[i] = nuevaArista
-			rutasInvertidas++
+			invertir = append(invertir, arista)
+		}
+	}
+
+	// Reemplazar cada arista original por una invertida
+	for _, arista := range invertir {
+		if _, err := sc.grafo.InvertirArista(arista.Desde, arista.Hasta); err != nil {
+			return err
 		}
 	}
 
-	if rutasInvertidas == 0 {
+	if len(invertir) == 0 {
 		return fmt.Errorf("la cueva %s no tiene rutas dirigidas entrantes para invertir", cuevaID)
 	}
 
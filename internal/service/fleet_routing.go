@@ -0,0 +1,168 @@
+package service
+
+import (
+	"fmt"
+	"proyecto-grafos-go/internal/domain"
+	"proyecto-grafos-go/pkg/algorithms"
+	"sort"
+)
+
+// PlanFlota es el reparto de la demanda de las cuevas entre los camiones
+type PlanFlota struct {
+	Deposito      string                    `json:"deposito"`
+	Asignaciones  map[string][]string       `json:"asignaciones"` // Camión -> paradas, listo para SimularFlota
+	Rutas         map[string]*domain.Ruta   `json:"rutas"`        // Ida y vuelta al depósito de cada camión
+	Cargas        map[string]map[string]int `json:"cargas"`       // Camión -> recurso -> cantidad cargada
+	Demandas      map[string]int            `json:"demandas"`     // Déficit total de cada cueva
+	CostoTotal    float64                   `json:"costo_total"`
+	SinAsignar    []string                  `json:"sin_asignar"`
+	Inalcanzables []string                  `json:"inalcanzables"`
+	Ociosos       []string                  `json:"ociosos"` // Camiones que no hace falta despachar
+}
+
+// PlanificarFlotaCVRP reparte las cuevas con déficit entre los camiones según su
+// capacidad máxima y ordena las paradas de cada uno. Cada camión despachado se
+// reinicia en el depósito con la ruta asignada y cargado con el déficit de sus
+// paradas. Sin camionIDs se usan todos los camiones registrados.
+func (ts *TruckService) PlanificarFlotaCVRP(grafo *domain.Grafo, deposito string, camionIDs []string) (*PlanFlota, error) {
+	if grafo == nil {
+		return nil, fmt.Errorf("grafo no puede ser nil")
+	}
+	ids := append([]string(nil), camionIDs...)
+	if len(ids) == 0 {
+		for id := range ts.camiones {
+			ids = append(ids, id)
+		}
+	}
+	sort.Strings(ids)
+	camionIDs = ids
+
+	vehiculos := make([]algorithms.VehiculoCVRP, 0, len(camionIDs))
+	for _, id := range camionIDs {
+		camion, existe := ts.camiones[id]
+		if !existe {
+			return nil, fmt.Errorf("camión '%s' no encontrado", id)
+		}
+		vehiculos = append(vehiculos, algorithms.VehiculoCVRP{ID: id, Capacidad: camion.CapacidadMaxima})
+	}
+
+	demandas := make(map[string]int)
+	for id, cueva := range grafo.Cuevas {
+		for recurso := range cueva.Demanda {
+			if deficit := cueva.Deficit(recurso); deficit > 0 && id != deposito {
+				demandas[id] += deficit
+			}
+		}
+	}
+
+	cvrp, err := algorithms.PlanificarCVRP(grafo, deposito, demandas, vehiculos)
+	if err != nil {
+		return nil, err
+	}
+	plan := &PlanFlota{
+		Deposito:      deposito,
+		Asignaciones:  make(map[string][]string),
+		Rutas:         make(map[string]*domain.Ruta),
+		Cargas:        make(map[string]map[string]int),
+		Demandas:      demandas,
+		CostoTotal:    cvrp.CostoTotal,
+		SinAsignar:    cvrp.SinAsignar,
+		Inalcanzables: cvrp.Inalcanzables,
+		Ociosos:       make([]string, 0),
+	}
+	for _, rutaCVRP := range cvrp.Rutas {
+		id := rutaCVRP.VehiculoID
+		ruta := domain.NuevaRuta(fmt.Sprintf("ruta_%s_cvrp", id))
+		ruta.AgregarCueva(deposito, 0)
+		carga := make(map[string]int)
+		for _, parada := range rutaCVRP.Paradas {
+			if err := ts.agregarTramo(grafo, ruta, parada); err != nil {
+				return nil, err
+			}
+			cueva := grafo.Cuevas[parada]
+			for recurso := range cueva.Demanda {
+				if deficit := cueva.Deficit(recurso); deficit > 0 {
+					carga[recurso] += deficit
+				}
+			}
+		}
+		if err := ts.agregarTramo(grafo, ruta, deposito); err != nil {
+			return nil, err
+		}
+
+		if err := ts.ReiniciarCamion(id, deposito); err != nil {
+			return nil, err
+		}
+		if err := ts.CargarInsumos(id, carga); err != nil {
+			return nil, err
+		}
+		ts.camiones[id].RutaAsignada = ruta
+		plan.Asignaciones[id] = rutaCVRP.Paradas
+		plan.Rutas[id] = ruta
+		plan.Cargas[id] = carga
+	}
+	for _, id := range camionIDs {
+		if _, despachado := plan.Asignaciones[id]; !despachado {
+			plan.Ociosos = append(plan.Ociosos, id)
+		}
+	}
+	return plan, nil
+}
+
+// agregarTramo extiende la ruta hasta destino con cada cueva del camino más corto
+func (ts *TruckService) agregarTramo(grafo *domain.Grafo, ruta *domain.Ruta, destino string) error {
+	desde := ruta.UltimaCueva()
+	camino, err := ts.traversalService.BuscarCamino(grafo, desde, destino, ts.algoritmoCamino)
+	if err != nil {
+		return fmt.Errorf("no hay camino de %s a %s: %w", desde, destino, err)
+	}
+	for i := 1; i < len(camino.Ruta); i++ {
+		ruta.AgregarCueva(camino.Ruta[i], ts.distanciaTrayecto(grafo, camino.Ruta[i-1], camino.Ruta[i]))
+	}
+	return nil
+}
+
+// GenerarReportePlanFlota genera el reporte de un plan de rutas de la flota
+func (ts *TruckService) GenerarReportePlanFlota(plan *PlanFlota) string {
+	reporte := "=== PLAN DE RUTAS DE LA FLOTA (CVRP) ===\n"
+	reporte += fmt.Sprintf("Depósito: %s\n", plan.Deposito)
+	reporte += fmt.Sprintf("Camiones despachados: %d\n", len(plan.Asignaciones))
+	reporte += fmt.Sprintf("Distancia total: %.2f km\n", plan.CostoTotal)
+
+	ids := make([]string, 0, len(plan.Asignaciones))
+	for id := range plan.Asignaciones {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	reporte += "\n--- RUTAS ---\n"
+	for _, id := range ids {
+		ruta := plan.Rutas[id]
+		total := 0
+		for _, cantidad := range plan.Cargas[id] {
+			total += cantidad
+		}
+		capacidad := 0
+		if camion, existe := ts.camiones[id]; existe {
+			capacidad = camion.CapacidadMaxima
+		}
+		reporte += fmt.Sprintf("%s: %v (%.2f km, carga %d/%d)\n", id, ruta.CuevaIDs, ruta.DistanciaTotal, total, capacidad)
+		for _, parada := range plan.Asignaciones[id] {
+			reporte += fmt.Sprintf("  - %s: %d\n", parada, plan.Demandas[parada])
+		}
+	}
+	if len(plan.Ociosos) > 0 {
+		reporte += fmt.Sprintf("Sin despachar: %v\n", plan.Ociosos)
+	}
+
+	if len(plan.SinAsignar) > 0 || len(plan.Inalcanzables) > 0 {
+		reporte += "\n--- CUEVAS SIN ATENDER ---\n"
+		for _, id := range plan.SinAsignar {
+			reporte += fmt.Sprintf("Cueva %s: su demanda de %d no entra en ningún camión\n", id, plan.Demandas[id])
+		}
+		for _, id := range plan.Inalcanzables {
+			reporte += fmt.Sprintf("Cueva %s: sin ida o vuelta desde el depósito\n", id)
+		}
+	}
+	return reporte
+}
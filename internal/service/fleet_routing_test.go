@@ -0,0 +1,69 @@
+package service
+
+import (
+	"reflect"
+	"sort"
+	"testing"
+)
+
+func TestPlanificarFlotaCVRP(t *testing.T) {
+	// Faltan 50 de agua en B, 45 en C y 40 de medicina en D para dos camiones de 100
+	grafo := crearRedDemanda()
+	grafo.Cuevas["ALM"].AgregarRecurso("agua", 200)
+	grafo.Cuevas["ALM"].AgregarRecurso("medicina", 100)
+	ts := nuevoTruckServicePrueba(grafo)
+	for _, id := range []string{"T1", "T2", "T3"} {
+		if _, err := ts.CrearCamion(id, CamionPequeno, "ALM"); err != nil {
+			t.Fatalf("Error al crear camión: %v", err)
+		}
+	}
+
+	camionIDs := []string{"T2", "T1"}
+	plan, err := ts.PlanificarFlotaCVRP(grafo, "ALM", camionIDs)
+	if err != nil {
+		t.Fatalf("Error al planificar: %v", err)
+	}
+	if !reflect.DeepEqual(camionIDs, []string{"T2", "T1"}) {
+		t.Errorf("Se reordenaron los camiones del llamador: %v", camionIDs)
+	}
+
+	// Lo más corto es B por un lado (2 km) y C, D por el otro (6 km)
+	if plan.CostoTotal != 8 || len(plan.Asignaciones) != 2 || len(plan.Ociosos) != 0 {
+		t.Fatalf("Plan inesperado: %+v", plan)
+	}
+	paradas := make([]string, 0)
+	for id, asignadas := range plan.Asignaciones {
+		paradas = append(paradas, asignadas...)
+		camion, _ := ts.ObtenerCamion(id)
+		if camion.RutaAsignada != plan.Rutas[id] || !reflect.DeepEqual(camion.CargaActual, plan.Cargas[id]) {
+			t.Errorf("%s no quedó con la ruta y la carga del plan", id)
+		}
+		if ruta := camion.RutaAsignada.CuevaIDs; ruta[0] != "ALM" || ruta[len(ruta)-1] != "ALM" {
+			t.Errorf("La ruta de %s no sale y vuelve al depósito: %v", id, ruta)
+		}
+		// Cada tramo pasa por las cuevas intermedias del camino más corto
+		if asignadas[0] != "B" {
+			ruta := []string{"ALM", "B", "C", "D", "C", "B", "ALM"}
+			if !reflect.DeepEqual(plan.Rutas[id].CuevaIDs, ruta) || plan.Rutas[id].DistanciaTotal != 6 {
+				t.Errorf("Ruta de %s inesperada: %v (%.2f km)", id, plan.Rutas[id].CuevaIDs, plan.Rutas[id].DistanciaTotal)
+			}
+		}
+	}
+	sort.Strings(paradas)
+	if !reflect.DeepEqual(paradas, []string{"B", "C", "D"}) {
+		t.Errorf("Paradas inesperadas: %v", paradas)
+	}
+
+	// El plan se simula tal cual y cubre toda la demanda
+	resultado, err := ts.SimularFlota(grafo, "ALM", plan.Asignaciones)
+	if err != nil {
+		t.Fatalf("Error en simulación de flota: %v", err)
+	}
+	if len(resultado.DemandaInsatisfecha) != 0 || len(resultado.FaltanteDeposito) != 0 {
+		t.Errorf("Quedó demanda sin cubrir: %+v, faltante %v", resultado.DemandaInsatisfecha, resultado.FaltanteDeposito)
+	}
+
+	if _, err := ts.PlanificarFlotaCVRP(grafo, "ALM", []string{"T9"}); err == nil {
+		t.Error("Se esperaba un error por camión inexistente")
+	}
+}
@@ -0,0 +1,507 @@
+package service
+
+import (
+	"fmt"
+	"proyecto-grafos-go/internal/domain"
+	"sort"
+	"time"
+)
+
+// TiempoCargaEnDeposito es lo que tarda un camión en cargar en el depósito
+const TiempoCargaEnDeposito = 10 * time.Minute
+
+// ResultadoFlota resume una simulación de varios camiones sobre la misma red
+type ResultadoFlota struct {
+	Deposito            string                  `json:"deposito"`
+	Camiones            []*ResultadoCamionFlota `json:"camiones"` // Ordenados por ID
+	Makespan            time.Duration           `json:"makespan"` // Hasta que termina el último camión
+	EsperasPorTunel     []EsperaTunel           `json:"esperas_por_tunel"`
+	StockDeposito       map[string]int          `json:"stock_deposito"`    // Lo que queda en el depósito
+	FaltanteDeposito    map[string]int          `json:"faltante_deposito"` // Lo pedido que el depósito no tenía
+	DemandaInsatisfecha []DemandaInsatisfecha   `json:"demanda_insatisfecha"`
+	Eventos             []EventoSimulacion      `json:"eventos"`
+	Errores             []string                `json:"errores"`
+}
+
+// ResultadoCamionFlota es el desempeño de un camión dentro de la flota
+type ResultadoCamionFlota struct {
+	CamionID         string                    `json:"camion_id"`
+	Paradas          []string                  `json:"paradas"`
+	Ruta             []string                  `json:"ruta"` // Todas las cuevas recorridas, intermedias incluidas
+	Entregas         map[string]map[string]int `json:"entregas"`
+	Distancia        float64                   `json:"distancia"`
+	TiempoFin        time.Duration             `json:"tiempo_fin"`
+	TiempoMovimiento time.Duration             `json:"tiempo_movimiento"`
+	TiempoCarga      time.Duration             `json:"tiempo_carga"` // Carga en el depósito y descargas en las paradas
+	TiempoEspera     time.Duration             `json:"tiempo_espera"`
+	Utilizacion      float64                   `json:"utilizacion"`       // % del makespan en movimiento, cargando o descargando
+	Viajes           int                       `json:"viajes"`            // Salidas cargado desde el depósito
+	DistanciaRegreso float64                   `json:"distancia_regreso"` // Parte de la distancia hecha volviendo al depósito
+}
+
+// EsperaTunel es la congestión de un túnel angosto o con capacidad definida
+type EsperaTunel struct {
+	Desde    string        `json:"desde"`
+	Hasta    string        `json:"hasta"`
+	Cruces   int           `json:"cruces"`
+	Esperas  int           `json:"esperas"` // Cruces que tuvieron que esperar
+	Espera   time.Duration `json:"espera"`  // Espera total
+	Promedio time.Duration `json:"promedio"`
+}
+
+// estadoTunel guarda desde cuándo puede entrar el próximo camión a un túnel regulado
+type estadoTunel struct {
+	informe    EsperaTunel
+	libreDesde time.Duration
+}
+
+// agenteFlota sigue el avance de un camión por sus paradas
+type agenteFlota struct {
+	camion     *Camion
+	paradas    []string
+	siguiente  int      // Índice de la próxima parada
+	tramo      []string // Camino hacia la próxima parada
+	paso       int      // Posición del camión en el tramo
+	ruta       *domain.Ruta
+	original   map[string]int // Carga pedida al salir por primera vez
+	regresando bool           // El tramo actual lleva al depósito
+	recargar   bool           // Al llegar al depósito recarga para las paradas que faltan
+	informe    *ResultadoCamionFlota
+}
+
+// simulacionFlota es el estado compartido por todos los camiones
+type simulacionFlota struct {
+	ts        *TruckService
+	grafo     *domain.Grafo
+	motor     *MotorSimulacion
+	deposito  *domain.Cueva
+	tuneles   map[[2]string]*estadoTunel
+	resultado *ResultadoFlota
+}
+
+// SimularFlota despacha a la vez los camiones de asignaciones (camión -> paradas)
+// desde el depósito. Cada camión carga lo que tiene pedido del stock del
+// depósito, que son los recursos de su cueva, y va de parada en parada por el
+// camino más corto. Los túneles con capacidad definida admiten como mucho
+// Capacidad entradas por hora, espaciadas en partes iguales; los angostos
+// admiten además un solo camión adentro. El resto espera.
+// Los camiones vuelven al depósito al terminar sus paradas, al quedar vacíos
+// (recargan y siguen) o cuando la jornada no alcanza para la próxima parada.
+func (ts *TruckService) SimularFlota(grafo *domain.Grafo, deposito string, asignaciones map[string][]string) (*ResultadoFlota, error) {
+	if grafo == nil {
+		return nil, fmt.Errorf("grafo no puede ser nil")
+	}
+	cuevaDeposito, existe := grafo.ObtenerCueva(deposito)
+	if !existe {
+		return nil, fmt.Errorf("el depósito '%s' no existe en el grafo", deposito)
+	}
+	if len(asignaciones) == 0 {
+		return nil, fmt.Errorf("no hay camiones asignados")
+	}
+
+	ids := make([]string, 0, len(asignaciones))
+	for id, paradas := range asignaciones {
+		if _, existe := ts.camiones[id]; !existe {
+			return nil, fmt.Errorf("camión '%s' no encontrado", id)
+		}
+		for _, parada := range paradas {
+			if _, existe := grafo.ObtenerCueva(parada); !existe {
+				return nil, fmt.Errorf("la parada '%s' del camión '%s' no existe en el grafo", parada, id)
+			}
+		}
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	sim := &simulacionFlota{
+		ts:       ts,
+		grafo:    grafo,
+		motor:    NuevoMotorSimulacion(),
+		deposito: cuevaDeposito,
+		tuneles:  make(map[[2]string]*estadoTunel),
+		resultado: &ResultadoFlota{
+			Deposito:         deposito,
+			Camiones:         make([]*ResultadoCamionFlota, 0, len(ids)),
+			EsperasPorTunel:  make([]EsperaTunel, 0),
+			FaltanteDeposito: make(map[string]int),
+			Errores:          make([]string, 0),
+		},
+	}
+
+	agentes := make([]*agenteFlota, 0, len(ids))
+	for _, id := range ids {
+		camion := ts.camiones[id]
+		camion.CuevaActual = deposito
+		camion.DistanciaRecorrida = 0.0
+		camion.Estado = EnAlmacen
+		camion.TiempoInicio = EpocaSimulacion
+
+		agente := &agenteFlota{
+			camion:   camion,
+			paradas:  asignaciones[id],
+			ruta:     domain.NuevaRuta(fmt.Sprintf("ruta_%s_flota", id)),
+			original: make(map[string]int),
+			informe: &ResultadoCamionFlota{
+				CamionID: id,
+				Paradas:  asignaciones[id],
+				Entregas: make(map[string]map[string]int),
+			},
+		}
+		agente.ruta.AgregarCueva(deposito, 0)
+		for recurso, cantidad := range camion.CargaActual {
+			agente.original[recurso] = cantidad
+		}
+		agentes = append(agentes, agente)
+		sim.cargar(agente)
+	}
+
+	if err := sim.motor.Ejecutar(ts.reproduccion); err != nil {
+		return nil, err
+	}
+
+	// Resumen de la flota
+	resultado := sim.resultado
+	for _, agente := range agentes {
+		if agente.informe.TiempoFin > resultado.Makespan {
+			resultado.Makespan = agente.informe.TiempoFin
+		}
+	}
+	visitadas := make([]string, 0)
+	for _, agente := range agentes {
+		informe := agente.informe
+		informe.Ruta = agente.ruta.CuevaIDs
+		informe.Distancia = agente.camion.DistanciaRecorrida
+		if resultado.Makespan > 0 {
+			informe.Utilizacion = float64(informe.TiempoMovimiento+informe.TiempoCarga) / float64(resultado.Makespan) * 100
+		}
+		agente.camion.RutaAsignada = agente.ruta
+		resultado.Camiones = append(resultado.Camiones, informe)
+		visitadas = append(visitadas, informe.Ruta...)
+	}
+	for _, tunel := range sim.tuneles {
+		if tunel.informe.Esperas > 0 {
+			tunel.informe.Promedio = tunel.informe.Espera / time.Duration(tunel.informe.Esperas)
+		}
+		resultado.EsperasPorTunel = append(resultado.EsperasPorTunel, tunel.informe)
+	}
+	sort.Slice(resultado.EsperasPorTunel, func(i, j int) bool {
+		a, b := resultado.EsperasPorTunel[i], resultado.EsperasPorTunel[j]
+		if a.Espera != b.Espera {
+			return a.Espera > b.Espera
+		}
+		return a.Desde < b.Desde || (a.Desde == b.Desde && a.Hasta < b.Hasta)
+	})
+	resultado.StockDeposito = make(map[string]int, len(cuevaDeposito.Recursos))
+	for recurso, cantidad := range cuevaDeposito.Recursos {
+		resultado.StockDeposito[recurso] = cantidad
+	}
+	resultado.DemandaInsatisfecha = demandaInsatisfecha(grafo, visitadas)
+	resultado.Eventos = sim.motor.Registro()
+	return resultado, nil
+}
+
+// cargar toma del stock del depósito lo que el camión tiene pedido
+func (s *simulacionFlota) cargar(agente *agenteFlota) {
+	evento := EventoSimulacion{Tipo: EventoCarga, CamionID: agente.camion.ID, CuevaID: s.deposito.ID}
+	s.motor.Programar(TiempoCargaEnDeposito, evento, func(evento *EventoSimulacion) {
+		evento.Carga, evento.Descripcion = cargarDesdeDeposito(s.deposito, agente.camion, s.resultado.FaltanteDeposito)
+		if len(evento.Carga) > 0 {
+			agente.informe.Viajes++
+		}
+		agente.informe.TiempoCarga += TiempoCargaEnDeposito
+		s.haciaSiguienteParada(agente)
+	})
+}
+
+// haciaSiguienteParada busca el camino a la próxima parada o vuelve al depósito
+func (s *simulacionFlota) haciaSiguienteParada(agente *agenteFlota) {
+	for agente.siguiente < len(agente.paradas) {
+		destino := agente.paradas[agente.siguiente]
+		if cargaTotal(agente.camion) == 0 {
+			s.regresar(agente, "camión vacío", true)
+			return
+		}
+		if destino == agente.camion.CuevaActual {
+			s.llegarAParada(agente)
+			return
+		}
+		camino, err := s.ts.traversalService.BuscarCamino(s.grafo, agente.camion.CuevaActual, destino, s.ts.algoritmoCamino)
+		if err == nil && len(camino.Ruta) > 1 {
+			if !s.alcanzaJornada(agente, destino, 0) {
+				s.regresar(agente, "fin de la jornada", false)
+				return
+			}
+			agente.tramo = camino.Ruta
+			agente.paso = 0
+			s.cruzarTunel(agente)
+			return
+		}
+		s.resultado.Errores = append(s.resultado.Errores,
+			fmt.Sprintf("El camión %s no tiene camino de %s a %s", agente.camion.ID, agente.camion.CuevaActual, destino))
+		agente.siguiente++
+	}
+	s.regresar(agente, "fin del recorrido", false)
+}
+
+// alcanzaJornada indica si el camión puede ir hasta destino tras la demora,
+// descargar y volver al depósito antes del fin de la jornada, sin contar las
+// esperas en los túneles
+func (s *simulacionFlota) alcanzaJornada(agente *agenteFlota, destino string, demora time.Duration) bool {
+	fin := s.motor.Ahora() + demora + TiempoDescargaPorCueva
+	for _, tramo := range [][2]string{{agente.camion.CuevaActual, destino}, {destino, s.deposito.ID}} {
+		if tramo[0] == tramo[1] {
+			continue
+		}
+		if camino, err := s.ts.traversalService.BuscarCamino(s.grafo, tramo[0], tramo[1], s.ts.algoritmoCamino); err == nil {
+			fin += duracionViaje(camino.Costo, agente.camion.VelocidadPromedio)
+		}
+	}
+	return fin <= s.ts.jornada
+}
+
+// regresar lleva al camión al depósito por el camino más corto, túnel por túnel
+func (s *simulacionFlota) regresar(agente *agenteFlota, motivo string, recargar bool) {
+	if agente.camion.CuevaActual == s.deposito.ID {
+		s.enDeposito(agente, recargar)
+		return
+	}
+	camino, err := s.ts.caminoRegreso(s.grafo, agente.camion.CuevaActual, s.deposito.ID)
+	if err != nil {
+		s.resultado.Errores = append(s.resultado.Errores, fmt.Sprintf("Camión %s: %s", agente.camion.ID, err.Error()))
+		s.terminar(agente)
+		return
+	}
+
+	evento := EventoSimulacion{Tipo: EventoRegreso, CamionID: agente.camion.ID, CuevaID: agente.camion.CuevaActual}
+	s.motor.Programar(0, evento, func(evento *EventoSimulacion) {
+		agente.camion.Estado = Regresando
+		evento.Descripcion = fmt.Sprintf("Hacia %s (%.2f km): %s", s.deposito.ID, camino.Costo, motivo)
+		agente.regresando = true
+		agente.recargar = recargar
+		agente.tramo = camino.Ruta
+		agente.paso = 0
+		s.cruzarTunel(agente)
+	})
+}
+
+// enDeposito recarga para las paradas que faltan o termina el recorrido
+func (s *simulacionFlota) enDeposito(agente *agenteFlota, recargar bool) {
+	agente.camion.Estado = EnAlmacen
+	if recargar && agente.siguiente < len(agente.paradas) &&
+		s.alcanzaJornada(agente, agente.paradas[agente.siguiente], TiempoCargaEnDeposito) {
+		carga := s.ts.recargarEnDeposito(s.grafo, s.deposito, agente.camion, agente.paradas[agente.siguiente:], agente.original)
+		if len(carga) > 0 {
+			evento := EventoSimulacion{Tipo: EventoCarga, CamionID: agente.camion.ID, CuevaID: s.deposito.ID, Carga: carga}
+			s.motor.Programar(TiempoCargaEnDeposito, evento, func(evento *EventoSimulacion) {
+				evento.Descripcion = "Recarga para las paradas que faltan"
+				agente.informe.Viajes++
+				agente.informe.TiempoCarga += TiempoCargaEnDeposito
+				s.haciaSiguienteParada(agente)
+			})
+			return
+		}
+	}
+	s.terminar(agente)
+}
+
+// terminar cierra el recorrido del camión donde esté
+func (s *simulacionFlota) terminar(agente *agenteFlota) {
+	agente.camion.Estado = Completado
+	agente.camion.TiempoFin = s.motor.Instante()
+	agente.informe.TiempoFin = s.motor.Ahora()
+}
+
+// cruzarTunel recorre el siguiente túnel del tramo, esperando si está ocupado
+func (s *simulacionFlota) cruzarTunel(agente *agenteFlota) {
+	if agente.paso+1 >= len(agente.tramo) {
+		if agente.regresando {
+			agente.regresando = false
+			s.enDeposito(agente, agente.recargar)
+			return
+		}
+		s.llegarAParada(agente)
+		return
+	}
+	desde, hasta := agente.tramo[agente.paso], agente.tramo[agente.paso+1]
+	distancia := s.ts.obtenerDistanciaEntreAristas(s.grafo, desde, hasta)
+	viaje := duracionViaje(distancia, agente.camion.VelocidadPromedio)
+
+	// Los túneles regulados se reservan en el orden en que los piden los camiones
+	espera := time.Duration(0)
+	capacidad, angosto := s.reglasTunel(desde, hasta)
+	if tunel := s.tunelRegulado(desde, hasta, capacidad, angosto); tunel != nil {
+		ahora := s.motor.Ahora()
+		entrada := max(ahora, tunel.libreDesde)
+		espera = entrada - ahora
+		tunel.libreDesde = entrada
+		if capacidad > 0 {
+			tunel.libreDesde = entrada + time.Duration(float64(time.Hour)/capacidad)
+		}
+		if angosto {
+			tunel.libreDesde = max(tunel.libreDesde, entrada+viaje)
+		}
+		tunel.informe.Cruces++
+		if espera > 0 {
+			tunel.informe.Esperas++
+			tunel.informe.Espera += espera
+		}
+		agente.informe.TiempoEspera += espera
+	}
+
+	salida := EventoSimulacion{Tipo: EventoSalida, CamionID: agente.camion.ID, CuevaID: desde}
+	s.motor.Programar(espera, salida, func(evento *EventoSimulacion) {
+		if !agente.regresando {
+			agente.camion.Estado = EnTransito
+		}
+		evento.Descripcion = fmt.Sprintf("Túnel hacia %s (%.2f km)", hasta, distancia)
+		if espera > 0 {
+			evento.Descripcion += fmt.Sprintf(" tras esperar %s", FormatearReloj(espera))
+		}
+
+		llegada := EventoSimulacion{Tipo: EventoLlegada, CamionID: agente.camion.ID, CuevaID: hasta}
+		s.motor.Programar(viaje, llegada, func(evento *EventoSimulacion) {
+			agente.camion.CuevaActual = hasta
+			agente.camion.DistanciaRecorrida += distancia
+			agente.ruta.AgregarCueva(hasta, distancia)
+			agente.informe.TiempoMovimiento += viaje
+			if agente.regresando {
+				agente.informe.DistanciaRegreso += distancia
+			}
+			agente.paso++
+			if agente.paso+1 < len(agente.tramo) {
+				evento.Descripcion = "De paso"
+			}
+			s.cruzarTunel(agente)
+		})
+	})
+}
+
+// llegarAParada descarga en la parada actual lo que la regla de prioridad le
+// asigna entre las paradas que faltan, o su parte proporcional si nadie demanda
+// el recurso
+func (s *simulacionFlota) llegarAParada(agente *agenteFlota) {
+	agente.camion.Estado = Entregando
+	cuevaID := agente.paradas[agente.siguiente]
+	restantes := len(agente.paradas) - agente.siguiente
+
+	descarga := EventoSimulacion{Tipo: EventoDescarga, CamionID: agente.camion.ID, CuevaID: cuevaID}
+	s.motor.Programar(TiempoDescargaPorCueva, descarga, func(evento *EventoSimulacion) {
+		// El déficit se mide al descargar: otro camión pudo haberlo cubierto antes
+		cueva := s.grafo.Cuevas[cuevaID]
+		plan := s.ts.planificarEntregas(s.grafo, agente.paradas[agente.siguiente:], agente.camion.CargaActual)
+		entrega := make(map[string]int)
+		for recurso, disponible := range agente.camion.CargaActual {
+			cantidad := disponible / restantes
+			if plan.conDemanda[recurso] {
+				cantidad = min(plan.asignado[cuevaID][recurso], disponible)
+			}
+			if cantidad > 0 {
+				entrega[recurso] = cantidad
+				agente.camion.CargaActual[recurso] -= cantidad
+				cueva.AgregarRecurso(recurso, cueva.ObtenerRecurso(recurso)+cantidad)
+			}
+		}
+		evento.Carga = entrega
+		if anteriores, ok := agente.informe.Entregas[cuevaID]; ok {
+			for recurso, cantidad := range entrega {
+				anteriores[recurso] += cantidad
+			}
+		} else {
+			agente.informe.Entregas[cuevaID] = entrega
+		}
+		agente.informe.TiempoCarga += TiempoDescargaPorCueva
+		agente.siguiente++
+		s.haciaSiguienteParada(agente)
+	})
+}
+
+// tunelRegulado devuelve el estado del túnel si es angosto o tiene capacidad
+// definida; en un grafo no dirigido ambos sentidos comparten el túnel
+func (s *simulacionFlota) tunelRegulado(desde, hasta string, capacidad float64, angosto bool) *estadoTunel {
+	if capacidad <= 0 && !angosto {
+		return nil
+	}
+	clave := [2]string{desde, hasta}
+	if !s.grafo.EsDirigido && hasta < desde {
+		clave = [2]string{hasta, desde}
+	}
+	tunel, existe := s.tuneles[clave]
+	if !existe {
+		tunel = &estadoTunel{informe: EsperaTunel{Desde: clave[0], Hasta: clave[1]}}
+		s.tuneles[clave] = tunel
+	}
+	return tunel
+}
+
+// reglasTunel devuelve la capacidad del túnel desde -> hasta (0 si no está
+// definida) y si es angosto; en un grafo no dirigido basta que lo indique uno
+// de los sentidos
+func (s *simulacionFlota) reglasTunel(desde, hasta string) (float64, bool) {
+	capacidad, angosto := 0.0, false
+	sentidos := [][2]string{{desde, hasta}}
+	if !s.grafo.EsDirigido {
+		sentidos = append(sentidos, [2]string{hasta, desde})
+	}
+	for _, sentido := range sentidos {
+		if arista, existe := s.grafo.ObtenerConexion(sentido[0], sentido[1]); existe {
+			if capacidad <= 0 {
+				capacidad = arista.Capacidad
+			}
+			angosto = angosto || arista.EsAngosto
+		}
+	}
+	return capacidad, angosto
+}
+
+// GenerarReporteFlota genera un reporte de la simulación de la flota
+func (ts *TruckService) GenerarReporteFlota(resultado *ResultadoFlota) string {
+	reporte := "=== REPORTE DE SIMULACIÓN DE FLOTA ===\n"
+	reporte += fmt.Sprintf("Depósito: %s\n", resultado.Deposito)
+	reporte += fmt.Sprintf("Camiones: %d\n", len(resultado.Camiones))
+	reporte += fmt.Sprintf("Makespan: %s\n", FormatearReloj(resultado.Makespan))
+
+	reporte += "\n--- CAMIONES ---\n"
+	for _, camion := range resultado.Camiones {
+		reporte += fmt.Sprintf("%s: termina %s, %.2f km (%.2f de regreso), %d viajes, utilización %.1f%%, espera %s\n",
+			camion.CamionID, FormatearReloj(camion.TiempoFin), camion.Distancia, camion.DistanciaRegreso, camion.Viajes,
+			camion.Utilizacion, FormatearReloj(camion.TiempoEspera))
+		reporte += fmt.Sprintf("  Paradas: %v\n", camion.Paradas)
+		reporte += fmt.Sprintf("  Ruta: %v\n", camion.Ruta)
+	}
+
+	if len(resultado.EsperasPorTunel) > 0 {
+		reporte += "\n--- ESPERAS POR TÚNEL ---\n"
+		for _, tunel := range resultado.EsperasPorTunel {
+			reporte += fmt.Sprintf("%s - %s: %d cruces, %d esperas, total %s, promedio %s\n",
+				tunel.Desde, tunel.Hasta, tunel.Cruces, tunel.Esperas, FormatearReloj(tunel.Espera), FormatearReloj(tunel.Promedio))
+		}
+	}
+
+	reporte += "\n--- STOCK DEL DEPÓSITO ---\n"
+	for recurso, cantidad := range resultado.StockDeposito {
+		reporte += fmt.Sprintf("  - %s: %d\n", recurso, cantidad)
+	}
+	for recurso, cantidad := range resultado.FaltanteDeposito {
+		reporte += fmt.Sprintf("  Faltaron %d de %s para completar las cargas\n", cantidad, recurso)
+	}
+
+	if len(resultado.DemandaInsatisfecha) > 0 {
+		reporte += "\n--- DEMANDA INSATISFECHA ---\n"
+		for _, faltante := range resultado.DemandaInsatisfecha {
+			reporte += fmt.Sprintf("Cueva %s - %s: faltan %d\n", faltante.CuevaID, faltante.Recurso, faltante.Faltante)
+		}
+	}
+
+	if len(resultado.Errores) > 0 {
+		reporte += "\n--- ERRORES ---\n"
+		for _, error := range resultado.Errores {
+			reporte += fmt.Sprintf("- %s\n", error)
+		}
+	}
+
+	reporte += "\n--- REGISTRO DE EVENTOS ---\n"
+	for _, evento := range resultado.Eventos {
+		reporte += FormatearEvento(evento) + "\n"
+	}
+	return reporte
+}
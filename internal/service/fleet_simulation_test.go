@@ -0,0 +1,224 @@
+package service
+
+import (
+	"math"
+	"proyecto-grafos-go/internal/domain"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestSimularFlotaCapacidadTunel(t *testing.T) {
+	// ALM - N admite 2 camiones por hora y pasan ambos por él
+	grafo := domain.NuevoGrafo(false)
+	for _, id := range []string{"ALM", "N", "A", "B", "D"} {
+		grafo.AgregarCueva(domain.NuevaCueva(id, id))
+	}
+	grafo.AgregarConexion("ALM", "N", 10)
+	grafo.AgregarConexion("N", "A", 10)
+	grafo.AgregarConexion("N", "D", 10)
+	if err := NuevoServicioConexion(grafo).EstablecerCapacidad("N", "ALM", 2); err != nil {
+		t.Fatalf("Error al definir capacidad: %v", err)
+	}
+	grafo.Cuevas["ALM"].AgregarRecurso("agua", 50)
+	grafo.Cuevas["A"].EstablecerDemanda("agua", 30)
+	grafo.Cuevas["B"].EstablecerDemanda("agua", 5)
+
+	ts := nuevoTruckServicePrueba(grafo)
+	for _, id := range []string{"T1", "T2"} {
+		if _, err := ts.CrearCamion(id, CamionPequeno, "ALM"); err != nil {
+			t.Fatalf("Error al crear camión: %v", err)
+		}
+		if err := ts.CargarInsumos(id, map[string]int{"agua": 40}); err != nil {
+			t.Fatalf("Error al cargar: %v", err)
+		}
+	}
+
+	resultado, err := ts.SimularFlota(grafo, "ALM", map[string][]string{"T1": {"A"}, "T2": {"D"}})
+	if err != nil {
+		t.Fatalf("Error en simulación de flota: %v", err)
+	}
+
+	// T1 entra a 0:10 y el próximo puede entrar media hora después: T2 entra a
+	// 0:40. A la vuelta T1 lo toma a 1:10 y T2, que llega a 1:20, recién a 1:40
+	if resultado.Makespan != 110*time.Minute {
+		t.Errorf("Makespan %v, se esperaban 110m", resultado.Makespan)
+	}
+	t1, t2 := resultado.Camiones[0], resultado.Camiones[1]
+	if t1.TiempoFin != 80*time.Minute || t2.TiempoFin != 110*time.Minute || t2.TiempoEspera != 50*time.Minute {
+		t.Errorf("Tiempos inesperados: T1 %v, T2 %v con espera %v", t1.TiempoFin, t2.TiempoFin, t2.TiempoEspera)
+	}
+	if math.Abs(t1.Utilizacion-60.0/110*100) > 1e-9 || t1.Distancia != 40 || t1.DistanciaRegreso != 20 || t1.Viajes != 1 {
+		t.Errorf("T1: utilización %.2f, distancia %.2f (%.2f de regreso) y %d viajes inesperados",
+			t1.Utilizacion, t1.Distancia, t1.DistanciaRegreso, t1.Viajes)
+	}
+	camion, _ := ts.ObtenerCamion("T2")
+	if !camion.TiempoInicio.Equal(EpocaSimulacion) || !camion.TiempoFin.Equal(EpocaSimulacion.Add(110*time.Minute)) {
+		t.Errorf("Fechas de T2 inesperadas: inicio %v, fin %v", camion.TiempoInicio, camion.TiempoFin)
+	}
+	if !reflect.DeepEqual(t2.Ruta, []string{"ALM", "N", "D", "N", "ALM"}) {
+		t.Errorf("Ruta de T2 inesperada: %v", t2.Ruta)
+	}
+	esperas := []EsperaTunel{{Desde: "ALM", Hasta: "N", Cruces: 4, Esperas: 3, Espera: 70 * time.Minute, Promedio: 70 * time.Minute / 3}}
+	if !reflect.DeepEqual(resultado.EsperasPorTunel, esperas) {
+		t.Errorf("Esperas por túnel inesperadas: %+v", resultado.EsperasPorTunel)
+	}
+
+	// El depósito solo tenía 50 de agua: T2 carga 10 y los reparte en D, que no demanda
+	if resultado.StockDeposito["agua"] != 0 || resultado.FaltanteDeposito["agua"] != 30 {
+		t.Errorf("Stock %v y faltante %v inesperados", resultado.StockDeposito, resultado.FaltanteDeposito)
+	}
+	if t1.Entregas["A"]["agua"] != 30 || t2.Entregas["D"]["agua"] != 10 {
+		t.Errorf("Entregas inesperadas: T1 %v, T2 %v", t1.Entregas, t2.Entregas)
+	}
+	faltantes := []DemandaInsatisfecha{{CuevaID: "B", Recurso: "agua", Demanda: 5, Faltante: 5}}
+	if !reflect.DeepEqual(resultado.DemandaInsatisfecha, faltantes) {
+		t.Errorf("Demanda insatisfecha inesperada: %+v", resultado.DemandaInsatisfecha)
+	}
+
+	if _, err := ts.SimularFlota(grafo, "ALM", map[string][]string{"T9": {"A"}}); err == nil {
+		t.Error("Se esperaba un error por camión inexistente")
+	}
+	if _, err := ts.SimularFlota(grafo, "ALM", map[string][]string{"T1": {"Z"}}); err == nil {
+		t.Error("Se esperaba un error por parada inexistente")
+	}
+}
+
+func TestSimularFlotaTunelAngosto(t *testing.T) {
+	// T1 y T2 salen juntos hacia A por ALM - N, de 10 minutos de cruce
+	simular := func(angosto bool, capacidad float64) *ResultadoFlota {
+		grafo := domain.NuevoGrafo(false)
+		for _, id := range []string{"ALM", "N", "A"} {
+			grafo.AgregarCueva(domain.NuevaCueva(id, id))
+		}
+		grafo.AgregarConexion("ALM", "N", 10)
+		grafo.AgregarConexion("N", "A", 10)
+		conexiones := NuevoServicioConexion(grafo)
+		if err := conexiones.EstablecerAngosto("ALM", "N", angosto); err != nil {
+			t.Fatalf("Error al marcar el túnel: %v", err)
+		}
+		if err := conexiones.EstablecerCapacidad("ALM", "N", capacidad); err != nil {
+			t.Fatalf("Error al definir capacidad: %v", err)
+		}
+		grafo.Cuevas["ALM"].AgregarRecurso("agua", 20)
+		grafo.Cuevas["A"].EstablecerDemanda("agua", 20)
+
+		ts := nuevoTruckServicePrueba(grafo)
+		for _, id := range []string{"T1", "T2"} {
+			if _, err := ts.CrearCamion(id, CamionPequeno, "ALM"); err != nil {
+				t.Fatalf("Error al crear camión: %v", err)
+			}
+			if err := ts.CargarInsumos(id, map[string]int{"agua": 10}); err != nil {
+				t.Fatalf("Error al cargar: %v", err)
+			}
+		}
+		resultado, err := ts.SimularFlota(grafo, "ALM", map[string][]string{"T1": {"A"}, "T2": {"A"}})
+		if err != nil {
+			t.Fatalf("Error en simulación de flota: %v", err)
+		}
+		return resultado
+	}
+
+	// Angosto: T2 espera a que T1 salga del túnel
+	resultado := simular(true, 0)
+	esperas := []EsperaTunel{{Desde: "ALM", Hasta: "N", Cruces: 4, Esperas: 1, Espera: 10 * time.Minute, Promedio: 10 * time.Minute}}
+	if !reflect.DeepEqual(resultado.EsperasPorTunel, esperas) {
+		t.Errorf("Angosto: esperas inesperadas %+v", resultado.EsperasPorTunel)
+	}
+
+	// Con 100 camiones por hora y dos carriles solo se espacian las entradas
+	resultado = simular(false, 100)
+	espaciado := 36 * time.Second
+	esperas = []EsperaTunel{{Desde: "ALM", Hasta: "N", Cruces: 4, Esperas: 1, Espera: espaciado, Promedio: espaciado}}
+	if !reflect.DeepEqual(resultado.EsperasPorTunel, esperas) {
+		t.Errorf("Capacidad 100: esperas inesperadas %+v", resultado.EsperasPorTunel)
+	}
+	if resultado.Camiones[1].TiempoEspera != espaciado {
+		t.Errorf("T2 esperó %v, se esperaba %v", resultado.Camiones[1].TiempoEspera, espaciado)
+	}
+}
+
+func TestSimularFlotaRecargaEnDeposito(t *testing.T) {
+	// Con 60 de agua T1 cubre B y parte de C; vacío, vuelve por medicina para D
+	grafo := crearRedDemanda()
+	grafo.Cuevas["ALM"].AgregarRecurso("agua", 60)
+	grafo.Cuevas["ALM"].AgregarRecurso("medicina", 100)
+	ts := nuevoTruckServicePrueba(grafo)
+	if _, err := ts.CrearCamion("T1", CamionPequeno, "ALM"); err != nil {
+		t.Fatalf("Error al crear camión: %v", err)
+	}
+	if err := ts.CargarInsumos("T1", map[string]int{"agua": 100}); err != nil {
+		t.Fatalf("Error al cargar: %v", err)
+	}
+
+	resultado, err := ts.SimularFlota(grafo, "ALM", map[string][]string{"T1": {"B", "C", "D"}})
+	if err != nil {
+		t.Fatalf("Error en simulación de flota: %v", err)
+	}
+
+	t1 := resultado.Camiones[0]
+	if t1.Viajes != 2 || t1.Distancia != 10 || t1.DistanciaRegreso != 5 {
+		t.Errorf("%d viajes y %.2f km (%.2f de regreso) inesperados", t1.Viajes, t1.Distancia, t1.DistanciaRegreso)
+	}
+	ruta := []string{"ALM", "B", "C", "B", "ALM", "B", "C", "D", "C", "B", "ALM"}
+	if !reflect.DeepEqual(t1.Ruta, ruta) {
+		t.Errorf("Ruta inesperada: %v", t1.Ruta)
+	}
+	if t1.Entregas["D"]["medicina"] != 40 || resultado.StockDeposito["medicina"] != 60 {
+		t.Errorf("Entregas %v y stock %v inesperados", t1.Entregas, resultado.StockDeposito)
+	}
+	camion, _ := ts.ObtenerCamion("T1")
+	if camion.Estado != Completado || camion.CuevaActual != "ALM" {
+		t.Errorf("El camión terminó en %s (%s)", camion.CuevaActual, camion.Estado)
+	}
+}
+
+func TestSimularFlotaPrioridadEntrega(t *testing.T) {
+	// Con 50 de agua para B (faltan 20) y C (faltan 50) el mayor déficit va primero
+	grafo := crearRedDemanda()
+	grafo.Cuevas["B"].EstablecerDemanda("agua", 20)
+	grafo.Cuevas["C"].EstablecerDemanda("agua", 50)
+	grafo.Cuevas["C"].AgregarRecurso("agua", 0)
+	grafo.Cuevas["ALM"].AgregarRecurso("agua", 50)
+	ts := nuevoTruckServicePrueba(grafo)
+	if _, err := ts.CrearCamion("T1", CamionPequeno, "ALM"); err != nil {
+		t.Fatalf("Error al crear camión: %v", err)
+	}
+	if err := ts.CargarInsumos("T1", map[string]int{"agua": 50}); err != nil {
+		t.Fatalf("Error al cargar: %v", err)
+	}
+
+	resultado, err := ts.SimularFlota(grafo, "ALM", map[string][]string{"T1": {"B", "C"}})
+	if err != nil {
+		t.Fatalf("Error en simulación de flota: %v", err)
+	}
+	entregas := resultado.Camiones[0].Entregas
+	if entregas["B"]["agua"] != 0 || entregas["C"]["agua"] != 50 {
+		t.Errorf("Entregas inesperadas: %v", entregas)
+	}
+}
+
+func TestSimularFlotaRecargaSinDemanda(t *testing.T) {
+	// T1 deja sus 50 de agua en B; D no pide agua, así que repone la carga original
+	grafo := crearRedDemanda()
+	grafo.Cuevas["ALM"].AgregarRecurso("agua", 100)
+	ts := nuevoTruckServicePrueba(grafo)
+	if _, err := ts.CrearCamion("T1", CamionPequeno, "ALM"); err != nil {
+		t.Fatalf("Error al crear camión: %v", err)
+	}
+	if err := ts.CargarInsumos("T1", map[string]int{"agua": 50}); err != nil {
+		t.Fatalf("Error al cargar: %v", err)
+	}
+
+	resultado, err := ts.SimularFlota(grafo, "ALM", map[string][]string{"T1": {"B", "D"}})
+	if err != nil {
+		t.Fatalf("Error en simulación de flota: %v", err)
+	}
+	t1 := resultado.Camiones[0]
+	if t1.Viajes != 2 {
+		t.Errorf("Se esperaban 2 viajes, hubo %d", t1.Viajes)
+	}
+	if t1.Entregas["B"]["agua"] != 50 || t1.Entregas["D"]["agua"] != 50 || resultado.StockDeposito["agua"] != 0 {
+		t.Errorf("Entregas %v y stock %v inesperados", t1.Entregas, resultado.StockDeposito)
+	}
+}
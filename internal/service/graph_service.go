@@ -1,48 +1,114 @@
 package service
 
 import (
+	"fmt"
+	"proyecto-grafos-go/configs"
 	"proyecto-grafos-go/internal/domain"
 	"proyecto-grafos-go/internal/repository"
-	"strings"
+	"proyecto-grafos-go/pkg/algorithms"
+	"time"
 )
 
 type ServicioGrafo struct {
 	grafo       *domain.Grafo
-	repositorio *repository.RepositorioArchivo
+	repositorio repository.GraphRepository
+	config      *configs.Config // Formatos habilitados; nil usa la configuración por defecto
 }
 
-func NuevoServicioGrafo(grafo *domain.Grafo, repositorio *repository.RepositorioArchivo) *ServicioGrafo {
+func NuevoServicioGrafo(grafo *domain.Grafo, repositorio repository.GraphRepository) *ServicioGrafo {
 	return &ServicioGrafo{grafo: grafo, repositorio: repositorio}
 }
 
+// EstablecerConfiguracion define la configuración de la que se toman los formatos habilitados
+func (sg *ServicioGrafo) EstablecerConfiguracion(config *configs.Config) {
+	sg.config = config
+}
+
+// FormatoSoportado indica si el formato ("json", "csv", "matriz", ...) está habilitado
+func (sg *ServicioGrafo) FormatoSoportado(formato string) bool {
+	return sg.configuracion().IsFormatSupported(formato)
+}
+
+// FormatosSoportados devuelve los formatos habilitados en la configuración
+func (sg *ServicioGrafo) FormatosSoportados() []string {
+	return sg.configuracion().Database.SupportedFormats
+}
+
+func (sg *ServicioGrafo) configuracion() *configs.Config {
+	if sg.config == nil {
+		return configs.DefaultConfig()
+	}
+	return sg.config
+}
+
+// verifica que el formato del archivo esté habilitado antes de leerlo
+func (sg *ServicioGrafo) validarFormato(archivo string) error {
+	if formato := repository.FormatoArchivo(archivo); !sg.FormatoSoportado(formato) {
+		return fmt.Errorf("el formato '%s' no está habilitado en la configuración", formato)
+	}
+	return nil
+}
+
 // 1a: Cargar grafo desde archivo
 func (sg *ServicioGrafo) CargarGrafo(archivo string) error {
-	// Detectar tipo de archivo por extensión
-	if strings.HasSuffix(strings.ToLower(archivo), ".xml") {
-		grafo, err := sg.repositorio.CargarXML(archivo)
-		if err != nil {
-			return err
-		}
-		*sg.grafo = *grafo
-		return nil
-	} else if strings.HasSuffix(strings.ToLower(archivo), ".txt") {
-		grafo, err := sg.repositorio.CargarTXT(archivo)
-		if err != nil {
-			return err
-		}
-		*sg.grafo = *grafo
-		return nil
+	if sg.repositorio == nil {
+		return fmt.Errorf("repositorio no configurado")
+	}
+	if err := sg.validarFormato(archivo); err != nil {
+		return err
 	}
 
-	// Por defecto, cargar como JSON
-	grafo, err := sg.repositorio.CargarJSON(archivo)
+	// El repositorio detecta el formato (por ejemplo, por extensión)
+	grafo, err := sg.repositorio.Cargar(archivo)
 	if err != nil {
 		return err
 	}
-	*sg.grafo = *grafo // Actualizar el grafo existente
+	sg.reemplazarGrafo(grafo) // Actualizar el grafo existente
 	return nil
 }
 
+// CargarGrafoValidado carga el grafo validando el archivo; en modo tolerante
+// devuelve los problemas encontrados y en modo estricto rechaza la carga
+func (sg *ServicioGrafo) CargarGrafoValidado(archivo string, modo repository.ModoValidacion) ([]repository.ProblemaValidacion, error) {
+	repositorio, ok := sg.repositorio.(repository.RepositorioValidado)
+	if !ok {
+		return nil, fmt.Errorf("el repositorio configurado no admite validación")
+	}
+	if err := sg.validarFormato(archivo); err != nil {
+		return nil, err
+	}
+
+	grafo, problemas, err := repositorio.CargarConValidacion(archivo, modo)
+	if err != nil {
+		return problemas, err
+	}
+	sg.reemplazarGrafo(grafo)
+	return problemas, nil
+}
+
+// AdmiteValidacion indica si el repositorio configurado puede validar al cargar
+func (sg *ServicioGrafo) AdmiteValidacion() bool {
+	_, ok := sg.repositorio.(repository.RepositorioValidado)
+	return ok
+}
+
+// ListarResumenes describe los grafos guardados, si el repositorio lo permite
+func (sg *ServicioGrafo) ListarResumenes() ([]repository.ResumenGrafo, error) {
+	repositorio, ok := sg.repositorio.(repository.RepositorioConResumenes)
+	if !ok {
+		return nil, fmt.Errorf("el repositorio configurado no admite resúmenes")
+	}
+	return repositorio.ListarResumenes()
+}
+
+// reemplazarGrafo actualiza el grafo existente sin copiar su mutex
+func (sg *ServicioGrafo) reemplazarGrafo(grafo *domain.Grafo) {
+	sg.grafo.Cuevas = grafo.Cuevas
+	sg.grafo.Aristas = grafo.Aristas
+	sg.grafo.EsDirigido = grafo.EsDirigido
+	sg.grafo.MarcarModificado()
+}
+
 // 1c: Cambiar tipo de grafo (dirigido/no dirigido)
 func (sg *ServicioGrafo) CambiarTipoGrafo(esDirigido bool) {
 	sg.grafo.EsDirigido = esDirigido
@@ -69,15 +135,152 @@ func (sg *ServicioGrafo) ObtenerGrafo() *domain.Grafo {
 
 // GuardarGrafo guarda el grafo actual en un archivo
 func (sg *ServicioGrafo) GuardarGrafo(archivo string) error {
-	// Detectar tipo de archivo por extensión
-	if strings.HasSuffix(strings.ToLower(archivo), ".xml") {
-		return sg.repositorio.GuardarXML(sg.grafo, archivo)
-	} else if strings.HasSuffix(strings.ToLower(archivo), ".txt") {
-		return sg.repositorio.GuardarTXT(sg.grafo, archivo)
+	if sg.repositorio == nil {
+		return fmt.Errorf("repositorio no configurado")
+	}
+
+	// El repositorio detecta el formato (por ejemplo, por extensión)
+	return sg.repositorio.Guardar(archivo, sg.grafo)
+}
+
+// GuardarGrafoConMensaje guarda el grafo actual registrando un snapshot con el mensaje indicado
+func (sg *ServicioGrafo) GuardarGrafoConMensaje(archivo, mensaje string) error {
+	repositorio, err := sg.repositorioConSnapshots()
+	if err != nil {
+		return err
 	}
+	return repositorio.GuardarConMensaje(archivo, sg.grafo, mensaje)
+}
+
+// ListarSnapshots devuelve el historial de versiones de un archivo
+func (sg *ServicioGrafo) ListarSnapshots(archivo string) ([]repository.Snapshot, error) {
+	repositorio, err := sg.repositorioConSnapshots()
+	if err != nil {
+		return nil, err
+	}
+	return repositorio.ListarSnapshots(archivo)
+}
 
-	// Por defecto, guardar como JSON
-	return sg.repositorio.GuardarJSON(sg.grafo, archivo)
+// RestaurarSnapshot restaura una versión del archivo y la carga como grafo actual
+func (sg *ServicioGrafo) RestaurarSnapshot(archivo string, numero int) error {
+	repositorio, err := sg.repositorioConSnapshots()
+	if err != nil {
+		return err
+	}
+	if err := repositorio.RestaurarSnapshot(archivo, numero); err != nil {
+		return err
+	}
+	return sg.CargarGrafo(archivo)
+}
+
+// PodarSnapshots elimina versiones antiguas por cantidad o antigüedad (0 desactiva el criterio)
+func (sg *ServicioGrafo) PodarSnapshots(archivo string, maxCantidad int, maxAntiguedad time.Duration) (int, error) {
+	repositorio, err := sg.repositorioConSnapshots()
+	if err != nil {
+		return 0, err
+	}
+	return repositorio.PodarSnapshots(archivo, maxCantidad, maxAntiguedad)
+}
+
+// CompararConArchivo calcula los cambios del grafo actual respecto al guardado en el archivo
+func (sg *ServicioGrafo) CompararConArchivo(archivo string) (*domain.ParcheGrafo, error) {
+	if sg.repositorio == nil {
+		return nil, fmt.Errorf("repositorio no configurado")
+	}
+
+	guardado, err := sg.repositorio.Cargar(archivo)
+	if err != nil {
+		return nil, err
+	}
+	return domain.CompararGrafos(guardado, sg.grafo), nil
+}
+
+// CompararSnapshots calcula los cambios entre dos versiones de un archivo
+func (sg *ServicioGrafo) CompararSnapshots(archivo string, desde, hasta int) (*domain.ParcheGrafo, error) {
+	repositorio, err := sg.repositorioConSnapshots()
+	if err != nil {
+		return nil, err
+	}
+
+	antes, err := repositorio.CargarSnapshot(archivo, desde)
+	if err != nil {
+		return nil, err
+	}
+	despues, err := repositorio.CargarSnapshot(archivo, hasta)
+	if err != nil {
+		return nil, err
+	}
+	return domain.CompararGrafos(antes, despues), nil
+}
+
+// AplicarParche aplica un parche sobre el grafo actual; si hay conflictos no se modifica
+func (sg *ServicioGrafo) AplicarParche(parche *domain.ParcheGrafo) error {
+	return sg.grafo.AplicarParche(parche)
+}
+
+// GuardarParche guarda un parche en un archivo JSON
+func (sg *ServicioGrafo) GuardarParche(archivo string, parche *domain.ParcheGrafo) error {
+	repositorio, ok := sg.repositorio.(repository.RepositorioParches)
+	if !ok {
+		return fmt.Errorf("el repositorio configurado no admite parches")
+	}
+	return repositorio.GuardarParche(archivo, parche)
+}
+
+// AplicarParcheDesdeArchivo carga un parche JSON y lo aplica sobre el grafo actual
+func (sg *ServicioGrafo) AplicarParcheDesdeArchivo(archivo string) (*domain.ParcheGrafo, error) {
+	repositorio, ok := sg.repositorio.(repository.RepositorioParches)
+	if !ok {
+		return nil, fmt.Errorf("el repositorio configurado no admite parches")
+	}
+	parche, err := repositorio.CargarParche(archivo)
+	if err != nil {
+		return nil, err
+	}
+	return parche, sg.AplicarParche(parche)
+}
+
+// FusionarGrafo importa otro grafo en el actual según las opciones de prefijo,
+// remapeo, política de conflictos y costuras; si falla no se modifica
+func (sg *ServicioGrafo) FusionarGrafo(otro *domain.Grafo, opciones domain.OpcionesFusion) (*domain.ReporteFusion, error) {
+	return sg.grafo.Fusionar(otro, opciones)
+}
+
+// FusionarDesdeArchivo carga un grafo desde el repositorio y lo fusiona con el actual
+func (sg *ServicioGrafo) FusionarDesdeArchivo(archivo string, opciones domain.OpcionesFusion) (*domain.ReporteFusion, error) {
+	if sg.repositorio == nil {
+		return nil, fmt.Errorf("repositorio no configurado")
+	}
+
+	otro, err := sg.repositorio.Cargar(archivo)
+	if err != nil {
+		return nil, err
+	}
+	return sg.FusionarGrafo(otro, opciones)
+}
+
+// repositorioConSnapshots obtiene el repositorio si admite historial de versiones
+// AdmiteSnapshots indica si el repositorio configurado guarda versiones
+func (sg *ServicioGrafo) AdmiteSnapshots() bool {
+	_, err := sg.repositorioConSnapshots()
+	return err == nil
+}
+
+func (sg *ServicioGrafo) repositorioConSnapshots() (repository.RepositorioConSnapshots, error) {
+	repositorio, ok := sg.repositorio.(repository.RepositorioConSnapshots)
+	if !ok {
+		return nil, fmt.Errorf("el repositorio configurado no admite snapshots")
+	}
+	return repositorio, nil
+}
+
+// ExportarDOT exporta el grafo actual a Graphviz DOT superponiendo los resaltados indicados
+func (sg *ServicioGrafo) ExportarDOT(archivo string, resaltados ...repository.ResaltadoDOT) error {
+	exportador, ok := sg.repositorio.(repository.ExportadorDOT)
+	if !ok {
+		return fmt.Errorf("el repositorio configurado no admite exportación DOT")
+	}
+	return exportador.GuardarDOT(sg.grafo, archivo, resaltados...)
 }
 
 // ActualizarGrafo actualiza el grafo actual
@@ -127,10 +330,27 @@ func (sg *ServicioGrafo) ObtenerEstadisticas() (*EstadisticasGrafo, error) {
 		EsConexo:      sg.esConexo(),
 		TieneCiclos:   sg.tieneCiclos(),
 		Densidad:      sg.calcularDensidad(),
+		Diametro:      -1,
+		Radio:         -1,
+	}
+
+	// Métricas de distancia; con un ciclo negativo quedan sin calcular
+	if matriz, err := sg.MatrizDistancias(); err == nil {
+		metricas := matriz.Metricas()
+		stats.Excentricidades = metricas.Excentricidades
+		stats.Diametro = metricas.Diametro
+		stats.Radio = metricas.Radio
+		stats.Centro = metricas.Centro
+		stats.Periferia = metricas.Periferia
 	}
 	return stats, nil
 }
 
+// MatrizDistancias calcula las distancias mínimas y siguientes saltos entre todo par de cuevas
+func (sg *ServicioGrafo) MatrizDistancias() (*algorithms.MatrizDistancias, error) {
+	return algorithms.CalcularMatrizDistancias(sg.grafo)
+}
+
 // EstadisticasGrafo contiene información estadística del grafo
 type EstadisticasGrafo struct {
 	NumCuevas     int     `json:"num_cuevas"`
@@ -139,6 +359,13 @@ type EstadisticasGrafo struct {
 	EsConexo      bool    `json:"es_conexo"`
 	TieneCiclos   bool    `json:"tiene_ciclos"`
 	Densidad      float64 `json:"densidad"`
+
+	// Métricas sobre las distancias mínimas; -1 indica que no todas las cuevas se alcanzan
+	Excentricidades map[string]float64 `json:"excentricidades"`
+	Diametro        float64            `json:"diametro"`
+	Radio           float64            `json:"radio"`
+	Centro          []string           `json:"centro"`    // Mejores ubicaciones para un centro de recursos
+	Periferia       []string           `json:"periferia"` // Cuevas más alejadas del resto
 }
 
 // esConexo verifica si el grafo es conexo
@@ -0,0 +1,70 @@
+package service
+
+import (
+	"proyecto-grafos-go/configs"
+	"proyecto-grafos-go/internal/domain"
+	"proyecto-grafos-go/internal/repository"
+	"reflect"
+	"testing"
+)
+
+// TestObtenerEstadisticasMetricasDistancia verifica el centro y la periferia de una cadena A - B - C - D
+func TestObtenerEstadisticasMetricasDistancia(t *testing.T) {
+	grafo := domain.NuevoGrafo(false)
+	for _, id := range []string{"A", "B", "C", "D"} {
+		grafo.AgregarCueva(domain.NuevaCueva(id, id))
+	}
+	grafo.AgregarConexion("A", "B", 1)
+	grafo.AgregarConexion("B", "C", 1)
+	grafo.AgregarConexion("C", "D", 3)
+
+	stats, err := NuevoServicioGrafo(grafo, nil).ObtenerEstadisticas()
+	if err != nil {
+		t.Fatalf("Error al obtener estadísticas: %v", err)
+	}
+	if stats.Diametro != 5 || stats.Radio != 3 {
+		t.Errorf("Diámetro %v y radio %v, se esperaba 5 y 3", stats.Diametro, stats.Radio)
+	}
+	if !reflect.DeepEqual(stats.Centro, []string{"C"}) || !reflect.DeepEqual(stats.Periferia, []string{"A", "D"}) {
+		t.Errorf("Centro %v y periferia %v inesperados", stats.Centro, stats.Periferia)
+	}
+
+	// Una cueva aislada deja las métricas en -1
+	grafo.AgregarCueva(domain.NuevaCueva("Z", "Aislada"))
+	stats, _ = NuevoServicioGrafo(grafo, nil).ObtenerEstadisticas()
+	if stats.Diametro != -1 || stats.Radio != -1 || stats.Excentricidades["Z"] != -1 {
+		t.Errorf("Métricas inesperadas con una cueva aislada: %+v", stats)
+	}
+}
+
+// TestCargarGrafoFormatoDeshabilitado verifica que la carga respeta los formatos de la configuración
+func TestCargarGrafoFormatoDeshabilitado(t *testing.T) {
+	repo := repository.NuevoRepositorio(t.TempDir())
+	grafo := domain.NuevoGrafo(false)
+	grafo.AgregarCueva(domain.NuevaCueva("A", "A"))
+	for _, archivo := range []string{"red.json", "red.matriz.csv"} {
+		if err := repo.Guardar(archivo, grafo); err != nil {
+			t.Fatalf("Error guardando %s: %v", archivo, err)
+		}
+	}
+
+	config := configs.DefaultConfig()
+	config.Database.SupportedFormats = []string{"json"}
+	svc := NuevoServicioGrafo(domain.NuevoGrafo(false), repo)
+	svc.EstablecerConfiguracion(config)
+
+	if err := svc.CargarGrafo("red.json"); err != nil {
+		t.Errorf("JSON está habilitado: %v", err)
+	}
+	if err := svc.CargarGrafo("red.matriz.csv"); err == nil {
+		t.Error("Se esperaba error al cargar una matriz CSV con el formato deshabilitado")
+	}
+	if _, err := svc.CargarGrafoValidado("red.matriz.csv", repository.ValidacionTolerante); err == nil {
+		t.Error("Se esperaba error al validar una matriz CSV con el formato deshabilitado")
+	}
+
+	// Sin configuración se usan los formatos por defecto, que incluyen la matriz
+	if err := NuevoServicioGrafo(domain.NuevoGrafo(false), repo).CargarGrafo("red.matriz.csv"); err != nil {
+		t.Errorf("La matriz CSV está habilitada por defecto: %v", err)
+	}
+}
@@ -60,6 +60,13 @@ type RutaAcceso struct {
 	OrdenCreacion  int      `json:"orden_creacion"`
 }
 
+// SteinerResult contiene el árbol de Steiner que conecta un subconjunto de cuevas
+type SteinerResult struct {
+	Arbol    *algorithms.ArbolSteiner `json:"arbol"`
+	Mensaje  string                   `json:"mensaje"`
+	Detalles []string                 `json:"detalles"`
+}
+
 // ObtenerMSTGeneral implementa el requisito 3a:
 // Obtiene el árbol de expansión mínimo general de toda la red
 func (ms *MSTService) ObtenerMSTGeneral(grafo *domain.Grafo) (*MSTResult, error) {
@@ -321,6 +328,48 @@ func (ms *MSTService) dfsComponente(grafo *domain.Grafo, nodoActual string, visi
 	}
 }
 
+// ObtenerArbolSteiner conecta solo las cuevas terminales (por ejemplo, el centro
+// de recursos y las cuevas que necesitan insumos) con un árbol de Steiner aproximado
+func (ms *MSTService) ObtenerArbolSteiner(grafo *domain.Grafo, terminales []string) (*SteinerResult, error) {
+	if grafo == nil {
+		return nil, fmt.Errorf("el grafo no puede ser nil")
+	}
+
+	arbol, err := algorithms.ArbolSteinerAproximado(grafo, terminales)
+	if err != nil {
+		return nil, fmt.Errorf("error al calcular el árbol de Steiner: %v", err)
+	}
+
+	return &SteinerResult{
+		Arbol:    arbol,
+		Mensaje:  "Árbol de Steiner calculado exitosamente (a lo sumo el doble del óptimo)",
+		Detalles: ms.generarDetallesSteiner(arbol),
+	}, nil
+}
+
+// generarDetallesSteiner lista las cuevas intermedias y los túneles del árbol de Steiner
+func (ms *MSTService) generarDetallesSteiner(arbol *algorithms.ArbolSteiner) []string {
+	var detalles []string
+
+	detalles = append(detalles, fmt.Sprintf("Cuevas terminales: %d (%s)", len(arbol.Terminales), strings.Join(arbol.Terminales, ", ")))
+	if len(arbol.CuevasSteiner) > 0 {
+		detalles = append(detalles, fmt.Sprintf("Cuevas intermedias: %d (%s)", len(arbol.CuevasSteiner), strings.Join(arbol.CuevasSteiner, ", ")))
+	} else {
+		detalles = append(detalles, "Cuevas intermedias: ninguna")
+	}
+
+	if len(arbol.Aristas) > 0 {
+		detalles = append(detalles, "")
+		detalles = append(detalles, "Conexiones requeridas:")
+		for i, arista := range arbol.Aristas {
+			detalles = append(detalles, fmt.Sprintf("  %d. %s ↔ %s (distancia: %.2f)",
+				i+1, arista.Desde, arista.Hasta, arista.Distancia))
+		}
+	}
+
+	return detalles
+}
+
 // generarDetallesMST genera información detallada sobre el MST calculado
 func (ms *MSTService) generarDetallesMST(mst *algorithms.MST, grafo *domain.Grafo) []string {
 	var detalles []string
@@ -451,6 +500,23 @@ func (ms *MSTService) FormatearResultadoParaVisualizacion(resultado *MSTResult)
 	return sb.String()
 }
 
+// FormatearArbolSteinerParaVisualizacion formatea el árbol de Steiner para mostrar en CLI
+func (ms *MSTService) FormatearArbolSteinerParaVisualizacion(resultado *SteinerResult) string {
+	var sb strings.Builder
+
+	sb.WriteString("=== ÁRBOL DE STEINER (SUBCONJUNTO DE CUEVAS) ===\n\n")
+	sb.WriteString(fmt.Sprintf("Estado: %s\n", resultado.Mensaje))
+	sb.WriteString(fmt.Sprintf("Longitud total: %.2f\n", resultado.Arbol.PesoTotal))
+	sb.WriteString(fmt.Sprintf("Número de conexiones: %d\n", resultado.Arbol.NumAristas))
+	sb.WriteString("\n")
+
+	for _, detalle := range resultado.Detalles {
+		sb.WriteString(detalle + "\n")
+	}
+
+	return sb.String()
+}
+
 // FormatearResultadoMSTDesdeCuevaParaVisualizacion formatea el resultado para mostrar en CLI
 func (ms *MSTService) FormatearResultadoMSTDesdeCuevaParaVisualizacion(resultado *MSTDesdeCuevaResult) string {
 	var sb strings.Builder
@@ -0,0 +1,205 @@
+package service
+
+import (
+	"container/heap"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// TipoEvento identifica lo que ocurre en un instante de la simulación
+type TipoEvento string
+
+const (
+	EventoSalida   TipoEvento = "SALIDA"   // El camión deja una cueva
+	EventoLlegada  TipoEvento = "LLEGADA"  // El camión llega a una cueva
+	EventoDescarga TipoEvento = "DESCARGA" // El camión termina de descargar en una cueva
+	EventoRegreso  TipoEvento = "REGRESO"  // El camión vuelve al almacén
+	EventoCarga    TipoEvento = "CARGA"    // El camión termina de cargar en el depósito
+)
+
+// EventoSimulacion es una entrada del registro de la simulación
+type EventoSimulacion struct {
+	Tiempo      time.Duration  `json:"tiempo"` // Reloj virtual desde el inicio de la simulación
+	Tipo        TipoEvento     `json:"tipo"`
+	CamionID    string         `json:"camion_id"`
+	CuevaID     string         `json:"cueva_id"`
+	Descripcion string         `json:"descripcion,omitempty"`
+	Carga       map[string]int `json:"carga,omitempty"` // Lo descargado o cargado en el evento
+}
+
+// ModoReproduccion define cómo avanza el reloj virtual respecto del real
+type ModoReproduccion string
+
+const (
+	ReproduccionRapida    ModoReproduccion = "RAPIDA"      // Procesa todos los eventos sin esperar
+	ReproduccionPasoAPaso ModoReproduccion = "PASO_A_PASO" // Espera a AlAvanzar después de cada evento
+	ReproduccionEscalada  ModoReproduccion = "ESCALADA"    // Espera el tiempo virtual multiplicado por Escala
+)
+
+// ModosReproduccion lista los modos disponibles en el orden en que se ofrecen
+var ModosReproduccion = []ModoReproduccion{ReproduccionRapida, ReproduccionPasoAPaso, ReproduccionEscalada}
+
+// EpocaSimulacion es el instante que corresponde al cero del reloj virtual; las
+// fechas de inicio y fin de los camiones se calculan desde ella y no desde el
+// reloj del sistema, para que dos corridas iguales den el mismo resultado
+var EpocaSimulacion = time.Date(2000, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+// EscalaPredeterminada reproduce una hora simulada en 3,6 segundos reales
+const EscalaPredeterminada = 0.001
+
+// Reproduccion configura cómo se reproduce una simulación. El registro de
+// eventos resultante es el mismo en todos los modos.
+type Reproduccion struct {
+	Modo      ModoReproduccion
+	Escala    float64                // Segundos reales por segundo simulado en el modo escalado
+	AlAvanzar func(EventoSimulacion) // Se llama tras cada evento; en el paso a paso debe esperar al usuario
+}
+
+// eventoProgramado es un evento pendiente con la acción que lo procesa
+type eventoProgramado struct {
+	evento    EventoSimulacion
+	accion    func(*EventoSimulacion)
+	secuencia int // Desempata eventos simultáneos en el orden en que se programaron
+}
+
+// colaEventos implementa heap.Interface ordenando por tiempo y secuencia
+type colaEventos []*eventoProgramado
+
+func (c colaEventos) Len() int { return len(c) }
+func (c colaEventos) Less(i, j int) bool {
+	if c[i].evento.Tiempo != c[j].evento.Tiempo {
+		return c[i].evento.Tiempo < c[j].evento.Tiempo
+	}
+	return c[i].secuencia < c[j].secuencia
+}
+func (c colaEventos) Swap(i, j int) { c[i], c[j] = c[j], c[i] }
+
+func (c *colaEventos) Push(x interface{}) {
+	*c = append(*c, x.(*eventoProgramado))
+}
+
+func (c *colaEventos) Pop() interface{} {
+	anterior := *c
+	n := len(anterior)
+	item := anterior[n-1]
+	*c = anterior[:n-1]
+	return item
+}
+
+// MotorSimulacion es un simulador de eventos discretos con reloj virtual
+type MotorSimulacion struct {
+	reloj     time.Duration
+	cola      colaEventos
+	registro  []EventoSimulacion
+	secuencia int
+	dormir    func(time.Duration) // Reemplazable en pruebas
+}
+
+// NuevoMotorSimulacion crea un motor con el reloj en cero
+func NuevoMotorSimulacion() *MotorSimulacion {
+	return &MotorSimulacion{
+		cola:     make(colaEventos, 0),
+		registro: make([]EventoSimulacion, 0),
+		dormir:   time.Sleep,
+	}
+}
+
+// Ahora devuelve el tiempo virtual transcurrido
+func (m *MotorSimulacion) Ahora() time.Duration {
+	return m.reloj
+}
+
+// Instante devuelve la fecha virtual del reloj actual
+func (m *MotorSimulacion) Instante() time.Time {
+	return EpocaSimulacion.Add(m.reloj)
+}
+
+// Programar agenda un evento dentro de retraso; la acción se ejecuta al
+// procesarlo y puede completar el evento antes de que se registre
+func (m *MotorSimulacion) Programar(retraso time.Duration, evento EventoSimulacion, accion func(*EventoSimulacion)) {
+	if retraso < 0 {
+		retraso = 0
+	}
+	evento.Tiempo = m.reloj + retraso
+	m.secuencia++
+	heap.Push(&m.cola, &eventoProgramado{evento: evento, accion: accion, secuencia: m.secuencia})
+}
+
+// Pendientes indica cuántos eventos quedan en la cola
+func (m *MotorSimulacion) Pendientes() int {
+	return m.cola.Len()
+}
+
+// Paso procesa el siguiente evento y avanza el reloj hasta él
+func (m *MotorSimulacion) Paso() (EventoSimulacion, bool) {
+	if m.cola.Len() == 0 {
+		return EventoSimulacion{}, false
+	}
+	siguiente := heap.Pop(&m.cola).(*eventoProgramado)
+	m.reloj = siguiente.evento.Tiempo
+	if siguiente.accion != nil {
+		siguiente.accion(&siguiente.evento)
+	}
+	m.registro = append(m.registro, siguiente.evento)
+	return siguiente.evento, true
+}
+
+// Ejecutar procesa la cola hasta vaciarla con el modo de reproducción indicado
+func (m *MotorSimulacion) Ejecutar(reproduccion Reproduccion) error {
+	escala := reproduccion.Escala
+	switch reproduccion.Modo {
+	case ReproduccionRapida, "", ReproduccionPasoAPaso:
+	case ReproduccionEscalada:
+		if escala <= 0 {
+			escala = EscalaPredeterminada
+		}
+	default:
+		return fmt.Errorf("modo de reproducción no válido: %s", reproduccion.Modo)
+	}
+
+	for m.cola.Len() > 0 {
+		if reproduccion.Modo == ReproduccionEscalada {
+			if espera := m.cola[0].evento.Tiempo - m.reloj; espera > 0 {
+				m.dormir(time.Duration(float64(espera) * escala))
+			}
+		}
+		evento, _ := m.Paso()
+		if reproduccion.AlAvanzar != nil {
+			reproduccion.AlAvanzar(evento)
+		}
+	}
+	return nil
+}
+
+// Registro devuelve los eventos procesados en orden
+func (m *MotorSimulacion) Registro() []EventoSimulacion {
+	return m.registro
+}
+
+// FormatearReloj muestra un tiempo virtual como hh:mm:ss
+func FormatearReloj(tiempo time.Duration) string {
+	segundos := int64(tiempo.Round(time.Second) / time.Second)
+	return fmt.Sprintf("%02d:%02d:%02d", segundos/3600, segundos/60%60, segundos%60)
+}
+
+// FormatearEvento muestra un evento del registro en una línea
+func FormatearEvento(evento EventoSimulacion) string {
+	linea := fmt.Sprintf("[%s] %-8s %s en %s", FormatearReloj(evento.Tiempo), evento.Tipo, evento.CamionID, evento.CuevaID)
+	if len(evento.Carga) > 0 {
+		recursos := make([]string, 0, len(evento.Carga))
+		for recurso := range evento.Carga {
+			recursos = append(recursos, recurso)
+		}
+		sort.Strings(recursos)
+		for i, recurso := range recursos {
+			recursos[i] = fmt.Sprintf("%s: %d", recurso, evento.Carga[recurso])
+		}
+		linea += " (" + strings.Join(recursos, ", ") + ")"
+	}
+	if evento.Descripcion != "" {
+		linea += " - " + evento.Descripcion
+	}
+	return linea
+}
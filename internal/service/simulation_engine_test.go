@@ -0,0 +1,159 @@
+package service
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+// programarPrueba agenda una cadena de eventos: cada llegada programa la siguiente salida
+func programarPrueba(motor *MotorSimulacion) {
+	var salir func(i int)
+	salir = func(i int) {
+		if i == 3 {
+			return
+		}
+		motor.Programar(0, EventoSimulacion{Tipo: EventoSalida, CamionID: "T", CuevaID: string(rune('A' + i))}, func(*EventoSimulacion) {
+			motor.Programar(time.Duration(i+1)*time.Hour, EventoSimulacion{Tipo: EventoLlegada, CamionID: "T", CuevaID: string(rune('B' + i))}, func(*EventoSimulacion) {
+				salir(i + 1)
+			})
+		})
+	}
+	salir(0)
+	// Los eventos simultáneos se procesan en el orden en que se programaron: estos
+	// dos van antes que la primera llegada, que se programa al procesar la salida
+	motor.Programar(time.Hour, EventoSimulacion{Tipo: EventoDescarga, CamionID: "U", CuevaID: "X"}, nil)
+	motor.Programar(time.Hour, EventoSimulacion{Tipo: EventoRegreso, CamionID: "U", CuevaID: "X"}, nil)
+}
+
+func TestMotorSimulacionModosReproduccion(t *testing.T) {
+	var registros [][]EventoSimulacion
+	for _, modo := range ModosReproduccion {
+		motor := NuevoMotorSimulacion()
+		dormido := time.Duration(0)
+		motor.dormir = func(d time.Duration) { dormido += d }
+		programarPrueba(motor)
+
+		avances := 0
+		err := motor.Ejecutar(Reproduccion{Modo: modo, AlAvanzar: func(EventoSimulacion) { avances++ }})
+		if err != nil {
+			t.Fatalf("%s: error al ejecutar: %v", modo, err)
+		}
+		if motor.Ahora() != 6*time.Hour || avances != 8 || motor.Pendientes() != 0 {
+			t.Errorf("%s: reloj %v con %d avances", modo, motor.Ahora(), avances)
+		}
+
+		// Solo el modo escalado espera: 6 horas a 0,001 son 21,6 segundos
+		esperado := time.Duration(0)
+		if modo == ReproduccionEscalada {
+			esperado = time.Duration(float64(6*time.Hour) * EscalaPredeterminada)
+		}
+		if dormido != esperado {
+			t.Errorf("%s: se esperó %v en lugar de %v", modo, dormido, esperado)
+		}
+		registros = append(registros, motor.Registro())
+	}
+
+	tipos := make([]TipoEvento, 0)
+	for _, evento := range registros[0] {
+		tipos = append(tipos, evento.Tipo)
+	}
+	esperados := []TipoEvento{EventoSalida, EventoDescarga, EventoRegreso, EventoLlegada, EventoSalida, EventoLlegada, EventoSalida, EventoLlegada}
+	if !reflect.DeepEqual(tipos, esperados) {
+		t.Errorf("Orden de eventos inesperado: %v", tipos)
+	}
+	for i := 1; i < len(registros); i++ {
+		if !reflect.DeepEqual(registros[i], registros[0]) {
+			t.Errorf("El registro del modo %s difiere del rápido", ModosReproduccion[i])
+		}
+	}
+
+	if err := NuevoMotorSimulacion().Ejecutar(Reproduccion{Modo: "otro"}); err == nil {
+		t.Error("Se esperaba un error por modo no válido")
+	}
+}
+
+func TestSimularEntregaRelojVirtual(t *testing.T) {
+	grafo := crearRedDemanda()
+	grafo.Cuevas["ALM"].AgregarRecurso("agua", 60)
+	grafo.Cuevas["ALM"].AgregarRecurso("medicina", 100)
+	ts := nuevoTruckServicePrueba(grafo)
+	if _, err := ts.CrearCamion("T1", CamionPequeno, "ALM"); err != nil {
+		t.Fatalf("Error al crear camión: %v", err)
+	}
+	if err := ts.CargarInsumos("T1", map[string]int{"agua": 60}); err != nil {
+		t.Fatalf("Error al cargar: %v", err)
+	}
+
+	resultado, err := ts.SimularEntregaBFS(grafo, "T1", "ALM")
+	if err != nil {
+		t.Fatalf("Error en simulación: %v", err)
+	}
+
+	// A 60 km/h cada túnel de 1 km es un minuto. El agua alcanza para B y C;
+	// vacío, el camión vuelve al depósito por medicina para D y regresa al final
+	if resultado.TiempoTotal != 50*time.Minute || resultado.DistanciaTotal != 10 {
+		t.Errorf("Tiempo total %v y distancia %.2f, se esperaban 50m y 10 km", resultado.TiempoTotal, resultado.DistanciaTotal)
+	}
+	linea := make([]string, 0)
+	for _, evento := range resultado.Eventos {
+		linea = append(linea, FormatearReloj(evento.Tiempo)+" "+string(evento.Tipo)+" "+evento.CuevaID)
+	}
+	esperada := []string{
+		"00:00:00 CARGA ALM", "00:00:00 SALIDA ALM", "00:01:00 LLEGADA B",
+		"00:11:00 DESCARGA B", "00:11:00 SALIDA B", "00:12:00 LLEGADA C",
+		"00:22:00 DESCARGA C", "00:22:00 REGRESO C", "00:24:00 LLEGADA ALM",
+		"00:34:00 CARGA ALM", "00:34:00 SALIDA ALM", "00:37:00 LLEGADA D",
+		"00:47:00 DESCARGA D", "00:47:00 REGRESO D", "00:50:00 LLEGADA ALM",
+	}
+	if !reflect.DeepEqual(linea, esperada) {
+		t.Errorf("Registro inesperado:\n%v", linea)
+	}
+	camion, _ := ts.ObtenerCamion("T1")
+	if resultado.EstadisticasEntrega["viajes"] != 2 || resultado.EstadisticasEntrega["distancia_regreso"] != 5.0 ||
+		camion.Estado != Completado || camion.CuevaActual != "ALM" {
+		t.Errorf("Estadísticas %v y camión en %s (%s) inesperados", resultado.EstadisticasEntrega, camion.CuevaActual, camion.Estado)
+	}
+	// Las fechas salen del reloj virtual, no del sistema
+	if !camion.TiempoInicio.Equal(EpocaSimulacion) || !camion.TiempoFin.Equal(EpocaSimulacion.Add(50*time.Minute)) {
+		t.Errorf("Fechas inesperadas: inicio %v, fin %v", camion.TiempoInicio, camion.TiempoFin)
+	}
+	if resultado.EntregasRealizadas["D"]["medicina"] != 40 || grafo.Cuevas["ALM"].ObtenerRecurso("medicina") != 60 {
+		t.Errorf("Recarga inesperada: D recibió %v", resultado.EntregasRealizadas["D"])
+	}
+
+	if err := ts.EstablecerReproduccion(Reproduccion{Modo: "otro"}); err == nil {
+		t.Error("Se esperaba un error por modo de reproducción no válido")
+	}
+}
+
+func TestSimularEntregaFinDeJornada(t *testing.T) {
+	grafo := crearRedDemanda()
+	grafo.Cuevas["ALM"].AgregarRecurso("agua", 100)
+	ts := nuevoTruckServicePrueba(grafo)
+	if _, err := ts.CrearCamion("T1", CamionPequeno, "ALM"); err != nil {
+		t.Fatalf("Error al crear camión: %v", err)
+	}
+	if err := ts.CargarInsumos("T1", map[string]int{"agua": 100}); err != nil {
+		t.Fatalf("Error al cargar: %v", err)
+	}
+	// Tras B (0:11) ir a C, descargar y volver llevaría hasta 0:24
+	if err := ts.EstablecerJornada(20 * time.Minute); err != nil {
+		t.Fatalf("Error al definir la jornada: %v", err)
+	}
+
+	resultado, err := ts.SimularEntregaBFS(grafo, "T1", "ALM")
+	if err != nil {
+		t.Fatalf("Error en simulación: %v", err)
+	}
+	if resultado.TiempoTotal != 12*time.Minute || !reflect.DeepEqual(resultado.RutaCompleta, []string{"ALM", "B", "ALM"}) {
+		t.Errorf("Tiempo %v y ruta %v inesperados", resultado.TiempoTotal, resultado.RutaCompleta)
+	}
+	if resultado.EstadisticasEntrega["cuevas_sin_visitar"] != 2 {
+		t.Errorf("Se esperaban 2 cuevas sin visitar: %v", resultado.EstadisticasEntrega)
+	}
+
+	if err := ts.EstablecerJornada(0); err == nil {
+		t.Error("Se esperaba un error por jornada nula")
+	}
+}
@@ -3,6 +3,7 @@ package service
 import (
 	"fmt"
 	"proyecto-grafos-go/internal/domain"
+	"proyecto-grafos-go/pkg/algorithms"
 )
 
 // TipoRecorrido define los tipos de recorrido disponibles
@@ -11,6 +12,7 @@ type TipoRecorrido string
 const (
 	DFS TipoRecorrido = "DFS" // Búsqueda en Profundidad
 	BFS TipoRecorrido = "BFS" // Búsqueda en Anchura
+	TSP TipoRecorrido = "TSP" // Recorrido planificado sobre caminos mínimos
 )
 
 // RecorridoResultado representa el resultado de un recorrido
@@ -21,6 +23,7 @@ type RecorridoResultado struct {
 	DistanciaTotal float64       `json:"distancia_total"`
 	CuevaOrigen    string        `json:"cueva_origen"`
 	Completado     bool          `json:"completado"`
+	Tramos         []float64     `json:"tramos,omitempty"` // Distancia real de cada cueva a la siguiente (solo TSP)
 }
 
 // TraversalService proporciona algoritmos de recorrido de grafos
@@ -192,6 +195,54 @@ func (ts *TraversalService) obtenerDistanciaEntreAristas(grafo *domain.Grafo, de
 	return 0.0
 }
 
+// RealizarRecorridoTSP planifica la visita a todas las cuevas alcanzables desde
+// el origen por caminos mínimos, con el modo de planificación indicado
+func (ts *TraversalService) RealizarRecorridoTSP(grafo *domain.Grafo, cuevaOrigen string, modo algorithms.ModoTSP) (*RecorridoResultado, error) {
+	if grafo == nil {
+		return nil, fmt.Errorf("el grafo no puede ser nulo")
+	}
+
+	if _, existe := grafo.ObtenerCueva(cuevaOrigen); !existe {
+		return nil, fmt.Errorf("cueva origen '%s' no existe en el grafo", cuevaOrigen)
+	}
+
+	plan, err := algorithms.PlanificarRecorridoTSP(grafo, cuevaOrigen, nil, modo, false)
+	if err != nil {
+		return nil, err
+	}
+
+	ordenVisita := make([]int, len(plan.Orden))
+	for i := range ordenVisita {
+		ordenVisita[i] = i + 1
+	}
+
+	return &RecorridoResultado{
+		TipoRecorrido:  TSP,
+		CuevasVisitas:  plan.Orden,
+		OrdenVisita:    ordenVisita,
+		DistanciaTotal: plan.Costo,
+		CuevaOrigen:    cuevaOrigen,
+		Completado:     len(plan.Inalcanzables) == 0,
+		Tramos:         plan.Tramos,
+	}, nil
+}
+
+// BuscarCamino busca el camino más corto entre dos cuevas con el algoritmo indicado
+func (ts *TraversalService) BuscarCamino(grafo *domain.Grafo, desde, hasta string, algoritmo algorithms.AlgoritmoCamino) (*algorithms.ResultadoCamino, error) {
+	if grafo == nil {
+		return nil, fmt.Errorf("grafo no puede ser nil")
+	}
+	return algorithms.BuscarCamino(grafo, algoritmo, desde, hasta)
+}
+
+// KCaminosMasCortos busca hasta k rutas alternativas sin ciclos entre dos cuevas, ordenadas por distancia
+func (ts *TraversalService) KCaminosMasCortos(grafo *domain.Grafo, desde, hasta string, k int) ([]*algorithms.ResultadoCamino, error) {
+	if grafo == nil {
+		return nil, fmt.Errorf("grafo no puede ser nil")
+	}
+	return algorithms.KCaminosMasCortos(grafo, desde, hasta, k)
+}
+
 // ObtenerCuevasAccesibles obtiene todas las cuevas accesibles desde una cueva origen
 func (ts *TraversalService) ObtenerCuevasAccesibles(grafo *domain.Grafo, cuevaOrigen string) ([]string, error) {
 	resultado, err := ts.RealizarRecorridoDFS(grafo, cuevaOrigen)
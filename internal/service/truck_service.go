@@ -3,6 +3,8 @@ package service
 import (
 	"fmt"
 	"proyecto-grafos-go/internal/domain"
+	"proyecto-grafos-go/pkg/algorithms"
+	"sort"
 	"time"
 )
 
@@ -27,6 +29,18 @@ const (
 	Interrumpido EstadoCamion = "INTERRUMPIDO"
 )
 
+// PrioridadEntrega define en qué orden se atienden las demandas de las cuevas
+// cuando la carga no alcanza para todas
+type PrioridadEntrega string
+
+const (
+	PrioridadMayorDeficit    PrioridadEntrega = "MAYOR_DEFICIT"    // Primero a quien más le falta
+	PrioridadCriticosPrimero PrioridadEntrega = "CRITICOS_PRIMERO" // Primero los recursos críticos, luego por déficit
+)
+
+// PrioridadesEntrega lista las reglas disponibles en el orden en que se ofrecen
+var PrioridadesEntrega = []PrioridadEntrega{PrioridadMayorDeficit, PrioridadCriticosPrimero}
+
 // Camion representa un camión de entrega
 type Camion struct {
 	ID                 string         `json:"id"`
@@ -53,6 +67,19 @@ type SimulacionResultado struct {
 	Exitoso             bool                      `json:"exitoso"`
 	Errores             []string                  `json:"errores"`
 	EstadisticasEntrega map[string]interface{}    `json:"estadisticas_entrega"`
+	DemandaInsatisfecha []DemandaInsatisfecha     `json:"demanda_insatisfecha"`        // Déficit que queda en cada cueva tras la entrega
+	FaltanteDeposito    map[string]int            `json:"faltante_deposito,omitempty"` // Lo pedido que el depósito no tenía
+	Eventos             []EventoSimulacion        `json:"eventos"`                     // Registro con el reloj virtual
+}
+
+// DemandaInsatisfecha es la parte de la demanda de un recurso que una cueva no recibió
+type DemandaInsatisfecha struct {
+	CuevaID  string `json:"cueva_id"`
+	Recurso  string `json:"recurso"`
+	Demanda  int    `json:"demanda"`
+	Stock    int    `json:"stock"`
+	Faltante int    `json:"faltante"`
+	Visitada bool   `json:"visitada"` // La cueva estaba en la ruta del camión
 }
 
 // TruckService proporciona funcionalidades para la simulación de camiones
@@ -60,6 +87,26 @@ type TruckService struct {
 	traversalService *TraversalService
 	graphService     *ServicioGrafo
 	camiones         map[string]*Camion
+	algoritmoCamino  algorithms.AlgoritmoCamino // Para los trayectos entre cuevas no adyacentes
+	modoTSP          algorithms.ModoTSP         // Para planificar los recorridos TSP
+	prioridadEntrega PrioridadEntrega           // Para repartir la carga según la demanda
+	recursosCriticos []string                   // Recursos que se atienden primero, en orden de importancia
+	reproduccion     Reproduccion               // Cómo avanza el reloj virtual de las simulaciones
+	jornada          time.Duration              // Tiempo en que cada camión debe estar de vuelta en el depósito
+}
+
+// TiempoDescargaPorCueva es lo que tarda un camión en descargar en una cueva
+const TiempoDescargaPorCueva = 10 * time.Minute
+
+// JornadaPredeterminada es la duración de la jornada de los camiones
+const JornadaPredeterminada = 8 * time.Hour
+
+// duracionViaje convierte una distancia en km a tiempo según la velocidad en km/h
+func duracionViaje(distancia, velocidad float64) time.Duration {
+	if distancia <= 0 || velocidad <= 0 {
+		return 0
+	}
+	return time.Duration(distancia / velocidad * float64(time.Hour))
 }
 
 // NuevoTruckService crea una nueva instancia del servicio de camiones
@@ -68,7 +115,113 @@ func NuevoTruckService(traversalService *TraversalService, graphService *Servici
 		traversalService: traversalService,
 		graphService:     graphService,
 		camiones:         make(map[string]*Camion),
+		algoritmoCamino:  algorithms.CaminoDijkstra,
+		modoTSP:          algorithms.TSPMejorado,
+		prioridadEntrega: PrioridadMayorDeficit,
+		recursosCriticos: []string{},
+		reproduccion:     Reproduccion{Modo: ReproduccionRapida},
+		jornada:          JornadaPredeterminada,
+	}
+}
+
+// EstablecerAlgoritmoCamino elige el algoritmo con que se calculan los trayectos entre cuevas no adyacentes
+func (ts *TruckService) EstablecerAlgoritmoCamino(algoritmo algorithms.AlgoritmoCamino) error {
+	for _, disponible := range algorithms.AlgoritmosCamino {
+		if algoritmo == disponible {
+			ts.algoritmoCamino = algoritmo
+			return nil
+		}
 	}
+	return fmt.Errorf("algoritmo de camino no válido: %s", algoritmo)
+}
+
+// ObtenerAlgoritmoCamino devuelve el algoritmo usado para los trayectos
+func (ts *TruckService) ObtenerAlgoritmoCamino() algorithms.AlgoritmoCamino {
+	return ts.algoritmoCamino
+}
+
+// EstablecerModoTSP elige cómo se planifican los recorridos TSP
+func (ts *TruckService) EstablecerModoTSP(modo algorithms.ModoTSP) error {
+	for _, disponible := range algorithms.ModosTSP {
+		if modo == disponible {
+			ts.modoTSP = modo
+			return nil
+		}
+	}
+	return fmt.Errorf("modo de recorrido TSP no válido: %s", modo)
+}
+
+// ObtenerModoTSP devuelve el modo usado para los recorridos TSP
+func (ts *TruckService) ObtenerModoTSP() algorithms.ModoTSP {
+	return ts.modoTSP
+}
+
+// EstablecerPrioridadEntrega elige la regla con que se reparte la carga entre las demandas
+func (ts *TruckService) EstablecerPrioridadEntrega(prioridad PrioridadEntrega) error {
+	for _, disponible := range PrioridadesEntrega {
+		if prioridad == disponible {
+			ts.prioridadEntrega = prioridad
+			return nil
+		}
+	}
+	return fmt.Errorf("prioridad de entrega no válida: %s", prioridad)
+}
+
+// ObtenerPrioridadEntrega devuelve la regla usada para repartir la carga
+func (ts *TruckService) ObtenerPrioridadEntrega() PrioridadEntrega {
+	return ts.prioridadEntrega
+}
+
+// EstablecerRecursosCriticos define los recursos que la regla de críticos atiende primero
+func (ts *TruckService) EstablecerRecursosCriticos(recursos []string) {
+	ts.recursosCriticos = make([]string, 0, len(recursos))
+	incluidos := make(map[string]bool)
+	for _, recurso := range recursos {
+		if recurso != "" && !incluidos[recurso] {
+			incluidos[recurso] = true
+			ts.recursosCriticos = append(ts.recursosCriticos, recurso)
+		}
+	}
+}
+
+// ObtenerRecursosCriticos devuelve los recursos críticos en orden de importancia
+func (ts *TruckService) ObtenerRecursosCriticos() []string {
+	return ts.recursosCriticos
+}
+
+// EstablecerReproduccion elige cómo se reproducen las simulaciones; el registro
+// de eventos no depende del modo
+func (ts *TruckService) EstablecerReproduccion(reproduccion Reproduccion) error {
+	for _, disponible := range ModosReproduccion {
+		if reproduccion.Modo == disponible {
+			if reproduccion.Modo == ReproduccionEscalada && reproduccion.Escala <= 0 {
+				reproduccion.Escala = EscalaPredeterminada
+			}
+			ts.reproduccion = reproduccion
+			return nil
+		}
+	}
+	return fmt.Errorf("modo de reproducción no válido: %s", reproduccion.Modo)
+}
+
+// ObtenerReproduccion devuelve la configuración de reproducción actual
+func (ts *TruckService) ObtenerReproduccion() Reproduccion {
+	return ts.reproduccion
+}
+
+// EstablecerJornada define cuánto dura la jornada de los camiones; ninguno sale
+// hacia una cueva si no alcanza a descargar y volver al depósito antes del final
+func (ts *TruckService) EstablecerJornada(jornada time.Duration) error {
+	if jornada <= 0 {
+		return fmt.Errorf("la jornada debe ser mayor que cero")
+	}
+	ts.jornada = jornada
+	return nil
+}
+
+// ObtenerJornada devuelve la duración de la jornada de los camiones
+func (ts *TruckService) ObtenerJornada() time.Duration {
+	return ts.jornada
 }
 
 // CrearCamion crea un nuevo camión con especificaciones dadas
@@ -144,6 +297,157 @@ func (ts *TruckService) CargarInsumos(camionID string, insumos map[string]int) e
 	return nil
 }
 
+// CargarSegunDemanda carga el camión con lo que les falta a las cuevas del grafo.
+// Si la capacidad no alcanza, la regla de prioridad decide qué déficits se
+// cargan primero. Devuelve lo cargado.
+func (ts *TruckService) CargarSegunDemanda(grafo *domain.Grafo, camionID string) (map[string]int, error) {
+	camion, existe := ts.camiones[camionID]
+	if !existe {
+		return nil, fmt.Errorf("camión '%s' no encontrado", camionID)
+	}
+
+	ids := make([]string, 0, len(grafo.Cuevas))
+	for id := range grafo.Cuevas {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	insumos := ts.cargaSegunDemanda(grafo, camion, ids, nil)
+	if len(insumos) == 0 {
+		return insumos, nil
+	}
+	if err := ts.CargarInsumos(camionID, insumos); err != nil {
+		return nil, err
+	}
+	return insumos, nil
+}
+
+// cargaSegunDemanda calcula qué sumar a la carga del camión para cubrir el
+// déficit de las cuevas en el orden de la regla de prioridad, sin pasar su
+// capacidad. Lo que ya lleva cubre primero los pedidos de ese recurso. Con
+// depósito no se carga más que su stock; sin él el stock es ilimitado.
+func (ts *TruckService) cargaSegunDemanda(grafo *domain.Grafo, camion *Camion, cuevas []string, deposito *domain.Cueva) map[string]int {
+	libre := camion.CapacidadMaxima
+	disponible := make(map[string]int, len(camion.CargaActual))
+	for recurso, cantidad := range camion.CargaActual {
+		libre -= cantidad
+		disponible[recurso] = cantidad
+	}
+
+	insumos := make(map[string]int)
+	for _, pedido := range ts.pedidosDemanda(grafo, cuevas) {
+		cubierto := min(pedido.deficit, disponible[pedido.recurso])
+		disponible[pedido.recurso] -= cubierto
+		cantidad := min(pedido.deficit-cubierto, libre)
+		if deposito != nil {
+			cantidad = min(cantidad, deposito.ObtenerRecurso(pedido.recurso)-insumos[pedido.recurso])
+		}
+		if cantidad > 0 {
+			insumos[pedido.recurso] += cantidad
+			libre -= cantidad
+		}
+	}
+	return insumos
+}
+
+// recargarEnDeposito pasa del stock del depósito al camión lo que les falta a
+// las cuevas que le quedan por visitar. Los recursos que ninguna de ellas
+// demanda se reponen hasta la carga original. Devuelve lo cargado.
+func (ts *TruckService) recargarEnDeposito(grafo *domain.Grafo, deposito *domain.Cueva, camion *Camion, restantes []string, original map[string]int) map[string]int {
+	carga := ts.cargaSegunDemanda(grafo, camion, restantes, deposito)
+	libre := camion.CapacidadMaxima - cargaTotal(camion)
+	for _, cantidad := range carga {
+		libre -= cantidad
+	}
+
+	demandados := make(map[string]bool)
+	for _, id := range restantes {
+		if cueva, existe := grafo.ObtenerCueva(id); existe {
+			for recurso := range cueva.Demanda {
+				demandados[recurso] = true
+			}
+		}
+	}
+	recursos := make([]string, 0, len(original))
+	for recurso := range original {
+		recursos = append(recursos, recurso)
+	}
+	sort.Strings(recursos)
+	for _, recurso := range recursos {
+		if demandados[recurso] {
+			continue
+		}
+		cantidad := min(original[recurso]-camion.CargaActual[recurso], libre, deposito.ObtenerRecurso(recurso))
+		if cantidad > 0 {
+			carga[recurso] = cantidad
+			libre -= cantidad
+		}
+	}
+
+	for recurso, cantidad := range carga {
+		deposito.AgregarRecurso(recurso, deposito.ObtenerRecurso(recurso)-cantidad)
+		camion.CargaActual[recurso] += cantidad
+	}
+	return carga
+}
+
+// cargarDesdeDeposito ajusta la carga pedida del camión a lo que el depósito
+// tiene y lo descuenta de su stock. Lo que no alcanzó se suma a faltante.
+// Devuelve lo cargado y una descripción si el depósito no tenía todo.
+func cargarDesdeDeposito(deposito *domain.Cueva, camion *Camion, faltante map[string]int) (map[string]int, string) {
+	recursos := make([]string, 0, len(camion.CargaActual))
+	for recurso := range camion.CargaActual {
+		recursos = append(recursos, recurso)
+	}
+	sort.Strings(recursos)
+
+	carga := make(map[string]int)
+	descripcion := ""
+	for _, recurso := range recursos {
+		pedido := camion.CargaActual[recurso]
+		cargado := min(pedido, deposito.ObtenerRecurso(recurso))
+		if cargado < pedido {
+			faltante[recurso] += pedido - cargado
+			descripcion = "El depósito no alcanzó para todo lo pedido"
+		}
+		if cargado > 0 {
+			deposito.AgregarRecurso(recurso, deposito.ObtenerRecurso(recurso)-cargado)
+			carga[recurso] = cargado
+		}
+		camion.CargaActual[recurso] = cargado
+	}
+	return carga, descripcion
+}
+
+// sinCueva devuelve las visitas sin las apariciones de la cueva indicada
+func sinCueva(visitas []string, cuevaID string) []string {
+	resultado := make([]string, 0, len(visitas))
+	for _, visita := range visitas {
+		if visita != cuevaID {
+			resultado = append(resultado, visita)
+		}
+	}
+	return resultado
+}
+
+// cargaTotal suma las unidades que lleva el camión
+func cargaTotal(camion *Camion) int {
+	total := 0
+	for _, cantidad := range camion.CargaActual {
+		total += cantidad
+	}
+	return total
+}
+
+// caminoRegreso busca el camino más corto de vuelta al depósito
+func (ts *TruckService) caminoRegreso(grafo *domain.Grafo, desde, deposito string) (*algorithms.ResultadoCamino, error) {
+	camino, err := ts.traversalService.BuscarCamino(grafo, desde, deposito, ts.algoritmoCamino)
+	if err != nil {
+		return nil, fmt.Errorf("el camión no tiene camino de %s al depósito %s: %w", desde, deposito, err)
+	}
+	return camino, nil
+}
+
 // SimularEntregaDFS simula la entrega de insumos usando recorrido DFS
 func (ts *TruckService) SimularEntregaDFS(grafo *domain.Grafo, camionID string, cuevaOrigen string) (*SimulacionResultado, error) {
 	return ts.simularEntrega(grafo, camionID, cuevaOrigen, DFS)
@@ -154,6 +458,11 @@ func (ts *TruckService) SimularEntregaBFS(grafo *domain.Grafo, camionID string,
 	return ts.simularEntrega(grafo, camionID, cuevaOrigen, BFS)
 }
 
+// SimularEntregaTSP simula la entrega de insumos siguiendo un recorrido TSP por caminos mínimos
+func (ts *TruckService) SimularEntregaTSP(grafo *domain.Grafo, camionID string, cuevaOrigen string) (*SimulacionResultado, error) {
+	return ts.simularEntrega(grafo, camionID, cuevaOrigen, TSP)
+}
+
 // simularEntrega realiza la simulación de entrega con el algoritmo especificado
 func (ts *TruckService) simularEntrega(grafo *domain.Grafo, camionID string, cuevaOrigen string, tipoRecorrido TipoRecorrido) (*SimulacionResultado, error) {
 	camion, existe := ts.camiones[camionID]
@@ -168,7 +477,7 @@ func (ts *TruckService) simularEntrega(grafo *domain.Grafo, camionID string, cue
 
 	// Inicializar simulación
 	camion.Estado = EnTransito
-	camion.TiempoInicio = time.Now()
+	camion.TiempoInicio = EpocaSimulacion
 	camion.CuevaActual = cuevaOrigen
 	camion.DistanciaRecorrida = 0.0
 
@@ -179,6 +488,7 @@ func (ts *TruckService) simularEntrega(grafo *domain.Grafo, camionID string, cue
 		EntregasRealizadas:  make(map[string]map[string]int),
 		Errores:             make([]string, 0),
 		EstadisticasEntrega: make(map[string]interface{}),
+		FaltanteDeposito:    make(map[string]int),
 	}
 
 	// Obtener ruta usando el algoritmo especificado
@@ -190,6 +500,8 @@ func (ts *TruckService) simularEntrega(grafo *domain.Grafo, camionID string, cue
 		recorrido, err = ts.traversalService.RealizarRecorridoDFS(grafo, cuevaOrigen)
 	case BFS:
 		recorrido, err = ts.traversalService.RealizarRecorridoBFS(grafo, cuevaOrigen)
+	case TSP:
+		recorrido, err = ts.traversalService.RealizarRecorridoTSP(grafo, cuevaOrigen, ts.modoTSP)
 	default:
 		return nil, fmt.Errorf("tipo de recorrido no válido: %s", tipoRecorrido)
 	}
@@ -209,74 +521,386 @@ func (ts *TruckService) simularEntrega(grafo *domain.Grafo, camionID string, cue
 		cargaOriginal[recurso] = cantidad
 	}
 
+	// El camión sale con lo que el depósito tiene de lo pedido, como en la flota
+	deposito, _ := grafo.ObtenerCueva(cuevaOrigen)
+	cargaInicial := EventoSimulacion{Tipo: EventoCarga, CamionID: camionID, CuevaID: cuevaOrigen}
+	cargaInicial.Carga, cargaInicial.Descripcion = cargarDesdeDeposito(deposito, camion, resultado.FaltanteDeposito)
+
+	// Repartir de antemano los recursos que alguna cueva de la ruta demanda; el
+	// depósito es el origen del recorrido y no recibe entregas
+	visitas := recorrido.CuevasVisitas
+	paradas := sinCueva(visitas, cuevaOrigen)
+	deficitInicial := deficitTotal(grafo)
+	plan := ts.planificarEntregas(grafo, paradas, camion.CargaActual)
+
+	// Cada visita es una llegada, una descarga y una salida en el reloj virtual
+	motor := NuevoMotorSimulacion()
 	entregasExitosas := 0
-	for _, cuevaID := range recorrido.CuevasVisitas {
-		// Agregar cueva a la ruta
-		distancia := 0.0
-		if len(ruta.CuevaIDs) > 0 {
-			distancia = ts.obtenerDistanciaEntreAristas(grafo, ruta.UltimaCueva(), cuevaID)
+	viajes := 1
+	distanciaRegreso := 0.0
+	ultimaVisita := 0 // Índice de la última cueva atendida
+
+	// tramo es la distancia de la posición actual a la visita i+1; el recorrido
+	// TSP ya trae la de cada tramo, salvo que el camión venga del depósito
+	tramo := func(i int) float64 {
+		if recorrido.Tramos != nil && camion.CuevaActual == visitas[i] {
+			return recorrido.Tramos[i]
+		}
+		return ts.distanciaTrayecto(grafo, camion.CuevaActual, visitas[i+1])
+	}
+
+	// alcanzaJornada indica si el camión puede ir a la visita i+1, descargar y
+	// volver al depósito antes de que termine la jornada
+	alcanzaJornada := func(i int, demora time.Duration) bool {
+		fin := motor.Ahora() + demora + duracionViaje(tramo(i), camion.VelocidadPromedio) + TiempoDescargaPorCueva
+		if camino, err := ts.caminoRegreso(grafo, visitas[i+1], cuevaOrigen); err == nil {
+			fin += duracionViaje(camino.Costo, camion.VelocidadPromedio)
 		}
-		ruta.AgregarCueva(cuevaID, distancia)
+		return fin <= ts.jornada
+	}
+
+	var atender func(i int, distancia float64)
+	var salir func(i int)
+	var regresar func(i int, motivo string)
+
+	// enDeposito recarga para seguir con la visita i+1 o termina la jornada
+	enDeposito := func(i int, recargar bool) {
+		camion.Estado = EnAlmacen
+		if !recargar || i+1 >= len(visitas) || !alcanzaJornada(i, TiempoCargaEnDeposito) {
+			camion.Estado = Completado
+			return
+		}
+		restantes := sinCueva(visitas[i+1:], cuevaOrigen)
+		carga := ts.recargarEnDeposito(grafo, deposito, camion, restantes, cargaOriginal)
+		if len(carga) == 0 {
+			camion.Estado = Completado
+			return
+		}
+		motor.Programar(TiempoCargaEnDeposito, EventoSimulacion{Tipo: EventoCarga, CamionID: camionID, CuevaID: cuevaOrigen, Carga: carga}, func(*EventoSimulacion) {
+			viajes++
+			plan = ts.planificarEntregas(grafo, restantes, camion.CargaActual)
+			salir(i)
+		})
+	}
+
+	salir = func(i int) {
+		switch {
+		case i+1 >= len(visitas):
+			regresar(i, "fin del recorrido")
+			return
+		case cargaTotal(camion) == 0:
+			regresar(i, "camión vacío")
+			return
+		case !alcanzaJornada(i, 0):
+			regresar(i, "fin de la jornada")
+			return
+		}
+		distancia := tramo(i)
+		motor.Programar(0, EventoSimulacion{Tipo: EventoSalida, CamionID: camionID, CuevaID: camion.CuevaActual}, func(evento *EventoSimulacion) {
+			camion.Estado = EnTransito
+			evento.Descripcion = fmt.Sprintf("Hacia %s (%.2f km)", visitas[i+1], distancia)
+			motor.Programar(duracionViaje(distancia, camion.VelocidadPromedio),
+				EventoSimulacion{Tipo: EventoLlegada, CamionID: camionID, CuevaID: visitas[i+1]},
+				func(*EventoSimulacion) { atender(i+1, distancia) })
+		})
+	}
+
+	// regresar lleva al camión al depósito por el camino más corto; si volvió
+	// vacío recarga para las visitas que le quedan
+	regresar = func(i int, motivo string) {
+		recargar := motivo == "camión vacío"
+		if camion.CuevaActual == cuevaOrigen {
+			enDeposito(i, recargar)
+			return
+		}
+		camino, err := ts.caminoRegreso(grafo, camion.CuevaActual, cuevaOrigen)
+		if err != nil {
+			resultado.Errores = append(resultado.Errores, err.Error())
+			camion.Estado = Completado
+			return
+		}
+		motor.Programar(0, EventoSimulacion{Tipo: EventoRegreso, CamionID: camionID, CuevaID: camion.CuevaActual}, func(evento *EventoSimulacion) {
+			camion.Estado = Regresando
+			evento.Descripcion = fmt.Sprintf("Hacia %s por %v (%.2f km): %s", cuevaOrigen, camino.Ruta, camino.Costo, motivo)
+			motor.Programar(duracionViaje(camino.Costo, camion.VelocidadPromedio),
+				EventoSimulacion{Tipo: EventoLlegada, CamionID: camionID, CuevaID: cuevaOrigen},
+				func(*EventoSimulacion) {
+					ts.agregarTrayecto(grafo, ruta, cuevaOrigen, camino.Costo)
+					camion.CuevaActual = cuevaOrigen
+					camion.DistanciaRecorrida += camino.Costo
+					distanciaRegreso += camino.Costo
+					enDeposito(i, recargar)
+				})
+		})
+	}
+
+	atender = func(i int, distancia float64) {
+		cuevaID := visitas[i]
+		if i == 0 {
+			ruta.AgregarCueva(cuevaID, distancia)
+		} else {
+			ts.agregarTrayecto(grafo, ruta, cuevaID, distancia)
+		}
+		ultimaVisita = i
 
 		// Actualizar posición del camión
 		camion.CuevaActual = cuevaID
 		camion.DistanciaRecorrida += distancia
 
+		// En el depósito no se descarga: la carga es para las demás cuevas
+		if cuevaID == cuevaOrigen {
+			salir(i)
+			return
+		}
+
 		// Obtener cueva para verificar necesidades
 		cueva, existe := grafo.ObtenerCueva(cuevaID)
 		if !existe {
 			resultado.Errores = append(resultado.Errores, fmt.Sprintf("Cueva '%s' no encontrada", cuevaID))
-			continue
+			salir(i)
+			return
 		}
 
-		// Simular entrega de insumos
+		// Determinar qué entregar basándose en las necesidades de la cueva
 		camion.Estado = Entregando
 		entregaEnCueva := make(map[string]int)
-
-		// Determinar qué entregar basándose en las necesidades de la cueva
+		paradasRestantes := len(sinCueva(visitas[i:], cuevaOrigen))
 		for recurso, cantidadDisponible := range camion.CargaActual {
 			if cantidadDisponible > 0 {
-				// Simplificación: entregar cantidad proporcional
-				cantidadAEntregar := cantidadDisponible / (len(recorrido.CuevasVisitas) - entregasExitosas)
+				var cantidadAEntregar int
+				if plan.conDemanda[recurso] {
+					cantidadAEntregar = min(plan.asignado[cuevaID][recurso], cantidadDisponible)
+				} else {
+					// Sin demanda declarada: entregar cantidad proporcional entre las paradas que faltan
+					cantidadAEntregar = cantidadDisponible / paradasRestantes
+				}
 				if cantidadAEntregar > 0 {
 					entregaEnCueva[recurso] = cantidadAEntregar
-					camion.CargaActual[recurso] -= cantidadAEntregar
-
-					// Actualizar recursos de la cueva
-					cueva.AgregarRecurso(recurso, cueva.ObtenerRecurso(recurso)+cantidadAEntregar)
 				}
 			}
 		}
-
-		resultado.EntregasRealizadas[cuevaID] = entregaEnCueva
 		entregasExitosas++
 
-		// Simular tiempo de entrega (basado en distancia y velocidad)
-		if distancia > 0 {
-			tiempoViaje := time.Duration(distancia/camion.VelocidadPromedio*3600) * time.Second
-			time.Sleep(tiempoViaje / 1000) // Simulación acelerada
+		// La descarga lleva tiempo solo si hay algo que descargar
+		duracion := time.Duration(0)
+		if len(entregaEnCueva) > 0 {
+			duracion = TiempoDescargaPorCueva
 		}
+		motor.Programar(duracion, EventoSimulacion{Tipo: EventoDescarga, CamionID: camionID, CuevaID: cuevaID, Carga: entregaEnCueva}, func(*EventoSimulacion) {
+			for recurso, cantidad := range entregaEnCueva {
+				camion.CargaActual[recurso] -= cantidad
+				cueva.AgregarRecurso(recurso, cueva.ObtenerRecurso(recurso)+cantidad)
+			}
+			resultado.EntregasRealizadas[cuevaID] = entregaEnCueva
+			salir(i)
+		})
+	}
+
+	// La carga inicial ya estaba lista al empezar la jornada: no suma tiempo
+	motor.Programar(0, cargaInicial, func(*EventoSimulacion) { atender(0, 0) })
+	if err := motor.Ejecutar(ts.reproduccion); err != nil {
+		return nil, err
 	}
 
 	// Finalizar simulación
-	camion.Estado = Completado
-	camion.TiempoFin = time.Now()
+	camion.TiempoFin = motor.Instante()
 	camion.RutaAsignada = ruta
 
-	resultado.RutaCompleta = recorrido.CuevasVisitas
-	resultado.TiempoTotal = camion.TiempoFin.Sub(camion.TiempoInicio)
+	resultado.RutaCompleta = ruta.CuevaIDs
+	resultado.TiempoTotal = motor.Ahora()
+	resultado.Eventos = motor.Registro()
 	resultado.DistanciaTotal = camion.DistanciaRecorrida
 	resultado.Exitoso = len(resultado.Errores) == 0 && entregasExitosas > 0
+	resultado.DemandaInsatisfecha = demandaInsatisfecha(grafo, ruta.CuevaIDs)
 
 	// Generar estadísticas
 	resultado.EstadisticasEntrega["entregas_exitosas"] = entregasExitosas
 	resultado.EstadisticasEntrega["carga_original"] = cargaOriginal
 	resultado.EstadisticasEntrega["carga_restante"] = camion.CargaActual
-	resultado.EstadisticasEntrega["eficiencia_entrega"] = float64(entregasExitosas) / float64(len(recorrido.CuevasVisitas)) * 100
+	resultado.EstadisticasEntrega["algoritmo_trayectos"] = ts.algoritmoCamino
+	resultado.EstadisticasEntrega["viajes"] = viajes
+	resultado.EstadisticasEntrega["distancia_regreso"] = distanciaRegreso
+	if sinVisitar := len(visitas) - 1 - ultimaVisita; sinVisitar > 0 {
+		resultado.EstadisticasEntrega["cuevas_sin_visitar"] = sinVisitar
+	}
+	if tipoRecorrido == TSP {
+		resultado.EstadisticasEntrega["modo_tsp"] = ts.modoTSP
+	}
+	if len(paradas) > 0 {
+		resultado.EstadisticasEntrega["eficiencia_entrega"] = float64(entregasExitosas) / float64(len(paradas)) * 100
+	}
+	if deficitInicial > 0 {
+		cubierta := deficitInicial - deficitTotal(grafo)
+		resultado.EstadisticasEntrega["prioridad_entrega"] = ts.prioridadEntrega
+		resultado.EstadisticasEntrega["demanda_cubierta"] = cubierta
+		resultado.EstadisticasEntrega["porcentaje_demanda_cubierta"] = float64(cubierta) / float64(deficitInicial) * 100
+	}
 
 	return resultado, nil
 }
 
+// planEntrega indica cuánto de cada recurso demandado recibe cada cueva de la ruta
+type planEntrega struct {
+	asignado   map[string]map[string]int // cueva_id -> recurso -> cantidad
+	conDemanda map[string]bool           // Recursos que alguna cueva de la ruta demanda
+}
+
+// pedidoEntrega es el déficit de un recurso en una cueva de la ruta
+type pedidoEntrega struct {
+	cuevaID  string
+	recurso  string
+	deficit  int
+	posicion int // Orden de visita de la cueva
+	critico  int // Posición en los recursos críticos; len(críticos) si no lo es
+}
+
+// pedidosDemanda lista los déficits de las cuevas indicadas, en el orden en que
+// los atiende la regla de prioridad. Los empates se resuelven por déficit,
+// orden de visita y nombre del recurso.
+func (ts *TruckService) pedidosDemanda(grafo *domain.Grafo, visitas []string) []pedidoEntrega {
+	rangoCritico := make(map[string]int)
+	if ts.prioridadEntrega == PrioridadCriticosPrimero {
+		for i, recurso := range ts.recursosCriticos {
+			rangoCritico[recurso] = i
+		}
+	}
+
+	pedidos := make([]pedidoEntrega, 0)
+	vistas := make(map[string]bool)
+	for posicion, cuevaID := range visitas {
+		cueva, existe := grafo.ObtenerCueva(cuevaID)
+		if !existe || vistas[cuevaID] {
+			continue
+		}
+		vistas[cuevaID] = true
+		for recurso := range cueva.Demanda {
+			if deficit := cueva.Deficit(recurso); deficit > 0 {
+				critico, ok := rangoCritico[recurso]
+				if !ok {
+					critico = len(rangoCritico)
+				}
+				pedidos = append(pedidos, pedidoEntrega{cuevaID, recurso, deficit, posicion, critico})
+			}
+		}
+	}
+
+	sort.Slice(pedidos, func(i, j int) bool {
+		a, b := pedidos[i], pedidos[j]
+		if a.critico != b.critico {
+			return a.critico < b.critico
+		}
+		if a.deficit != b.deficit {
+			return a.deficit > b.deficit
+		}
+		if a.posicion != b.posicion {
+			return a.posicion < b.posicion
+		}
+		return a.recurso < b.recurso
+	})
+	return pedidos
+}
+
+// planificarEntregas reparte la carga entre los déficits de las cuevas de la
+// ruta: cada pedido, en orden de prioridad, recibe lo que le falta o lo que
+// quede del recurso
+func (ts *TruckService) planificarEntregas(grafo *domain.Grafo, visitas []string, carga map[string]int) *planEntrega {
+	plan := &planEntrega{asignado: make(map[string]map[string]int), conDemanda: make(map[string]bool)}
+	for _, cuevaID := range visitas {
+		if cueva, existe := grafo.ObtenerCueva(cuevaID); existe {
+			for recurso := range cueva.Demanda {
+				plan.conDemanda[recurso] = true
+			}
+		}
+	}
+
+	restante := make(map[string]int, len(carga))
+	for recurso, cantidad := range carga {
+		restante[recurso] = cantidad
+	}
+	for _, pedido := range ts.pedidosDemanda(grafo, visitas) {
+		cantidad := min(pedido.deficit, restante[pedido.recurso])
+		if cantidad <= 0 {
+			continue
+		}
+		if plan.asignado[pedido.cuevaID] == nil {
+			plan.asignado[pedido.cuevaID] = make(map[string]int)
+		}
+		plan.asignado[pedido.cuevaID][pedido.recurso] = cantidad
+		restante[pedido.recurso] -= cantidad
+	}
+	return plan
+}
+
+// demandaInsatisfecha lista, ordenado por cueva y recurso, el déficit que queda en el grafo
+func demandaInsatisfecha(grafo *domain.Grafo, visitas []string) []DemandaInsatisfecha {
+	visitada := make(map[string]bool, len(visitas))
+	for _, id := range visitas {
+		visitada[id] = true
+	}
+
+	faltantes := make([]DemandaInsatisfecha, 0)
+	for id, cueva := range grafo.Cuevas {
+		for recurso, demanda := range cueva.Demanda {
+			if faltante := cueva.Deficit(recurso); faltante > 0 {
+				faltantes = append(faltantes, DemandaInsatisfecha{
+					CuevaID:  id,
+					Recurso:  recurso,
+					Demanda:  demanda,
+					Stock:    cueva.ObtenerRecurso(recurso),
+					Faltante: faltante,
+					Visitada: visitada[id],
+				})
+			}
+		}
+	}
+	sort.Slice(faltantes, func(i, j int) bool {
+		if faltantes[i].CuevaID != faltantes[j].CuevaID {
+			return faltantes[i].CuevaID < faltantes[j].CuevaID
+		}
+		return faltantes[i].Recurso < faltantes[j].Recurso
+	})
+	return faltantes
+}
+
+// deficitTotal suma lo que les falta a todas las cuevas para cubrir su demanda
+func deficitTotal(grafo *domain.Grafo) int {
+	total := 0
+	for _, cueva := range grafo.Cuevas {
+		for recurso := range cueva.Demanda {
+			total += cueva.Deficit(recurso)
+		}
+	}
+	return total
+}
+
+// distanciaTrayecto usa el túnel directo entre dos cuevas consecutivas del
+// recorrido o, si no son adyacentes, el camino más corto del algoritmo elegido;
+// sin camino devuelve 0 como antes
+func (ts *TruckService) distanciaTrayecto(grafo *domain.Grafo, desde, hasta string) float64 {
+	if grafo.ExisteConexion(desde, hasta) || (!grafo.EsDirigido && grafo.ExisteConexion(hasta, desde)) {
+		return ts.obtenerDistanciaEntreAristas(grafo, desde, hasta)
+	}
+
+	camino, err := ts.traversalService.BuscarCamino(grafo, desde, hasta, ts.algoritmoCamino)
+	if err != nil {
+		return 0.0
+	}
+	return camino.Costo
+}
+
+// agregarTrayecto extiende la ruta hasta destino como lo mide distanciaTrayecto:
+// por el túnel directo si existe y si no con cada cueva del camino más corto
+func (ts *TruckService) agregarTrayecto(grafo *domain.Grafo, ruta *domain.Ruta, destino string, distancia float64) {
+	desde := ruta.UltimaCueva()
+	if grafo.ExisteConexion(desde, destino) || (!grafo.EsDirigido && grafo.ExisteConexion(destino, desde)) {
+		ruta.AgregarCueva(destino, distancia)
+		return
+	}
+	if err := ts.agregarTramo(grafo, ruta, destino); err != nil {
+		ruta.AgregarCueva(destino, distancia)
+	}
+}
+
 // obtenerDistanciaEntreAristas obtiene la distancia entre dos cuevas conectadas
 func (ts *TruckService) obtenerDistanciaEntreAristas(grafo *domain.Grafo, desde, hasta string) float64 {
 	for _, arista := range grafo.Aristas {
@@ -365,6 +989,29 @@ func (ts *TruckService) GenerarReporteSimulacion(resultado *SimulacionResultado)
 		}
 	}
 
+	for recurso, cantidad := range resultado.FaltanteDeposito {
+		reporte += fmt.Sprintf("Faltaron %d de %s en el depósito para completar la carga\n", cantidad, recurso)
+	}
+
+	if len(resultado.DemandaInsatisfecha) > 0 {
+		reporte += "\n--- DEMANDA INSATISFECHA ---\n"
+		for _, faltante := range resultado.DemandaInsatisfecha {
+			nota := ""
+			if !faltante.Visitada {
+				nota = " (no visitada)"
+			}
+			reporte += fmt.Sprintf("Cueva %s - %s: faltan %d (demanda %d, stock %d)%s\n",
+				faltante.CuevaID, faltante.Recurso, faltante.Faltante, faltante.Demanda, faltante.Stock, nota)
+		}
+	}
+
+	if len(resultado.Eventos) > 0 {
+		reporte += "\n--- REGISTRO DE EVENTOS ---\n"
+		for _, evento := range resultado.Eventos {
+			reporte += FormatearEvento(evento) + "\n"
+		}
+	}
+
 	reporte += "\n--- ESTADÍSTICAS ---\n"
 	for clave, valor := range resultado.EstadisticasEntrega {
 		reporte += fmt.Sprintf("%s: %v\n", clave, valor)
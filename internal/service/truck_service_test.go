@@ -0,0 +1,175 @@
+package service
+
+import (
+	"proyecto-grafos-go/internal/domain"
+	"reflect"
+	"testing"
+)
+
+// crearRedDemanda arma la línea ALM - B - C - D con demanda de agua y medicina
+func crearRedDemanda() *domain.Grafo {
+	grafo := domain.NuevoGrafo(false)
+	for _, id := range []string{"ALM", "B", "C", "D"} {
+		grafo.AgregarCueva(domain.NuevaCueva(id, id))
+	}
+	grafo.AgregarConexion("ALM", "B", 1)
+	grafo.AgregarConexion("B", "C", 1)
+	grafo.AgregarConexion("C", "D", 1)
+
+	grafo.Cuevas["B"].EstablecerDemanda("agua", 50)
+	grafo.Cuevas["C"].AgregarRecurso("agua", 10)
+	grafo.Cuevas["C"].EstablecerDemanda("agua", 55)
+	grafo.Cuevas["D"].EstablecerDemanda("medicina", 40)
+	return grafo
+}
+
+func nuevoTruckServicePrueba(grafo *domain.Grafo) *TruckService {
+	servicioGrafo := NuevoServicioGrafo(grafo, nil)
+	return NuevoTruckService(NuevoTraversalService(servicioGrafo), servicioGrafo)
+}
+
+func TestCargarSegunDemandaPrioridad(t *testing.T) {
+	// Faltan 50 + 45 de agua y 40 de medicina para un camión de 100 unidades
+	casos := []struct {
+		prioridad PrioridadEntrega
+		criticos  []string
+		esperado  map[string]int
+	}{
+		{PrioridadMayorDeficit, nil, map[string]int{"agua": 95, "medicina": 5}},
+		{PrioridadCriticosPrimero, []string{"medicina"}, map[string]int{"agua": 60, "medicina": 40}},
+	}
+	for _, caso := range casos {
+		grafo := crearRedDemanda()
+		ts := nuevoTruckServicePrueba(grafo)
+		if err := ts.EstablecerPrioridadEntrega(caso.prioridad); err != nil {
+			t.Fatalf("Error al elegir prioridad: %v", err)
+		}
+		ts.EstablecerRecursosCriticos(caso.criticos)
+		if _, err := ts.CrearCamion("T1", CamionPequeno, "ALM"); err != nil {
+			t.Fatalf("Error al crear camión: %v", err)
+		}
+
+		cargados, err := ts.CargarSegunDemanda(grafo, "T1")
+		if err != nil {
+			t.Fatalf("%s: error al cargar: %v", caso.prioridad, err)
+		}
+		if !reflect.DeepEqual(cargados, caso.esperado) {
+			t.Errorf("%s: se cargó %v, se esperaba %v", caso.prioridad, cargados, caso.esperado)
+		}
+	}
+
+	ts := nuevoTruckServicePrueba(crearRedDemanda())
+	if err := ts.EstablecerPrioridadEntrega("otra"); err == nil {
+		t.Error("Se esperaba un error por prioridad no válida")
+	}
+}
+
+func TestSimularEntregaSegunDemanda(t *testing.T) {
+	grafo := crearRedDemanda()
+	grafo.Cuevas["ALM"].AgregarRecurso("agua", 60)
+	grafo.Cuevas["ALM"].AgregarRecurso("medicina", 30)
+	grafo.Cuevas["ALM"].AgregarRecurso("carbon", 8)
+	ts := nuevoTruckServicePrueba(grafo)
+	ts.EstablecerRecursosCriticos([]string{"medicina"})
+	if _, err := ts.CrearCamion("T1", CamionPequeno, "ALM"); err != nil {
+		t.Fatalf("Error al crear camión: %v", err)
+	}
+	// El carbón no lo demanda nadie y se reparte entre las paradas; el depósito no recibe nada
+	if err := ts.CargarInsumos("T1", map[string]int{"agua": 60, "medicina": 30, "carbon": 8}); err != nil {
+		t.Fatalf("Error al cargar: %v", err)
+	}
+
+	resultado, err := ts.SimularEntregaBFS(grafo, "T1", "ALM")
+	if err != nil {
+		t.Fatalf("Error en simulación: %v", err)
+	}
+
+	// Mayor déficit primero: B (50) antes que C (45) con 60 de agua
+	esperado := map[string]map[string]int{
+		"B": {"agua": 50, "carbon": 2},
+		"C": {"agua": 10, "carbon": 3},
+		"D": {"medicina": 30, "carbon": 3},
+	}
+	if !reflect.DeepEqual(resultado.EntregasRealizadas, esperado) {
+		t.Errorf("Entregas inesperadas: %v", resultado.EntregasRealizadas)
+	}
+
+	faltantes := []DemandaInsatisfecha{
+		{CuevaID: "C", Recurso: "agua", Demanda: 55, Stock: 20, Faltante: 35, Visitada: true},
+		{CuevaID: "D", Recurso: "medicina", Demanda: 40, Stock: 30, Faltante: 10, Visitada: true},
+	}
+	if !reflect.DeepEqual(resultado.DemandaInsatisfecha, faltantes) {
+		t.Errorf("Demanda insatisfecha inesperada: %+v", resultado.DemandaInsatisfecha)
+	}
+	if cubierta := resultado.EstadisticasEntrega["demanda_cubierta"]; cubierta != 90 {
+		t.Errorf("Se esperaban 90 unidades de demanda cubierta, se obtuvo %v", cubierta)
+	}
+}
+
+func TestSimularEntregaDescuentaCargaDelDeposito(t *testing.T) {
+	// Se piden 100 de agua y el depósito tiene 70: en los dos modos el camión
+	// sale con 70, el depósito queda vacío y se informan 30 faltantes
+	simulaciones := map[string]func(ts *TruckService, grafo *domain.Grafo) (map[string]map[string]int, map[string]int, error){
+		"un camión": func(ts *TruckService, grafo *domain.Grafo) (map[string]map[string]int, map[string]int, error) {
+			resultado, err := ts.SimularEntregaBFS(grafo, "T1", "ALM")
+			if err != nil {
+				return nil, nil, err
+			}
+			return resultado.EntregasRealizadas, resultado.FaltanteDeposito, nil
+		},
+		"flota": func(ts *TruckService, grafo *domain.Grafo) (map[string]map[string]int, map[string]int, error) {
+			resultado, err := ts.SimularFlota(grafo, "ALM", map[string][]string{"T1": {"B", "C", "D"}})
+			if err != nil {
+				return nil, nil, err
+			}
+			return resultado.Camiones[0].Entregas, resultado.FaltanteDeposito, nil
+		},
+	}
+
+	for modo, simular := range simulaciones {
+		grafo := crearRedDemanda()
+		grafo.Cuevas["ALM"].AgregarRecurso("agua", 70)
+		ts := nuevoTruckServicePrueba(grafo)
+		if _, err := ts.CrearCamion("T1", CamionPequeno, "ALM"); err != nil {
+			t.Fatalf("Error al crear camión: %v", err)
+		}
+		if err := ts.CargarInsumos("T1", map[string]int{"agua": 100}); err != nil {
+			t.Fatalf("Error al cargar: %v", err)
+		}
+
+		entregas, faltante, err := simular(ts, grafo)
+		if err != nil {
+			t.Fatalf("%s: error en simulación: %v", modo, err)
+		}
+		entregado := 0
+		for _, entrega := range entregas {
+			entregado += entrega["agua"]
+		}
+		if entregado != 70 || grafo.Cuevas["ALM"].ObtenerRecurso("agua") != 0 || !reflect.DeepEqual(faltante, map[string]int{"agua": 30}) {
+			t.Errorf("%s: entregado %d, stock %d y faltante %v inesperados", modo, entregado, grafo.Cuevas["ALM"].ObtenerRecurso("agua"), faltante)
+		}
+	}
+}
+
+func TestSimularEntregaRutaCompleta(t *testing.T) {
+	// El BFS desde B visita ALM y luego C, que no es vecina de ALM: la ruta
+	// debe pasar de nuevo por B, igual que al volver de D al depósito
+	grafo := crearRedDemanda()
+	grafo.Cuevas["B"].AgregarRecurso("carbon", 3)
+	ts := nuevoTruckServicePrueba(grafo)
+	if _, err := ts.CrearCamion("T1", CamionPequeno, "B"); err != nil {
+		t.Fatalf("Error al crear camión: %v", err)
+	}
+	if err := ts.CargarInsumos("T1", map[string]int{"carbon": 3}); err != nil {
+		t.Fatalf("Error al cargar: %v", err)
+	}
+
+	resultado, err := ts.SimularEntregaBFS(grafo, "T1", "B")
+	if err != nil {
+		t.Fatalf("Error en simulación: %v", err)
+	}
+	esperada := []string{"B", "ALM", "B", "C", "D", "C", "B"}
+	if !reflect.DeepEqual(resultado.RutaCompleta, esperada) || resultado.DistanciaTotal != 6 {
+		t.Errorf("Ruta %v de %.2f km, se esperaba %v de 6 km", resultado.RutaCompleta, resultado.DistanciaTotal, esperada)
+	}
+}
@@ -37,6 +37,31 @@ func (sv *ServicioValidacion) EsFuertementeConectado() bool {
 	return algorithms.VerificarConectividadFuerte(sv.grafo)
 }
 
+// ProponerTunelesConectividad propone el mínimo de túneles nuevos de un solo
+// sentido que hacen fuertemente conectada la red, a partir de su condensación
+func (sv *ServicioValidacion) ProponerTunelesConectividad() ([]algorithms.TunelPropuesto, error) {
+	propuestos, _, err := algorithms.TunelesParaConectividadFuerte(sv.grafo)
+	return propuestos, err
+}
+
+// AnalizarOrdenTopologico obtiene el orden topológico y la ruta crítica de una
+// red dirigida acíclica o, si tiene ciclos, hasta limiteCiclos de ellos
+func (sv *ServicioValidacion) AnalizarOrdenTopologico(limiteCiclos int) (*algorithms.AnalisisDAG, error) {
+	return algorithms.AnalizarDAG(sv.grafo, limiteCiclos)
+}
+
+// AnalizarPuntosFalla encuentra las cuevas y túneles cuyo colapso divide la red,
+// y sus bloques biconexos. Solo aplica a grafos no dirigidos.
+func (sv *ServicioValidacion) AnalizarPuntosFalla() (*algorithms.ResultadoBiconexion, error) {
+	return algorithms.AnalizarBiconexion(sv.grafo)
+}
+
+// CalcularFlujoMaximo obtiene cuántos camiones por hora pueden ir del origen al
+// destino y el corte mínimo de túneles que limita ese flujo
+func (sv *ServicioValidacion) CalcularFlujoMaximo(origen, destino string) (*algorithms.ResultadoFlujo, error) {
+	return algorithms.FlujoMaximo(sv.grafo, origen, destino)
+}
+
 // DetectarPozos encuentra las cuevas que son pozos (sin conexiones salientes)
 func (sv *ServicioValidacion) DetectarPozos() []string {
 	var pozos []string
@@ -154,6 +179,16 @@ func (sv *ServicioValidacion) generarSoluciones(cuevasInaccesibles []string, cue
 		soluciones = append(soluciones, solucionesCueva...)
 	}
 
+	// Túneles nuevos mínimos según la condensación del grafo
+	if propuestos, err := sv.ProponerTunelesConectividad(); err == nil && len(propuestos) > 0 {
+		soluciones = append(soluciones, "")
+		soluciones = append(soluciones, fmt.Sprintf("TÚNELES NUEVOS MÍNIMOS (%d) PARA CONECTAR TODA LA RED:", len(propuestos)))
+		for _, propuesto := range propuestos {
+			soluciones = append(soluciones, fmt.Sprintf("- %s -> %s (distancia %.2f, de %s a %s)",
+				propuesto.Desde, propuesto.Hasta, propuesto.Distancia, propuesto.ComponenteDesde, propuesto.ComponenteHasta))
+		}
+	}
+
 	// Soluciones generales
 	soluciones = append(soluciones, "")
 	soluciones = append(soluciones, "SOLUCIONES GENERALES:")
@@ -181,14 +216,8 @@ func (sv *ServicioValidacion) analizarSolucionesParaCueva(cuevaInaccesible strin
 		soluciones = append(soluciones, fmt.Sprintf("- Cueva '%s': Cambiar dirección de: %v", cuevaInaccesible, aristasProblematicas))
 	}
 
-	// Sugerir conexiones nuevas
-	conexionesSugeridas := sv.sugerirNuevasConexiones(cuevaInaccesible, cuevasAccesibles)
-	if len(conexionesSugeridas) > 0 {
-		soluciones = append(soluciones, fmt.Sprintf("- Cueva '%s': Agregar conexiones hacia: %v", cuevaInaccesible, conexionesSugeridas))
-	}
-
 	if len(soluciones) == 0 {
-		soluciones = append(soluciones, fmt.Sprintf("- Cueva '%s': Requiere análisis manual para determinar la mejor conexión", cuevaInaccesible))
+		soluciones = append(soluciones, fmt.Sprintf("- Cueva '%s': Requiere un túnel nuevo (ver túneles nuevos mínimos)", cuevaInaccesible))
 	}
 
 	return soluciones
@@ -232,36 +261,3 @@ func (sv *ServicioValidacion) verificarAristasDireccionales(cuevaID string, cuev
 
 	return aristasProblematicas
 }
-
-// sugerirNuevasConexiones sugiere conexiones nuevas con cuevas accesibles cercanas
-func (sv *ServicioValidacion) sugerirNuevasConexiones(cuevaID string, cuevasAccesibles map[string]bool) []string {
-	var sugerencias []string
-	contador := 0
-	maxSugerencias := 3
-
-	// Sugerir conexiones con las primeras cuevas accesibles encontradas
-	for cuevaAccesible := range cuevasAccesibles {
-		if contador >= maxSugerencias {
-			break
-		}
-
-		// Verificar que no existe ya una conexión
-		if !sv.existeConexion(cuevaAccesible, cuevaID) {
-			sugerencias = append(sugerencias, cuevaAccesible)
-			contador++
-		}
-	}
-
-	return sugerencias
-}
-
-// existeConexion verifica si existe una conexión entre dos cuevas
-func (sv *ServicioValidacion) existeConexion(desde, hasta string) bool {
-	for _, arista := range sv.grafo.Aristas {
-		if (arista.Desde == desde && arista.Hasta == hasta) ||
-			(!sv.grafo.EsDirigido && arista.Desde == hasta && arista.Hasta == desde) {
-			return true
-		}
-	}
-	return false
-}
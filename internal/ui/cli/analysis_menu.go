@@ -2,6 +2,7 @@ package cli
 
 import (
 	"fmt"
+	"math"
 	"proyecto-grafos-go/internal/domain"
 	"proyecto-grafos-go/internal/handler"
 	"proyecto-grafos-go/internal/service"
@@ -13,6 +14,7 @@ type MenuAnalisis struct {
 	grafoSvc        *service.ServicioGrafo
 	conexionSvc     *service.ServicioConexion
 	analysisHandler *handler.AnalysisHandler
+	grafoHandler    *handler.GraphHandler
 }
 
 func NuevoMenuAnalisis(
@@ -20,12 +22,14 @@ func NuevoMenuAnalisis(
 	grafoSvc *service.ServicioGrafo,
 	conexionSvc *service.ServicioConexion,
 	analysisHandler *handler.AnalysisHandler,
+	grafoHandler *handler.GraphHandler,
 ) *MenuAnalisis {
 	return &MenuAnalisis{
 		validacionSvc:   validacionSvc,
 		grafoSvc:        grafoSvc,
 		conexionSvc:     conexionSvc,
 		analysisHandler: analysisHandler,
+		grafoHandler:    grafoHandler,
 	}
 }
 
@@ -36,21 +40,27 @@ func (m *MenuAnalisis) Mostrar() {
 		fmt.Println(strings.Repeat("=", 50))
 		fmt.Println("=== ANÁLISIS DE CONECTIVIDAD ===")
 		fmt.Println("1. Verificar conectividad fuerte")
-		fmt.Println("2. Detectar pozos")
-		fmt.Println("3. Mostrar grados de vertices")
-		fmt.Println("4. Detectar cuevas inaccesibles")
-		fmt.Println("5. Analizar accesibilidad desde cueva específica")
+		fmt.Println("2. Orden topológico, ciclos y ruta crítica (red dirigida)")
+		fmt.Println("3. Detectar pozos")
+		fmt.Println("4. Mostrar grados de vertices")
+		fmt.Println("5. Detectar cuevas inaccesibles")
+		fmt.Println("6. Analizar accesibilidad desde cueva específica")
 		fmt.Println("")
 		fmt.Println("=== ANÁLISIS DE OPTIMIZACIÓN (MST) ===")
-		fmt.Println("6. Ver estadísticas de la red")
-		fmt.Println("7. Validar conectividad para MST")
-		fmt.Println("8. Calcular Árbol de Expansión Mínimo General (Req. 3a)")
-		fmt.Println("9. MST desde cueva específica (Req. 3b)")
-		fmt.Println("10. Rutas de acceso mínimas en orden de creación (Req. 3c)")
-		fmt.Println("11. Listar cuevas disponibles para MST")
-		fmt.Println("12. Exportar MST como nuevo grafo")
+		fmt.Println("7. Ver estadísticas de la red")
+		fmt.Println("8. Validar conectividad para MST")
+		fmt.Println("9. Calcular Árbol de Expansión Mínimo General (Req. 3a)")
+		fmt.Println("10. MST desde cueva específica (Req. 3b)")
+		fmt.Println("11. Rutas de acceso mínimas en orden de creación (Req. 3c)")
+		fmt.Println("12. Listar cuevas disponibles para MST")
+		fmt.Println("13. Exportar MST como nuevo grafo")
+		fmt.Println("14. Árbol de Steiner para un subconjunto de cuevas")
 		fmt.Println("")
-		fmt.Println("13. Salir")
+		fmt.Println("=== ANÁLISIS DE FALLAS ===")
+		fmt.Println("15. Puntos únicos de falla (cuevas y túneles críticos)")
+		fmt.Println("16. Flujo máximo y corte mínimo entre dos cuevas")
+		fmt.Println("")
+		fmt.Println("17. Salir")
 		fmt.Println(strings.Repeat("=", 50))
 
 		opcion := ObtenerInputInt("Seleccione una opción: ")
@@ -59,28 +69,36 @@ func (m *MenuAnalisis) Mostrar() {
 		case 1:
 			m.mostrarConectividad()
 		case 2:
-			m.mostrarPozos()
+			m.mostrarOrdenTopologico()
 		case 3:
-			m.mostrarGrados()
+			m.mostrarPozos()
 		case 4:
-			m.detectarCuevasInaccesibles()
+			m.mostrarGrados()
 		case 5:
-			m.analizarAccesibilidadEspecifica()
+			m.detectarCuevasInaccesibles()
 		case 6:
-			m.mostrarEstadisticasRed()
+			m.analizarAccesibilidadEspecifica()
 		case 7:
-			m.validarConectividadMST()
+			m.mostrarEstadisticasRed()
 		case 8:
-			m.calcularMSTGeneral()
+			m.validarConectividadMST()
 		case 9:
-			m.calcularMSTDesdeCueva()
+			m.calcularMSTGeneral()
 		case 10:
-			m.calcularRutasAccesoMinimas()
+			m.calcularMSTDesdeCueva()
 		case 11:
-			m.listarCuevasDisponibles()
+			m.calcularRutasAccesoMinimas()
 		case 12:
-			m.exportarMST()
+			m.listarCuevasDisponibles()
 		case 13:
+			m.exportarMST()
+		case 14:
+			m.calcularArbolSteiner()
+		case 15:
+			m.mostrarPuntosFalla()
+		case 16:
+			m.mostrarFlujoMaximo()
+		case 17:
 			return
 		default:
 			fmt.Println("Opción inválida")
@@ -94,6 +112,45 @@ func (m *MenuAnalisis) mostrarConectividad() {
 		fmt.Println("El grafo es fuertemente conectado")
 	} else {
 		fmt.Println("El grafo NO es fuertemente conectado")
+		m.mostrarTunelesPropuestos()
+	}
+}
+
+func (m *MenuAnalisis) mostrarTunelesPropuestos() {
+	propuestos, err := m.validacionSvc.ProponerTunelesConectividad()
+	if err != nil {
+		fmt.Printf(" Error: %v\n", err)
+		return
+	}
+
+	fmt.Printf("Túneles nuevos mínimos para lograrlo: %d\n", len(propuestos))
+	for i, propuesto := range propuestos {
+		fmt.Printf("   %d. %s -> %s (distancia %.2f, de %s a %s)\n", i+1, propuesto.Desde, propuesto.Hasta,
+			propuesto.Distancia, propuesto.ComponenteDesde, propuesto.ComponenteHasta)
+	}
+}
+
+func (m *MenuAnalisis) mostrarOrdenTopologico() {
+	limite := ObtenerInputInt("Máximo de ciclos a listar si la red tiene ciclos: ")
+	analisis, err := m.validacionSvc.AnalizarOrdenTopologico(limite)
+	if err != nil {
+		fmt.Printf(" Error: %v\n", err)
+		return
+	}
+
+	if analisis.EsAciclico {
+		fmt.Println("\nLa red es acíclica")
+		fmt.Printf("Orden topológico: %s\n", strings.Join(analisis.OrdenTopologico, " -> "))
+		fmt.Printf("Ruta crítica (%.2f): %s\n", analisis.RutaCritica.Costo, strings.Join(analisis.RutaCritica.Ruta, " -> "))
+		return
+	}
+
+	fmt.Printf("\nLa red tiene ciclos; se encontraron %d:\n", len(analisis.Ciclos))
+	for i, ciclo := range analisis.Ciclos {
+		fmt.Printf("   %d. %s -> %s\n", i+1, strings.Join(ciclo, " -> "), ciclo[0])
+	}
+	if analisis.CiclosTruncados {
+		fmt.Printf(" Se alcanzó el límite de %d ciclos; hay más sin listar\n", limite)
 	}
 }
 
@@ -177,6 +234,90 @@ func (m *MenuAnalisis) analizarAccesibilidadEspecifica() {
 	ObtenerInputString("")
 }
 
+// mostrarPuntosFalla lista las cuevas y túneles críticos y cuántas cuevas aislaría cada falla
+func (m *MenuAnalisis) mostrarPuntosFalla() {
+	resultado, err := m.validacionSvc.AnalizarPuntosFalla()
+	if err != nil {
+		fmt.Printf(" Error: %v\n", err)
+		return
+	}
+
+	fmt.Println("\n" + strings.Repeat("=", 50))
+	fmt.Println(" PUNTOS ÚNICOS DE FALLA")
+	fmt.Println(strings.Repeat("=", 50))
+
+	if len(resultado.CuevasCriticas) == 0 {
+		fmt.Println(" No hay cuevas críticas: ningún colapso de cueva divide la red")
+	} else {
+		fmt.Println("\n CUEVAS CRÍTICAS:")
+		for i, critica := range resultado.CuevasCriticas {
+			fmt.Printf("   %d. %s: divide la red en %d partes y aísla %d cueva(s)\n",
+				i+1, critica.Cueva, critica.Fragmentos, critica.CuevasAisladas)
+		}
+	}
+
+	if len(resultado.TunelesCriticos) == 0 {
+		fmt.Println(" No hay túneles críticos: todo túnel tiene una alternativa")
+	} else {
+		fmt.Println("\n TÚNELES CRÍTICOS:")
+		for i, tunel := range resultado.TunelesCriticos {
+			fmt.Printf("   %d. %s <-> %s: su colapso aísla %d cueva(s)\n",
+				i+1, tunel.Desde, tunel.Hasta, tunel.CuevasAisladas)
+		}
+	}
+
+	fmt.Printf("\n BLOQUES BICONEXOS: %d\n", len(resultado.Bloques))
+	for i, bloque := range resultado.Bloques {
+		fmt.Printf("   %d. %s\n", i+1, strings.Join(bloque, ", "))
+	}
+
+	fmt.Println("\nPresione Enter para continuar...")
+	ObtenerInputString("")
+}
+
+func (m *MenuAnalisis) mostrarFlujoMaximo() {
+	origen := ObtenerInputString("ID del centro de recursos (origen): ")
+	destino := ObtenerInputString("ID de la cueva destino: ")
+
+	resultado, err := m.validacionSvc.CalcularFlujoMaximo(origen, destino)
+	if err != nil {
+		fmt.Printf(" Error: %v\n", err)
+		return
+	}
+
+	fmt.Println("\n" + strings.Repeat("=", 50))
+	fmt.Printf(" FLUJO MÁXIMO %s -> %s\n", resultado.Origen, resultado.Destino)
+	fmt.Println(strings.Repeat("=", 50))
+	fmt.Printf(" Capacidad máxima: %.2f camiones/hora\n", resultado.FlujoMaximo)
+
+	if len(resultado.Tuneles) > 0 {
+		fmt.Println("\n FLUJO POR TÚNEL:")
+		for _, tunel := range resultado.Tuneles {
+			fmt.Printf("   %s -> %s: %.2f / %s\n", tunel.Desde, tunel.Hasta, tunel.Flujo, formatearCapacidad(tunel.Capacidad))
+		}
+	}
+
+	if len(resultado.CorteMinimo) == 0 {
+		fmt.Println("\n No hay ruta abierta entre ambas cuevas")
+	} else {
+		fmt.Println("\n CORTE MÍNIMO (túneles a ampliar primero):")
+		for i, tunel := range resultado.CorteMinimo {
+			fmt.Printf("   %d. %s -> %s: capacidad %s\n", i+1, tunel.Desde, tunel.Hasta, formatearCapacidad(tunel.Capacidad))
+		}
+		fmt.Printf(" Lado del origen: %s\n", strings.Join(resultado.LadoOrigen, ", "))
+	}
+
+	fmt.Println("\nPresione Enter para continuar...")
+	ObtenerInputString("")
+}
+
+func formatearCapacidad(capacidad float64) string {
+	if math.IsInf(capacidad, 1) {
+		return "sin límite"
+	}
+	return fmt.Sprintf("%.2f", capacidad)
+}
+
 // Nuevos métodos para MST (Requisito 3a)
 func (m *MenuAnalisis) mostrarEstadisticasRed() {
 	grafo := m.grafoSvc.ObtenerGrafo()
@@ -241,7 +382,53 @@ func (m *MenuAnalisis) calcularMSTGeneral() {
 	if SolicitarConfirmacion("¿Desea ver una explicación detallada del algoritmo utilizado?") {
 		m.mostrarExplicacionAlgoritmo()
 	}
+	if SolicitarConfirmacion("¿Desea exportar el MST resaltado en Graphviz DOT?") {
+		m.exportarMSTDOT(grafo)
+	}
+
+	fmt.Println("\nPresione Enter para continuar...")
+	ObtenerInputString("")
+}
 
+func (m *MenuAnalisis) exportarMSTDOT(grafo *domain.Grafo) {
+	mst, err := m.analysisHandler.ObtenerMST(grafo)
+	if err != nil {
+		fmt.Printf(" Error: %v\n", err)
+		return
+	}
+
+	nombreArchivo := ObtenerInputString("Nombre del archivo DOT: ")
+	if err := m.grafoHandler.ExportarMSTDOT(nombreArchivo, mst); err != nil {
+		fmt.Printf(" Error al exportar: %v\n", err)
+		return
+	}
+	fmt.Println(" MST exportado en formato DOT")
+}
+
+func (m *MenuAnalisis) calcularArbolSteiner() {
+	grafo := m.grafoSvc.ObtenerGrafo()
+	if grafo == nil {
+		fmt.Println(" No hay grafo cargado en el sistema")
+		return
+	}
+
+	fmt.Println("\n Ingrese las cuevas a conectar separadas por comas")
+	fmt.Println("(por ejemplo, el centro de recursos y las cuevas que necesitan insumos)")
+	entrada := ObtenerInputString("Cuevas: ")
+	terminales := make([]string, 0)
+	for _, id := range strings.Split(entrada, ",") {
+		if id = strings.TrimSpace(id); id != "" {
+			terminales = append(terminales, id)
+		}
+	}
+
+	resultado, err := m.analysisHandler.CalcularArbolSteiner(grafo, terminales)
+	if err != nil {
+		fmt.Printf(" Error: %v\n", err)
+		return
+	}
+
+	fmt.Println(resultado)
 	fmt.Println("\nPresione Enter para continuar...")
 	ObtenerInputString("")
 }
@@ -35,7 +35,10 @@ func (m *MenuCueva) Mostrar() {
 		fmt.Println("13. Invertir todas las rutas salientes de una cueva")
 		fmt.Println("14. Invertir todas las rutas entrantes a una cueva")
 		fmt.Println("15. Mostrar estadísticas de conexiones")
-		fmt.Println("16. Volver al menú principal")
+		fmt.Println("16. Definir capacidad de una conexión")
+		fmt.Println("17. Definir demanda de una cueva")
+		fmt.Println("18. Marcar una conexión como angosta (un camión a la vez)")
+		fmt.Println("19. Volver al menú principal")
 
 		opcion := ObtenerInputInt("Seleccione una opción: ")
 
@@ -71,6 +74,12 @@ func (m *MenuCueva) Mostrar() {
 		case 15:
 			m.mostrarEstadisticasConexiones()
 		case 16:
+			m.definirCapacidad()
+		case 17:
+			m.definirDemanda()
+		case 18:
+			m.definirAngosto()
+		case 19:
 			return
 		default:
 			fmt.Println("Opción inválida")
@@ -133,6 +142,22 @@ func (m *MenuCueva) listarCuevas() {
 	}
 }
 
+func (m *MenuCueva) definirDemanda() {
+	id := ObtenerInputString("ID de la cueva: ")
+	recurso := ObtenerInputString("Recurso: ")
+	cantidad := ObtenerInputInt("Stock deseado (0 = sin demanda): ")
+
+	if cantidad < 0 {
+		fmt.Println("Error: la demanda no puede ser negativa")
+		return
+	}
+	if err := m.cuevaSvc.EstablecerDemanda(id, recurso, cantidad); err != nil {
+		fmt.Println("Error:", err)
+	} else {
+		fmt.Printf("Demanda de %s en la cueva %s actualizada\n", recurso, id)
+	}
+}
+
 // ===================== FUNCIONES DE CONEXIONES =====================
 
 func (m *MenuCueva) obstruirConexion(obstruir bool) {
@@ -254,6 +279,30 @@ func (m *MenuCueva) desobstruirTodasConexiones() {
 	}
 }
 
+func (m *MenuCueva) definirCapacidad() {
+	desde := ObtenerInputString("ID cueva origen: ")
+	hasta := ObtenerInputString("ID cueva destino: ")
+	capacidad := ObtenerInputFloat("Capacidad en camiones por hora (0 = sin definir): ")
+
+	if err := m.conexionSvc.EstablecerCapacidad(desde, hasta, capacidad); err != nil {
+		fmt.Printf("Error: %v\n", err)
+	} else {
+		fmt.Printf("Capacidad de la conexión desde %s hasta %s actualizada\n", desde, hasta)
+	}
+}
+
+func (m *MenuCueva) definirAngosto() {
+	desde := ObtenerInputString("ID cueva origen: ")
+	hasta := ObtenerInputString("ID cueva destino: ")
+	angosto := ObtenerInputBool("¿Túnel angosto? (s/n): ")
+
+	if err := m.conexionSvc.EstablecerAngosto(desde, hasta, angosto); err != nil {
+		fmt.Printf("Error: %v\n", err)
+	} else {
+		fmt.Printf("Conexión desde %s hasta %s actualizada\n", desde, hasta)
+	}
+}
+
 func (m *MenuCueva) mostrarEstadisticasConexiones() {
 	stats := m.conexionSvc.EstadisticasConexiones()
 
@@ -2,8 +2,11 @@ package cli
 
 import (
 	"fmt"
+	"proyecto-grafos-go/internal/domain"
 	"proyecto-grafos-go/internal/handler"
+	"proyecto-grafos-go/internal/repository"
 	"proyecto-grafos-go/internal/service"
+	"time"
 )
 
 type MainMenu struct {
@@ -43,7 +46,10 @@ func (m *MainMenu) Mostrar() {
 		fmt.Println("2. Gestión de cuevas y conexiones (1b, 2a)")
 		fmt.Println("3. Cambiar tipo de grafo (dirigido/no) (1c)")
 		fmt.Println("4. Análisis del grafo (1d-1f)")
-		fmt.Println("5. Salir")
+		fmt.Println("5. Guardar grafo en archivo (con mensaje opcional)")
+		fmt.Println("6. Historial de versiones")
+		fmt.Println("7. Fusionar otra red desde archivo")
+		fmt.Println("8. Salir")
 
 		opcion := ObtenerInputInt("Seleccione una opción: ")
 
@@ -57,6 +63,12 @@ func (m *MainMenu) Mostrar() {
 		case 4:
 			m.mostrarMenuAnalisis()
 		case 5:
+			m.guardarGrafo()
+		case 6:
+			m.mostrarHistorial()
+		case 7:
+			m.fusionarGrafo()
+		case 8:
 			return
 		default:
 			fmt.Println("Opción inválida")
@@ -65,14 +77,219 @@ func (m *MainMenu) Mostrar() {
 }
 
 func (m *MainMenu) cargarGrafo() {
+	if resumenes, err := m.grafoSvc.ListarResumenes(); err == nil {
+		m.mostrarResumenes(resumenes)
+	}
 	archivo := ObtenerInputString("Nombre del archivo (ej: caves.json): ")
-	if err := m.grafoSvc.CargarGrafo(archivo); err != nil {
+	if !m.grafoSvc.AdmiteValidacion() {
+		if err := m.grafoSvc.CargarGrafo(archivo); err != nil {
+			fmt.Println("Error:", err)
+		} else {
+			fmt.Println("Grafo cargado correctamente")
+		}
+		return
+	}
+
+	modo := repository.ValidacionTolerante
+	if ObtenerInputBool("¿Validación estricta (rechazar ante cualquier problema)? (s/n): ") {
+		modo = repository.ValidacionEstricta
+	}
+
+	problemas, err := m.grafoSvc.CargarGrafoValidado(archivo, modo)
+	if err != nil {
 		fmt.Println("Error:", err)
+		return
+	}
+	for _, problema := range problemas {
+		fmt.Println("  -", problema)
+	}
+	if len(problemas) > 0 {
+		fmt.Printf("Grafo cargado con %d advertencia(s); los elementos con errores se omitieron\n", len(problemas))
 	} else {
 		fmt.Println("Grafo cargado correctamente")
 	}
 }
 
+// mostrarResumenes lista los grafos guardados en la base de datos
+func (m *MainMenu) mostrarResumenes(resumenes []repository.ResumenGrafo) {
+	if len(resumenes) == 0 {
+		fmt.Println("No hay grafos guardados")
+		return
+	}
+	fmt.Println("\nGrafos guardados:")
+	for _, resumen := range resumenes {
+		tipo := "no dirigido"
+		if resumen.EsDirigido {
+			tipo = "dirigido"
+		}
+		fmt.Printf("  - %s (%s, %d cuevas, %d túneles, guardado %s)\n",
+			resumen.Nombre, tipo, resumen.NumCuevas, resumen.NumAristas,
+			resumen.GuardadoEn.Local().Format("2006-01-02 15:04"))
+	}
+}
+
+func (m *MainMenu) guardarGrafo() {
+	archivo := ObtenerInputString("Nombre del archivo (ej: caves.json): ")
+	if !m.grafoSvc.AdmiteSnapshots() {
+		if err := m.grafoSvc.GuardarGrafo(archivo); err != nil {
+			fmt.Println("Error:", err)
+		} else {
+			fmt.Println("Grafo guardado correctamente")
+		}
+		return
+	}
+	mensaje := ObtenerInputString("Mensaje de la versión (opcional): ")
+	if err := m.grafoSvc.GuardarGrafoConMensaje(archivo, mensaje); err != nil {
+		fmt.Println("Error:", err)
+	} else {
+		fmt.Println("Grafo guardado correctamente")
+	}
+}
+
+func (m *MainMenu) fusionarGrafo() {
+	archivo := ObtenerInputString("Archivo de la red a importar (ej: seccion_norte.json): ")
+	opciones := domain.OpcionesFusion{
+		Prefijo: ObtenerInputString("Prefijo para los IDs importados (vacío para ninguno): "),
+	}
+
+	fmt.Println("Política ante IDs repetidos: 1. Omitir  2. Sobrescribir  3. Renombrar  4. Sumar recursos y demanda")
+	politicas := map[int]domain.PoliticaConflicto{
+		1: domain.ConflictoOmitir,
+		2: domain.ConflictoSobrescribir,
+		3: domain.ConflictoRenombrar,
+		4: domain.ConflictoSumarRecursos,
+	}
+	politica, ok := politicas[ObtenerInputInt("Seleccione una política: ")]
+	if !ok {
+		fmt.Println("Política inválida")
+		return
+	}
+	opciones.Politica = politica
+
+	for ObtenerInputBool("¿Agregar un túnel de unión entre ambas redes?") {
+		opciones.Costuras = append(opciones.Costuras, domain.CosturaFusion{
+			Desde:     ObtenerInputString("Cueva de la red actual: "),
+			Hasta:     ObtenerInputString("Cueva de la red importada (ID original): "),
+			Distancia: ObtenerInputFloat("Distancia: "),
+		})
+	}
+
+	reporte, err := m.grafoSvc.FusionarDesdeArchivo(archivo, opciones)
+	if err != nil {
+		fmt.Println("Error:", err)
+		return
+	}
+	fmt.Print(reporte.Reporte())
+}
+
+func (m *MainMenu) mostrarHistorial() {
+	archivo := ObtenerInputString("Nombre del archivo (ej: caves.json): ")
+	for {
+		fmt.Printf("\n=== Historial de %s ===\n", archivo)
+		fmt.Println("1. Listar versiones")
+		fmt.Println("2. Restaurar una versión")
+		fmt.Println("3. Podar por cantidad")
+		fmt.Println("4. Podar por antigüedad")
+		fmt.Println("5. Comparar dos versiones")
+		fmt.Println("6. Comparar archivo con el grafo en memoria")
+		fmt.Println("7. Aplicar parche JSON al grafo en memoria")
+		fmt.Println("8. Volver")
+
+		switch ObtenerInputInt("Seleccione una opción: ") {
+		case 1:
+			m.listarSnapshots(archivo)
+		case 2:
+			numero := ObtenerInputInt("Número de versión a restaurar: ")
+			if err := m.grafoSvc.RestaurarSnapshot(archivo, numero); err != nil {
+				fmt.Println("Error:", err)
+			} else {
+				fmt.Printf("Versión %d restaurada y cargada\n", numero)
+			}
+		case 3:
+			cantidad := ObtenerInputInt("Cantidad de versiones a conservar: ")
+			m.podarSnapshots(archivo, cantidad, 0)
+		case 4:
+			dias := ObtenerInputInt("Eliminar versiones con más de N días: ")
+			m.podarSnapshots(archivo, 0, time.Duration(dias)*24*time.Hour)
+		case 5:
+			desde := ObtenerInputInt("Versión inicial: ")
+			hasta := ObtenerInputInt("Versión final: ")
+			parche, err := m.grafoSvc.CompararSnapshots(archivo, desde, hasta)
+			m.mostrarParche(parche, err)
+		case 6:
+			parche, err := m.grafoSvc.CompararConArchivo(archivo)
+			m.mostrarParche(parche, err)
+		case 7:
+			m.aplicarParche()
+		case 8:
+			return
+		default:
+			fmt.Println("Opción inválida")
+		}
+	}
+}
+
+func (m *MainMenu) mostrarParche(parche *domain.ParcheGrafo, err error) {
+	if err != nil {
+		fmt.Println("Error:", err)
+		return
+	}
+	fmt.Print(parche.Reporte())
+	if parche.EstaVacio() {
+		return
+	}
+
+	destino := ObtenerInputString("Guardar parche JSON en (vacío para omitir): ")
+	if destino == "" {
+		return
+	}
+	if err := m.grafoSvc.GuardarParche(destino, parche); err != nil {
+		fmt.Println("Error:", err)
+	} else {
+		fmt.Println("Parche guardado correctamente")
+	}
+}
+
+func (m *MainMenu) aplicarParche() {
+	archivo := ObtenerInputString("Archivo del parche (ej: cambios.patch.json): ")
+	parche, err := m.grafoSvc.AplicarParcheDesdeArchivo(archivo)
+	if err != nil {
+		fmt.Println("Error:", err)
+		return
+	}
+	fmt.Print(parche.Reporte())
+	fmt.Println("Parche aplicado correctamente")
+}
+
+func (m *MainMenu) listarSnapshots(archivo string) {
+	snapshots, err := m.grafoSvc.ListarSnapshots(archivo)
+	if err != nil {
+		fmt.Println("Error:", err)
+		return
+	}
+	if len(snapshots) == 0 {
+		fmt.Println("No hay versiones guardadas")
+		return
+	}
+	for _, snapshot := range snapshots {
+		fmt.Printf("  #%d  %s  %d bytes  %s\n", snapshot.Numero,
+			snapshot.Fecha.Format("2006-01-02 15:04:05"), snapshot.Tamano, snapshot.Mensaje)
+	}
+}
+
+func (m *MainMenu) podarSnapshots(archivo string, maxCantidad int, maxAntiguedad time.Duration) {
+	if maxCantidad <= 0 && maxAntiguedad <= 0 {
+		fmt.Println("Error: el valor debe ser mayor a 0")
+		return
+	}
+	eliminados, err := m.grafoSvc.PodarSnapshots(archivo, maxCantidad, maxAntiguedad)
+	if err != nil {
+		fmt.Println("Error:", err)
+		return
+	}
+	fmt.Printf("Se eliminaron %d versiones (la más reciente siempre se conserva)\n", eliminados)
+}
+
 func (m *MainMenu) cambiarTipoGrafo() {
 	dirigido := ObtenerInputBool("¿Grafo dirigido? (s/n): ")
 	m.grafoSvc.CambiarTipoGrafo(dirigido)
@@ -85,7 +302,7 @@ func (m *MainMenu) mostrarMenuCuevas() {
 }
 
 func (m *MainMenu) mostrarMenuAnalisis() {
-	menuAnalisis := NuevoMenuAnalisis(m.validacionSvc, m.grafoSvc, m.conexionSvc, m.analysisHandler)
+	menuAnalisis := NuevoMenuAnalisis(m.validacionSvc, m.grafoSvc, m.conexionSvc, m.analysisHandler, m.grafoHandler)
 	menuAnalisis.Mostrar()
 }
 
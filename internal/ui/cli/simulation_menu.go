@@ -5,6 +5,8 @@ import (
 	"proyecto-grafos-go/internal/domain"
 	"proyecto-grafos-go/internal/handler"
 	"proyecto-grafos-go/internal/service"
+	"proyecto-grafos-go/pkg/algorithms"
+	"sort"
 	"strconv"
 	"strings"
 )
@@ -13,14 +15,16 @@ import (
 type SimulationMenu struct {
 	simulationHandler *handler.SimulationHandler
 	traversalHandler  *handler.TraversalHandler
+	grafoHandler      *handler.GraphHandler
 	grafo             *domain.Grafo
 }
 
 // NuevoSimulationMenu crea una nueva instancia del menú de simulación
-func NuevoSimulationMenu(simulationHandler *handler.SimulationHandler, traversalHandler *handler.TraversalHandler, grafo *domain.Grafo) *SimulationMenu {
+func NuevoSimulationMenu(simulationHandler *handler.SimulationHandler, traversalHandler *handler.TraversalHandler, grafoHandler *handler.GraphHandler, grafo *domain.Grafo) *SimulationMenu {
 	return &SimulationMenu{
 		simulationHandler: simulationHandler,
 		traversalHandler:  traversalHandler,
+		grafoHandler:      grafoHandler,
 		grafo:             grafo,
 	}
 }
@@ -35,11 +39,21 @@ func (sm *SimulationMenu) MostrarMenu() {
 		fmt.Println("2. Cargar insumos en camión")
 		fmt.Println("3. Simular entrega con DFS")
 		fmt.Println("4. Simular entrega con BFS")
-		fmt.Println("5. Comparar algoritmos DFS vs BFS")
-		fmt.Println("6. Analizar recorridos (solo navegación)")
-		fmt.Println("7. Ver estado de camiones")
-		fmt.Println("8. Gestionar camiones")
-		fmt.Println("9. Análisis de conectividad")
+		fmt.Println("5. Simular entrega con TSP (caminos mínimos)")
+		fmt.Println("6. Comparar algoritmos DFS, BFS y TSP")
+		fmt.Println("7. Analizar recorridos (solo navegación)")
+		fmt.Println("8. Ver estado de camiones")
+		fmt.Println("9. Gestionar camiones")
+		fmt.Println("10. Análisis de conectividad")
+		fmt.Println("11. Camino más corto entre dos cuevas")
+		fmt.Println("12. Algoritmo de trayectos de los camiones")
+		fmt.Println("13. Rutas alternativas entre dos cuevas (K más cortas)")
+		fmt.Println("14. Modo de planificación TSP")
+		fmt.Println("15. Prioridad de entrega según demanda")
+		fmt.Println("16. Modo de reproducción de la simulación")
+		fmt.Println("17. Simular flota de camiones")
+		fmt.Println("18. Planificar rutas de la flota según demanda (CVRP)")
+		fmt.Println("19. Jornada de los camiones")
 		fmt.Println("0. Volver al menú principal")
 		fmt.Println(strings.Repeat("-", 60))
 
@@ -55,15 +69,35 @@ func (sm *SimulationMenu) MostrarMenu() {
 		case "4":
 			sm.simularEntregaBFS()
 		case "5":
-			sm.compararAlgoritmos()
+			sm.simularEntregaTSP()
 		case "6":
-			sm.analizarRecorridos()
+			sm.compararAlgoritmos()
 		case "7":
-			sm.verEstadoCamiones()
+			sm.analizarRecorridos()
 		case "8":
-			sm.gestionarCamiones()
+			sm.verEstadoCamiones()
 		case "9":
+			sm.gestionarCamiones()
+		case "10":
 			sm.analizarConectividad()
+		case "11":
+			sm.buscarCamino()
+		case "12":
+			sm.elegirAlgoritmoTrayectos()
+		case "13":
+			sm.buscarRutasAlternativas()
+		case "14":
+			sm.elegirModoTSP()
+		case "15":
+			sm.elegirPrioridadEntrega()
+		case "16":
+			sm.elegirReproduccion()
+		case "17":
+			sm.simularFlota()
+		case "18":
+			sm.planificarFlota()
+		case "19":
+			sm.elegirJornada()
 		case "0":
 			fmt.Println("Volviendo al menú principal...")
 			return
@@ -157,6 +191,23 @@ func (sm *SimulationMenu) cargarInsumos() {
 		return
 	}
 
+	if ObtenerInputBool("¿Cargar según la demanda de las cuevas?") {
+		cargados, err := sm.simulationHandler.CargarSegunDemanda(sm.grafo, camionID)
+		if err != nil {
+			fmt.Printf("ERROR: Error al cargar insumos: %s\n", err.Error())
+			return
+		}
+		if len(cargados) == 0 {
+			fmt.Println("Ninguna cueva tiene demanda pendiente que el camión pueda cargar")
+			return
+		}
+		fmt.Printf("EXITO: Insumos cargados según la demanda (regla %s):\n", sm.simulationHandler.ObtenerPrioridadEntrega())
+		for recurso, cantidad := range cargados {
+			fmt.Printf("   - %s: %d unidades\n", recurso, cantidad)
+		}
+		return
+	}
+
 	insumos := make(map[string]string)
 	fmt.Println("\nIngrese los insumos (presione Enter sin escribir para terminar):")
 
@@ -236,9 +287,29 @@ func (sm *SimulationMenu) simularEntregaBFS() {
 	sm.mostrarResultadoSimulacion(resultado)
 }
 
-// compararAlgoritmos compara DFS vs BFS para la misma simulación
+// simularEntregaTSP ejecuta una simulación con un recorrido TSP por caminos mínimos
+func (sm *SimulationMenu) simularEntregaTSP() {
+	fmt.Println("\nSIMULACION DE ENTREGA CON TSP")
+	fmt.Println(strings.Repeat("-", 40))
+	fmt.Printf("Modo de planificación: %s\n", sm.simulationHandler.ObtenerModoTSP())
+
+	camionID, cuevaOrigen := sm.obtenerParametrosSimulacion()
+	if camionID == "" || cuevaOrigen == "" {
+		return
+	}
+
+	resultado, err := sm.simulationHandler.EjecutarSimulacionTSP(sm.grafo, camionID, cuevaOrigen)
+	if err != nil {
+		fmt.Printf("ERROR: Error en simulación: %s\n", err.Error())
+		return
+	}
+
+	sm.mostrarResultadoSimulacion(resultado)
+}
+
+// compararAlgoritmos compara DFS, BFS y TSP para la misma simulación
 func (sm *SimulationMenu) compararAlgoritmos() {
-	fmt.Println("\nCOMPARACION DFS vs BFS")
+	fmt.Println("\nCOMPARACION DFS, BFS y TSP")
 	fmt.Println(strings.Repeat("-", 40))
 
 	camionID, cuevaOrigen := sm.obtenerParametrosSimulacion()
@@ -258,6 +329,103 @@ func (sm *SimulationMenu) compararAlgoritmos() {
 	fmt.Println(reporte)
 }
 
+// simularFlota despacha a la vez los camiones que el usuario asigna a sus paradas
+func (sm *SimulationMenu) simularFlota() {
+	fmt.Println("\nSIMULACION DE FLOTA")
+	fmt.Println(strings.Repeat("-", 40))
+
+	camiones := sm.simulationHandler.ListarTodosLosCamiones()
+	if len(camiones) == 0 {
+		fmt.Println("ERROR: No hay camiones disponibles. Cree uno primero.")
+		return
+	}
+
+	deposito := sm.seleccionarCuevaOrigen()
+	if deposito == "" {
+		return
+	}
+	fmt.Println("Los camiones cargan lo que tienen pedido del stock de la cueva depósito.")
+
+	ids := make([]string, 0, len(camiones))
+	for id := range camiones {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	asignaciones := make(map[string][]string)
+	fmt.Println("Indique las paradas de cada camión separadas por coma (Enter para no despacharlo):")
+	for _, id := range ids {
+		entrada := LeerEntrada(fmt.Sprintf("Paradas de %s: ", id))
+		paradas := make([]string, 0)
+		for _, parada := range strings.Split(entrada, ",") {
+			if parada = strings.TrimSpace(parada); parada != "" {
+				paradas = append(paradas, parada)
+			}
+		}
+		if len(paradas) > 0 {
+			asignaciones[id] = paradas
+		}
+	}
+	if len(asignaciones) == 0 {
+		fmt.Println("ERROR: No se despachó ningún camión")
+		return
+	}
+
+	resultado, err := sm.simulationHandler.EjecutarSimulacionFlota(sm.grafo, deposito, asignaciones)
+	if err != nil {
+		fmt.Printf("ERROR: Error en simulación de flota: %s\n", err.Error())
+		return
+	}
+	fmt.Println(sm.simulationHandler.GenerarReporteFlota(resultado))
+}
+
+// planificarFlota reparte la demanda entre los camiones y ofrece simular el plan
+func (sm *SimulationMenu) planificarFlota() {
+	fmt.Println("\nPLANIFICACION DE RUTAS DE LA FLOTA")
+	fmt.Println(strings.Repeat("-", 40))
+
+	camiones := sm.simulationHandler.ListarTodosLosCamiones()
+	if len(camiones) == 0 {
+		fmt.Println("ERROR: No hay camiones disponibles. Cree uno primero.")
+		return
+	}
+
+	deposito := sm.seleccionarCuevaOrigen()
+	if deposito == "" {
+		return
+	}
+
+	camionIDs := make([]string, 0)
+	entrada := LeerEntrada("Camiones a usar separados por coma (Enter para todos): ")
+	for _, id := range strings.Split(entrada, ",") {
+		if id = strings.TrimSpace(id); id != "" {
+			camionIDs = append(camionIDs, id)
+		}
+	}
+
+	plan, err := sm.simulationHandler.PlanificarFlotaCVRP(sm.grafo, deposito, camionIDs)
+	if err != nil {
+		fmt.Printf("ERROR: Error al planificar la flota: %s\n", err.Error())
+		return
+	}
+	fmt.Println(sm.simulationHandler.GenerarReportePlanFlota(plan))
+	if len(plan.Asignaciones) == 0 {
+		fmt.Println("No hay demanda que atender.")
+		return
+	}
+	fmt.Println("Los camiones quedaron cargados con el déficit de sus paradas.")
+
+	if !ObtenerInputBool("¿Simular el plan ahora?") {
+		return
+	}
+	resultado, err := sm.simulationHandler.EjecutarSimulacionFlota(sm.grafo, deposito, plan.Asignaciones)
+	if err != nil {
+		fmt.Printf("ERROR: Error en simulación de flota: %s\n", err.Error())
+		return
+	}
+	fmt.Println(sm.simulationHandler.GenerarReporteFlota(resultado))
+}
+
 // analizarRecorridos analiza recorridos sin simulación de camiones
 func (sm *SimulationMenu) analizarRecorridos() {
 	fmt.Println("\nANALISIS DE RECORRIDOS")
@@ -365,8 +533,241 @@ func (sm *SimulationMenu) analizarConectividad() {
 	fmt.Println(reporte)
 }
 
+// buscarCamino calcula el camino más corto entre dos cuevas con el algoritmo elegido
+func (sm *SimulationMenu) buscarCamino() {
+	fmt.Println("\nCAMINO MAS CORTO ENTRE DOS CUEVAS")
+	fmt.Println(strings.Repeat("-", 40))
+
+	cuevaOrigen := sm.seleccionarCuevaOrigen()
+	if cuevaOrigen == "" {
+		return
+	}
+	cuevaDestino := LeerEntrada("Cueva destino: ")
+	if cuevaDestino == "" {
+		fmt.Println("ERROR: La cueva destino no puede estar vacía")
+		return
+	}
+
+	algoritmo := sm.seleccionarAlgoritmoCamino()
+	if algoritmo == "" {
+		return
+	}
+
+	resultado, err := sm.traversalHandler.BuscarCamino(sm.grafo, cuevaOrigen, cuevaDestino, algoritmo)
+	if err != nil {
+		fmt.Printf("ERROR: Error al buscar el camino: %s\n", err.Error())
+		return
+	}
+
+	fmt.Println(sm.traversalHandler.GenerarReporteCamino(resultado))
+
+	if SolicitarConfirmacion("¿Desea exportar la ruta más corta resaltada en Graphviz DOT?") {
+		nombreArchivo := LeerEntrada("Nombre del archivo DOT: ")
+		if err := sm.grafoHandler.ExportarRutaDOT(nombreArchivo, cuevaOrigen, cuevaDestino); err != nil {
+			fmt.Printf("ERROR: Error al exportar: %s\n", err.Error())
+			return
+		}
+		fmt.Println("EXITO: Ruta exportada en formato DOT")
+	}
+}
+
+// buscarRutasAlternativas lista las K rutas más cortas entre dos cuevas
+func (sm *SimulationMenu) buscarRutasAlternativas() {
+	fmt.Println("\nRUTAS ALTERNATIVAS ENTRE DOS CUEVAS")
+	fmt.Println(strings.Repeat("-", 40))
+
+	cuevaOrigen := sm.seleccionarCuevaOrigen()
+	if cuevaOrigen == "" {
+		return
+	}
+	cuevaDestino := LeerEntrada("Cueva destino: ")
+	if cuevaDestino == "" {
+		fmt.Println("ERROR: La cueva destino no puede estar vacía")
+		return
+	}
+
+	k, err := strconv.Atoi(LeerEntrada("Cantidad de rutas (K): "))
+	if err != nil || k <= 0 {
+		fmt.Println("ERROR: La cantidad de rutas debe ser un entero positivo")
+		return
+	}
+
+	caminos, err := sm.traversalHandler.BuscarRutasAlternativas(sm.grafo, cuevaOrigen, cuevaDestino, k)
+	if err != nil {
+		fmt.Printf("ERROR: Error al buscar rutas: %s\n", err.Error())
+		return
+	}
+	if len(caminos) < k {
+		fmt.Printf("Solo existen %d ruta(s) sin ciclos entre ambas cuevas\n", len(caminos))
+	}
+
+	fmt.Println(sm.traversalHandler.GenerarReporteRutasAlternativas(sm.grafo, caminos))
+}
+
+// elegirAlgoritmoTrayectos elige el algoritmo con que los camiones van entre cuevas no adyacentes
+func (sm *SimulationMenu) elegirAlgoritmoTrayectos() {
+	fmt.Println("\nALGORITMO DE TRAYECTOS")
+	fmt.Println(strings.Repeat("-", 40))
+	fmt.Printf("Algoritmo actual: %s\n", sm.simulationHandler.ObtenerAlgoritmoCamino())
+
+	algoritmo := sm.seleccionarAlgoritmoCamino()
+	if algoritmo == "" {
+		return
+	}
+
+	if err := sm.simulationHandler.EstablecerAlgoritmoCamino(algoritmo); err != nil {
+		fmt.Printf("ERROR: %s\n", err.Error())
+		return
+	}
+	fmt.Printf("EXITO: Los camiones usarán %s\n", algoritmo)
+}
+
+// elegirModoTSP elige cómo se planifican los recorridos TSP de los camiones
+func (sm *SimulationMenu) elegirModoTSP() {
+	fmt.Println("\nMODO DE PLANIFICACION TSP")
+	fmt.Println(strings.Repeat("-", 40))
+	fmt.Printf("Modo actual: %s\n", sm.simulationHandler.ObtenerModoTSP())
+	fmt.Println("Modos disponibles:")
+	for i, modo := range algorithms.ModosTSP {
+		fmt.Printf("%d. %s\n", i+1, modo)
+	}
+	fmt.Printf("(%s es exacto y admite hasta %d cuevas)\n", algorithms.TSPHeldKarp, algorithms.MaxCuevasHeldKarp)
+
+	opcion, err := strconv.Atoi(LeerEntrada("Seleccione modo: "))
+	if err != nil || opcion < 1 || opcion > len(algorithms.ModosTSP) {
+		fmt.Println("ERROR: Modo no válido")
+		return
+	}
+
+	modo := string(algorithms.ModosTSP[opcion-1])
+	if err := sm.simulationHandler.EstablecerModoTSP(modo); err != nil {
+		fmt.Printf("ERROR: %s\n", err.Error())
+		return
+	}
+	fmt.Printf("EXITO: Los recorridos TSP usarán %s\n", modo)
+}
+
+// elegirPrioridadEntrega elige la regla de reparto de la carga y los recursos críticos
+func (sm *SimulationMenu) elegirPrioridadEntrega() {
+	fmt.Println("\nPRIORIDAD DE ENTREGA")
+	fmt.Println(strings.Repeat("-", 40))
+	fmt.Printf("Regla actual: %s\n", sm.simulationHandler.ObtenerPrioridadEntrega())
+	if criticos := sm.simulationHandler.ObtenerRecursosCriticos(); len(criticos) > 0 {
+		fmt.Printf("Recursos críticos: %s\n", strings.Join(criticos, ", "))
+	}
+	fmt.Println("Reglas disponibles:")
+	for i, prioridad := range service.PrioridadesEntrega {
+		fmt.Printf("%d. %s\n", i+1, prioridad)
+	}
+
+	opcion, err := strconv.Atoi(LeerEntrada("Seleccione regla: "))
+	if err != nil || opcion < 1 || opcion > len(service.PrioridadesEntrega) {
+		fmt.Println("ERROR: Regla no válida")
+		return
+	}
+
+	prioridad := service.PrioridadesEntrega[opcion-1]
+	if prioridad == service.PrioridadCriticosPrimero {
+		entrada := LeerEntrada("Recursos críticos, del más al menos importante (separados por coma): ")
+		criticos := make([]string, 0)
+		for _, recurso := range strings.Split(entrada, ",") {
+			if recurso = strings.TrimSpace(recurso); recurso != "" {
+				criticos = append(criticos, recurso)
+			}
+		}
+		if len(criticos) == 0 {
+			fmt.Println("ERROR: Debe indicar al menos un recurso crítico")
+			return
+		}
+		sm.simulationHandler.EstablecerRecursosCriticos(criticos)
+	}
+
+	if err := sm.simulationHandler.EstablecerPrioridadEntrega(string(prioridad)); err != nil {
+		fmt.Printf("ERROR: %s\n", err.Error())
+		return
+	}
+	fmt.Printf("EXITO: Las entregas usarán la regla %s\n", prioridad)
+}
+
+// elegirReproduccion elige cómo se muestran los eventos de la simulación
+func (sm *SimulationMenu) elegirReproduccion() {
+	fmt.Println("\nMODO DE REPRODUCCION")
+	fmt.Println(strings.Repeat("-", 40))
+	fmt.Printf("Modo actual: %s\n", sm.simulationHandler.ObtenerModoReproduccion())
+	fmt.Println("Modos disponibles:")
+	for i, modo := range service.ModosReproduccion {
+		fmt.Printf("%d. %s\n", i+1, modo)
+	}
+
+	opcion, err := strconv.Atoi(LeerEntrada("Seleccione modo: "))
+	if err != nil || opcion < 1 || opcion > len(service.ModosReproduccion) {
+		fmt.Println("ERROR: Modo no válido")
+		return
+	}
+
+	modo := service.ModosReproduccion[opcion-1]
+	escala := 0.0
+	var alAvanzar func(service.EventoSimulacion)
+	switch modo {
+	case service.ReproduccionPasoAPaso:
+		alAvanzar = func(evento service.EventoSimulacion) {
+			fmt.Println(service.FormatearEvento(evento))
+			LeerEntrada("Presione Enter para continuar...")
+		}
+	case service.ReproduccionEscalada:
+		entrada := LeerEntrada(fmt.Sprintf("Segundos reales por hora simulada (Enter = %.1f): ", service.EscalaPredeterminada*3600))
+		if entrada != "" {
+			segundos, err := strconv.ParseFloat(entrada, 64)
+			if err != nil || segundos <= 0 {
+				fmt.Println("ERROR: Debe ser un número positivo")
+				return
+			}
+			escala = segundos / 3600
+		}
+		alAvanzar = func(evento service.EventoSimulacion) {
+			fmt.Println(service.FormatearEvento(evento))
+		}
+	}
+
+	if err := sm.simulationHandler.EstablecerReproduccion(string(modo), escala, alAvanzar); err != nil {
+		fmt.Printf("ERROR: %s\n", err.Error())
+		return
+	}
+	fmt.Printf("EXITO: Las simulaciones se reproducirán en modo %s\n", modo)
+}
+
+// elegirJornada define hasta cuándo los camiones pueden salir a entregar antes de volver al depósito
+func (sm *SimulationMenu) elegirJornada() {
+	fmt.Println("\nJORNADA DE LOS CAMIONES")
+	fmt.Println(strings.Repeat("-", 40))
+	fmt.Printf("Jornada actual: %.2f horas\n", sm.simulationHandler.ObtenerJornada())
+	fmt.Println("Los camiones vuelven al depósito al vaciarse para recargar, al terminar")
+	fmt.Println("su recorrido o cuando no alcanzan a atender la próxima cueva y volver.")
+
+	horas := ObtenerInputFloat("Duración de la jornada en horas: ")
+	if err := sm.simulationHandler.EstablecerJornada(horas); err != nil {
+		fmt.Printf("ERROR: %s\n", err.Error())
+		return
+	}
+	fmt.Printf("EXITO: La jornada dura %.2f horas\n", horas)
+}
+
 // Métodos auxiliares
 
+func (sm *SimulationMenu) seleccionarAlgoritmoCamino() string {
+	fmt.Println("Algoritmos disponibles:")
+	for i, algoritmo := range algorithms.AlgoritmosCamino {
+		fmt.Printf("%d. %s\n", i+1, algoritmo)
+	}
+
+	opcion, err := strconv.Atoi(LeerEntrada("Seleccione algoritmo: "))
+	if err != nil || opcion < 1 || opcion > len(algorithms.AlgoritmosCamino) {
+		fmt.Println("ERROR: Algoritmo no válido")
+		return ""
+	}
+	return string(algorithms.AlgoritmosCamino[opcion-1])
+}
+
 func (sm *SimulationMenu) obtenerParametrosSimulacion() (string, string) {
 	// Verificar que hay camiones
 	camiones := sm.simulationHandler.ListarTodosLosCamiones()
@@ -427,6 +828,15 @@ func (sm *SimulationMenu) mostrarResultadoSimulacion(resultado *service.Simulaci
 	} else {
 		fmt.Println("ERROR: La simulación tuvo problemas")
 	}
+
+	if SolicitarConfirmacion("¿Desea exportar la ruta del camión resaltada en Graphviz DOT?") {
+		nombreArchivo := LeerEntrada("Nombre del archivo DOT: ")
+		if err := sm.grafoHandler.ExportarSimulacionDOT(nombreArchivo, resultado); err != nil {
+			fmt.Printf("ERROR: Error al exportar: %s\n", err.Error())
+			return
+		}
+		fmt.Println("EXITO: Ruta exportada en formato DOT")
+	}
 }
 
 func (sm *SimulationMenu) reiniciarCamion() {
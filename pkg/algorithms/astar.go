@@ -0,0 +1,100 @@
+package algorithms
+
+import (
+	"container/heap"
+	"fmt"
+	"proyecto-grafos-go/internal/domain"
+	"proyecto-grafos-go/pkg/utils"
+)
+
+// Heuristica estima la distancia restante entre una cueva y el destino. Para
+// que A* encuentre el camino óptimo no debe sobrestimar la distancia real.
+type Heuristica func(cueva, destino *domain.Cueva) float64
+
+// HeuristicaEuclidiana usa la distancia en línea recta entre las coordenadas X/Y
+func HeuristicaEuclidiana(cueva, destino *domain.Cueva) float64 {
+	return utils.CalcularDistanciaEuclidiana(cueva.X, cueva.Y, destino.X, destino.Y)
+}
+
+// HeuristicaAdmisible devuelve la heurística euclidiana escalada por la menor razón
+// distancia/línea recta de los túneles recorribles, de modo que nunca sobrestima
+// aunque algún túnel sea más corto que la separación entre sus cuevas. Con un
+// túnel de distancia 0 entre cuevas separadas la heurística es 0 (Dijkstra).
+func HeuristicaAdmisible(grafo *domain.Grafo) Heuristica {
+	factor := 1.0
+	for _, arista := range grafo.Aristas {
+		desde, okDesde := grafo.Cuevas[arista.Desde]
+		hasta, okHasta := grafo.Cuevas[arista.Hasta]
+		if arista.EsObstruido || !okDesde || !okHasta {
+			continue
+		}
+		if recta := HeuristicaEuclidiana(desde, hasta); recta > 0 && arista.Distancia/recta < factor {
+			factor = arista.Distancia / recta
+		}
+	}
+
+	if factor >= 1 {
+		return HeuristicaEuclidiana
+	}
+	if factor <= 0 {
+		return nil
+	}
+	return func(cueva, destino *domain.Cueva) float64 {
+		return factor * HeuristicaEuclidiana(cueva, destino)
+	}
+}
+
+// AEstrella busca el camino más corto guiado por una heurística; sin heurística equivale a Dijkstra
+func AEstrella(grafo *domain.Grafo, desde, hasta string, heuristica Heuristica) (*ResultadoCamino, error) {
+	if err := validarExtremos(grafo, desde, hasta); err != nil {
+		return nil, err
+	}
+
+	destino := grafo.Cuevas[hasta]
+	estimaciones := make(map[string]float64)
+	estimar := func(id string) float64 {
+		if heuristica == nil {
+			return 0
+		}
+		estimacion, ok := estimaciones[id]
+		if !ok {
+			estimacion = heuristica(grafo.Cuevas[id], destino)
+			estimaciones[id] = estimacion
+		}
+		return estimacion
+	}
+
+	distancias := map[string]float64{desde: 0}
+	predecesores := make(map[string]string)
+	resultado := &ResultadoCamino{Algoritmo: CaminoAEstrella}
+
+	cola := &colaNodos{{id: desde, distancia: estimar(desde)}}
+	for cola.Len() > 0 {
+		entrada := heap.Pop(cola).(nodoDistancia)
+		nodo := entrada.id
+		// Descartar entradas de una distancia ya mejorada
+		if entrada.distancia > distancias[nodo]+estimar(nodo) {
+			continue
+		}
+		if nodo == hasta {
+			resultado.Ruta = reconstruirRuta(predecesores, desde, hasta)
+			resultado.Costo = distancias[hasta]
+			return resultado, nil
+		}
+		resultado.NodosExpandidos++
+
+		for _, t := range tramosDesde(grafo, nodo, false) {
+			if t.distancia < 0 {
+				return nil, fmt.Errorf("distancia negativa en %s -> %s; use Bellman-Ford", nodo, t.vecino)
+			}
+			nuevaDistancia := distancias[nodo] + t.distancia
+			if actual, ok := distancias[t.vecino]; !ok || nuevaDistancia < actual {
+				distancias[t.vecino] = nuevaDistancia
+				predecesores[t.vecino] = nodo
+				heap.Push(cola, nodoDistancia{id: t.vecino, distancia: nuevaDistancia + estimar(t.vecino)})
+			}
+		}
+	}
+
+	return nil, fmt.Errorf("no hay ruta desde '%s' hasta '%s'", desde, hasta)
+}
@@ -0,0 +1,80 @@
+package algorithms
+
+import (
+	"fmt"
+	"math"
+	"proyecto-grafos-go/internal/domain"
+	"sort"
+)
+
+// BellmanFord calcula las distancias mínimas desde una cueva admitiendo
+// distancias negativas (costos con signo). Devuelve error si hay un ciclo de
+// costo negativo alcanzable, y la cantidad de cuevas expandidas.
+func BellmanFord(grafo *domain.Grafo, nodoInicio string) (map[string]float64, map[string]string, int, error) {
+	if grafo == nil {
+		return nil, nil, 0, fmt.Errorf("grafo no puede ser nil")
+	}
+	if _, existe := grafo.Cuevas[nodoInicio]; !existe {
+		return nil, nil, 0, fmt.Errorf("nodo de inicio '%s' no existe en el grafo", nodoInicio)
+	}
+
+	// Orden fijo de cuevas para que el resultado sea determinista
+	ids := make([]string, 0, len(grafo.Cuevas))
+	for id := range grafo.Cuevas {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	distancias := make(map[string]float64, len(ids))
+	for _, id := range ids {
+		distancias[id] = math.Inf(1)
+	}
+	distancias[nodoInicio] = 0
+	predecesores := make(map[string]string)
+	expandidos := 0
+
+	// Una ronda más que cuevas - 1: si en ella aún hay mejoras, hay un ciclo negativo
+	for ronda := 0; ronda < len(ids); ronda++ {
+		cambio := false
+		for _, nodo := range ids {
+			if math.IsInf(distancias[nodo], 1) {
+				continue
+			}
+			expandidos++
+			for _, t := range tramosDesde(grafo, nodo, false) {
+				if nuevaDistancia := distancias[nodo] + t.distancia; nuevaDistancia < distancias[t.vecino] {
+					distancias[t.vecino] = nuevaDistancia
+					predecesores[t.vecino] = nodo
+					cambio = true
+				}
+			}
+		}
+		if !cambio {
+			return distancias, predecesores, expandidos, nil
+		}
+	}
+
+	return nil, nil, expandidos, fmt.Errorf("hay un ciclo de costo negativo alcanzable desde '%s'", nodoInicio)
+}
+
+// BellmanFordCamino busca el camino de menor costo entre dos cuevas con Bellman-Ford
+func BellmanFordCamino(grafo *domain.Grafo, desde, hasta string) (*ResultadoCamino, error) {
+	if err := validarExtremos(grafo, desde, hasta); err != nil {
+		return nil, err
+	}
+
+	distancias, predecesores, expandidos, err := BellmanFord(grafo, desde)
+	if err != nil {
+		return nil, err
+	}
+	if math.IsInf(distancias[hasta], 1) {
+		return nil, fmt.Errorf("no hay ruta desde '%s' hasta '%s'", desde, hasta)
+	}
+
+	return &ResultadoCamino{
+		Algoritmo:       CaminoBellmanFord,
+		Ruta:            reconstruirRuta(predecesores, desde, hasta),
+		Costo:           distancias[hasta],
+		NodosExpandidos: expandidos,
+	}, nil
+}
@@ -27,7 +27,7 @@ func BFS(grafo *domain.Grafo, nodoInicio string) ([]string, error) {
 		resultado = append(resultado, nodoActual)
 
 		// Obtener vecinos del nodo actual
-		for _, arista := range grafo.Aristas {
+		for _, arista := range grafo.AristasIncidentes(nodoActual) {
 			if arista.Desde == nodoActual && !arista.EsObstruido {
 				if !visitados[arista.Hasta] {
 					visitados[arista.Hasta] = true
@@ -71,7 +71,7 @@ func BFSConDistancias(grafo *domain.Grafo, nodoInicio string) ([]string, map[str
 		resultado = append(resultado, nodoActual)
 
 		// Obtener vecinos del nodo actual
-		for _, arista := range grafo.Aristas {
+		for _, arista := range grafo.AristasIncidentes(nodoActual) {
 			if arista.Desde == nodoActual && !arista.EsObstruido {
 				if !visitados[arista.Hasta] {
 					visitados[arista.Hasta] = true
@@ -114,7 +114,7 @@ func BFSNiveles(grafo *domain.Grafo, nodoInicio string) ([][]string, error) {
 
 		for _, nodo := range nivelActual {
 			// Obtener vecinos del nodo actual
-			for _, arista := range grafo.Aristas {
+			for _, arista := range grafo.AristasIncidentes(nodo) {
 				if arista.Desde == nodo && !arista.EsObstruido {
 					if !visitados[arista.Hasta] {
 						visitados[arista.Hasta] = true
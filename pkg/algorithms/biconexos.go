@@ -0,0 +1,207 @@
+package algorithms
+
+import (
+	"fmt"
+	"proyecto-grafos-go/internal/domain"
+	"sort"
+)
+
+// CuevaCritica es un punto de articulación: su colapso divide la red
+type CuevaCritica struct {
+	Cueva          string `json:"cueva"`
+	Fragmentos     int    `json:"fragmentos"`      // Partes en que queda dividido su componente
+	CuevasAisladas int    `json:"cuevas_aisladas"` // Cuevas separadas de la parte más grande
+}
+
+// TunelCritico es un puente: su colapso desconecta la red
+type TunelCritico struct {
+	Desde          string `json:"desde"`
+	Hasta          string `json:"hasta"`
+	CuevasAisladas int    `json:"cuevas_aisladas"` // Cuevas del lado más pequeño
+}
+
+// ResultadoBiconexion reúne los puntos únicos de falla y los bloques biconexos
+type ResultadoBiconexion struct {
+	CuevasCriticas  []CuevaCritica `json:"cuevas_criticas"` // De mayor a menor impacto
+	TunelesCriticos []TunelCritico `json:"tuneles_criticos"`
+	Bloques         [][]string     `json:"bloques"` // Componentes biconexos, cada uno ordenado
+}
+
+// biconexion guarda el estado del DFS de Hopcroft-Tarjan
+type biconexion struct {
+	adyacencia map[string][]string
+	orden      map[string]int // Momento de descubrimiento
+	bajo       map[string]int // Menor orden alcanzable desde el subárbol
+	tamano     map[string]int // Cuevas del subárbol
+	tiempo     int
+	pila       [][2]string // Túneles del bloque en construcción
+	fragmentos map[string][]int
+	resultado  *ResultadoBiconexion
+}
+
+// AnalizarBiconexion encuentra los puentes, los puntos de articulación y los
+// bloques biconexos de un grafo no dirigido, ignorando los túneles obstruidos
+func AnalizarBiconexion(grafo *domain.Grafo) (*ResultadoBiconexion, error) {
+	if grafo == nil {
+		return nil, fmt.Errorf("grafo no puede ser nil")
+	}
+	if grafo.EsDirigido {
+		return nil, fmt.Errorf("el análisis de puentes y bloques biconexos requiere un grafo no dirigido")
+	}
+
+	// Adyacencia simple: cada túnel se guarda en ambos sentidos en Aristas
+	ids := make([]string, 0, len(grafo.Cuevas))
+	for id := range grafo.Cuevas {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	pares := make(map[[2]string]bool)
+	b := &biconexion{
+		adyacencia: make(map[string][]string, len(ids)),
+		orden:      make(map[string]int, len(ids)),
+		bajo:       make(map[string]int, len(ids)),
+		tamano:     make(map[string]int, len(ids)),
+		fragmentos: make(map[string][]int),
+		resultado:  &ResultadoBiconexion{CuevasCriticas: []CuevaCritica{}, TunelesCriticos: []TunelCritico{}},
+	}
+	for _, arista := range grafo.ObtenerAristas() {
+		desde, hasta := arista.Desde, arista.Hasta
+		if arista.EsObstruido || desde == hasta || grafo.Cuevas[desde] == nil || grafo.Cuevas[hasta] == nil {
+			continue
+		}
+		if hasta < desde {
+			desde, hasta = hasta, desde
+		}
+		if !pares[[2]string{desde, hasta}] {
+			pares[[2]string{desde, hasta}] = true
+			b.adyacencia[desde] = append(b.adyacencia[desde], hasta)
+			b.adyacencia[hasta] = append(b.adyacencia[hasta], desde)
+		}
+	}
+	for _, vecinos := range b.adyacencia {
+		sort.Strings(vecinos)
+	}
+
+	for _, id := range ids {
+		if _, visitada := b.orden[id]; visitada {
+			continue
+		}
+		b.visitar(id, "")
+		if len(b.adyacencia[id]) == 0 {
+			b.resultado.Bloques = append(b.resultado.Bloques, []string{id})
+		}
+		b.cerrarComponente(id)
+	}
+
+	criticas, tuneles := b.resultado.CuevasCriticas, b.resultado.TunelesCriticos
+	sort.Slice(criticas, func(i, j int) bool {
+		if criticas[i].CuevasAisladas != criticas[j].CuevasAisladas {
+			return criticas[i].CuevasAisladas > criticas[j].CuevasAisladas
+		}
+		return criticas[i].Cueva < criticas[j].Cueva
+	})
+	sort.SliceStable(tuneles, func(i, j int) bool {
+		return tuneles[i].CuevasAisladas > tuneles[j].CuevasAisladas
+	})
+	return b.resultado, nil
+}
+
+func (b *biconexion) visitar(cueva, padre string) {
+	b.orden[cueva] = b.tiempo
+	b.bajo[cueva] = b.tiempo
+	b.tamano[cueva] = 1
+	b.tiempo++
+
+	for _, vecino := range b.adyacencia[cueva] {
+		if vecino == padre {
+			continue
+		}
+		if _, visitado := b.orden[vecino]; visitado {
+			// Túnel de retroceso hacia un ancestro
+			if b.orden[vecino] < b.orden[cueva] {
+				b.pila = append(b.pila, [2]string{cueva, vecino})
+				b.bajo[cueva] = min(b.bajo[cueva], b.orden[vecino])
+			}
+			continue
+		}
+
+		b.pila = append(b.pila, [2]string{cueva, vecino})
+		b.visitar(vecino, cueva)
+		b.tamano[cueva] += b.tamano[vecino]
+		b.bajo[cueva] = min(b.bajo[cueva], b.bajo[vecino])
+
+		if b.bajo[vecino] > b.orden[cueva] {
+			b.resultado.TunelesCriticos = append(b.resultado.TunelesCriticos, TunelCritico{Desde: cueva, Hasta: vecino, CuevasAisladas: b.tamano[vecino]})
+		}
+		if b.bajo[vecino] >= b.orden[cueva] {
+			// El subárbol del vecino queda separado si cae la cueva
+			b.fragmentos[cueva] = append(b.fragmentos[cueva], b.tamano[vecino])
+			b.cerrarBloque(cueva, vecino)
+		}
+	}
+}
+
+// cerrarBloque saca de la pila los túneles del bloque que termina en cueva -> vecino
+func (b *biconexion) cerrarBloque(cueva, vecino string) {
+	miembros := make(map[string]bool)
+	for len(b.pila) > 0 {
+		tunel := b.pila[len(b.pila)-1]
+		b.pila = b.pila[:len(b.pila)-1]
+		miembros[tunel[0]], miembros[tunel[1]] = true, true
+		if tunel == [2]string{cueva, vecino} {
+			break
+		}
+	}
+
+	bloque := make([]string, 0, len(miembros))
+	for id := range miembros {
+		bloque = append(bloque, id)
+	}
+	sort.Strings(bloque)
+	b.resultado.Bloques = append(b.resultado.Bloques, bloque)
+}
+
+// cerrarComponente calcula el impacto de las fallas del componente recién recorrido
+func (b *biconexion) cerrarComponente(raiz string) {
+	total := b.tamano[raiz]
+
+	// Los puentes aíslan el lado más pequeño
+	for i := range b.resultado.TunelesCriticos {
+		tunel := &b.resultado.TunelesCriticos[i]
+		if b.orden[tunel.Desde] >= b.orden[raiz] && b.orden[tunel.Desde] < b.orden[raiz]+total {
+			tunel.CuevasAisladas = min(tunel.CuevasAisladas, total-tunel.CuevasAisladas)
+		}
+	}
+
+	// Una cueva es crítica si separa al menos un subárbol, y la raíz si separa dos
+	for cueva, separados := range b.fragmentos {
+		piezas := append([]int{}, separados...)
+		if resto := total - 1 - suma(separados); resto > 0 {
+			piezas = append(piezas, resto)
+		}
+		delete(b.fragmentos, cueva)
+		if len(piezas) < 2 {
+			continue
+		}
+
+		mayor := 0
+		for _, pieza := range piezas {
+			if pieza > mayor {
+				mayor = pieza
+			}
+		}
+		b.resultado.CuevasCriticas = append(b.resultado.CuevasCriticas, CuevaCritica{
+			Cueva:          cueva,
+			Fragmentos:     len(piezas),
+			CuevasAisladas: total - 1 - mayor,
+		})
+	}
+}
+
+func suma(valores []int) int {
+	total := 0
+	for _, valor := range valores {
+		total += valor
+	}
+	return total
+}
@@ -0,0 +1,70 @@
+package algorithms
+
+import (
+	"proyecto-grafos-go/internal/domain"
+	"reflect"
+	"testing"
+)
+
+// dos triángulos A-B-C y D-E-F unidos por el puente C-D, con G colgando de F y Z aislada
+func crearRedBiconexa() *domain.Grafo {
+	grafo := domain.NuevoGrafo(false)
+	for _, id := range []string{"A", "B", "C", "D", "E", "F", "G", "Z"} {
+		grafo.AgregarCueva(domain.NuevaCueva(id, id))
+	}
+	for _, par := range [][2]string{{"A", "B"}, {"B", "C"}, {"C", "A"}, {"C", "D"}, {"D", "E"}, {"E", "F"}, {"F", "D"}, {"F", "G"}} {
+		grafo.AgregarConexion(par[0], par[1], 1)
+	}
+	return grafo
+}
+
+func TestAnalizarBiconexion(t *testing.T) {
+	resultado, err := AnalizarBiconexion(crearRedBiconexa())
+	if err != nil {
+		t.Fatalf("Error en el análisis: %v", err)
+	}
+
+	criticas := []CuevaCritica{
+		{Cueva: "D", Fragmentos: 2, CuevasAisladas: 3},
+		{Cueva: "C", Fragmentos: 2, CuevasAisladas: 2},
+		{Cueva: "F", Fragmentos: 2, CuevasAisladas: 1},
+	}
+	if !reflect.DeepEqual(resultado.CuevasCriticas, criticas) {
+		t.Errorf("Cuevas críticas %+v, se esperaba %+v", resultado.CuevasCriticas, criticas)
+	}
+
+	tuneles := []TunelCritico{
+		{Desde: "C", Hasta: "D", CuevasAisladas: 3},
+		{Desde: "F", Hasta: "G", CuevasAisladas: 1},
+	}
+	if !reflect.DeepEqual(resultado.TunelesCriticos, tuneles) {
+		t.Errorf("Túneles críticos %+v, se esperaba %+v", resultado.TunelesCriticos, tuneles)
+	}
+
+	bloques := [][]string{{"F", "G"}, {"D", "E", "F"}, {"C", "D"}, {"A", "B", "C"}, {"Z"}}
+	if !reflect.DeepEqual(resultado.Bloques, bloques) {
+		t.Errorf("Bloques %v, se esperaba %v", resultado.Bloques, bloques)
+	}
+}
+
+func TestAnalizarBiconexionObstruidos(t *testing.T) {
+	grafo := crearRedBiconexa()
+	// Un túnel obstruido ya no sostiene el triángulo D-E-F: D-E y E-F pasan a ser puentes
+	for _, arista := range grafo.Aristas {
+		if (arista.Desde == "F" && arista.Hasta == "D") || (arista.Desde == "D" && arista.Hasta == "F") {
+			arista.EsObstruido = true
+		}
+	}
+
+	resultado, err := AnalizarBiconexion(grafo)
+	if err != nil {
+		t.Fatalf("Error en el análisis: %v", err)
+	}
+	if len(resultado.TunelesCriticos) != 4 {
+		t.Errorf("Se esperaban 4 túneles críticos: %+v", resultado.TunelesCriticos)
+	}
+
+	if _, err := AnalizarBiconexion(domain.NuevoGrafo(true)); err == nil {
+		t.Error("Se esperaba un error con un grafo dirigido")
+	}
+}
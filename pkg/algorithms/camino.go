@@ -0,0 +1,114 @@
+package algorithms
+
+import (
+	"fmt"
+	"proyecto-grafos-go/internal/domain"
+)
+
+// AlgoritmoCamino identifica un algoritmo de camino más corto entre dos cuevas
+type AlgoritmoCamino string
+
+const (
+	CaminoDijkstra              AlgoritmoCamino = "dijkstra"
+	CaminoAEstrella             AlgoritmoCamino = "a_estrella"
+	CaminoDijkstraBidireccional AlgoritmoCamino = "dijkstra_bidireccional"
+	CaminoBellmanFord           AlgoritmoCamino = "bellman_ford"
+)
+
+// AlgoritmosCamino lista los algoritmos disponibles en el orden en que se ofrecen
+var AlgoritmosCamino = []AlgoritmoCamino{
+	CaminoDijkstra,
+	CaminoAEstrella,
+	CaminoDijkstraBidireccional,
+	CaminoBellmanFord,
+}
+
+// ResultadoCamino es el resultado de una búsqueda de camino entre dos cuevas
+type ResultadoCamino struct {
+	Algoritmo       AlgoritmoCamino `json:"algoritmo"`
+	Ruta            []string        `json:"ruta"`
+	Costo           float64         `json:"costo"`
+	NodosExpandidos int             `json:"nodos_expandidos"`
+}
+
+// BuscarCamino busca el camino más corto con el algoritmo indicado; A* usa la
+// heurística euclidiana, escalada si algún túnel es más corto que la línea recta
+func BuscarCamino(grafo *domain.Grafo, algoritmo AlgoritmoCamino, desde, hasta string) (*ResultadoCamino, error) {
+	switch algoritmo {
+	case CaminoDijkstra, "":
+		return DijkstraCamino(grafo, desde, hasta)
+	case CaminoAEstrella:
+		return AEstrella(grafo, desde, hasta, HeuristicaAdmisible(grafo))
+	case CaminoDijkstraBidireccional:
+		return DijkstraBidireccional(grafo, desde, hasta)
+	case CaminoBellmanFord:
+		return BellmanFordCamino(grafo, desde, hasta)
+	default:
+		return nil, fmt.Errorf("algoritmo de camino no válido: %s", algoritmo)
+	}
+}
+
+// DijkstraCamino es Dijkstra entre dos cuevas, deteniéndose al extraer el destino
+func DijkstraCamino(grafo *domain.Grafo, desde, hasta string) (*ResultadoCamino, error) {
+	resultado, err := AEstrella(grafo, desde, hasta, nil)
+	if err != nil {
+		return nil, err
+	}
+	resultado.Algoritmo = CaminoDijkstra
+	return resultado, nil
+}
+
+// tramo es una arista recorrible desde una cueva
+type tramo struct {
+	vecino    string
+	distancia float64
+}
+
+// tramosDesde devuelve las aristas no obstruidas que salen de una cueva; con
+// inverso devuelve las que llegan a ella, para buscar hacia atrás
+func tramosDesde(grafo *domain.Grafo, nodo string, inverso bool) []tramo {
+	var tramos []tramo
+	for _, arista := range grafo.AristasIncidentes(nodo) {
+		if arista.EsObstruido {
+			continue
+		}
+
+		origen, destino := arista.Desde, arista.Hasta
+		if inverso {
+			origen, destino = destino, origen
+		}
+		if origen == nodo {
+			tramos = append(tramos, tramo{vecino: destino, distancia: arista.Distancia})
+		} else if !grafo.EsDirigido && destino == nodo {
+			tramos = append(tramos, tramo{vecino: origen, distancia: arista.Distancia})
+		}
+	}
+	return tramos
+}
+
+// validarExtremos verifica el grafo y que ambas cuevas existan
+func validarExtremos(grafo *domain.Grafo, desde, hasta string) error {
+	if grafo == nil {
+		return fmt.Errorf("grafo no puede ser nil")
+	}
+	if _, existe := grafo.Cuevas[desde]; !existe {
+		return fmt.Errorf("nodo de inicio '%s' no existe en el grafo", desde)
+	}
+	if _, existe := grafo.Cuevas[hasta]; !existe {
+		return fmt.Errorf("nodo de destino '%s' no existe en el grafo", hasta)
+	}
+	return nil
+}
+
+// reconstruirRuta sigue los predecesores desde el destino hasta el inicio
+func reconstruirRuta(predecesores map[string]string, inicio, destino string) []string {
+	ruta := []string{destino}
+	for nodo := destino; nodo != inicio; {
+		nodo = predecesores[nodo]
+		ruta = append(ruta, nodo)
+	}
+	for i, j := 0, len(ruta)-1; i < j; i, j = i+1, j-1 {
+		ruta[i], ruta[j] = ruta[j], ruta[i]
+	}
+	return ruta
+}
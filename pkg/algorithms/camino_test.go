@@ -0,0 +1,200 @@
+package algorithms
+
+import (
+	"fmt"
+	"math"
+	"proyecto-grafos-go/internal/domain"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// crearCuadricula crea una cuadrícula no dirigida de lado n con túneles de distancia 1
+func crearCuadricula(n int) *domain.Grafo {
+	grafo := domain.NuevoGrafo(false)
+	id := func(x, y int) string { return fmt.Sprintf("C%d_%d", x, y) }
+	for x := 0; x < n; x++ {
+		for y := 0; y < n; y++ {
+			cueva := domain.NuevaCueva(id(x, y), id(x, y))
+			cueva.X, cueva.Y = float64(x), float64(y)
+			grafo.AgregarCueva(cueva)
+		}
+	}
+	for x := 0; x < n; x++ {
+		for y := 0; y < n; y++ {
+			if x+1 < n {
+				grafo.AgregarConexion(id(x, y), id(x+1, y), 1)
+			}
+			if y+1 < n {
+				grafo.AgregarConexion(id(x, y), id(x, y+1), 1)
+			}
+		}
+	}
+	return grafo
+}
+
+func TestBuscarCaminoCoincideConDijkstra(t *testing.T) {
+	grafo := crearCuadricula(8)
+	// Obstruir una columna casi completa para forzar un desvío
+	for y := 0; y < 7; y++ {
+		desde, hasta := fmt.Sprintf("C3_%d", y), fmt.Sprintf("C4_%d", y)
+		for _, arista := range grafo.Aristas {
+			if (arista.Desde == desde && arista.Hasta == hasta) || (arista.Desde == hasta && arista.Hasta == desde) {
+				arista.EsObstruido = true
+			}
+		}
+	}
+
+	_, esperado, err := DijkstraRuta(grafo, "C0_0", "C7_0")
+	if err != nil {
+		t.Fatalf("Error en DijkstraRuta: %v", err)
+	}
+
+	expandidos := make(map[AlgoritmoCamino]int)
+	for _, algoritmo := range AlgoritmosCamino {
+		resultado, err := BuscarCamino(grafo, algoritmo, "C0_0", "C7_0")
+		if err != nil {
+			t.Fatalf("%s: error inesperado: %v", algoritmo, err)
+		}
+		if resultado.Algoritmo != algoritmo {
+			t.Errorf("%s: algoritmo reportado %s", algoritmo, resultado.Algoritmo)
+		}
+		if math.Abs(resultado.Costo-esperado) > 1e-9 {
+			t.Errorf("%s: costo %.2f, se esperaba %.2f", algoritmo, resultado.Costo, esperado)
+		}
+		if costo := costoRuta(t, grafo, resultado.Ruta); math.Abs(costo-resultado.Costo) > 1e-9 {
+			t.Errorf("%s: la ruta %v cuesta %.2f y no %.2f", algoritmo, resultado.Ruta, costo, resultado.Costo)
+		}
+		expandidos[algoritmo] = resultado.NodosExpandidos
+	}
+
+	if expandidos[CaminoAEstrella] >= expandidos[CaminoDijkstra] {
+		t.Errorf("A* debería expandir menos nodos que Dijkstra: %v", expandidos)
+	}
+}
+
+func TestCaminoNodosExpandidos(t *testing.T) {
+	grafo := crearCuadricula(30)
+	expandidos := make(map[AlgoritmoCamino]int)
+	for _, algoritmo := range []AlgoritmoCamino{CaminoDijkstra, CaminoAEstrella, CaminoDijkstraBidireccional} {
+		resultado, err := BuscarCamino(grafo, algoritmo, "C0_15", "C29_15")
+		if err != nil || resultado.Costo != 29 {
+			t.Fatalf("%s: resultado %+v, error %v", algoritmo, resultado, err)
+		}
+		expandidos[algoritmo] = resultado.NodosExpandidos
+	}
+
+	if expandidos[CaminoAEstrella] >= expandidos[CaminoDijkstraBidireccional] ||
+		expandidos[CaminoDijkstraBidireccional] >= expandidos[CaminoDijkstra] {
+		t.Errorf("Se esperaba A* < bidireccional < Dijkstra en nodos expandidos: %v", expandidos)
+	}
+}
+
+// TestAEstrellaTunelesMasCortosQueLaRecta verifica que A* sigue siendo óptimo
+// cuando un túnel mide menos que la distancia entre las coordenadas de sus cuevas
+func TestAEstrellaTunelesMasCortosQueLaRecta(t *testing.T) {
+	grafo := domain.NuevoGrafo(false)
+	for _, datos := range []struct {
+		id   string
+		x, y float64
+	}{{"A", 0, 0}, {"B", 10, 0}, {"C", 100, 0}} {
+		cueva := domain.NuevaCueva(datos.id, datos.id)
+		cueva.X, cueva.Y = datos.x, datos.y
+		grafo.AgregarCueva(cueva)
+	}
+	grafo.AgregarConexion("A", "B", 20)
+	grafo.AgregarConexion("A", "C", 1)
+	grafo.AgregarConexion("C", "B", 1)
+
+	resultado, err := BuscarCamino(grafo, CaminoAEstrella, "A", "B")
+	if err != nil || resultado.Costo != 2 || !reflect.DeepEqual(resultado.Ruta, []string{"A", "C", "B"}) {
+		t.Errorf("resultado %+v, error %v", resultado, err)
+	}
+
+	// Un túnel de distancia 0 entre cuevas separadas anula la heurística
+	lejana := domain.NuevaCueva("D", "D")
+	lejana.X, lejana.Y = 50, 50
+	grafo.AgregarCueva(lejana)
+	grafo.AgregarConexion("A", "D", 0)
+	if HeuristicaAdmisible(grafo) != nil {
+		t.Error("Se esperaba heurística nula con un túnel de distancia 0")
+	}
+}
+
+func TestCaminoDirigido(t *testing.T) {
+	grafo := domain.NuevoGrafo(true)
+	for _, id := range []string{"A", "B", "C"} {
+		grafo.AgregarCueva(domain.NuevaCueva(id, id))
+	}
+	grafo.AgregarArista(domain.NuevaArista("A", "B", 1, true))
+	grafo.AgregarArista(domain.NuevaArista("B", "C", 1, true))
+	grafo.AgregarArista(domain.NuevaArista("A", "C", 5, true))
+
+	for _, algoritmo := range AlgoritmosCamino {
+		resultado, err := BuscarCamino(grafo, algoritmo, "A", "C")
+		if err != nil || resultado.Costo != 2 || !reflect.DeepEqual(resultado.Ruta, []string{"A", "B", "C"}) {
+			t.Errorf("%s: resultado %+v, error %v", algoritmo, resultado, err)
+		}
+		if _, err := BuscarCamino(grafo, algoritmo, "C", "A"); err == nil {
+			t.Errorf("%s: no debería haber ruta C -> A en un grafo dirigido", algoritmo)
+		}
+	}
+}
+
+func TestBellmanFordCostosNegativos(t *testing.T) {
+	grafo := domain.NuevoGrafo(true)
+	for _, id := range []string{"A", "B", "C", "D"} {
+		grafo.AgregarCueva(domain.NuevaCueva(id, id))
+	}
+	grafo.AgregarArista(domain.NuevaArista("A", "B", 4, true))
+	grafo.AgregarArista(domain.NuevaArista("A", "C", 2, true))
+	grafo.AgregarArista(domain.NuevaArista("B", "D", -3, true))
+	grafo.AgregarArista(domain.NuevaArista("C", "D", 2, true))
+
+	resultado, err := BellmanFordCamino(grafo, "A", "D")
+	if err != nil {
+		t.Fatalf("Error en Bellman-Ford: %v", err)
+	}
+	if resultado.Costo != 1 || !reflect.DeepEqual(resultado.Ruta, []string{"A", "B", "D"}) {
+		t.Errorf("Resultado inesperado: %+v", resultado)
+	}
+
+	if _, err := DijkstraBidireccional(grafo, "A", "D"); err == nil || !strings.Contains(err.Error(), "Bellman-Ford") {
+		t.Errorf("Dijkstra bidireccional debería rechazar distancias negativas: %v", err)
+	}
+
+	// Ciclo negativo D -> B -> D
+	grafo.AgregarArista(domain.NuevaArista("D", "B", 1, true))
+	if _, err := BellmanFordCamino(grafo, "A", "D"); err == nil || !strings.Contains(err.Error(), "ciclo") {
+		t.Errorf("Se esperaba un error por ciclo negativo: %v", err)
+	}
+}
+
+func TestBuscarCaminoErrores(t *testing.T) {
+	grafo := crearCuadricula(2)
+	if _, err := BuscarCamino(grafo, "voraz", "C0_0", "C1_1"); err == nil {
+		t.Error("Se esperaba un error por algoritmo desconocido")
+	}
+	if _, err := BuscarCamino(grafo, CaminoAEstrella, "C0_0", "Z"); err == nil {
+		t.Error("Se esperaba un error por cueva inexistente")
+	}
+
+	resultado, err := DijkstraBidireccional(grafo, "C1_1", "C1_1")
+	if err != nil || resultado.Costo != 0 || len(resultado.Ruta) != 1 {
+		t.Errorf("Camino trivial inesperado: %+v, %v", resultado, err)
+	}
+}
+
+// costoRuta suma las distancias de los túneles consecutivos de una ruta
+func costoRuta(t *testing.T, grafo *domain.Grafo, ruta []string) float64 {
+	t.Helper()
+	costo := 0.0
+	for i := 1; i < len(ruta); i++ {
+		arista, ok := grafo.ObtenerConexion(ruta[i-1], ruta[i])
+		if !ok || arista.EsObstruido {
+			t.Fatalf("La ruta %v usa un túnel inexistente %s -> %s", ruta, ruta[i-1], ruta[i])
+		}
+		costo += arista.Distancia
+	}
+	return costo
+}
@@ -0,0 +1,230 @@
+package algorithms
+
+import (
+	"fmt"
+	"math"
+	"proyecto-grafos-go/internal/domain"
+	"sort"
+	"strings"
+)
+
+// Condensacion es el DAG que resulta de reducir cada componente fuertemente
+// conectado a una sola cueva
+type Condensacion struct {
+	Grafo          *domain.Grafo                // Una cueva "CFC<n>" por componente, dirigido
+	Componentes    [][]string                   // Cuevas de cada componente; la posición i es la cueva CFC<i+1>
+	ComponenteDe   map[string]string            // Cueva original -> ID de su componente
+	Representantes map[[2]string]*domain.Arista // Túnel original más barato de cada arista del DAG
+}
+
+// TunelPropuesto es un túnel nuevo que acerca la red a ser fuertemente conectada
+type TunelPropuesto struct {
+	Desde           string  `json:"desde"`
+	Hasta           string  `json:"hasta"`
+	Distancia       float64 `json:"distancia"` // Distancia euclidiana entre ambas cuevas
+	ComponenteDesde string  `json:"componente_desde"`
+	ComponenteHasta string  `json:"componente_hasta"`
+}
+
+// CondensarGrafo construye el grafo de condensación a partir de los componentes
+// de Tarjan. Cada arista del DAG tiene la distancia del túnel no obstruido más
+// corto entre los dos componentes.
+func CondensarGrafo(grafo *domain.Grafo) (*Condensacion, error) {
+	if grafo == nil {
+		return nil, fmt.Errorf("grafo no puede ser nil")
+	}
+
+	componentes := ObtenerComponentes(grafo)
+	for _, componente := range componentes {
+		sort.Strings(componente)
+	}
+	sort.Slice(componentes, func(i, j int) bool {
+		return componentes[i][0] < componentes[j][0]
+	})
+
+	condensacion := &Condensacion{
+		Grafo:          domain.NuevoGrafo(true),
+		Componentes:    componentes,
+		ComponenteDe:   make(map[string]string, len(grafo.Cuevas)),
+		Representantes: make(map[[2]string]*domain.Arista),
+	}
+	for i, componente := range componentes {
+		id := fmt.Sprintf("CFC%d", i+1)
+		cueva := domain.NuevaCueva(id, strings.Join(componente, ", "))
+		// La cueva del componente se ubica en el centroide de sus cuevas
+		for _, miembro := range componente {
+			condensacion.ComponenteDe[miembro] = id
+			cueva.X += grafo.Cuevas[miembro].X / float64(len(componente))
+			cueva.Y += grafo.Cuevas[miembro].Y / float64(len(componente))
+		}
+		condensacion.Grafo.AgregarCueva(cueva)
+	}
+
+	for _, arista := range grafo.ObtenerAristas() {
+		desde, hasta := condensacion.ComponenteDe[arista.Desde], condensacion.ComponenteDe[arista.Hasta]
+		if arista.EsObstruido || desde == "" || hasta == "" || desde == hasta {
+			continue
+		}
+		clave := [2]string{desde, hasta}
+		if actual, ok := condensacion.Representantes[clave]; !ok || arista.Distancia < actual.Distancia {
+			condensacion.Representantes[clave] = arista
+		}
+	}
+	for clave, arista := range condensacion.Representantes {
+		condensacion.Grafo.AgregarArista(domain.NuevaArista(clave[0], clave[1], arista.Distancia, true))
+	}
+	return condensacion, nil
+}
+
+// TunelesParaConectividadFuerte propone el mínimo de túneles de un solo sentido
+// que hacen fuertemente conectada la red: max(fuentes, sumideros) del DAG de
+// condensación (Eswaran-Tarjan). En un grafo no dirigido son los túneles que
+// unen sus componentes conexos. Cada túnel une las cuevas más cercanas de los
+// componentes que conecta.
+func TunelesParaConectividadFuerte(grafo *domain.Grafo) ([]TunelPropuesto, *Condensacion, error) {
+	condensacion, err := CondensarGrafo(grafo)
+	if err != nil {
+		return nil, nil, err
+	}
+	n := len(condensacion.Componentes)
+	propuestos := []TunelPropuesto{}
+	if n <= 1 {
+		return propuestos, condensacion, nil
+	}
+
+	// En un grafo no dirigido basta encadenar los componentes conexos
+	if !grafo.EsDirigido {
+		for i := 0; i+1 < n; i++ {
+			propuestos = append(propuestos, tunelMasCercano(grafo, condensacion, i, i+1))
+		}
+		return propuestos, condensacion, nil
+	}
+
+	indice := make(map[string]int, n)
+	for i := 0; i < n; i++ {
+		indice[fmt.Sprintf("CFC%d", i+1)] = i
+	}
+	salientes := make([][]int, n)
+	entrantes := make([][]int, n)
+	for clave := range condensacion.Representantes {
+		desde, hasta := indice[clave[0]], indice[clave[1]]
+		salientes[desde] = append(salientes[desde], hasta)
+		entrantes[hasta] = append(entrantes[hasta], desde)
+	}
+	for i := 0; i < n; i++ {
+		sort.Ints(salientes[i])
+		sort.Ints(entrantes[i])
+	}
+
+	// Con más fuentes que sumideros se resuelve el DAG inverso y se invierten los túneles
+	fuentes, sumideros := extremosDAG(entrantes), extremosDAG(salientes)
+	invertido := len(fuentes) > len(sumideros)
+	if invertido {
+		salientes, entrantes = entrantes, salientes
+		fuentes, sumideros = sumideros, fuentes
+	}
+
+	for _, par := range aumentoEswaranTarjan(salientes, fuentes, sumideros) {
+		desde, hasta := par[0], par[1]
+		if invertido {
+			desde, hasta = hasta, desde
+		}
+		propuestos = append(propuestos, tunelMasCercano(grafo, condensacion, desde, hasta))
+	}
+	return propuestos, condensacion, nil
+}
+
+// aumentoEswaranTarjan devuelve los pares de componentes (sumidero -> fuente)
+// a conectar, suponiendo que no hay más fuentes que sumideros
+func aumentoEswaranTarjan(salientes [][]int, fuentes, sumideros []int) [][2]int {
+	esSumidero := make([]bool, len(salientes))
+	for _, sumidero := range sumideros {
+		esSumidero[sumidero] = true
+	}
+
+	// Emparejar cada fuente con un sumidero alcanzable por cuevas aún no visitadas
+	visitados := make([]bool, len(salientes))
+	var buscar func(v int) int
+	buscar = func(v int) int {
+		visitados[v] = true
+		if esSumidero[v] {
+			return v
+		}
+		for _, w := range salientes[v] {
+			if !visitados[w] {
+				if sumidero := buscar(w); sumidero != -1 {
+					return sumidero
+				}
+			}
+		}
+		return -1
+	}
+	var fuentesPareadas, sumiderosPareados, fuentesSolas []int
+	emparejado := make([]bool, len(salientes))
+	for _, fuente := range fuentes {
+		if visitados[fuente] {
+			fuentesSolas = append(fuentesSolas, fuente)
+			continue
+		}
+		if sumidero := buscar(fuente); sumidero != -1 {
+			fuentesPareadas = append(fuentesPareadas, fuente)
+			sumiderosPareados = append(sumiderosPareados, sumidero)
+			emparejado[sumidero] = true
+		} else {
+			fuentesSolas = append(fuentesSolas, fuente)
+		}
+	}
+	var sumiderosSolos []int
+	for _, sumidero := range sumideros {
+		if !emparejado[sumidero] {
+			sumiderosSolos = append(sumiderosSolos, sumidero)
+		}
+	}
+
+	// Los pares forman un ciclo; cada fuente sin par recibe un sumidero sin par
+	// y los sumideros restantes se encadenan antes de cerrar el ciclo
+	p := len(fuentesPareadas)
+	var pares [][2]int
+	for i := 0; i+1 < p; i++ {
+		pares = append(pares, [2]int{sumiderosPareados[i], fuentesPareadas[i+1]})
+	}
+	for i, fuente := range fuentesSolas {
+		pares = append(pares, [2]int{sumiderosSolos[i], fuente})
+	}
+	ultimo := sumiderosPareados[p-1]
+	for _, sumidero := range sumiderosSolos[len(fuentesSolas):] {
+		pares = append(pares, [2]int{ultimo, sumidero})
+		ultimo = sumidero
+	}
+	pares = append(pares, [2]int{ultimo, fuentesPareadas[0]})
+	return pares
+}
+
+// extremosDAG devuelve los componentes sin aristas en la lista de adyacencia dada
+func extremosDAG(adyacencia [][]int) []int {
+	var extremos []int
+	for i, vecinos := range adyacencia {
+		if len(vecinos) == 0 {
+			extremos = append(extremos, i)
+		}
+	}
+	return extremos
+}
+
+// tunelMasCercano elige el par de cuevas más cercanas entre dos componentes
+func tunelMasCercano(grafo *domain.Grafo, condensacion *Condensacion, desde, hasta int) TunelPropuesto {
+	mejor := TunelPropuesto{
+		Distancia:       math.Inf(1),
+		ComponenteDesde: fmt.Sprintf("CFC%d", desde+1),
+		ComponenteHasta: fmt.Sprintf("CFC%d", hasta+1),
+	}
+	for _, origen := range condensacion.Componentes[desde] {
+		for _, destino := range condensacion.Componentes[hasta] {
+			a, b := grafo.Cuevas[origen], grafo.Cuevas[destino]
+			if distancia := math.Hypot(a.X-b.X, a.Y-b.Y); distancia < mejor.Distancia {
+				mejor.Desde, mejor.Hasta, mejor.Distancia = origen, destino, distancia
+			}
+		}
+	}
+	return mejor
+}
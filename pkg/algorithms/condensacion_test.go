@@ -0,0 +1,96 @@
+package algorithms
+
+import (
+	"fmt"
+	"math/rand"
+	"proyecto-grafos-go/internal/domain"
+	"reflect"
+	"testing"
+)
+
+func TestCondensarGrafo(t *testing.T) {
+	// Dos ciclos A-B-C y D-E unidos por dos túneles, más la cueva suelta F
+	grafo := crearRedDirigida([]string{"A", "B", "C", "D", "E", "F"}, []tunelPrueba{
+		{"A", "B", 1}, {"B", "C", 1}, {"C", "A", 1}, {"D", "E", 1}, {"E", "D", 1},
+		{"B", "D", 7}, {"C", "E", 3},
+	})
+
+	condensacion, err := CondensarGrafo(grafo)
+	if err != nil {
+		t.Fatalf("Error condensando el grafo: %v", err)
+	}
+	esperados := [][]string{{"A", "B", "C"}, {"D", "E"}, {"F"}}
+	if !reflect.DeepEqual(condensacion.Componentes, esperados) {
+		t.Fatalf("Componentes %v, se esperaba %v", condensacion.Componentes, esperados)
+	}
+	if condensacion.Grafo.NumeroCuevas() != 3 || len(condensacion.Grafo.Aristas) != 1 {
+		t.Fatalf("Condensación inesperada: %v", condensacion.Grafo)
+	}
+	arista, ok := condensacion.Grafo.ObtenerConexion("CFC1", "CFC2")
+	if !ok || arista.Distancia != 3 {
+		t.Errorf("La arista CFC1 -> CFC2 debe tener el túnel más barato (3): %v", arista)
+	}
+	if representante := condensacion.Representantes[[2]string{"CFC1", "CFC2"}]; representante.Desde != "C" || representante.Hasta != "E" {
+		t.Errorf("Representante inesperado: %v", representante)
+	}
+
+	// Fuentes CFC1 y CFC3, sumideros CFC2 y CFC3: bastan dos túneles
+	propuestos, _, err := TunelesParaConectividadFuerte(grafo)
+	if err != nil || len(propuestos) != 2 {
+		t.Fatalf("Se esperaban 2 túneles, se obtuvieron %+v (%v)", propuestos, err)
+	}
+	agregarPropuestos(grafo, propuestos)
+	if !VerificarConectividadFuerte(grafo) {
+		t.Errorf("Los túneles %+v no hacen fuertemente conectada la red", propuestos)
+	}
+}
+
+func TestTunelesParaConectividadFuerteAleatorio(t *testing.T) {
+	r := rand.New(rand.NewSource(18))
+	for i := 0; i < 200; i++ {
+		grafo := domain.NuevoGrafo(true)
+		n := 1 + r.Intn(12)
+		for j := 0; j < n; j++ {
+			cueva := domain.NuevaCueva(fmt.Sprintf("C%02d", j), "")
+			cueva.X, cueva.Y = r.Float64()*100, r.Float64()*100
+			grafo.AgregarCueva(cueva)
+		}
+		for j := r.Intn(2 * n); j > 0; j-- {
+			desde, hasta := fmt.Sprintf("C%02d", r.Intn(n)), fmt.Sprintf("C%02d", r.Intn(n))
+			if desde != hasta {
+				grafo.AgregarArista(domain.NuevaArista(desde, hasta, 1, true))
+			}
+		}
+
+		propuestos, condensacion, err := TunelesParaConectividadFuerte(grafo)
+		if err != nil {
+			t.Fatalf("iteración %d: %v", i, err)
+		}
+		fuentes, sumideros := 0, 0
+		for _, cueva := range condensacion.Grafo.Cuevas {
+			if len(condensacion.Grafo.ProximasAristas(cueva.ID)) == 0 {
+				fuentes++
+			}
+			if len(condensacion.Grafo.AristasSalientes(cueva.ID)) == 0 {
+				sumideros++
+			}
+		}
+		minimo := max(fuentes, sumideros)
+		if len(condensacion.Componentes) == 1 {
+			minimo = 0
+		}
+		if len(propuestos) != minimo {
+			t.Fatalf("iteración %d: %d túneles propuestos, el mínimo es %d", i, len(propuestos), minimo)
+		}
+		agregarPropuestos(grafo, propuestos)
+		if !VerificarConectividadFuerte(grafo) {
+			t.Fatalf("iteración %d: los túneles %+v no hacen fuertemente conectada la red", i, propuestos)
+		}
+	}
+}
+
+func agregarPropuestos(grafo *domain.Grafo, propuestos []TunelPropuesto) {
+	for _, propuesto := range propuestos {
+		grafo.AgregarArista(domain.NuevaArista(propuesto.Desde, propuesto.Hasta, propuesto.Distancia, true))
+	}
+}
@@ -0,0 +1,341 @@
+package algorithms
+
+import (
+	"fmt"
+	"math"
+	"proyecto-grafos-go/internal/domain"
+	"sort"
+)
+
+// VehiculoCVRP es un camión disponible para el ruteo con su capacidad en unidades
+type VehiculoCVRP struct {
+	ID        string `json:"id"`
+	Capacidad int    `json:"capacidad"`
+}
+
+// RutaCVRP es el recorrido de un vehículo que sale del depósito y vuelve a él
+type RutaCVRP struct {
+	VehiculoID string   `json:"vehiculo_id"`
+	Paradas    []string `json:"paradas"` // Cuevas en orden de visita, sin el depósito
+	Carga      int      `json:"carga"`   // Suma de las demandas de las paradas
+	Costo      float64  `json:"costo"`   // Ida y vuelta por caminos mínimos
+}
+
+// ResultadoCVRP es un plan de reparto de la demanda entre los vehículos
+type ResultadoCVRP struct {
+	Deposito      string     `json:"deposito"`
+	Rutas         []RutaCVRP `json:"rutas"` // En el orden de los vehículos recibidos; los que no salen no aparecen
+	CostoTotal    float64    `json:"costo_total"`
+	SinAsignar    []string   `json:"sin_asignar"`   // Cuevas que no entran en ningún vehículo
+	Inalcanzables []string   `json:"inalcanzables"` // Cuevas sin ida o sin vuelta desde el depósito
+}
+
+// PlanificarCVRP reparte las cuevas con demanda entre los vehículos sin superar
+// su capacidad. Construye las rutas con los ahorros de Clarke-Wright, las asigna
+// al vehículo más chico que las admite y las mejora con reubicaciones entre
+// rutas y 2-opt/Or-opt dentro de cada una. Las distancias son caminos mínimos.
+func PlanificarCVRP(grafo *domain.Grafo, deposito string, demandas map[string]int, vehiculos []VehiculoCVRP) (*ResultadoCVRP, error) {
+	if err := validarExtremos(grafo, deposito, deposito); err != nil {
+		return nil, err
+	}
+	if len(vehiculos) == 0 {
+		return nil, fmt.Errorf("se requiere al menos un vehículo")
+	}
+	capacidadMaxima := 0
+	for _, vehiculo := range vehiculos {
+		if vehiculo.Capacidad <= 0 {
+			return nil, fmt.Errorf("el vehículo '%s' no tiene capacidad", vehiculo.ID)
+		}
+		capacidadMaxima = max(capacidadMaxima, vehiculo.Capacidad)
+	}
+	matriz, err := CalcularMatrizDistancias(grafo)
+	if err != nil {
+		return nil, err
+	}
+
+	resultado := &ResultadoCVRP{Deposito: deposito, Rutas: []RutaCVRP{}, SinAsignar: []string{}, Inalcanzables: []string{}}
+	clientes := make([]string, 0, len(demandas))
+	for id, demanda := range demandas {
+		if _, existe := grafo.Cuevas[id]; !existe {
+			return nil, fmt.Errorf("la cueva '%s' no existe en el grafo", id)
+		}
+		if demanda < 0 {
+			return nil, fmt.Errorf("la demanda de la cueva '%s' es negativa", id)
+		}
+		if demanda > 0 && id != deposito {
+			clientes = append(clientes, id)
+		}
+	}
+	sort.Strings(clientes)
+
+	ruteo := &ruteoCVRP{matriz: matriz, deposito: deposito, demandas: demandas}
+	atendibles := make([]string, 0, len(clientes))
+	for _, id := range clientes {
+		switch {
+		case math.IsInf(matriz.Distancia(deposito, id), 1) || math.IsInf(matriz.Distancia(id, deposito), 1):
+			resultado.Inalcanzables = append(resultado.Inalcanzables, id)
+		case demandas[id] > capacidadMaxima:
+			resultado.SinAsignar = append(resultado.SinAsignar, id)
+		default:
+			atendibles = append(atendibles, id)
+		}
+	}
+
+	rutas := ruteo.ahorrosClarkeWright(atendibles, capacidadMaxima)
+	pendientes := ruteo.asignarVehiculos(rutas, vehiculos)
+	resultado.SinAsignar = append(resultado.SinAsignar, ruteo.insertarPendientes(pendientes)...)
+	sort.Strings(resultado.SinAsignar)
+	ruteo.mejorar()
+
+	// Devolver las rutas en el orden de los vehículos
+	for _, vehiculo := range vehiculos {
+		for _, ruta := range ruteo.rutas {
+			if ruta.vehiculo.ID == vehiculo.ID && len(ruta.paradas) > 0 {
+				resultado.Rutas = append(resultado.Rutas, RutaCVRP{
+					VehiculoID: vehiculo.ID,
+					Paradas:    ruta.paradas,
+					Carga:      ruta.carga,
+					Costo:      ruteo.costoRuta(ruta.paradas),
+				})
+				resultado.CostoTotal += ruteo.costoRuta(ruta.paradas)
+			}
+		}
+	}
+	return resultado, nil
+}
+
+// rutaAsignada es una ruta en construcción con el vehículo que la hace
+type rutaAsignada struct {
+	vehiculo VehiculoCVRP
+	paradas  []string
+	carga    int
+}
+
+type ruteoCVRP struct {
+	matriz   *MatrizDistancias
+	deposito string
+	demandas map[string]int
+	rutas    []*rutaAsignada
+}
+
+func (r *ruteoCVRP) distancia(desde, hasta string) float64 {
+	return r.matriz.Distancia(desde, hasta)
+}
+
+// costoRuta suma la salida del depósito, los tramos entre paradas y la vuelta
+func (r *ruteoCVRP) costoRuta(paradas []string) float64 {
+	if len(paradas) == 0 {
+		return 0
+	}
+	costo := r.distancia(r.deposito, paradas[0]) + r.distancia(paradas[len(paradas)-1], r.deposito)
+	for i := 1; i < len(paradas); i++ {
+		costo += r.distancia(paradas[i-1], paradas[i])
+	}
+	return costo
+}
+
+// ahorrosClarkeWright parte de una ruta por cueva y une el final de una con el
+// principio de otra en orden de ahorro decreciente mientras quepan en el
+// vehículo más grande
+func (r *ruteoCVRP) ahorrosClarkeWright(clientes []string, capacidad int) [][]string {
+	type ahorro struct {
+		desde, hasta string
+		valor        float64
+	}
+	ahorros := make([]ahorro, 0)
+	for _, i := range clientes {
+		for _, j := range clientes {
+			if i == j || math.IsInf(r.distancia(i, j), 1) {
+				continue
+			}
+			if valor := r.distancia(i, r.deposito) + r.distancia(r.deposito, j) - r.distancia(i, j); valor > toleranciaTSP {
+				ahorros = append(ahorros, ahorro{i, j, valor})
+			}
+		}
+	}
+	sort.Slice(ahorros, func(a, b int) bool {
+		if ahorros[a].valor != ahorros[b].valor {
+			return ahorros[a].valor > ahorros[b].valor
+		}
+		if ahorros[a].desde != ahorros[b].desde {
+			return ahorros[a].desde < ahorros[b].desde
+		}
+		return ahorros[a].hasta < ahorros[b].hasta
+	})
+
+	rutas := make(map[string][]string, len(clientes)) // Primera parada -> ruta
+	rutaDe := make(map[string]string, len(clientes))  // Cueva -> primera parada de su ruta
+	carga := make(map[string]int, len(clientes))
+	for _, id := range clientes {
+		rutas[id] = []string{id}
+		rutaDe[id] = id
+		carga[id] = r.demandas[id]
+	}
+	for _, a := range ahorros {
+		ri, rj := rutaDe[a.desde], rutaDe[a.hasta]
+		if ri == rj || rj != a.hasta || carga[ri]+carga[rj] > capacidad {
+			continue
+		}
+		if ruta := rutas[ri]; ruta[len(ruta)-1] != a.desde {
+			continue
+		}
+		rutas[ri] = append(rutas[ri], rutas[rj]...)
+		carga[ri] += carga[rj]
+		for _, id := range rutas[rj] {
+			rutaDe[id] = ri
+		}
+		delete(rutas, rj)
+	}
+
+	resultado := make([][]string, 0, len(rutas))
+	for _, id := range clientes {
+		if ruta, ok := rutas[id]; ok {
+			resultado = append(resultado, ruta)
+		}
+	}
+	return resultado
+}
+
+// asignarVehiculos da cada ruta, de la más cargada a la menos, al vehículo
+// libre más chico que la admite; devuelve las cuevas de las rutas sin vehículo
+func (r *ruteoCVRP) asignarVehiculos(rutas [][]string, vehiculos []VehiculoCVRP) []string {
+	cargas := make([]int, len(rutas))
+	for i, ruta := range rutas {
+		for _, id := range ruta {
+			cargas[i] += r.demandas[id]
+		}
+	}
+	orden := make([]int, len(rutas))
+	for i := range orden {
+		orden[i] = i
+	}
+	sort.SliceStable(orden, func(a, b int) bool { return cargas[orden[a]] > cargas[orden[b]] })
+
+	libres := append([]VehiculoCVRP{}, vehiculos...)
+	sort.SliceStable(libres, func(a, b int) bool { return libres[a].Capacidad < libres[b].Capacidad })
+
+	pendientes := make([]string, 0)
+	for _, i := range orden {
+		elegido := -1
+		for k, vehiculo := range libres {
+			if vehiculo.Capacidad >= cargas[i] {
+				elegido = k
+				break
+			}
+		}
+		if elegido == -1 {
+			pendientes = append(pendientes, rutas[i]...)
+			continue
+		}
+		r.rutas = append(r.rutas, &rutaAsignada{vehiculo: libres[elegido], paradas: rutas[i], carga: cargas[i]})
+		libres = append(libres[:elegido], libres[elegido+1:]...)
+	}
+	// Los vehículos sin ruta quedan disponibles para las cuevas pendientes
+	for _, vehiculo := range libres {
+		r.rutas = append(r.rutas, &rutaAsignada{vehiculo: vehiculo, paradas: []string{}})
+	}
+	return pendientes
+}
+
+// insertarPendientes ubica cada cueva pendiente, de mayor a menor demanda, en la
+// posición más barata de una ruta con lugar; devuelve las que no entran
+func (r *ruteoCVRP) insertarPendientes(pendientes []string) []string {
+	sort.SliceStable(pendientes, func(a, b int) bool { return r.demandas[pendientes[a]] > r.demandas[pendientes[b]] })
+	sinLugar := make([]string, 0)
+	for _, id := range pendientes {
+		ruta, posicion, _ := r.mejorInsercion(id, nil)
+		if ruta == nil {
+			sinLugar = append(sinLugar, id)
+			continue
+		}
+		ruta.insertar(id, posicion, r.demandas[id])
+	}
+	return sinLugar
+}
+
+// mejorInsercion busca la ruta con lugar y la posición de menor costo
+// adicional para la cueva, sin considerar la ruta excluida
+func (r *ruteoCVRP) mejorInsercion(id string, excluida *rutaAsignada) (*rutaAsignada, int, float64) {
+	var mejorRuta *rutaAsignada
+	mejorPosicion, mejorCosto := -1, math.Inf(1)
+	for _, ruta := range r.rutas {
+		if ruta == excluida || ruta.carga+r.demandas[id] > ruta.vehiculo.Capacidad {
+			continue
+		}
+		for posicion := 0; posicion <= len(ruta.paradas); posicion++ {
+			previa, siguiente := r.deposito, r.deposito
+			if posicion > 0 {
+				previa = ruta.paradas[posicion-1]
+			}
+			if posicion < len(ruta.paradas) {
+				siguiente = ruta.paradas[posicion]
+			}
+			costo := r.distancia(previa, id) + r.distancia(id, siguiente) - r.distancia(previa, siguiente)
+			if costo < mejorCosto-toleranciaTSP {
+				mejorRuta, mejorPosicion, mejorCosto = ruta, posicion, costo
+			}
+		}
+	}
+	return mejorRuta, mejorPosicion, mejorCosto
+}
+
+func (ruta *rutaAsignada) insertar(id string, posicion, demanda int) {
+	ruta.paradas = append(ruta.paradas[:posicion], append([]string{id}, ruta.paradas[posicion:]...)...)
+	ruta.carga += demanda
+}
+
+// mejorar alterna reubicaciones entre rutas y 2-opt/Or-opt dentro de cada ruta
+// hasta que ninguna reduzca el costo total
+func (r *ruteoCVRP) mejorar() {
+	for {
+		for _, ruta := range r.rutas {
+			r.mejorarOrden(ruta)
+		}
+		if !r.reubicar() {
+			return
+		}
+	}
+}
+
+// mejorarOrden reordena las paradas de una ruta con el planificador TSP cerrado
+func (r *ruteoCVRP) mejorarOrden(ruta *rutaAsignada) {
+	if len(ruta.paradas) < 2 {
+		return
+	}
+	objetivos := append([]string{r.deposito}, ruta.paradas...)
+	planificador := nuevoPlanificadorTSP(r.matriz, objetivos, true)
+	orden := make([]int, len(objetivos))
+	for i := range orden {
+		orden[i] = i
+	}
+	orden = planificador.mejorar(orden)
+	for i, posicion := range orden[1:] {
+		ruta.paradas[i] = objetivos[posicion]
+	}
+}
+
+// reubicar mueve la primera cueva cuyo traslado a otra ruta reduce el costo total
+func (r *ruteoCVRP) reubicar() bool {
+	for _, origen := range r.rutas {
+		for posicion, id := range origen.paradas {
+			previa, siguiente := r.deposito, r.deposito
+			if posicion > 0 {
+				previa = origen.paradas[posicion-1]
+			}
+			if posicion+1 < len(origen.paradas) {
+				siguiente = origen.paradas[posicion+1]
+			}
+			ahorro := r.distancia(previa, id) + r.distancia(id, siguiente) - r.distancia(previa, siguiente)
+
+			destino, nueva, costo := r.mejorInsercion(id, origen)
+			if destino == nil || costo >= ahorro-toleranciaTSP {
+				continue
+			}
+			origen.paradas = append(origen.paradas[:posicion], origen.paradas[posicion+1:]...)
+			origen.carga -= r.demandas[id]
+			destino.insertar(id, nueva, r.demandas[id])
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,173 @@
+package algorithms
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"proyecto-grafos-go/internal/domain"
+	"reflect"
+	"testing"
+)
+
+// verificarPlanCVRP comprueba capacidades, costos y que cada cueva se atienda una sola vez
+func verificarPlanCVRP(t *testing.T, grafo *domain.Grafo, plan *ResultadoCVRP, demandas map[string]int, vehiculos []VehiculoCVRP) {
+	t.Helper()
+	matriz, err := CalcularMatrizDistancias(grafo)
+	if err != nil {
+		t.Fatalf("Error en matriz de distancias: %v", err)
+	}
+	capacidad := make(map[string]int)
+	for _, vehiculo := range vehiculos {
+		capacidad[vehiculo.ID] = vehiculo.Capacidad
+	}
+
+	atendidas := make(map[string]bool)
+	total := 0.0
+	for _, ruta := range plan.Rutas {
+		carga := 0
+		costo := 0.0
+		anterior := plan.Deposito
+		for _, id := range ruta.Paradas {
+			if atendidas[id] {
+				t.Errorf("La cueva %s está en más de una ruta", id)
+			}
+			atendidas[id] = true
+			carga += demandas[id]
+			costo += matriz.Distancia(anterior, id)
+			anterior = id
+		}
+		costo += matriz.Distancia(anterior, plan.Deposito)
+		if carga != ruta.Carga || carga > capacidad[ruta.VehiculoID] {
+			t.Errorf("Ruta de %s con carga %d (informa %d, capacidad %d)", ruta.VehiculoID, carga, ruta.Carga, capacidad[ruta.VehiculoID])
+		}
+		if math.Abs(costo-ruta.Costo) > 1e-9 {
+			t.Errorf("Ruta de %s cuesta %.2f e informa %.2f", ruta.VehiculoID, costo, ruta.Costo)
+		}
+		total += costo
+	}
+	if math.Abs(total-plan.CostoTotal) > 1e-9 {
+		t.Errorf("Costo total %.2f, suma de rutas %.2f", plan.CostoTotal, total)
+	}
+	for _, id := range append(append([]string{}, plan.SinAsignar...), plan.Inalcanzables...) {
+		if atendidas[id] {
+			t.Errorf("La cueva %s figura atendida y sin atender", id)
+		}
+		atendidas[id] = true
+	}
+	for id, demanda := range demandas {
+		if demanda > 0 && id != plan.Deposito && !atendidas[id] {
+			t.Errorf("La cueva %s no aparece en el plan", id)
+		}
+	}
+}
+
+func TestPlanificarCVRPSeparaGrupos(t *testing.T) {
+	// Dos grupos de cuevas a cada lado del depósito unidos por un túnel largo
+	grafo := domain.NuevoGrafo(false)
+	for _, id := range []string{"DEP", "N1", "N2", "S1", "S2"} {
+		grafo.AgregarCueva(domain.NuevaCueva(id, id))
+	}
+	for _, tunel := range []tunelPrueba{{"DEP", "N1", 10}, {"N1", "N2", 1}, {"DEP", "S1", 10}, {"S1", "S2", 1}, {"N2", "S2", 30}} {
+		grafo.AgregarConexion(tunel.desde, tunel.hasta, tunel.distancia)
+	}
+	demandas := map[string]int{"N1": 40, "N2": 50, "S1": 60, "S2": 30, "DEP": 5}
+	vehiculos := []VehiculoCVRP{{"GRANDE", 400}}
+
+	plan, err := PlanificarCVRP(grafo, "DEP", demandas, vehiculos)
+	if err != nil {
+		t.Fatalf("Error en CVRP: %v", err)
+	}
+	verificarPlanCVRP(t, grafo, plan, demandas, vehiculos)
+	// El camión grande atiende todo en una ruta que pasa por el depósito en lugar del túnel largo
+	if len(plan.Rutas) != 1 || plan.Rutas[0].VehiculoID != "GRANDE" || plan.CostoTotal != 44 {
+		t.Errorf("Plan inesperado: %+v", plan.Rutas)
+	}
+
+	// Con dos camiones pequeños conviene ir y volver por cada grupo: 10+1+1+10
+	vehiculos = []VehiculoCVRP{{"P1", 100}, {"P2", 100}}
+	plan, err = PlanificarCVRP(grafo, "DEP", demandas, vehiculos)
+	if err != nil {
+		t.Fatalf("Error en CVRP: %v", err)
+	}
+	verificarPlanCVRP(t, grafo, plan, demandas, vehiculos)
+	if len(plan.Rutas) != 2 || plan.CostoTotal != 44 {
+		t.Errorf("Se esperaban dos rutas de 22, se obtuvo %+v", plan.Rutas)
+	}
+}
+
+func TestPlanificarCVRPAleatorio(t *testing.T) {
+	r := rand.New(rand.NewSource(24))
+	for caso := 0; caso < 60; caso++ {
+		grafo := domain.NuevoGrafo(caso%3 == 0)
+		n := 3 + r.Intn(12)
+		ids := make([]string, n)
+		for i := range ids {
+			ids[i] = fmt.Sprintf("C%d", i)
+			grafo.AgregarCueva(domain.NuevaCueva(ids[i], ids[i]))
+		}
+		for i := range ids {
+			grafo.AgregarConexion(ids[i], ids[(i+1)%n], float64(1+r.Intn(20)))
+		}
+		for k := 0; k < n; k++ {
+			a, b := ids[r.Intn(n)], ids[r.Intn(n)]
+			if a != b && !grafo.ExisteConexion(a, b) {
+				grafo.AgregarConexion(a, b, float64(1+r.Intn(20)))
+			}
+		}
+
+		demandas := make(map[string]int)
+		for _, id := range ids[1:] {
+			demandas[id] = r.Intn(60)
+		}
+		vehiculos := []VehiculoCVRP{{"V1", 50 + r.Intn(100)}, {"V2", 50 + r.Intn(100)}}
+		if r.Intn(2) == 0 {
+			vehiculos = append(vehiculos, VehiculoCVRP{"V3", 400})
+		}
+
+		plan, err := PlanificarCVRP(grafo, "C0", demandas, vehiculos)
+		if err != nil {
+			t.Fatalf("Caso %d: error en CVRP: %v", caso, err)
+		}
+		verificarPlanCVRP(t, grafo, plan, demandas, vehiculos)
+
+		// Un vehículo sin límite hace un solo recorrido cerrado que no empeora al vecino más cercano
+		sinLimite := []VehiculoCVRP{{"U", 1 << 30}}
+		plan, err = PlanificarCVRP(grafo, "C0", demandas, sinLimite)
+		if err != nil {
+			t.Fatalf("Caso %d: error en CVRP: %v", caso, err)
+		}
+		verificarPlanCVRP(t, grafo, plan, demandas, sinLimite)
+		if len(plan.SinAsignar) != 0 || len(plan.Rutas) > 1 {
+			t.Errorf("Caso %d: un vehículo sin límite debería atender todo en una ruta: %+v", caso, plan)
+		}
+	}
+}
+
+func TestPlanificarCVRPSinAtender(t *testing.T) {
+	grafo := crearRedDirigida([]string{"DEP", "A", "B", "C"}, []tunelPrueba{
+		{"DEP", "A", 1}, {"A", "DEP", 1}, {"DEP", "B", 1}, {"B", "DEP", 1}, {"DEP", "C", 1},
+	})
+	plan, err := PlanificarCVRP(grafo, "DEP", map[string]int{"A": 10, "B": 500, "C": 5}, []VehiculoCVRP{{"V", 100}})
+	if err != nil {
+		t.Fatalf("Error en CVRP: %v", err)
+	}
+	if !reflect.DeepEqual(plan.SinAsignar, []string{"B"}) || !reflect.DeepEqual(plan.Inalcanzables, []string{"C"}) {
+		t.Errorf("Sin asignar %v e inalcanzables %v inesperados", plan.SinAsignar, plan.Inalcanzables)
+	}
+	if len(plan.Rutas) != 1 || !reflect.DeepEqual(plan.Rutas[0].Paradas, []string{"A"}) {
+		t.Errorf("Rutas inesperadas: %+v", plan.Rutas)
+	}
+
+	if _, err := PlanificarCVRP(grafo, "DEP", map[string]int{"A": 1}, nil); err == nil {
+		t.Error("Se esperaba un error sin vehículos")
+	}
+	if _, err := PlanificarCVRP(grafo, "DEP", map[string]int{"Z": 1}, []VehiculoCVRP{{"V", 1}}); err == nil {
+		t.Error("Se esperaba un error por cueva inexistente")
+	}
+	if _, err := PlanificarCVRP(grafo, "DEP", map[string]int{"A": -1}, []VehiculoCVRP{{"V", 1}}); err == nil {
+		t.Error("Se esperaba un error por demanda negativa")
+	}
+	if _, err := PlanificarCVRP(grafo, "DEP", map[string]int{"A": 1}, []VehiculoCVRP{{"V", 0}}); err == nil {
+		t.Error("Se esperaba un error por vehículo sin capacidad")
+	}
+}
@@ -28,7 +28,7 @@ func dfsRecursivo(grafo *domain.Grafo, nodoActual string, visitados map[string]b
 	*resultado = append(*resultado, nodoActual)
 
 	// Obtener vecinos del nodo actual
-	for _, arista := range grafo.Aristas {
+	for _, arista := range grafo.AristasIncidentes(nodoActual) {
 		if arista.Desde == nodoActual && !arista.EsObstruido {
 			if !visitados[arista.Hasta] {
 				dfsRecursivo(grafo, arista.Hasta, visitados, resultado)
@@ -69,7 +69,7 @@ func dfsConDistanciasRecursivo(grafo *domain.Grafo, nodoActual string, visitados
 	*resultado = append(*resultado, nodoActual)
 
 	// Obtener vecinos del nodo actual
-	for _, arista := range grafo.Aristas {
+	for _, arista := range grafo.AristasIncidentes(nodoActual) {
 		if arista.Desde == nodoActual && !arista.EsObstruido {
 			if !visitados[arista.Hasta] {
 				distancias[arista.Hasta] = distancias[nodoActual] + arista.Distancia
@@ -1,6 +1,7 @@
 package algorithms
 
 import (
+	"container/heap"
 	"fmt"
 	"math"
 	"proyecto-grafos-go/internal/domain"
@@ -26,26 +27,17 @@ func Dijkstra(grafo *domain.Grafo, nodoInicio string) (map[string]float64, map[s
 	}
 	distancias[nodoInicio] = 0
 
-	for {
-		// Encontrar el nodo no visitado con menor distancia
-		nodoActual := ""
-		menorDistancia := math.Inf(1)
-
-		for id := range grafo.Cuevas {
-			if !visitados[id] && distancias[id] < menorDistancia {
-				menorDistancia = distancias[id]
-				nodoActual = id
-			}
-		}
-
-		if nodoActual == "" {
-			break // No hay más nodos alcanzables
+	cola := &colaNodos{{id: nodoInicio, distancia: 0}}
+	for cola.Len() > 0 {
+		// Extraer el nodo no visitado con menor distancia; las entradas viejas se descartan
+		nodoActual := heap.Pop(cola).(nodoDistancia).id
+		if visitados[nodoActual] {
+			continue
 		}
-
 		visitados[nodoActual] = true
 
 		// Actualizar distancias de los vecinos
-		for _, arista := range grafo.Aristas {
+		for _, arista := range grafo.AristasIncidentes(nodoActual) {
 			if arista.EsObstruido {
 				continue
 			}
@@ -67,6 +59,7 @@ func Dijkstra(grafo *domain.Grafo, nodoInicio string) (map[string]float64, map[s
 			if nuevaDistancia < distancias[vecino] {
 				distancias[vecino] = nuevaDistancia
 				predecesores[vecino] = nodoActual
+				heap.Push(cola, nodoDistancia{id: vecino, distancia: nuevaDistancia})
 			}
 		}
 	}
@@ -135,3 +128,28 @@ func DijkstraTodasLasRutas(grafo *domain.Grafo, nodoInicio string) (map[string][
 
 	return rutas, distancias, nil
 }
+
+// nodoDistancia es una entrada de la cola de prioridad de Dijkstra
+type nodoDistancia struct {
+	id        string
+	distancia float64
+}
+
+// colaNodos implementa heap.Interface ordenando por distancia
+type colaNodos []nodoDistancia
+
+func (c colaNodos) Len() int           { return len(c) }
+func (c colaNodos) Less(i, j int) bool { return c[i].distancia < c[j].distancia }
+func (c colaNodos) Swap(i, j int)      { c[i], c[j] = c[j], c[i] }
+
+func (c *colaNodos) Push(x interface{}) {
+	*c = append(*c, x.(nodoDistancia))
+}
+
+func (c *colaNodos) Pop() interface{} {
+	anterior := *c
+	n := len(anterior)
+	item := anterior[n-1]
+	*c = anterior[:n-1]
+	return item
+}
@@ -0,0 +1,104 @@
+package algorithms
+
+import (
+	"container/heap"
+	"fmt"
+	"math"
+	"proyecto-grafos-go/internal/domain"
+)
+
+// busquedaDireccion es el estado de una de las dos búsquedas de DijkstraBidireccional
+type busquedaDireccion struct {
+	inversa      bool
+	distancias   map[string]float64
+	predecesores map[string]string // En la búsqueda inversa apunta hacia el destino
+	cerrados     map[string]bool
+	cola         *colaNodos
+}
+
+func nuevaBusquedaDireccion(origen string, inversa bool) *busquedaDireccion {
+	return &busquedaDireccion{
+		inversa:      inversa,
+		distancias:   map[string]float64{origen: 0},
+		predecesores: make(map[string]string),
+		cerrados:     make(map[string]bool),
+		cola:         &colaNodos{{id: origen, distancia: 0}},
+	}
+}
+
+// tope devuelve la menor distancia pendiente, descartando entradas viejas
+func (b *busquedaDireccion) tope() float64 {
+	for b.cola.Len() > 0 {
+		entrada := (*b.cola)[0]
+		if !b.cerrados[entrada.id] && entrada.distancia <= b.distancias[entrada.id] {
+			return entrada.distancia
+		}
+		heap.Pop(b.cola)
+	}
+	return math.Inf(1)
+}
+
+// DijkstraBidireccional busca a la vez desde el origen y hacia atrás desde el
+// destino, y se detiene cuando ambas búsquedas ya no pueden mejorar el encuentro
+func DijkstraBidireccional(grafo *domain.Grafo, desde, hasta string) (*ResultadoCamino, error) {
+	if err := validarExtremos(grafo, desde, hasta); err != nil {
+		return nil, err
+	}
+
+	resultado := &ResultadoCamino{Algoritmo: CaminoDijkstraBidireccional}
+	if desde == hasta {
+		resultado.Ruta = []string{desde}
+		return resultado, nil
+	}
+
+	adelante := nuevaBusquedaDireccion(desde, false)
+	atras := nuevaBusquedaDireccion(hasta, true)
+	mejor := math.Inf(1)
+	encuentro := ""
+
+	for {
+		topeAdelante, topeAtras := adelante.tope(), atras.tope()
+		if math.IsInf(topeAdelante, 1) || math.IsInf(topeAtras, 1) || topeAdelante+topeAtras >= mejor {
+			break
+		}
+
+		// Avanzar la búsqueda con el menor tope
+		actual, otra := adelante, atras
+		if topeAtras < topeAdelante {
+			actual, otra = atras, adelante
+		}
+		nodo := heap.Pop(actual.cola).(nodoDistancia).id
+		actual.cerrados[nodo] = true
+		resultado.NodosExpandidos++
+
+		for _, t := range tramosDesde(grafo, nodo, actual.inversa) {
+			if t.distancia < 0 {
+				return nil, fmt.Errorf("distancia negativa entre %s y %s; use Bellman-Ford", nodo, t.vecino)
+			}
+			nuevaDistancia := actual.distancias[nodo] + t.distancia
+			if distancia, ok := actual.distancias[t.vecino]; !ok || nuevaDistancia < distancia {
+				actual.distancias[t.vecino] = nuevaDistancia
+				actual.predecesores[t.vecino] = nodo
+				heap.Push(actual.cola, nodoDistancia{id: t.vecino, distancia: nuevaDistancia})
+			}
+			if restante, ok := otra.distancias[t.vecino]; ok && nuevaDistancia+restante < mejor {
+				mejor = nuevaDistancia + restante
+				encuentro = t.vecino
+			}
+		}
+	}
+
+	if encuentro == "" {
+		return nil, fmt.Errorf("no hay ruta desde '%s' hasta '%s'", desde, hasta)
+	}
+
+	// Unir la mitad hacia el encuentro con la mitad que sigue hasta el destino
+	ruta := reconstruirRuta(adelante.predecesores, desde, encuentro)
+	for nodo := encuentro; nodo != hasta; {
+		nodo = atras.predecesores[nodo]
+		ruta = append(ruta, nodo)
+	}
+	resultado.Ruta = ruta
+	resultado.Costo = mejor
+	return resultado, nil
+}
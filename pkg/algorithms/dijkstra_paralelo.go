@@ -0,0 +1,115 @@
+package algorithms
+
+import (
+	"container/heap"
+	"fmt"
+	"proyecto-grafos-go/internal/domain"
+	"runtime"
+	"sync"
+)
+
+// salto es un tramo con la cueva destino por su posición en la matriz
+type salto struct {
+	hasta     int
+	distancia float64
+}
+
+// DijkstraTodosLosPares calcula la matriz de distancias ejecutando Dijkstra
+// desde cada cueva en paralelo. Con trabajadores <= 0 usa un trabajador por CPU.
+// No admite distancias negativas; para ellas use FloydWarshall.
+func DijkstraTodosLosPares(grafo *domain.Grafo, trabajadores int) (*MatrizDistancias, error) {
+	if grafo == nil {
+		return nil, fmt.Errorf("grafo no puede ser nil")
+	}
+
+	// Adyacencia por posición, construida una sola vez y compartida en solo lectura
+	matriz := nuevaMatrizDistancias(grafo)
+	adyacencia := make([][]salto, len(matriz.IDs))
+	for i, id := range matriz.IDs {
+		for _, t := range tramosDesde(grafo, id, false) {
+			if t.distancia < 0 {
+				return nil, fmt.Errorf("distancia negativa en %s -> %s; use Floyd-Warshall", id, t.vecino)
+			}
+			if j, existe := matriz.Indice[t.vecino]; existe {
+				adyacencia[i] = append(adyacencia[i], salto{hasta: j, distancia: t.distancia})
+			}
+		}
+	}
+
+	if trabajadores <= 0 {
+		trabajadores = runtime.NumCPU()
+	}
+	origenes := make(chan int)
+	var wg sync.WaitGroup
+	for t := 0; t < trabajadores; t++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			// Cada origen escribe solo su propia fila
+			for origen := range origenes {
+				dijkstraFila(adyacencia, origen, matriz.Distancias[origen], matriz.Siguiente[origen])
+			}
+		}()
+	}
+	for origen := range matriz.IDs {
+		origenes <- origen
+	}
+	close(origenes)
+	wg.Wait()
+
+	return matriz, nil
+}
+
+// dijkstraFila completa la fila de distancias y primeros saltos de un origen
+func dijkstraFila(adyacencia [][]salto, origen int, distancias []float64, siguiente []int) {
+	cerrados := make([]bool, len(adyacencia))
+	cola := &colaPosiciones{{posicion: origen, distancia: 0}}
+	for cola.Len() > 0 {
+		actual := heap.Pop(cola).(posicionDistancia).posicion
+		if cerrados[actual] {
+			continue
+		}
+		cerrados[actual] = true
+
+		for _, s := range adyacencia[actual] {
+			if cerrados[s.hasta] {
+				continue
+			}
+			if nuevaDistancia := distancias[actual] + s.distancia; nuevaDistancia < distancias[s.hasta] {
+				distancias[s.hasta] = nuevaDistancia
+				// El primer salto se hereda del nodo actual, salvo al salir del origen
+				if actual == origen {
+					siguiente[s.hasta] = s.hasta
+				} else {
+					siguiente[s.hasta] = siguiente[actual]
+				}
+				heap.Push(cola, posicionDistancia{posicion: s.hasta, distancia: nuevaDistancia})
+			}
+		}
+	}
+}
+
+// posicionDistancia es una entrada de la cola de prioridad por posición
+type posicionDistancia struct {
+	posicion  int
+	distancia float64
+}
+
+// colaPosiciones implementa heap.Interface ordenando por distancia
+type colaPosiciones []posicionDistancia
+
+func (c colaPosiciones) Len() int           { return len(c) }
+func (c colaPosiciones) Less(i, j int) bool { return c[i].distancia < c[j].distancia }
+func (c colaPosiciones) Swap(i, j int)      { c[i], c[j] = c[j], c[i] }
+
+func (c *colaPosiciones) Push(x interface{}) {
+	*c = append(*c, x.(posicionDistancia))
+}
+
+func (c *colaPosiciones) Pop() interface{} {
+	anterior := *c
+	n := len(anterior)
+	item := anterior[n-1]
+	*c = anterior[:n-1]
+	return item
+}
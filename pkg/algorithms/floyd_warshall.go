@@ -0,0 +1,51 @@
+package algorithms
+
+import (
+	"fmt"
+	"math"
+	"proyecto-grafos-go/internal/domain"
+)
+
+// FloydWarshall calcula la matriz de distancias mínimas entre todo par de
+// cuevas. Admite distancias negativas y devuelve error si hay un ciclo negativo.
+func FloydWarshall(grafo *domain.Grafo) (*MatrizDistancias, error) {
+	if grafo == nil {
+		return nil, fmt.Errorf("grafo no puede ser nil")
+	}
+
+	matriz := nuevaMatrizDistancias(grafo)
+	for i, id := range matriz.IDs {
+		for _, t := range tramosDesde(grafo, id, false) {
+			j, existe := matriz.Indice[t.vecino]
+			if existe && t.distancia < matriz.Distancias[i][j] {
+				matriz.Distancias[i][j] = t.distancia
+				matriz.Siguiente[i][j] = j
+			}
+		}
+	}
+
+	n := len(matriz.IDs)
+	for k := 0; k < n; k++ {
+		filaK := matriz.Distancias[k]
+		for i := 0; i < n; i++ {
+			distanciaIK := matriz.Distancias[i][k]
+			if math.IsInf(distanciaIK, 1) {
+				continue
+			}
+			filaI := matriz.Distancias[i]
+			for j := 0; j < n; j++ {
+				if nuevaDistancia := distanciaIK + filaK[j]; nuevaDistancia < filaI[j] {
+					filaI[j] = nuevaDistancia
+					matriz.Siguiente[i][j] = matriz.Siguiente[i][k]
+				}
+			}
+		}
+	}
+
+	for i, id := range matriz.IDs {
+		if matriz.Distancias[i][i] < 0 {
+			return nil, fmt.Errorf("hay un ciclo de costo negativo que pasa por '%s'", id)
+		}
+	}
+	return matriz, nil
+}
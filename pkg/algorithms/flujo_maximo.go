@@ -0,0 +1,204 @@
+package algorithms
+
+import (
+	"fmt"
+	"math"
+	"proyecto-grafos-go/internal/domain"
+	"sort"
+	"strings"
+)
+
+// FlujoTunel es un túnel de la red de flujo, en el sentido en que circula el flujo
+type FlujoTunel struct {
+	Desde     string  `json:"desde"`
+	Hasta     string  `json:"hasta"`
+	Flujo     float64 `json:"flujo"`
+	Capacidad float64 `json:"capacidad"` // +Inf si el túnel no tiene capacidad definida
+}
+
+// ResultadoFlujo es el flujo máximo entre dos cuevas y el corte mínimo que lo limita
+type ResultadoFlujo struct {
+	Origen      string       `json:"origen"`
+	Destino     string       `json:"destino"`
+	FlujoMaximo float64      `json:"flujo_maximo"` // Camiones por hora
+	Tuneles     []FlujoTunel `json:"tuneles"`      // Túneles con flujo positivo
+	CorteMinimo []FlujoTunel `json:"corte_minimo"` // Túneles saturados que separan origen y destino
+	LadoOrigen  []string     `json:"lado_origen"`  // Cuevas del lado del origen en el corte
+	Aumentos    int          `json:"aumentos"`     // Caminos aumentantes usados
+}
+
+// arcoFlujo es un arco de la red residual; el arco i y el i^1 son mutuamente inversos
+type arcoFlujo struct {
+	desde, hasta int
+	capacidad    float64
+	flujo        float64
+}
+
+const toleranciaFlujo = 1e-9
+
+// FlujoMaximo calcula con Edmonds-Karp el flujo máximo entre origen y destino
+// usando la Capacidad de cada túnel. Los túneles obstruidos no transportan
+// flujo y los que no tienen capacidad definida se consideran ilimitados; si el
+// flujo resulta ilimitado se devuelve un error. En grafos no dirigidos cada
+// túnel comparte su capacidad entre ambos sentidos.
+func FlujoMaximo(grafo *domain.Grafo, origen, destino string) (*ResultadoFlujo, error) {
+	if err := validarExtremos(grafo, origen, destino); err != nil {
+		return nil, err
+	}
+	if origen == destino {
+		return nil, fmt.Errorf("el origen y el destino deben ser cuevas distintas")
+	}
+
+	ids := make([]string, 0, len(grafo.Cuevas))
+	for id := range grafo.Cuevas {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	indice := make(map[string]int, len(ids))
+	for i, id := range ids {
+		indice[id] = i
+	}
+
+	// Red residual: un par de arcos por túnel; los túneles repetidos se cuentan una vez
+	var arcos []arcoFlujo
+	salientes := make([][]int, len(ids))
+	agregarPar := func(desde, hasta int, capacidad, capacidadInversa float64) {
+		salientes[desde] = append(salientes[desde], len(arcos))
+		salientes[hasta] = append(salientes[hasta], len(arcos)+1)
+		arcos = append(arcos,
+			arcoFlujo{desde: desde, hasta: hasta, capacidad: capacidad},
+			arcoFlujo{desde: hasta, hasta: desde, capacidad: capacidadInversa})
+	}
+	vistos := make(map[[2]string]bool)
+	for _, arista := range grafo.ObtenerAristas() {
+		desde, okDesde := indice[arista.Desde]
+		hasta, okHasta := indice[arista.Hasta]
+		if arista.EsObstruido || !okDesde || !okHasta || desde == hasta {
+			continue
+		}
+		if arista.Capacidad < 0 {
+			return nil, fmt.Errorf("capacidad negativa en %s -> %s", arista.Desde, arista.Hasta)
+		}
+		capacidad := arista.Capacidad
+		if capacidad == 0 {
+			capacidad = math.Inf(1)
+		}
+
+		if grafo.EsDirigido || arista.EsDirigido {
+			clave := [2]string{arista.Desde, arista.Hasta}
+			if !vistos[clave] {
+				vistos[clave] = true
+				agregarPar(desde, hasta, capacidad, 0)
+			}
+			continue
+		}
+		clave := [2]string{arista.Desde, arista.Hasta}
+		if clave[1] < clave[0] {
+			clave[0], clave[1] = clave[1], clave[0]
+		}
+		// Sin dirección, cada sentido es el residual del otro
+		if !vistos[clave] {
+			vistos[clave] = true
+			agregarPar(desde, hasta, capacidad, capacidad)
+		}
+	}
+
+	s, t := indice[origen], indice[destino]
+	resultado := &ResultadoFlujo{Origen: origen, Destino: destino}
+	for {
+		padre := caminoAumentante(arcos, salientes, s, t)
+		if padre[t] == -1 {
+			break
+		}
+
+		// Cuello de botella del camino más corto en número de túneles
+		cuello := math.Inf(1)
+		for v := t; v != s; v = arcos[padre[v]].desde {
+			arco := arcos[padre[v]]
+			cuello = math.Min(cuello, arco.capacidad-arco.flujo)
+		}
+		if math.IsInf(cuello, 1) {
+			return nil, fmt.Errorf("el flujo entre '%s' y '%s' es ilimitado: la ruta %s no tiene túneles con capacidad definida",
+				origen, destino, describirCaminoAumentante(arcos, padre, ids, s, t))
+		}
+
+		for v := t; v != s; v = arcos[padre[v]].desde {
+			arcos[padre[v]].flujo += cuello
+			arcos[padre[v]^1].flujo -= cuello
+		}
+		resultado.FlujoMaximo += cuello
+		resultado.Aumentos++
+	}
+
+	// El lado del origen son las cuevas aún alcanzables en la red residual
+	padre := caminoAumentante(arcos, salientes, s, t)
+	ladoOrigen := make([]bool, len(ids))
+	for i, id := range ids {
+		if i == s || padre[i] != -1 {
+			ladoOrigen[i] = true
+			resultado.LadoOrigen = append(resultado.LadoOrigen, id)
+		}
+	}
+
+	resultado.Tuneles = []FlujoTunel{}
+	resultado.CorteMinimo = []FlujoTunel{}
+	for _, arco := range arcos {
+		if arco.capacidad <= 0 {
+			continue
+		}
+		tunel := FlujoTunel{Desde: ids[arco.desde], Hasta: ids[arco.hasta], Flujo: math.Max(arco.flujo, 0), Capacidad: arco.capacidad}
+		if arco.flujo > toleranciaFlujo {
+			resultado.Tuneles = append(resultado.Tuneles, tunel)
+		}
+		if ladoOrigen[arco.desde] && !ladoOrigen[arco.hasta] {
+			resultado.CorteMinimo = append(resultado.CorteMinimo, tunel)
+		}
+	}
+	ordenarTunelesFlujo(resultado.Tuneles)
+	ordenarTunelesFlujo(resultado.CorteMinimo)
+
+	return resultado, nil
+}
+
+// caminoAumentante hace BFS por los arcos con capacidad residual y devuelve el
+// arco por el que se llegó a cada cueva; -1 si no se alcanzó
+func caminoAumentante(arcos []arcoFlujo, salientes [][]int, s, t int) []int {
+	padre := make([]int, len(salientes))
+	for i := range padre {
+		padre[i] = -1
+	}
+	cola := []int{s}
+	for len(cola) > 0 {
+		u := cola[0]
+		cola = cola[1:]
+		for _, a := range salientes[u] {
+			arco := arcos[a]
+			if arco.hasta == s || padre[arco.hasta] != -1 || arco.capacidad-arco.flujo <= toleranciaFlujo {
+				continue
+			}
+			padre[arco.hasta] = a
+			if arco.hasta == t {
+				return padre
+			}
+			cola = append(cola, arco.hasta)
+		}
+	}
+	return padre
+}
+
+func describirCaminoAumentante(arcos []arcoFlujo, padre []int, ids []string, s, t int) string {
+	ruta := []string{ids[t]}
+	for v := t; v != s; v = arcos[padre[v]].desde {
+		ruta = append([]string{ids[arcos[padre[v]].desde]}, ruta...)
+	}
+	return strings.Join(ruta, " -> ")
+}
+
+func ordenarTunelesFlujo(tuneles []FlujoTunel) {
+	sort.Slice(tuneles, func(i, j int) bool {
+		if tuneles[i].Desde != tuneles[j].Desde {
+			return tuneles[i].Desde < tuneles[j].Desde
+		}
+		return tuneles[i].Hasta < tuneles[j].Hasta
+	})
+}
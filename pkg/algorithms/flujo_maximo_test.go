@@ -0,0 +1,126 @@
+package algorithms
+
+import (
+	"proyecto-grafos-go/internal/domain"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// red dirigida del ejemplo clásico de flujo máximo (flujo 23)
+func crearRedFlujo() *domain.Grafo {
+	grafo := domain.NuevoGrafo(true)
+	for _, id := range []string{"S", "V1", "V2", "V3", "V4", "T"} {
+		grafo.AgregarCueva(domain.NuevaCueva(id, id))
+	}
+	tuneles := []struct {
+		desde, hasta string
+		capacidad    float64
+	}{
+		{"S", "V1", 16}, {"S", "V2", 13}, {"V2", "V1", 4}, {"V1", "V3", 12}, {"V3", "V2", 9},
+		{"V2", "V4", 14}, {"V4", "V3", 7}, {"V3", "T", 20}, {"V4", "T", 4},
+	}
+	for _, t := range tuneles {
+		arista := domain.NuevaArista(t.desde, t.hasta, 1, true)
+		arista.Capacidad = t.capacidad
+		grafo.AgregarArista(arista)
+	}
+	return grafo
+}
+
+func TestFlujoMaximo(t *testing.T) {
+	resultado, err := FlujoMaximo(crearRedFlujo(), "S", "T")
+	if err != nil {
+		t.Fatalf("Error en flujo máximo: %v", err)
+	}
+	if resultado.FlujoMaximo != 23 {
+		t.Errorf("Flujo máximo %.2f, se esperaba 23", resultado.FlujoMaximo)
+	}
+
+	esperado := []FlujoTunel{
+		{Desde: "V1", Hasta: "V3", Flujo: 12, Capacidad: 12},
+		{Desde: "V4", Hasta: "T", Flujo: 4, Capacidad: 4},
+		{Desde: "V4", Hasta: "V3", Flujo: 7, Capacidad: 7},
+	}
+	if !reflect.DeepEqual(resultado.CorteMinimo, esperado) {
+		t.Errorf("Corte mínimo %+v, se esperaba %+v", resultado.CorteMinimo, esperado)
+	}
+	if !reflect.DeepEqual(resultado.LadoOrigen, []string{"S", "V1", "V2", "V4"}) {
+		t.Errorf("Lado del origen inesperado: %v", resultado.LadoOrigen)
+	}
+
+	// Conservación del flujo en las cuevas intermedias
+	balance := make(map[string]float64)
+	for _, tunel := range resultado.Tuneles {
+		if tunel.Flujo > tunel.Capacidad {
+			t.Errorf("El túnel %s -> %s excede su capacidad", tunel.Desde, tunel.Hasta)
+		}
+		balance[tunel.Desde] -= tunel.Flujo
+		balance[tunel.Hasta] += tunel.Flujo
+	}
+	for id, neto := range balance {
+		if id != "S" && id != "T" && neto != 0 {
+			t.Errorf("La cueva %s no conserva el flujo: %.2f", id, neto)
+		}
+	}
+}
+
+func TestFlujoMaximoNoDirigido(t *testing.T) {
+	grafo := domain.NuevoGrafo(false)
+	for _, id := range []string{"A", "B", "C", "D"} {
+		grafo.AgregarCueva(domain.NuevaCueva(id, id))
+	}
+	capacidades := map[[2]string]float64{{"A", "B"}: 5, {"A", "C"}: 3, {"B", "C"}: 2, {"B", "D"}: 3, {"C", "D"}: 6}
+	for par, capacidad := range capacidades {
+		grafo.AgregarConexion(par[0], par[1], 1)
+		for _, arista := range grafo.Aristas {
+			if (arista.Desde == par[0] && arista.Hasta == par[1]) || (arista.Desde == par[1] && arista.Hasta == par[0]) {
+				arista.Capacidad = capacidad
+			}
+		}
+	}
+
+	resultado, err := FlujoMaximo(grafo, "D", "A")
+	if err != nil || resultado.FlujoMaximo != 8 {
+		t.Fatalf("Flujo D -> A inesperado: %+v, %v", resultado, err)
+	}
+
+	// Obstruir C-D deja solo B-D
+	for _, arista := range grafo.Aristas {
+		if arista.Desde == "C" && arista.Hasta == "D" || arista.Desde == "D" && arista.Hasta == "C" {
+			arista.EsObstruido = true
+		}
+	}
+	resultado, err = FlujoMaximo(grafo, "A", "D")
+	if err != nil || resultado.FlujoMaximo != 3 {
+		t.Fatalf("Flujo con C-D obstruido inesperado: %+v, %v", resultado, err)
+	}
+	if len(resultado.CorteMinimo) != 1 || resultado.CorteMinimo[0].Desde != "B" || resultado.CorteMinimo[0].Hasta != "D" {
+		t.Errorf("Se esperaba el corte B -> D, se obtuvo %+v", resultado.CorteMinimo)
+	}
+}
+
+func TestFlujoMaximoErrores(t *testing.T) {
+	grafo := crearRedFlujo()
+	if _, err := FlujoMaximo(grafo, "S", "S"); err == nil {
+		t.Error("Se esperaba un error con origen igual al destino")
+	}
+	if _, err := FlujoMaximo(grafo, "S", "Z"); err == nil {
+		t.Error("Se esperaba un error por cueva inexistente")
+	}
+
+	resultado, err := FlujoMaximo(grafo, "T", "S")
+	if err != nil || resultado.FlujoMaximo != 0 || len(resultado.CorteMinimo) != 0 {
+		t.Errorf("Sin ruta T -> S el flujo debe ser 0: %+v, %v", resultado, err)
+	}
+
+	// Una ruta sin capacidades definidas hace el flujo ilimitado
+	for _, arista := range grafo.Aristas {
+		if arista.Desde == "V3" && arista.Hasta == "T" || arista.Desde == "V1" && arista.Hasta == "V3" || arista.Desde == "S" && arista.Hasta == "V1" {
+			arista.Capacidad = 0
+		}
+	}
+	if _, err := FlujoMaximo(grafo, "S", "T"); err == nil || !strings.Contains(err.Error(), "ilimitado") {
+		t.Errorf("Se esperaba un error por flujo ilimitado: %v", err)
+	}
+}
@@ -0,0 +1,138 @@
+package algorithms
+
+import (
+	"math"
+	"proyecto-grafos-go/internal/domain"
+	"sort"
+)
+
+// MatrizDistancias guarda la distancia mínima y el siguiente salto entre cada par de cuevas
+type MatrizDistancias struct {
+	IDs        []string       // Cuevas en el orden de filas y columnas, ordenadas por ID
+	Indice     map[string]int // ID -> posición en IDs
+	Distancias [][]float64    // +Inf si no hay ruta
+	Siguiente  [][]int        // Primera cueva después del origen en la ruta mínima; -1 si no hay ruta
+}
+
+// CalcularMatrizDistancias usa Dijkstra en paralelo y, si hay distancias
+// negativas, Floyd-Warshall
+func CalcularMatrizDistancias(grafo *domain.Grafo) (*MatrizDistancias, error) {
+	matriz, err := DijkstraTodosLosPares(grafo, 0)
+	if err != nil {
+		return FloydWarshall(grafo)
+	}
+	return matriz, nil
+}
+
+// nuevaMatrizDistancias crea la matriz sin rutas salvo la de cada cueva a sí misma
+func nuevaMatrizDistancias(grafo *domain.Grafo) *MatrizDistancias {
+	ids := make([]string, 0, len(grafo.Cuevas))
+	for id := range grafo.Cuevas {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	n := len(ids)
+	matriz := &MatrizDistancias{
+		IDs:        ids,
+		Indice:     make(map[string]int, n),
+		Distancias: make([][]float64, n),
+		Siguiente:  make([][]int, n),
+	}
+	for i, id := range ids {
+		matriz.Indice[id] = i
+		matriz.Distancias[i] = make([]float64, n)
+		matriz.Siguiente[i] = make([]int, n)
+		for j := range ids {
+			matriz.Distancias[i][j] = math.Inf(1)
+			matriz.Siguiente[i][j] = -1
+		}
+		matriz.Distancias[i][i] = 0
+		matriz.Siguiente[i][i] = i
+	}
+	return matriz
+}
+
+// Distancia devuelve la distancia mínima entre dos cuevas; +Inf si no hay ruta o no existen
+func (m *MatrizDistancias) Distancia(desde, hasta string) float64 {
+	i, okDesde := m.Indice[desde]
+	j, okHasta := m.Indice[hasta]
+	if !okDesde || !okHasta {
+		return math.Inf(1)
+	}
+	return m.Distancias[i][j]
+}
+
+// Ruta reconstruye la ruta mínima entre dos cuevas siguiendo los saltos; nil si no hay ruta
+func (m *MatrizDistancias) Ruta(desde, hasta string) []string {
+	i, okDesde := m.Indice[desde]
+	j, okHasta := m.Indice[hasta]
+	if !okDesde || !okHasta || m.Siguiente[i][j] == -1 {
+		return nil
+	}
+
+	ruta := []string{desde}
+	for i != j {
+		i = m.Siguiente[i][j]
+		ruta = append(ruta, m.IDs[i])
+	}
+	return ruta
+}
+
+// MetricasDistancia resume las distancias de la red. Una excentricidad de -1
+// indica que la cueva no alcanza a todas las demás.
+type MetricasDistancia struct {
+	Excentricidades map[string]float64 `json:"excentricidades"`
+	Diametro        float64            `json:"diametro"`  // -1 si alguna cueva no alcanza a todas
+	Radio           float64            `json:"radio"`     // -1 si ninguna cueva alcanza a todas
+	Centro          []string           `json:"centro"`    // Cuevas de excentricidad igual al radio
+	Periferia       []string           `json:"periferia"` // Cuevas de excentricidad igual al diámetro; vacía si es -1
+}
+
+// Metricas calcula excentricidad, diámetro, radio, centro y periferia
+func (m *MatrizDistancias) Metricas() *MetricasDistancia {
+	metricas := &MetricasDistancia{
+		Excentricidades: make(map[string]float64, len(m.IDs)),
+		Diametro:        0,
+		Radio:           -1,
+		Centro:          []string{},
+		Periferia:       []string{},
+	}
+	if len(m.IDs) == 0 {
+		metricas.Radio = 0
+		return metricas
+	}
+
+	for i, id := range m.IDs {
+		excentricidad := 0.0
+		for _, distancia := range m.Distancias[i] {
+			if math.IsInf(distancia, 1) {
+				excentricidad = -1
+				break
+			}
+			excentricidad = math.Max(excentricidad, distancia)
+		}
+		metricas.Excentricidades[id] = excentricidad
+
+		if excentricidad == -1 || metricas.Diametro == -1 {
+			metricas.Diametro = -1
+		} else {
+			metricas.Diametro = math.Max(metricas.Diametro, excentricidad)
+		}
+		if excentricidad != -1 && (metricas.Radio == -1 || excentricidad < metricas.Radio) {
+			metricas.Radio = excentricidad
+		}
+	}
+
+	const tolerancia = 1e-9
+	for _, id := range m.IDs {
+		excentricidad := metricas.Excentricidades[id]
+		if metricas.Radio != -1 && math.Abs(excentricidad-metricas.Radio) < tolerancia {
+			metricas.Centro = append(metricas.Centro, id)
+		}
+		if metricas.Diametro != -1 && math.Abs(excentricidad-metricas.Diametro) < tolerancia {
+			metricas.Periferia = append(metricas.Periferia, id)
+		}
+	}
+	return metricas
+}
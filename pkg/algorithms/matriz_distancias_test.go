@@ -0,0 +1,134 @@
+package algorithms
+
+import (
+	"fmt"
+	"math"
+	"proyecto-grafos-go/internal/domain"
+	"reflect"
+	"testing"
+)
+
+// red en estrella: el centro E une las ramas A, B y C; D cuelga de C
+func crearRedEstrella() *domain.Grafo {
+	grafo := domain.NuevoGrafo(false)
+	for _, id := range []string{"A", "B", "C", "D", "E"} {
+		grafo.AgregarCueva(domain.NuevaCueva(id, id))
+	}
+	grafo.AgregarConexion("E", "A", 2)
+	grafo.AgregarConexion("E", "B", 3)
+	grafo.AgregarConexion("E", "C", 1)
+	grafo.AgregarConexion("C", "D", 2)
+	return grafo
+}
+
+func TestMatrizDistanciasCoincidenAlgoritmos(t *testing.T) {
+	grafo := crearCuadricula(6)
+	// Obstruir algunos túneles (cada uno guardado en ambos sentidos) para que no sea uniforme
+	for i := 0; i+1 < len(grafo.Aristas); i += 8 {
+		grafo.Aristas[i].EsObstruido = true
+		grafo.Aristas[i+1].EsObstruido = true
+	}
+
+	floyd, err := FloydWarshall(grafo)
+	if err != nil {
+		t.Fatalf("Error en Floyd-Warshall: %v", err)
+	}
+	paralelo, err := DijkstraTodosLosPares(grafo, 3)
+	if err != nil {
+		t.Fatalf("Error en Dijkstra paralelo: %v", err)
+	}
+
+	for _, desde := range floyd.IDs {
+		distancias, _, _ := Dijkstra(grafo, desde)
+		for _, hasta := range floyd.IDs {
+			esperado := distancias[hasta]
+			for nombre, matriz := range map[string]*MatrizDistancias{"floyd": floyd, "paralelo": paralelo} {
+				if got := matriz.Distancia(desde, hasta); got != esperado {
+					t.Fatalf("%s: distancia %s -> %s = %v, se esperaba %v", nombre, desde, hasta, got, esperado)
+				}
+				ruta := matriz.Ruta(desde, hasta)
+				if math.IsInf(esperado, 1) {
+					if ruta != nil {
+						t.Errorf("%s: no debería haber ruta %s -> %s", nombre, desde, hasta)
+					}
+					continue
+				}
+				if costo := costoRuta(t, grafo, ruta); math.Abs(costo-esperado) > 1e-9 {
+					t.Errorf("%s: la ruta %v cuesta %v y no %v", nombre, ruta, costo, esperado)
+				}
+			}
+		}
+	}
+}
+
+func TestMetricasDistancia(t *testing.T) {
+	matriz, err := DijkstraTodosLosPares(crearRedEstrella(), 0)
+	if err != nil {
+		t.Fatalf("Error al calcular la matriz: %v", err)
+	}
+
+	metricas := matriz.Metricas()
+	excentricidades := map[string]float64{"A": 5, "B": 6, "C": 4, "D": 6, "E": 3}
+	if !reflect.DeepEqual(metricas.Excentricidades, excentricidades) {
+		t.Errorf("Excentricidades %v, se esperaba %v", metricas.Excentricidades, excentricidades)
+	}
+	if metricas.Diametro != 6 || metricas.Radio != 3 {
+		t.Errorf("Diámetro %v y radio %v, se esperaba 6 y 3", metricas.Diametro, metricas.Radio)
+	}
+	if !reflect.DeepEqual(metricas.Centro, []string{"E"}) || !reflect.DeepEqual(metricas.Periferia, []string{"B", "D"}) {
+		t.Errorf("Centro %v y periferia %v inesperados", metricas.Centro, metricas.Periferia)
+	}
+}
+
+func TestMetricasDistanciaDesconectada(t *testing.T) {
+	grafo := crearRedEstrella()
+	grafo.AgregarCueva(domain.NuevaCueva("Z", "Aislada"))
+
+	matriz, err := FloydWarshall(grafo)
+	if err != nil {
+		t.Fatalf("Error en Floyd-Warshall: %v", err)
+	}
+	metricas := matriz.Metricas()
+	if metricas.Diametro != -1 || metricas.Radio != -1 || len(metricas.Centro) != 0 || len(metricas.Periferia) != 0 {
+		t.Errorf("Métricas inesperadas para una red desconectada: %+v", metricas)
+	}
+
+	// En un camino dirigido A -> B -> C solo A alcanza a todas
+	dirigido := domain.NuevoGrafo(true)
+	for _, id := range []string{"A", "B", "C"} {
+		dirigido.AgregarCueva(domain.NuevaCueva(id, id))
+	}
+	dirigido.AgregarConexion("A", "B", 1)
+	dirigido.AgregarConexion("B", "C", 1)
+	matriz, err = FloydWarshall(dirigido)
+	if err != nil {
+		t.Fatalf("Error en Floyd-Warshall: %v", err)
+	}
+	metricas = matriz.Metricas()
+	if metricas.Diametro != -1 || metricas.Radio != 2 || len(metricas.Periferia) != 0 {
+		t.Errorf("Métricas inesperadas para un camino dirigido: %+v", metricas)
+	}
+	if !reflect.DeepEqual(metricas.Centro, []string{"A"}) {
+		t.Errorf("Centro %v, se esperaba [A]", metricas.Centro)
+	}
+}
+
+func TestFloydWarshallCicloNegativo(t *testing.T) {
+	grafo := domain.NuevoGrafo(true)
+	for i := 0; i < 3; i++ {
+		grafo.AgregarCueva(domain.NuevaCueva(fmt.Sprintf("C%d", i), ""))
+	}
+	grafo.AgregarArista(domain.NuevaArista("C0", "C1", 1, true))
+	grafo.AgregarArista(domain.NuevaArista("C1", "C2", -1, true))
+	if matriz, err := FloydWarshall(grafo); err != nil || matriz.Distancia("C0", "C2") != 0 {
+		t.Errorf("Distancia C0 -> C2 inesperada: %v", err)
+	}
+	if _, err := DijkstraTodosLosPares(grafo, 1); err == nil {
+		t.Error("Dijkstra en paralelo debería rechazar distancias negativas")
+	}
+
+	grafo.AgregarArista(domain.NuevaArista("C2", "C1", 0, true))
+	if _, err := FloydWarshall(grafo); err == nil {
+		t.Error("Se esperaba un error por ciclo negativo")
+	}
+}
@@ -0,0 +1,310 @@
+package algorithms
+
+import (
+	"fmt"
+	"math"
+	"proyecto-grafos-go/internal/domain"
+	"sort"
+)
+
+// CaminoRutaCritica identifica la ruta más larga de un grafo acíclico
+const CaminoRutaCritica AlgoritmoCamino = "ruta_critica"
+
+// AnalisisDAG describe el orden de una red dirigida: su orden topológico si es
+// acíclica, o los ciclos que lo impiden si no lo es
+type AnalisisDAG struct {
+	EsAciclico      bool             `json:"es_aciclico"`
+	OrdenTopologico []string         `json:"orden_topologico"` // Vacío si hay ciclos
+	RutaCritica     *ResultadoCamino `json:"ruta_critica,omitempty"`
+	Ciclos          [][]string       `json:"ciclos"`           // Cada ciclo empieza en su menor ID y no repite la cueva inicial
+	CiclosTruncados bool             `json:"ciclos_truncados"` // Había más ciclos que el límite pedido
+}
+
+// AnalizarDAG calcula el orden topológico y la ruta crítica de un grafo
+// dirigido o, si tiene ciclos, enumera hasta limiteCiclos ciclos elementales.
+// Ignora los túneles obstruidos.
+func AnalizarDAG(grafo *domain.Grafo, limiteCiclos int) (*AnalisisDAG, error) {
+	orden, err := OrdenTopologico(grafo)
+	if err == nil {
+		ruta, err := RutaCritica(grafo)
+		if err != nil {
+			return nil, err
+		}
+		return &AnalisisDAG{EsAciclico: true, OrdenTopologico: orden, RutaCritica: ruta, Ciclos: [][]string{}}, nil
+	}
+	if grafo == nil || !grafo.EsDirigido {
+		return nil, err
+	}
+
+	ciclos, truncados, err := CiclosElementales(grafo, limiteCiclos)
+	if err != nil {
+		return nil, err
+	}
+	return &AnalisisDAG{OrdenTopologico: []string{}, Ciclos: ciclos, CiclosTruncados: truncados}, nil
+}
+
+// OrdenTopologico ordena las cuevas de un grafo dirigido de forma que todo
+// túnel vaya de una cueva a otra posterior (algoritmo de Kahn). Devuelve un
+// error si el grafo tiene ciclos.
+func OrdenTopologico(grafo *domain.Grafo) ([]string, error) {
+	ids, adyacencia, err := adyacenciaDirigida(grafo)
+	if err != nil {
+		return nil, err
+	}
+
+	entrantes := make([]int, len(ids))
+	for _, vecinos := range adyacencia {
+		for _, vecino := range vecinos {
+			entrantes[vecino]++
+		}
+	}
+	var cola []int
+	for i := range ids {
+		if entrantes[i] == 0 {
+			cola = append(cola, i)
+		}
+	}
+
+	orden := make([]string, 0, len(ids))
+	for len(cola) > 0 {
+		actual := cola[0]
+		cola = cola[1:]
+		orden = append(orden, ids[actual])
+		for _, vecino := range adyacencia[actual] {
+			entrantes[vecino]--
+			if entrantes[vecino] == 0 {
+				cola = append(cola, vecino)
+			}
+		}
+	}
+
+	if len(orden) < len(ids) {
+		return nil, fmt.Errorf("el grafo tiene ciclos: %d cueva(s) no tienen orden topológico", len(ids)-len(orden))
+	}
+	return orden, nil
+}
+
+// RutaCritica encuentra la ruta de mayor distancia total de un grafo dirigido
+// acíclico, que puede empezar y terminar en cualquier cueva
+func RutaCritica(grafo *domain.Grafo) (*ResultadoCamino, error) {
+	orden, err := OrdenTopologico(grafo)
+	if err != nil {
+		return nil, err
+	}
+	if len(orden) == 0 {
+		return nil, fmt.Errorf("el grafo no tiene cuevas")
+	}
+
+	// Cada cueva puede iniciar la ruta con distancia 0
+	distancias := make(map[string]float64, len(orden))
+	predecesores := make(map[string]string)
+	for _, id := range orden {
+		distancias[id] = 0
+	}
+	for _, id := range orden {
+		for _, t := range tramosDesde(grafo, id, false) {
+			if nueva := distancias[id] + t.distancia; nueva > distancias[t.vecino] {
+				distancias[t.vecino] = nueva
+				predecesores[t.vecino] = id
+			}
+		}
+	}
+
+	final, mayor := "", math.Inf(-1)
+	for _, id := range orden {
+		if distancias[id] > mayor || (distancias[id] == mayor && id < final) {
+			final, mayor = id, distancias[id]
+		}
+	}
+	inicio := final
+	for {
+		previo, ok := predecesores[inicio]
+		if !ok {
+			break
+		}
+		inicio = previo
+	}
+
+	return &ResultadoCamino{
+		Algoritmo:       CaminoRutaCritica,
+		Ruta:            reconstruirRuta(predecesores, inicio, final),
+		Costo:           mayor,
+		NodosExpandidos: len(orden),
+	}, nil
+}
+
+// CiclosElementales enumera con el algoritmo de Johnson los ciclos sin cuevas
+// repetidas de un grafo dirigido, hasta un máximo de limite. El segundo valor
+// indica si quedaron ciclos sin listar.
+func CiclosElementales(grafo *domain.Grafo, limite int) ([][]string, bool, error) {
+	if limite <= 0 {
+		return nil, false, fmt.Errorf("el límite de ciclos debe ser mayor que cero")
+	}
+	ids, adyacencia, err := adyacenciaDirigida(grafo)
+	if err != nil {
+		return nil, false, err
+	}
+
+	inversa := make([][]int, len(adyacencia))
+	for v, vecinos := range adyacencia {
+		for _, w := range vecinos {
+			inversa[w] = append(inversa[w], v)
+		}
+	}
+
+	j := &johnson{
+		ids:          ids,
+		adyacencia:   adyacencia,
+		bloqueada:    make([]bool, len(ids)),
+		dependientes: make([]map[int]bool, len(ids)),
+		limite:       limite,
+		ciclos:       [][]string{},
+	}
+	for inicio := range ids {
+		// Solo interesan los ciclos cuya menor cueva es inicio: se buscan en su
+		// componente fuerte dentro del subgrafo de cuevas >= inicio
+		j.inicio = inicio
+		j.componente = componenteFuerteDesde(adyacencia, inversa, inicio)
+		for v := range ids {
+			if j.componente[v] {
+				j.bloqueada[v] = false
+				j.dependientes[v] = make(map[int]bool)
+			}
+		}
+		j.circuito(inicio)
+		if j.truncado {
+			break
+		}
+	}
+	return j.ciclos, j.truncado, nil
+}
+
+// johnson guarda el estado de la búsqueda de ciclos de Johnson
+type johnson struct {
+	ids          []string
+	adyacencia   [][]int
+	componente   []bool
+	bloqueada    []bool
+	dependientes []map[int]bool // Cuevas a desbloquear cuando se desbloquee cada una
+	pila         []int
+	inicio       int
+	limite       int
+	ciclos       [][]string
+	truncado     bool
+}
+
+func (j *johnson) circuito(v int) bool {
+	hallado := false
+	j.pila = append(j.pila, v)
+	j.bloqueada[v] = true
+
+	for _, w := range j.adyacencia[v] {
+		if j.truncado {
+			break
+		}
+		if !j.componente[w] {
+			continue
+		}
+		if w == j.inicio {
+			j.registrarCiclo()
+			hallado = true
+		} else if !j.bloqueada[w] && j.circuito(w) {
+			hallado = true
+		}
+	}
+
+	if hallado {
+		j.desbloquear(v)
+	} else {
+		for _, w := range j.adyacencia[v] {
+			if j.componente[w] {
+				j.dependientes[w][v] = true
+			}
+		}
+	}
+	j.pila = j.pila[:len(j.pila)-1]
+	return hallado
+}
+
+func (j *johnson) desbloquear(v int) {
+	j.bloqueada[v] = false
+	for w := range j.dependientes[v] {
+		delete(j.dependientes[v], w)
+		if j.bloqueada[w] {
+			j.desbloquear(w)
+		}
+	}
+}
+
+func (j *johnson) registrarCiclo() {
+	if len(j.ciclos) == j.limite {
+		j.truncado = true
+		return
+	}
+	ciclo := make([]string, len(j.pila))
+	for i, v := range j.pila {
+		ciclo[i] = j.ids[v]
+	}
+	j.ciclos = append(j.ciclos, ciclo)
+}
+
+// componenteFuerteDesde marca las cuevas >= inicio que alcanzan a inicio y son
+// alcanzables desde ella sin pasar por cuevas menores
+func componenteFuerteDesde(adyacencia, inversa [][]int, inicio int) []bool {
+	alcanzar := func(ady [][]int) []bool {
+		visitadas := make([]bool, len(ady))
+		visitadas[inicio] = true
+		pila := []int{inicio}
+		for len(pila) > 0 {
+			v := pila[len(pila)-1]
+			pila = pila[:len(pila)-1]
+			for _, w := range ady[v] {
+				if w >= inicio && !visitadas[w] {
+					visitadas[w] = true
+					pila = append(pila, w)
+				}
+			}
+		}
+		return visitadas
+	}
+
+	adelante, atras := alcanzar(adyacencia), alcanzar(inversa)
+	for v := range adelante {
+		adelante[v] = adelante[v] && atras[v]
+	}
+	return adelante
+}
+
+// adyacenciaDirigida devuelve las cuevas ordenadas por ID y, para cada una, los
+// índices de sus vecinos sin repetir por túneles no obstruidos
+func adyacenciaDirigida(grafo *domain.Grafo) ([]string, [][]int, error) {
+	if grafo == nil {
+		return nil, nil, fmt.Errorf("grafo no puede ser nil")
+	}
+	if !grafo.EsDirigido {
+		return nil, nil, fmt.Errorf("el orden topológico requiere un grafo dirigido")
+	}
+
+	ids := make([]string, 0, len(grafo.Cuevas))
+	for id := range grafo.Cuevas {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	indice := make(map[string]int, len(ids))
+	for i, id := range ids {
+		indice[id] = i
+	}
+
+	adyacencia := make([][]int, len(ids))
+	for i, id := range ids {
+		vistos := make(map[int]bool)
+		for _, t := range tramosDesde(grafo, id, false) {
+			if w, ok := indice[t.vecino]; ok && !vistos[w] {
+				vistos[w] = true
+				adyacencia[i] = append(adyacencia[i], w)
+			}
+		}
+		sort.Ints(adyacencia[i])
+	}
+	return ids, adyacencia, nil
+}
@@ -0,0 +1,107 @@
+package algorithms
+
+import (
+	"proyecto-grafos-go/internal/domain"
+	"reflect"
+	"testing"
+)
+
+// tunelPrueba es un túnel dirigido de los grafos de prueba
+type tunelPrueba struct {
+	desde, hasta string
+	distancia    float64
+}
+
+// crearRedDirigida crea un grafo dirigido con los túneles indicados
+func crearRedDirigida(ids []string, tuneles []tunelPrueba) *domain.Grafo {
+	grafo := domain.NuevoGrafo(true)
+	for _, id := range ids {
+		grafo.AgregarCueva(domain.NuevaCueva(id, id))
+	}
+	for _, t := range tuneles {
+		grafo.AgregarArista(domain.NuevaArista(t.desde, t.hasta, t.distancia, true))
+	}
+	return grafo
+}
+
+func TestOrdenTopologicoYRutaCritica(t *testing.T) {
+	grafo := crearRedDirigida([]string{"A", "B", "C", "D", "E"}, []tunelPrueba{
+		{"A", "B", 3}, {"A", "C", 2}, {"B", "D", 4}, {"C", "D", 1}, {"C", "E", 9}, {"D", "E", 1},
+	})
+
+	analisis, err := AnalizarDAG(grafo, 10)
+	if err != nil {
+		t.Fatalf("Error analizando el DAG: %v", err)
+	}
+	if !analisis.EsAciclico || len(analisis.Ciclos) != 0 {
+		t.Fatalf("El grafo debería ser acíclico: %+v", analisis)
+	}
+
+	posicion := make(map[string]int)
+	for i, id := range analisis.OrdenTopologico {
+		posicion[id] = i
+	}
+	if len(posicion) != 5 {
+		t.Fatalf("Orden topológico incompleto: %v", analisis.OrdenTopologico)
+	}
+	for _, arista := range grafo.Aristas {
+		if posicion[arista.Desde] >= posicion[arista.Hasta] {
+			t.Errorf("El túnel %s -> %s no respeta el orden %v", arista.Desde, arista.Hasta, analisis.OrdenTopologico)
+		}
+	}
+
+	ruta := analisis.RutaCritica
+	if ruta == nil || ruta.Costo != 11 || !reflect.DeepEqual(ruta.Ruta, []string{"A", "C", "E"}) {
+		t.Errorf("Ruta crítica inesperada: %+v", ruta)
+	}
+
+	// Obstruir C -> E cambia la ruta crítica
+	arista, _ := grafo.ObtenerConexion("C", "E")
+	arista.EsObstruido = true
+	ruta, err = RutaCritica(grafo)
+	if err != nil || ruta.Costo != 8 || !reflect.DeepEqual(ruta.Ruta, []string{"A", "B", "D", "E"}) {
+		t.Errorf("Ruta crítica con C -> E obstruido inesperada: %+v, %v", ruta, err)
+	}
+}
+
+func TestCiclosElementales(t *testing.T) {
+	grafo := crearRedDirigida([]string{"A", "B", "C", "D"}, []tunelPrueba{
+		{"A", "B", 1}, {"B", "C", 1}, {"C", "A", 1}, {"C", "D", 1}, {"D", "C", 1}, {"D", "D", 1},
+	})
+
+	analisis, err := AnalizarDAG(grafo, 10)
+	if err != nil {
+		t.Fatalf("Error analizando el grafo: %v", err)
+	}
+	esperados := [][]string{{"A", "B", "C"}, {"C", "D"}, {"D"}}
+	if analisis.EsAciclico || analisis.CiclosTruncados || !reflect.DeepEqual(analisis.Ciclos, esperados) {
+		t.Errorf("Ciclos %v (truncados %t), se esperaba %v", analisis.Ciclos, analisis.CiclosTruncados, esperados)
+	}
+	if _, err := RutaCritica(grafo); err == nil {
+		t.Error("Se esperaba un error al buscar la ruta crítica de un grafo con ciclos")
+	}
+
+	// Un grafo dirigido completo de 4 cuevas tiene 6 + 8 + 6 = 20 ciclos
+	var tuneles []tunelPrueba
+	ids := []string{"A", "B", "C", "D"}
+	for _, desde := range ids {
+		for _, hasta := range ids {
+			if desde != hasta {
+				tuneles = append(tuneles, tunelPrueba{desde, hasta, 1})
+			}
+		}
+	}
+	completo := crearRedDirigida(ids, tuneles)
+	ciclos, truncados, err := CiclosElementales(completo, 100)
+	if err != nil || len(ciclos) != 20 || truncados {
+		t.Errorf("Se esperaban 20 ciclos, se obtuvieron %d (truncados %t, error %v)", len(ciclos), truncados, err)
+	}
+	ciclos, truncados, _ = CiclosElementales(completo, 5)
+	if len(ciclos) != 5 || !truncados {
+		t.Errorf("El límite debería truncar a 5 ciclos: %d, truncados %t", len(ciclos), truncados)
+	}
+
+	if _, err := AnalizarDAG(domain.NuevoGrafo(false), 10); err == nil {
+		t.Error("Se esperaba un error con un grafo no dirigido")
+	}
+}
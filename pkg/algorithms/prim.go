@@ -145,7 +145,7 @@ func Prim(grafo *domain.Grafo, cuevaInicio string) (*MSTDesdeCueva, error) {
 
 // agregarAristasDesdeCueva agrega todas las aristas válidas desde una cueva al priority queue
 func agregarAristasDesdeCueva(grafo *domain.Grafo, cueva string, visitado map[string]bool, pq *PriorityQueue) {
-	for _, arista := range grafo.Aristas {
+	for _, arista := range grafo.AristasIncidentes(cueva) {
 		if arista.EsObstruido {
 			continue
 		}
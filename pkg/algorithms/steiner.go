@@ -0,0 +1,173 @@
+package algorithms
+
+import (
+	"fmt"
+	"math"
+	"proyecto-grafos-go/internal/domain"
+	"sort"
+)
+
+// ArbolSteiner es un árbol que conecta un subconjunto de cuevas (terminales),
+// pudiendo pasar por otras cuevas intermedias
+type ArbolSteiner struct {
+	Terminales    []string         `json:"terminales"`
+	CuevasSteiner []string         `json:"cuevas_steiner"` // Cuevas del árbol que no son terminales
+	Aristas       []*domain.Arista `json:"aristas"`
+	PesoTotal     float64          `json:"peso_total"`
+	NumAristas    int              `json:"num_aristas"`
+}
+
+// ArbolSteinerAproximado conecta las cuevas terminales con la aproximación de
+// Kou, Markowsky y Berman: MST de la clausura métrica de las terminales,
+// expandido a caminos mínimos, vuelto a reducir con Kruskal y podado de hojas
+// que no son terminales. Su peso es a lo sumo el doble del óptimo. Como el
+// MST, trata los túneles como bidireccionales e ignora los obstruidos.
+func ArbolSteinerAproximado(grafo *domain.Grafo, terminales []string) (*ArbolSteiner, error) {
+	if grafo == nil {
+		return nil, fmt.Errorf("el grafo no puede ser nil")
+	}
+
+	arbol := &ArbolSteiner{Terminales: []string{}, CuevasSteiner: []string{}, Aristas: []*domain.Arista{}}
+	esTerminal := make(map[string]bool)
+	for _, id := range terminales {
+		if _, existe := grafo.Cuevas[id]; !existe {
+			return nil, fmt.Errorf("la cueva terminal '%s' no existe en el grafo", id)
+		}
+		if !esTerminal[id] {
+			esTerminal[id] = true
+			arbol.Terminales = append(arbol.Terminales, id)
+		}
+	}
+	if len(arbol.Terminales) == 0 {
+		return nil, fmt.Errorf("se requiere al menos una cueva terminal")
+	}
+
+	// Vista no dirigida con el túnel más corto entre cada par de cuevas
+	vista := domain.NuevoGrafo(false)
+	for _, cueva := range grafo.Cuevas {
+		vista.AgregarCueva(cueva)
+	}
+	representantes := make(map[[2]string]*domain.Arista)
+	for _, arista := range grafo.Aristas {
+		if arista.EsObstruido || arista.Desde == arista.Hasta {
+			continue
+		}
+		if arista.Distancia < 0 {
+			return nil, fmt.Errorf("el túnel %s -> %s tiene distancia negativa", arista.Desde, arista.Hasta)
+		}
+		clave := claveTunel(arista.Desde, arista.Hasta)
+		if actual, ok := representantes[clave]; !ok || arista.Distancia < actual.Distancia {
+			representantes[clave] = arista
+		}
+	}
+	for clave, arista := range representantes {
+		vista.AgregarConexion(clave[0], clave[1], arista.Distancia)
+	}
+
+	matriz, err := CalcularMatrizDistancias(vista)
+	if err != nil {
+		return nil, err
+	}
+
+	// MST de la clausura métrica de las terminales (Prim sobre la matriz completa)
+	k := len(arbol.Terminales)
+	enArbol := make([]bool, k)
+	costo := make([]float64, k)
+	padre := make([]int, k)
+	for i := range costo {
+		costo[i], padre[i] = math.Inf(1), -1
+	}
+	costo[0] = 0
+	usados := make(map[[2]string]bool)
+	for paso := 0; paso < k; paso++ {
+		actual := -1
+		for i := 0; i < k; i++ {
+			if !enArbol[i] && (actual == -1 || costo[i] < costo[actual]) {
+				actual = i
+			}
+		}
+		if math.IsInf(costo[actual], 1) {
+			return nil, fmt.Errorf("la cueva terminal '%s' no está conectada con '%s'", arbol.Terminales[actual], arbol.Terminales[0])
+		}
+		enArbol[actual] = true
+		if padre[actual] != -1 {
+			ruta := matriz.Ruta(arbol.Terminales[padre[actual]], arbol.Terminales[actual])
+			for i := 1; i < len(ruta); i++ {
+				usados[claveTunel(ruta[i-1], ruta[i])] = true
+			}
+		}
+		for i := 0; i < k; i++ {
+			if d := matriz.Distancia(arbol.Terminales[actual], arbol.Terminales[i]); !enArbol[i] && d < costo[i] {
+				costo[i], padre[i] = d, actual
+			}
+		}
+	}
+
+	// Los caminos pueden formar ciclos entre sí: Kruskal sobre los túneles usados
+	claves := make([][2]string, 0, len(usados))
+	for clave := range usados {
+		claves = append(claves, clave)
+	}
+	sort.Slice(claves, func(i, j int) bool {
+		di, dj := representantes[claves[i]].Distancia, representantes[claves[j]].Distancia
+		if di != dj {
+			return di < dj
+		}
+		return claves[i][0] < claves[j][0] || (claves[i][0] == claves[j][0] && claves[i][1] < claves[j][1])
+	})
+	uf := NuevoUnionFind()
+	grado := make(map[string]int)
+	for _, clave := range claves {
+		uf.MakeSet(clave[0])
+		uf.MakeSet(clave[1])
+	}
+	seleccionadas := make(map[[2]string]bool)
+	for _, clave := range claves {
+		if uf.Union(clave[0], clave[1]) {
+			seleccionadas[clave] = true
+			grado[clave[0]]++
+			grado[clave[1]]++
+		}
+	}
+
+	// Podar hojas que no son terminales hasta que no quede ninguna
+	for podado := true; podado; {
+		podado = false
+		for clave := range seleccionadas {
+			for _, extremo := range clave {
+				if grado[extremo] == 1 && !esTerminal[extremo] {
+					delete(seleccionadas, clave)
+					grado[clave[0]]--
+					grado[clave[1]]--
+					podado = true
+					break
+				}
+			}
+		}
+	}
+
+	for _, clave := range claves {
+		if !seleccionadas[clave] {
+			continue
+		}
+		arista := representantes[clave]
+		arbol.Aristas = append(arbol.Aristas, arista)
+		arbol.PesoTotal += arista.Distancia
+	}
+	for id, g := range grado {
+		if g > 0 && !esTerminal[id] {
+			arbol.CuevasSteiner = append(arbol.CuevasSteiner, id)
+		}
+	}
+	sort.Strings(arbol.CuevasSteiner)
+	arbol.NumAristas = len(arbol.Aristas)
+	return arbol, nil
+}
+
+// claveTunel identifica un túnel sin importar su sentido
+func claveTunel(a, b string) [2]string {
+	if b < a {
+		a, b = b, a
+	}
+	return [2]string{a, b}
+}
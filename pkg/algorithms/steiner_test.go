@@ -0,0 +1,156 @@
+package algorithms
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"proyecto-grafos-go/internal/domain"
+	"reflect"
+	"testing"
+)
+
+// verificarArbolSteiner comprueba que las aristas formen un árbol que une las
+// terminales y cuyas hojas sean todas terminales
+func verificarArbolSteiner(t *testing.T, arbol *ArbolSteiner) {
+	t.Helper()
+	uf := NuevoUnionFind()
+	grado := make(map[string]int)
+	peso := 0.0
+	for _, arista := range arbol.Aristas {
+		for _, id := range []string{arista.Desde, arista.Hasta} {
+			if _, ok := grado[id]; !ok {
+				uf.MakeSet(id)
+			}
+			grado[id]++
+		}
+		if !uf.Union(arista.Desde, arista.Hasta) {
+			t.Fatalf("El árbol tiene un ciclo en %s - %s", arista.Desde, arista.Hasta)
+		}
+		peso += arista.Distancia
+	}
+	if math.Abs(peso-arbol.PesoTotal) > 1e-9 || arbol.NumAristas != len(arbol.Aristas) {
+		t.Errorf("Peso o número de aristas inconsistentes: %+v", arbol)
+	}
+
+	esTerminal := make(map[string]bool)
+	for _, id := range arbol.Terminales {
+		esTerminal[id] = true
+		if len(arbol.Terminales) > 1 && uf.Find(id) != uf.Find(arbol.Terminales[0]) {
+			t.Errorf("La terminal %s no está conectada", id)
+		}
+	}
+	for id, g := range grado {
+		if g == 1 && !esTerminal[id] {
+			t.Errorf("La hoja %s no es terminal", id)
+		}
+	}
+}
+
+func TestArbolSteinerUsaCuevaIntermedia(t *testing.T) {
+	// Ir de una terminal a otra por S cuesta 2 y el túnel directo 3
+	grafo := domain.NuevoGrafo(false)
+	for _, id := range []string{"A", "B", "C", "S", "X"} {
+		grafo.AgregarCueva(domain.NuevaCueva(id, id))
+	}
+	for _, par := range [][2]string{{"A", "S"}, {"B", "S"}, {"C", "S"}} {
+		grafo.AgregarConexion(par[0], par[1], 1)
+	}
+	for _, par := range [][2]string{{"A", "B"}, {"B", "C"}, {"A", "C"}} {
+		grafo.AgregarConexion(par[0], par[1], 3)
+	}
+	grafo.AgregarConexion("X", "A", 1)
+
+	arbol, err := ArbolSteinerAproximado(grafo, []string{"A", "B", "C", "A"})
+	if err != nil {
+		t.Fatalf("Error en árbol de Steiner: %v", err)
+	}
+	verificarArbolSteiner(t, arbol)
+	if arbol.PesoTotal != 3 || !reflect.DeepEqual(arbol.CuevasSteiner, []string{"S"}) {
+		t.Errorf("Se esperaba el árbol estrella por S de peso 3, se obtuvo %.2f con %v", arbol.PesoTotal, arbol.CuevasSteiner)
+	}
+	if !reflect.DeepEqual(arbol.Terminales, []string{"A", "B", "C"}) {
+		t.Errorf("Terminales inesperadas: %v", arbol.Terminales)
+	}
+
+	// Una sola terminal no necesita túneles
+	arbol, err = ArbolSteinerAproximado(grafo, []string{"X"})
+	if err != nil || arbol.NumAristas != 0 {
+		t.Errorf("Una terminal sola no debería tener túneles: %+v, %v", arbol, err)
+	}
+}
+
+func TestArbolSteinerAleatorio(t *testing.T) {
+	r := rand.New(rand.NewSource(20))
+	for caso := 0; caso < 100; caso++ {
+		grafo := domain.NuevoGrafo(caso%3 == 0)
+		n := 2 + r.Intn(12)
+		ids := make([]string, n)
+		for i := range ids {
+			ids[i] = fmt.Sprintf("C%d", i)
+			grafo.AgregarCueva(domain.NuevaCueva(ids[i], ids[i]))
+		}
+		// Un árbol aleatorio más túneles extra garantiza la conectividad
+		for i := 1; i < n; i++ {
+			grafo.AgregarConexion(ids[r.Intn(i)], ids[i], float64(1+r.Intn(15)))
+		}
+		for k := 0; k < n; k++ {
+			a, b := ids[r.Intn(n)], ids[r.Intn(n)]
+			if a != b {
+				grafo.AgregarConexion(a, b, float64(1+r.Intn(15)))
+			}
+		}
+
+		terminales := make([]string, 0)
+		for _, id := range ids {
+			if r.Intn(2) == 0 {
+				terminales = append(terminales, id)
+			}
+		}
+		if len(terminales) == 0 {
+			terminales = append(terminales, ids[0])
+		}
+		arbol, err := ArbolSteinerAproximado(grafo, terminales)
+		if err != nil {
+			t.Fatalf("Caso %d: error en árbol de Steiner: %v", caso, err)
+		}
+		verificarArbolSteiner(t, arbol)
+
+		// Con todas las cuevas como terminales el árbol es un MST
+		arbol, err = ArbolSteinerAproximado(grafo, ids)
+		if err != nil {
+			t.Fatalf("Caso %d: error en árbol de Steiner: %v", caso, err)
+		}
+		mst, err := Kruskal(grafo)
+		if err != nil {
+			t.Fatalf("Caso %d: error en Kruskal: %v", caso, err)
+		}
+		if math.Abs(arbol.PesoTotal-mst.PesoTotal) > 1e-9 {
+			t.Errorf("Caso %d: con todas las terminales el peso %.2f difiere del MST %.2f", caso, arbol.PesoTotal, mst.PesoTotal)
+		}
+	}
+}
+
+func TestArbolSteinerErrores(t *testing.T) {
+	grafo := domain.NuevoGrafo(true)
+	for _, id := range []string{"A", "B", "C"} {
+		grafo.AgregarCueva(domain.NuevaCueva(id, id))
+	}
+	grafo.AgregarConexion("A", "B", 2)
+	grafo.AgregarConexion("B", "C", 2)
+
+	// En una red dirigida los túneles se usan en ambos sentidos, como en el MST
+	if arbol, err := ArbolSteinerAproximado(grafo, []string{"C", "A"}); err != nil || arbol.PesoTotal != 4 {
+		t.Errorf("Árbol inesperado en red dirigida: %+v, %v", arbol, err)
+	}
+
+	grafo.Aristas[1].EsObstruido = true
+	if _, err := ArbolSteinerAproximado(grafo, []string{"A", "C"}); err == nil {
+		t.Error("Se esperaba un error con la terminal C aislada")
+	}
+	if _, err := ArbolSteinerAproximado(grafo, []string{"A", "Z"}); err == nil {
+		t.Error("Se esperaba un error por cueva inexistente")
+	}
+	if _, err := ArbolSteinerAproximado(grafo, nil); err == nil {
+		t.Error("Se esperaba un error sin terminales")
+	}
+}
@@ -0,0 +1,313 @@
+package algorithms
+
+import (
+	"fmt"
+	"math"
+	"proyecto-grafos-go/internal/domain"
+)
+
+// ModoTSP identifica la estrategia del planificador de recorridos
+type ModoTSP string
+
+const (
+	TSPVecinoCercano ModoTSP = "vecino_cercano" // Solo construcción por vecino más cercano
+	TSPMejorado      ModoTSP = "mejorado"       // Vecino más cercano mejorado con 2-opt y Or-opt
+	TSPHeldKarp      ModoTSP = "held_karp"      // Exacto, para pocas cuevas
+)
+
+// ModosTSP lista los modos disponibles en el orden en que se ofrecen
+var ModosTSP = []ModoTSP{TSPMejorado, TSPVecinoCercano, TSPHeldKarp}
+
+// MaxCuevasHeldKarp es el máximo de cuevas, origen incluido, del modo exacto
+const MaxCuevasHeldKarp = 16
+
+// ResultadoTSP es un recorrido que visita un conjunto de cuevas por caminos mínimos
+type ResultadoTSP struct {
+	Modo          ModoTSP   `json:"modo"`
+	Orden         []string  `json:"orden"`  // Cuevas en el orden de visita; empieza en el origen y, si es cerrado, termina en él
+	Tramos        []float64 `json:"tramos"` // Distancia mínima de cada cueva de Orden a la siguiente
+	Ruta          []string  `json:"ruta"`   // Ruta completa por túneles, con las cuevas intermedias
+	Costo         float64   `json:"costo"`
+	Cerrado       bool      `json:"cerrado"`       // El recorrido regresa al origen
+	Inalcanzables []string  `json:"inalcanzables"` // Cuevas pedidas sin ruta desde el origen
+}
+
+// PlanificarRecorridoTSP ordena la visita a las cuevas indicadas (todas si es
+// nil) sobre la clausura métrica de caminos mínimos del grafo. Las cuevas que
+// no se alcanzan desde el origen se omiten y se informan en Inalcanzables.
+// Con regresar el recorrido termina en el origen.
+func PlanificarRecorridoTSP(grafo *domain.Grafo, origen string, cuevas []string, modo ModoTSP, regresar bool) (*ResultadoTSP, error) {
+	if err := validarExtremos(grafo, origen, origen); err != nil {
+		return nil, err
+	}
+	matriz, err := CalcularMatrizDistancias(grafo)
+	if err != nil {
+		return nil, err
+	}
+	if cuevas == nil {
+		cuevas = matriz.IDs
+	}
+
+	resultado := &ResultadoTSP{Modo: modo, Cerrado: regresar, Inalcanzables: []string{}}
+	objetivos := []string{origen}
+	incluidas := map[string]bool{origen: true}
+	for _, id := range cuevas {
+		if _, existe := grafo.Cuevas[id]; !existe {
+			return nil, fmt.Errorf("la cueva '%s' no existe en el grafo", id)
+		}
+		if incluidas[id] {
+			continue
+		}
+		incluidas[id] = true
+		if math.IsInf(matriz.Distancia(origen, id), 1) {
+			resultado.Inalcanzables = append(resultado.Inalcanzables, id)
+			continue
+		}
+		objetivos = append(objetivos, id)
+	}
+
+	planificador := nuevoPlanificadorTSP(matriz, objetivos, regresar)
+	var orden []int
+	switch modo {
+	case TSPVecinoCercano:
+		orden = planificador.vecinoMasCercano()
+	case TSPMejorado, "":
+		resultado.Modo = TSPMejorado
+		orden = planificador.mejorar(planificador.vecinoMasCercano())
+	case TSPHeldKarp:
+		if len(objetivos) > MaxCuevasHeldKarp {
+			return nil, fmt.Errorf("Held-Karp admite hasta %d cuevas y el recorrido tiene %d", MaxCuevasHeldKarp, len(objetivos))
+		}
+		orden = planificador.heldKarp()
+	default:
+		return nil, fmt.Errorf("modo de recorrido TSP no válido: %s", modo)
+	}
+
+	// Armar el recorrido sobre los caminos mínimos reales
+	if regresar && len(orden) > 1 {
+		orden = append(orden, 0)
+	}
+	resultado.Ruta = []string{origen}
+	for i, posicion := range orden {
+		resultado.Orden = append(resultado.Orden, objetivos[posicion])
+		if i == 0 {
+			continue
+		}
+		desde, hasta := objetivos[orden[i-1]], objetivos[posicion]
+		tramo := matriz.Distancia(desde, hasta)
+		if math.IsInf(tramo, 1) {
+			return nil, fmt.Errorf("no hay un recorrido que visite todas las cuevas: falta ruta de '%s' a '%s'", desde, hasta)
+		}
+		resultado.Tramos = append(resultado.Tramos, tramo)
+		resultado.Costo += tramo
+		resultado.Ruta = append(resultado.Ruta, matriz.Ruta(desde, hasta)[1:]...)
+	}
+	return resultado, nil
+}
+
+// planificadorTSP trabaja con posiciones en objetivos; la 0 es el origen
+type planificadorTSP struct {
+	costos  [][]float64 // Las rutas inexistentes se penalizan con un costo finito enorme
+	cerrado bool
+}
+
+func nuevoPlanificadorTSP(matriz *MatrizDistancias, objetivos []string, cerrado bool) *planificadorTSP {
+	n := len(objetivos)
+	penalizacion := 1.0
+	for _, fila := range matriz.Distancias {
+		for _, distancia := range fila {
+			if !math.IsInf(distancia, 1) {
+				penalizacion += math.Abs(distancia)
+			}
+		}
+	}
+	penalizacion *= float64(n + 1)
+
+	p := &planificadorTSP{costos: make([][]float64, n), cerrado: cerrado}
+	for i, desde := range objetivos {
+		p.costos[i] = make([]float64, n)
+		for j, hasta := range objetivos {
+			if distancia := matriz.Distancia(desde, hasta); !math.IsInf(distancia, 1) {
+				p.costos[i][j] = distancia
+			} else {
+				p.costos[i][j] = penalizacion
+			}
+		}
+	}
+	return p
+}
+
+// costo entre dos posiciones del recorrido; -1 representa "ninguna"
+func (p *planificadorTSP) costo(desde, hasta int) float64 {
+	if desde < 0 || hasta < 0 {
+		return 0
+	}
+	return p.costos[desde][hasta]
+}
+
+// siguiente devuelve la cueva que sigue a la posición k del recorrido
+func (p *planificadorTSP) siguiente(recorrido []int, k int) int {
+	if k+1 < len(recorrido) {
+		return recorrido[k+1]
+	}
+	if p.cerrado {
+		return recorrido[0]
+	}
+	return -1
+}
+
+func (p *planificadorTSP) vecinoMasCercano() []int {
+	n := len(p.costos)
+	visitadas := make([]bool, n)
+	visitadas[0] = true
+	recorrido := []int{0}
+	for len(recorrido) < n {
+		actual, mejor := recorrido[len(recorrido)-1], -1
+		for j := 0; j < n; j++ {
+			if !visitadas[j] && (mejor == -1 || p.costos[actual][j] < p.costos[actual][mejor]) {
+				mejor = j
+			}
+		}
+		visitadas[mejor] = true
+		recorrido = append(recorrido, mejor)
+	}
+	return recorrido
+}
+
+// mejorar aplica 2-opt y Or-opt hasta que ninguno reduzca el costo
+func (p *planificadorTSP) mejorar(recorrido []int) []int {
+	for {
+		if !p.dosOpt(recorrido) && !p.orOpt(&recorrido) {
+			return recorrido
+		}
+	}
+}
+
+// dosOpt invierte el primer segmento cuya inversión acorta el recorrido. Como la
+// red puede ser dirigida, compara el costo del segmento en ambos sentidos.
+func (p *planificadorTSP) dosOpt(recorrido []int) bool {
+	n := len(recorrido)
+	adelante := make([]float64, n) // adelante[k]: costo de recorrido[0..k]
+	atras := make([]float64, n)    // atras[k]: lo mismo recorrido en sentido contrario
+	for k := 1; k < n; k++ {
+		adelante[k] = adelante[k-1] + p.costo(recorrido[k-1], recorrido[k])
+		atras[k] = atras[k-1] + p.costo(recorrido[k], recorrido[k-1])
+	}
+
+	for i := 1; i < n-1; i++ {
+		previa := recorrido[i-1]
+		for j := i + 1; j < n; j++ {
+			posterior := p.siguiente(recorrido, j)
+			antes := p.costo(previa, recorrido[i]) + adelante[j] - adelante[i] + p.costo(recorrido[j], posterior)
+			despues := p.costo(previa, recorrido[j]) + atras[j] - atras[i] + p.costo(recorrido[i], posterior)
+			if despues < antes-toleranciaTSP {
+				for a, b := i, j; a < b; a, b = a+1, b-1 {
+					recorrido[a], recorrido[b] = recorrido[b], recorrido[a]
+				}
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// orOpt mueve el primer segmento de 1 a 3 cuevas cuyo traslado acorta el recorrido
+func (p *planificadorTSP) orOpt(recorrido *[]int) bool {
+	actual := *recorrido
+	n := len(actual)
+	for largo := 1; largo <= 3; largo++ {
+		for i := 1; i+largo <= n; i++ {
+			segmento := actual[i : i+largo]
+			primera, ultima := segmento[0], segmento[largo-1]
+			previa, posterior := actual[i-1], p.siguiente(actual, i+largo-1)
+			ahorro := p.costo(previa, primera) + p.costo(ultima, posterior) - p.costo(previa, posterior)
+
+			resto := append(append([]int{}, actual[:i]...), actual[i+largo:]...)
+			for k := range resto {
+				u := resto[k]
+				if u == previa {
+					continue
+				}
+				v := -1
+				if k+1 < len(resto) {
+					v = resto[k+1]
+				} else if p.cerrado {
+					v = resto[0]
+				}
+				if p.costo(u, primera)+p.costo(ultima, v)-p.costo(u, v) < ahorro-toleranciaTSP {
+					nuevo := append(append(append([]int{}, resto[:k+1]...), segmento...), resto[k+1:]...)
+					*recorrido = nuevo
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+// heldKarp resuelve el recorrido óptimo por programación dinámica sobre subconjuntos
+func (p *planificadorTSP) heldKarp() []int {
+	n := len(p.costos)
+	if n == 1 {
+		return []int{0}
+	}
+
+	// mejor[mascara][j]: costo mínimo de salir del origen, visitar las cuevas de
+	// mascara (bit j-1 por cueva j) y terminar en j
+	m := n - 1
+	total := 1 << m
+	mejor := make([][]float64, total)
+	previa := make([][]int, total)
+	for mascara := range mejor {
+		mejor[mascara] = make([]float64, n)
+		previa[mascara] = make([]int, n)
+		for j := range mejor[mascara] {
+			mejor[mascara][j] = math.Inf(1)
+		}
+	}
+	for j := 1; j < n; j++ {
+		mejor[1<<(j-1)][j] = p.costos[0][j]
+		previa[1<<(j-1)][j] = 0
+	}
+
+	for mascara := 1; mascara < total; mascara++ {
+		for j := 1; j < n; j++ {
+			if mascara&(1<<(j-1)) == 0 || math.IsInf(mejor[mascara][j], 1) {
+				continue
+			}
+			for k := 1; k < n; k++ {
+				if mascara&(1<<(k-1)) != 0 {
+					continue
+				}
+				siguiente := mascara | 1<<(k-1)
+				if costo := mejor[mascara][j] + p.costos[j][k]; costo < mejor[siguiente][k] {
+					mejor[siguiente][k] = costo
+					previa[siguiente][k] = j
+				}
+			}
+		}
+	}
+
+	final, costoFinal := 0, math.Inf(1)
+	for j := 1; j < n; j++ {
+		costo := mejor[total-1][j]
+		if p.cerrado {
+			costo += p.costos[j][0]
+		}
+		if costo < costoFinal {
+			final, costoFinal = j, costo
+		}
+	}
+
+	recorrido := make([]int, n)
+	mascara := total - 1
+	for pos, j := n-1, final; pos > 0; pos-- {
+		recorrido[pos] = j
+		anterior := previa[mascara][j]
+		mascara &^= 1 << (j - 1)
+		j = anterior
+	}
+	return recorrido
+}
+
+const toleranciaTSP = 1e-9
@@ -0,0 +1,143 @@
+package algorithms
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"proyecto-grafos-go/internal/domain"
+	"reflect"
+	"testing"
+)
+
+// costoMinimoFuerzaBruta prueba todas las permutaciones de las cuevas distintas del origen
+func costoMinimoFuerzaBruta(matriz *MatrizDistancias, origen string, cuevas []string, regresar bool) float64 {
+	mejor := math.Inf(1)
+	var permutar func(actual string, restantes []string, costo float64)
+	permutar = func(actual string, restantes []string, costo float64) {
+		if len(restantes) == 0 {
+			if regresar {
+				costo += matriz.Distancia(actual, origen)
+			}
+			mejor = math.Min(mejor, costo)
+			return
+		}
+		for i, siguiente := range restantes {
+			resto := append(append([]string{}, restantes[:i]...), restantes[i+1:]...)
+			permutar(siguiente, resto, costo+matriz.Distancia(actual, siguiente))
+		}
+	}
+	permutar(origen, cuevas, 0)
+	return mejor
+}
+
+func TestPlanificarRecorridoTSPHeldKarpOptimo(t *testing.T) {
+	r := rand.New(rand.NewSource(19))
+	for caso := 0; caso < 40; caso++ {
+		dirigido := caso%2 == 1
+		grafo := domain.NuevoGrafo(dirigido)
+		n := 3 + r.Intn(5)
+		ids := make([]string, n)
+		for i := range ids {
+			ids[i] = fmt.Sprintf("C%d", i)
+			grafo.AgregarCueva(domain.NuevaCueva(ids[i], ids[i]))
+		}
+		// Un ciclo garantiza que todas las cuevas se alcanzan entre sí
+		for i := range ids {
+			grafo.AgregarConexion(ids[i], ids[(i+1)%n], float64(1+r.Intn(20)))
+		}
+		for k := 0; k < n; k++ {
+			a, b := ids[r.Intn(n)], ids[r.Intn(n)]
+			if a != b && !grafo.ExisteConexion(a, b) {
+				grafo.AgregarConexion(a, b, float64(1+r.Intn(20)))
+			}
+		}
+
+		matriz, err := CalcularMatrizDistancias(grafo)
+		if err != nil {
+			t.Fatalf("Error en matriz de distancias: %v", err)
+		}
+		for _, regresar := range []bool{false, true} {
+			optimo := costoMinimoFuerzaBruta(matriz, "C0", ids[1:], regresar)
+			exacto, err := PlanificarRecorridoTSP(grafo, "C0", nil, TSPHeldKarp, regresar)
+			if err != nil {
+				t.Fatalf("Caso %d: error en Held-Karp: %v", caso, err)
+			}
+			if math.Abs(exacto.Costo-optimo) > 1e-9 {
+				t.Errorf("Caso %d (regresar=%v): Held-Karp %.2f, fuerza bruta %.2f", caso, regresar, exacto.Costo, optimo)
+			}
+
+			vecino, _ := PlanificarRecorridoTSP(grafo, "C0", nil, TSPVecinoCercano, regresar)
+			mejorado, _ := PlanificarRecorridoTSP(grafo, "C0", nil, TSPMejorado, regresar)
+			if mejorado.Costo > vecino.Costo+1e-9 || mejorado.Costo < optimo-1e-9 {
+				t.Errorf("Caso %d (regresar=%v): mejorado %.2f fuera de [%.2f, %.2f]", caso, regresar, mejorado.Costo, optimo, vecino.Costo)
+			}
+
+			for _, resultado := range []*ResultadoTSP{exacto, vecino, mejorado} {
+				if got := costoRuta(t, grafo, resultado.Ruta); math.Abs(got-resultado.Costo) > 1e-9 {
+					t.Errorf("Caso %d: la ruta cuesta %.2f y el recorrido informa %.2f", caso, got, resultado.Costo)
+				}
+				visitadas := make(map[string]bool)
+				for _, id := range resultado.Orden {
+					visitadas[id] = true
+				}
+				if len(visitadas) != n || len(resultado.Tramos) != len(resultado.Orden)-1 {
+					t.Errorf("Caso %d: recorrido incompleto %v", caso, resultado.Orden)
+				}
+				if regresar && resultado.Orden[len(resultado.Orden)-1] != "C0" {
+					t.Errorf("Caso %d: el recorrido cerrado no vuelve al origen: %v", caso, resultado.Orden)
+				}
+			}
+		}
+	}
+}
+
+func TestPlanificarRecorridoTSPMejoraCuadricula(t *testing.T) {
+	grafo := crearCuadricula(5)
+	vecino, err := PlanificarRecorridoTSP(grafo, "C0_0", nil, TSPVecinoCercano, true)
+	if err != nil {
+		t.Fatalf("Error en vecino más cercano: %v", err)
+	}
+	mejorado, err := PlanificarRecorridoTSP(grafo, "C0_0", nil, TSPMejorado, true)
+	if err != nil {
+		t.Fatalf("Error en recorrido mejorado: %v", err)
+	}
+	if mejorado.Costo > vecino.Costo {
+		t.Errorf("El recorrido mejorado (%.2f) es peor que el vecino más cercano (%.2f)", mejorado.Costo, vecino.Costo)
+	}
+	if len(mejorado.Orden) != 26 {
+		t.Errorf("Se esperaban 25 cuevas más el regreso, se obtuvieron %d", len(mejorado.Orden))
+	}
+
+	if _, err := PlanificarRecorridoTSP(grafo, "C0_0", nil, TSPHeldKarp, true); err == nil {
+		t.Errorf("Se esperaba un error: Held-Karp admite hasta %d cuevas", MaxCuevasHeldKarp)
+	}
+}
+
+func TestPlanificarRecorridoTSPInalcanzables(t *testing.T) {
+	// B solo se alcanza desde C y D no tiene túneles
+	grafo := crearRedDirigida([]string{"A", "B", "C", "D"}, []tunelPrueba{
+		{"A", "C", 2}, {"C", "B", 3}, {"B", "A", 4},
+	})
+	resultado, err := PlanificarRecorridoTSP(grafo, "A", []string{"B", "D", "C", "B"}, TSPHeldKarp, false)
+	if err != nil {
+		t.Fatalf("Error en recorrido: %v", err)
+	}
+	if !reflect.DeepEqual(resultado.Orden, []string{"A", "C", "B"}) || resultado.Costo != 5 {
+		t.Errorf("Recorrido inesperado %v con costo %.2f", resultado.Orden, resultado.Costo)
+	}
+	if !reflect.DeepEqual(resultado.Inalcanzables, []string{"D"}) {
+		t.Errorf("Inalcanzables inesperadas: %v", resultado.Inalcanzables)
+	}
+
+	// Sin ruta de vuelta desde B el recorrido cerrado no es posible
+	grafo = crearRedDirigida([]string{"A", "B"}, []tunelPrueba{{"A", "B", 1}})
+	if _, err := PlanificarRecorridoTSP(grafo, "A", nil, TSPMejorado, true); err == nil {
+		t.Error("Se esperaba un error al no poder regresar al origen")
+	}
+	if _, err := PlanificarRecorridoTSP(grafo, "A", nil, "otro", false); err == nil {
+		t.Error("Se esperaba un error por modo no válido")
+	}
+	if _, err := PlanificarRecorridoTSP(grafo, "A", []string{"Z"}, TSPMejorado, false); err == nil {
+		t.Error("Se esperaba un error por cueva inexistente")
+	}
+}
@@ -0,0 +1,199 @@
+package algorithms
+
+import (
+	"container/heap"
+	"fmt"
+	"math"
+	"proyecto-grafos-go/internal/domain"
+	"sort"
+	"strings"
+)
+
+// CaminoYen identifica los caminos obtenidos con KCaminosMasCortos
+const CaminoYen AlgoritmoCamino = "yen"
+
+// SegmentoCompartido es un túnel que aparece en más de una de las rutas alternativas
+type SegmentoCompartido struct {
+	Desde string `json:"desde"`
+	Hasta string `json:"hasta"`
+	Rutas []int  `json:"rutas"` // Posiciones (desde 1) de las rutas que lo usan
+}
+
+// KCaminosMasCortos devuelve hasta k caminos sin ciclos entre dos cuevas,
+// ordenados por costo, usando el algoritmo de Yen. Ignora los túneles obstruidos.
+func KCaminosMasCortos(grafo *domain.Grafo, desde, hasta string, k int) ([]*ResultadoCamino, error) {
+	if err := validarExtremos(grafo, desde, hasta); err != nil {
+		return nil, err
+	}
+	if k <= 0 {
+		return nil, fmt.Errorf("k debe ser mayor que cero")
+	}
+
+	expandidos := 0
+	primero, err := caminoRestringido(grafo, desde, hasta, nil, nil, &expandidos)
+	if err != nil {
+		return nil, err
+	}
+	if primero == nil {
+		return nil, fmt.Errorf("no hay ruta desde '%s' hasta '%s'", desde, hasta)
+	}
+	primero.NodosExpandidos = expandidos
+
+	caminos := []*ResultadoCamino{primero}
+	vistos := map[string]bool{claveRuta(primero.Ruta): true}
+	var candidatos []*ResultadoCamino
+
+	for len(caminos) < k {
+		anterior := caminos[len(caminos)-1]
+
+		// Desviarse del camino anterior en cada una de sus cuevas
+		for i := 0; i < len(anterior.Ruta)-1; i++ {
+			raiz := anterior.Ruta[:i+1]
+			desvio := anterior.Ruta[i]
+
+			// Quitar los túneles con que los caminos ya hallados siguen a esta raíz
+			aristasExcluidas := make(map[[2]string]bool)
+			for _, camino := range caminos {
+				if len(camino.Ruta) > i+1 && claveRuta(camino.Ruta[:i+1]) == claveRuta(raiz) {
+					aristasExcluidas[[2]string{camino.Ruta[i], camino.Ruta[i+1]}] = true
+				}
+			}
+			// Y las cuevas de la raíz, para que el camino no tenga ciclos
+			nodosExcluidos := make(map[string]bool, i)
+			for _, id := range raiz[:i] {
+				nodosExcluidos[id] = true
+			}
+
+			tramoDesvio, err := caminoRestringido(grafo, desvio, hasta, aristasExcluidas, nodosExcluidos, &expandidos)
+			if err != nil {
+				return nil, err
+			}
+			if tramoDesvio == nil {
+				continue
+			}
+
+			ruta := append(append([]string{}, raiz[:i]...), tramoDesvio.Ruta...)
+			clave := claveRuta(ruta)
+			if vistos[clave] {
+				continue
+			}
+			vistos[clave] = true
+			candidatos = append(candidatos, &ResultadoCamino{
+				Algoritmo: CaminoYen,
+				Ruta:      ruta,
+				Costo:     costoRaiz(grafo, raiz) + tramoDesvio.Costo,
+			})
+		}
+
+		if len(candidatos) == 0 {
+			break
+		}
+
+		// El candidato más barato; a igual costo, el de menos cuevas y luego el menor alfabéticamente
+		sort.SliceStable(candidatos, func(a, b int) bool {
+			if candidatos[a].Costo != candidatos[b].Costo {
+				return candidatos[a].Costo < candidatos[b].Costo
+			}
+			if len(candidatos[a].Ruta) != len(candidatos[b].Ruta) {
+				return len(candidatos[a].Ruta) < len(candidatos[b].Ruta)
+			}
+			return claveRuta(candidatos[a].Ruta) < claveRuta(candidatos[b].Ruta)
+		})
+		siguiente := candidatos[0]
+		candidatos = candidatos[1:]
+		siguiente.NodosExpandidos = expandidos
+		caminos = append(caminos, siguiente)
+	}
+
+	return caminos, nil
+}
+
+// SegmentosCompartidos lista los túneles usados por más de una ruta, en orden de
+// primera aparición. En grafos no dirigidos A-B y B-A cuentan como el mismo túnel.
+func SegmentosCompartidos(caminos []*ResultadoCamino, dirigido bool) []SegmentoCompartido {
+	var orden [][2]string
+	rutas := make(map[[2]string][]int)
+	for i, camino := range caminos {
+		for j := 1; j < len(camino.Ruta); j++ {
+			clave := [2]string{camino.Ruta[j-1], camino.Ruta[j]}
+			if !dirigido && clave[1] < clave[0] {
+				clave[0], clave[1] = clave[1], clave[0]
+			}
+			usos := rutas[clave]
+			if len(usos) == 0 {
+				orden = append(orden, clave)
+			}
+			if len(usos) == 0 || usos[len(usos)-1] != i+1 {
+				rutas[clave] = append(usos, i+1)
+			}
+		}
+	}
+
+	var compartidos []SegmentoCompartido
+	for _, clave := range orden {
+		if len(rutas[clave]) > 1 {
+			compartidos = append(compartidos, SegmentoCompartido{Desde: clave[0], Hasta: clave[1], Rutas: rutas[clave]})
+		}
+	}
+	return compartidos
+}
+
+// caminoRestringido es Dijkstra entre dos cuevas sin usar los túneles ni las
+// cuevas excluidos. Devuelve nil si no hay camino.
+func caminoRestringido(grafo *domain.Grafo, desde, hasta string, aristasExcluidas map[[2]string]bool, nodosExcluidos map[string]bool, expandidos *int) (*ResultadoCamino, error) {
+	distancias := map[string]float64{desde: 0}
+	predecesores := make(map[string]string)
+	cerrados := make(map[string]bool)
+
+	cola := &colaNodos{{id: desde, distancia: 0}}
+	for cola.Len() > 0 {
+		nodo := heap.Pop(cola).(nodoDistancia).id
+		if cerrados[nodo] {
+			continue
+		}
+		if nodo == hasta {
+			return &ResultadoCamino{
+				Algoritmo: CaminoYen,
+				Ruta:      reconstruirRuta(predecesores, desde, hasta),
+				Costo:     distancias[hasta],
+			}, nil
+		}
+		cerrados[nodo] = true
+		*expandidos++
+
+		for _, t := range tramosDesde(grafo, nodo, false) {
+			if nodosExcluidos[t.vecino] || aristasExcluidas[[2]string{nodo, t.vecino}] {
+				continue
+			}
+			if t.distancia < 0 {
+				return nil, fmt.Errorf("distancia negativa en %s -> %s; use Bellman-Ford", nodo, t.vecino)
+			}
+			nuevaDistancia := distancias[nodo] + t.distancia
+			if actual, ok := distancias[t.vecino]; !ok || nuevaDistancia < actual {
+				distancias[t.vecino] = nuevaDistancia
+				predecesores[t.vecino] = nodo
+				heap.Push(cola, nodoDistancia{id: t.vecino, distancia: nuevaDistancia})
+			}
+		}
+	}
+	return nil, nil
+}
+
+// costoRaiz suma el túnel más corto entre cada par de cuevas consecutivas
+func costoRaiz(grafo *domain.Grafo, ruta []string) float64 {
+	costo := 0.0
+	for i := 1; i < len(ruta); i++ {
+		menor := math.Inf(1)
+		for _, t := range tramosDesde(grafo, ruta[i-1], false) {
+			if t.vecino == ruta[i] && t.distancia < menor {
+				menor = t.distancia
+			}
+		}
+		costo += menor
+	}
+	return costo
+}
+
+func claveRuta(ruta []string) string {
+	return strings.Join(ruta, "\x00")
+}
@@ -0,0 +1,89 @@
+package algorithms
+
+import (
+	"proyecto-grafos-go/internal/domain"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// red dirigida del ejemplo clásico del algoritmo de Yen
+func crearRedYen() *domain.Grafo {
+	grafo := domain.NuevoGrafo(true)
+	for _, id := range []string{"C", "D", "E", "F", "G", "H"} {
+		grafo.AgregarCueva(domain.NuevaCueva(id, id))
+	}
+	tuneles := []struct {
+		desde, hasta string
+		distancia    float64
+	}{
+		{"C", "D", 3}, {"C", "E", 2}, {"D", "F", 4}, {"E", "D", 1}, {"E", "F", 2},
+		{"E", "G", 3}, {"F", "G", 2}, {"F", "H", 1}, {"G", "H", 2},
+	}
+	for _, t := range tuneles {
+		grafo.AgregarArista(domain.NuevaArista(t.desde, t.hasta, t.distancia, true))
+	}
+	return grafo
+}
+
+func TestKCaminosMasCortos(t *testing.T) {
+	grafo := crearRedYen()
+	caminos, err := KCaminosMasCortos(grafo, "C", "H", 3)
+	if err != nil {
+		t.Fatalf("Error en Yen: %v", err)
+	}
+
+	esperados := []struct {
+		ruta  string
+		costo float64
+	}{
+		{"C-E-F-H", 5}, {"C-E-G-H", 7}, {"C-D-F-H", 8},
+	}
+	if len(caminos) != len(esperados) {
+		t.Fatalf("Se esperaban %d caminos, se obtuvieron %d", len(esperados), len(caminos))
+	}
+	for i, esperado := range esperados {
+		if ruta := strings.Join(caminos[i].Ruta, "-"); ruta != esperado.ruta || caminos[i].Costo != esperado.costo {
+			t.Errorf("Camino %d: %s (%.0f), se esperaba %s (%.0f)", i+1, ruta, caminos[i].Costo, esperado.ruta, esperado.costo)
+		}
+	}
+
+	compartidos := SegmentosCompartidos(caminos, true)
+	esperadosCompartidos := []SegmentoCompartido{
+		{Desde: "C", Hasta: "E", Rutas: []int{1, 2}},
+		{Desde: "F", Hasta: "H", Rutas: []int{1, 3}},
+	}
+	if !reflect.DeepEqual(compartidos, esperadosCompartidos) {
+		t.Errorf("Segmentos compartidos %+v, se esperaba %+v", compartidos, esperadosCompartidos)
+	}
+}
+
+func TestKCaminosMasCortosObstruidos(t *testing.T) {
+	grafo := crearRedYen()
+	arista, _ := grafo.ObtenerConexion("E", "F")
+	arista.EsObstruido = true
+
+	caminos, err := KCaminosMasCortos(grafo, "C", "H", 10)
+	if err != nil {
+		t.Fatalf("Error en Yen: %v", err)
+	}
+	// Sin E -> F quedan C-E-G-H, C-D-F-H, C-E-D-F-H, C-D-F-G-H y C-E-D-F-G-H
+	if len(caminos) != 5 {
+		t.Fatalf("Se esperaban 5 caminos, se obtuvieron %d", len(caminos))
+	}
+	for i, camino := range caminos {
+		if strings.Contains(strings.Join(camino.Ruta, "-"), "E-F") {
+			t.Errorf("El camino %v usa un túnel obstruido", camino.Ruta)
+		}
+		if i > 0 && camino.Costo < caminos[i-1].Costo {
+			t.Errorf("Los caminos deben estar ordenados por costo: %v", caminos)
+		}
+	}
+
+	if _, err := KCaminosMasCortos(grafo, "H", "C", 2); err == nil {
+		t.Error("Se esperaba un error sin ruta H -> C")
+	}
+	if _, err := KCaminosMasCortos(grafo, "C", "H", 0); err == nil {
+		t.Error("Se esperaba un error con k = 0")
+	}
+}
@@ -10,7 +10,6 @@ import (
 	"strings"
 
 	"proyecto-grafos-go/internal/domain"
-	"proyecto-grafos-go/internal/repository"
 )
 
 // ExisteArchivo verifica si un archivo existe
@@ -264,11 +263,30 @@ func CargarConfiguracionCuevaAcmePorDefecto() (*domain.Grafo, error) {
 		return nil, fmt.Errorf("archivo de configuración por defecto no encontrado: %s", rutaCompleta)
 	}
 
-	// Crear un repositorio de archivos para cargar el JSON
-	repo := repository.NuevoRepositorio("data")
+	// Leer el JSON directamente (utils no depende del repositorio para evitar ciclos de importación)
+	var datos struct {
+		Cuevas     []*domain.Cueva  `json:"cuevas"`
+		Aristas    []*domain.Arista `json:"aristas"`
+		EsDirigido bool             `json:"es_dirigido"`
+	}
+	if err := CargarJSON(rutaCompleta, &datos); err != nil {
+		return nil, fmt.Errorf("error cargando configuración por defecto: %v", err)
+	}
+
+	constructor := domain.NuevoConstructorGrafo()
+	constructor.EstablecerDirigido(datos.EsDirigido)
+	for _, cueva := range datos.Cuevas {
+		if err := constructor.AgregarCueva(cueva); err != nil {
+			return nil, fmt.Errorf("error cargando configuración por defecto: %v", err)
+		}
+	}
+	for _, arista := range datos.Aristas {
+		if err := constructor.AgregarArista(arista); err != nil {
+			return nil, fmt.Errorf("error cargando configuración por defecto: %v", err)
+		}
+	}
 
-	// Cargar el grafo usando el repositorio
-	grafo, err := repo.CargarJSON(rutaDefecto)
+	grafo, err := constructor.Construir()
 	if err != nil {
 		return nil, fmt.Errorf("error cargando configuración por defecto: %v", err)
 	}
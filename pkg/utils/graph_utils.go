@@ -34,7 +34,7 @@ func dfs(grafo *domain.Grafo, vertex string, visited map[string]bool) {
 	visited[vertex] = true
 
 	// Visitar todos los vecinos no visitados
-	for _, arista := range grafo.Aristas {
+	for _, arista := range grafo.AristasIncidentes(vertex) {
 		var vecino string
 		if arista.Desde == vertex && !arista.EsObstruido {
 			vecino = arista.Hasta
@@ -93,7 +93,7 @@ func tieneCiclosNoDirigido(grafo *domain.Grafo, vertex, parent string, visited m
 	visited[vertex] = true
 
 	// Buscar todos los vértices adyacentes
-	for _, arista := range grafo.Aristas {
+	for _, arista := range grafo.AristasIncidentes(vertex) {
 		if arista.EsObstruido {
 			continue
 		}
@@ -125,7 +125,7 @@ func tieneCiclosDFS(grafo *domain.Grafo, vertex string, visited, recStack map[st
 	recStack[vertex] = true
 
 	// Visitar todos los adyacentes
-	for _, arista := range grafo.Aristas {
+	for _, arista := range grafo.AristasIncidentes(vertex) {
 		if arista.Desde == vertex && !arista.EsObstruido {
 			vecino := arista.Hasta
 
@@ -165,7 +165,7 @@ func ObtenerGrado(grafo *domain.Grafo, vertice string) int {
 	}
 
 	grado := 0
-	for _, arista := range grafo.Aristas {
+	for _, arista := range grafo.AristasIncidentes(vertice) {
 		if arista.EsObstruido {
 			continue
 		}
@@ -220,7 +220,7 @@ func dfsComponente(grafo *domain.Grafo, vertex string, visited map[string]bool,
 	*componente = append(*componente, vertex)
 
 	// Visitar todos los vecinos no visitados
-	for _, arista := range grafo.Aristas {
+	for _, arista := range grafo.AristasIncidentes(vertex) {
 		if arista.EsObstruido {
 			continue
 		}
@@ -5,6 +5,12 @@ import (
 	"strings"
 )
 
+// Expresiones precompiladas para validar IDs
+var (
+	patronID    = regexp.MustCompile("^[a-zA-Z0-9_-]+$")
+	patronLetra = regexp.MustCompile("[a-zA-Z]")
+)
+
 // ValidarID valida que un ID sea válido (no vacío, formato correcto)
 func ValidarID(id string) bool {
 	if strings.TrimSpace(id) == "" {
@@ -16,14 +22,12 @@ func ValidarID(id string) bool {
 	}
 
 	// Verificar que solo contenga caracteres alfanuméricos y guiones
-	match, _ := regexp.MatchString("^[a-zA-Z0-9_-]+$", id)
-	if !match {
+	if !patronID.MatchString(id) {
 		return false
 	}
 
 	// Debe contener al menos una letra (no solo números)
-	hasLetter, _ := regexp.MatchString("[a-zA-Z]", id)
-	return hasLetter
+	return patronLetra.MatchString(id)
 }
 
 // ValidarNombre valida que un nombre sea válido
@@ -58,7 +62,10 @@ func ValidarArchivoExtension(archivo string) bool {
 	archivo = strings.ToLower(archivo)
 	return strings.HasSuffix(archivo, ".json") ||
 		strings.HasSuffix(archivo, ".xml") ||
-		strings.HasSuffix(archivo, ".txt")
+		strings.HasSuffix(archivo, ".txt") ||
+		strings.HasSuffix(archivo, ".graphml") ||
+		strings.HasSuffix(archivo, ".gexf") ||
+		strings.HasSuffix(archivo, ".csv")
 }
 
 // ValidarTipoCamion valida que el tipo de camión sea válido (A, B, C)
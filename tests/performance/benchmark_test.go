@@ -21,8 +21,7 @@ func BenchmarkDFS(b *testing.B) {
 			b.ResetTimer()
 			for i := 0; i < b.N; i++ {
 				// Ejecutar DFS desde el primer nodo
-				visited := make(map[string]bool)
-				algorithms.DFS(grafo, "C0", visited)
+				algorithms.DFS(grafo, "C0")
 			}
 		})
 	}
@@ -38,8 +37,7 @@ func BenchmarkBFS(b *testing.B) {
 
 			b.ResetTimer()
 			for i := 0; i < b.N; i++ {
-				visited := make(map[string]bool)
-				algorithms.BFS(grafo, "C0", visited)
+				algorithms.BFS(grafo, "C0")
 			}
 		})
 	}
@@ -55,7 +53,7 @@ func BenchmarkDijkstra(b *testing.B) {
 
 			b.ResetTimer()
 			for i := 0; i < b.N; i++ {
-				algorithms.Dijkstra(grafo, "C0", "C1")
+				algorithms.Dijkstra(grafo, "C0")
 			}
 		})
 	}
@@ -71,7 +69,7 @@ func BenchmarkPrim(b *testing.B) {
 
 			b.ResetTimer()
 			for i := 0; i < b.N; i++ {
-				algorithms.Prim(grafo)
+				algorithms.Prim(grafo, "C0")
 			}
 		})
 	}
@@ -206,6 +204,85 @@ func BenchmarkOperacionesGrafo(b *testing.B) {
 	})
 }
 
+// BenchmarkRedGrande compara las consultas indexadas con un recorrido lineal de las aristas en 10k cuevas
+func BenchmarkRedGrande(b *testing.B) {
+	const n = 10000
+	grafo := crearGrafoCompleto(n)
+
+	b.Run("ObtenerVecinos_indice", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			grafo.ObtenerVecinos(fmt.Sprintf("C%d", i%n))
+		}
+	})
+
+	b.Run("ObtenerVecinos_lineal", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			vecinosLineal(grafo, fmt.Sprintf("C%d", i%n))
+		}
+	})
+
+	// C{i} -> C{i+n/2} no existe: el recorrido lineal revisa todas las aristas (peor caso)
+	b.Run("ExisteConexion_indice", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			grafo.ExisteConexion(fmt.Sprintf("C%d", i%n), fmt.Sprintf("C%d", (i+n/2)%n))
+		}
+	})
+
+	b.Run("ExisteConexion_lineal", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			existeConexionLineal(grafo, fmt.Sprintf("C%d", i%n), fmt.Sprintf("C%d", (i+n/2)%n))
+		}
+	})
+
+	b.Run("BFS", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			algorithms.BFS(grafo, "C0")
+		}
+	})
+
+	b.Run("DFS", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			algorithms.DFS(grafo, "C0")
+		}
+	})
+
+	b.Run("Dijkstra", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			algorithms.Dijkstra(grafo, "C0")
+		}
+	})
+
+	b.Run("AgregarEliminarArista", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			desde, hasta := fmt.Sprintf("C%d", i%n), fmt.Sprintf("C%d", (i+n/2)%n)
+			arista := domain.NuevaArista(desde, hasta, 1, true)
+			grafo.AgregarArista(arista)
+			grafo.EliminarArista(arista)
+		}
+	})
+}
+
+// vecinosLineal obtiene los vecinos recorriendo todas las aristas, como antes del índice
+func vecinosLineal(grafo *domain.Grafo, id string) []string {
+	var vecinos []string
+	for _, arista := range grafo.Aristas {
+		if arista.Desde == id && !arista.EsObstruido {
+			vecinos = append(vecinos, arista.Hasta)
+		}
+	}
+	return vecinos
+}
+
+// existeConexionLineal busca la conexión recorriendo todas las aristas, como antes del índice
+func existeConexionLineal(grafo *domain.Grafo, desde, hasta string) bool {
+	for _, arista := range grafo.Aristas {
+		if arista.Desde == desde && arista.Hasta == hasta {
+			return true
+		}
+	}
+	return false
+}
+
 // Función auxiliar para crear un grafo completo con n nodos
 func crearGrafoCompleto(n int) *domain.Grafo {
 	grafo := domain.NuevoGrafo(false)